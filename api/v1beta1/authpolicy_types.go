@@ -1,12 +1,20 @@
 package v1beta1
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
@@ -26,6 +34,18 @@ type AuthSchemeSpec struct {
 	// At least one config of this list MUST evaluate to a valid identity for a request to be successful in the identity verification phase.
 	Identity []*authorinov1beta1.Identity `json:"identity,omitempty"`
 
+	// IdentityOrder lists the names of the identity sources in the order Authorino tries them.
+	// Each listed source gets a priority group of its own, so a source is only tried if the previous ones fail to
+	// resolve an identity. The sources not listed are tried last, concurrently.
+	// If omitted, the priorities of the identity sources apply as set.
+	// +optional
+	IdentityOrder []string `json:"identityOrder,omitempty"`
+
+	// ClientCertificate sets the attributes of the client certificates extracted into the identity objects resolved by
+	// the mTLS identity sources, for the requests terminated by the gateway with mutual TLS.
+	// +optional
+	ClientCertificate *ClientCertificateSpec `json:"clientCertificate,omitempty"`
+
 	// List of metadata source configs.
 	// Authorino fetches JSON content from sources on this list on every request.
 	Metadata []*authorinov1beta1.Metadata `json:"metadata,omitempty"`
@@ -42,6 +62,329 @@ type AuthSchemeSpec struct {
 	DenyWith *authorinov1beta1.DenyWith `json:"denyWith,omitempty"`
 }
 
+type ClientCertificateSpec struct {
+	// Attributes of the client certificate added to the identity objects, as properties of the same name:
+	// `cn` the common name of the subject, `san` the URI or DNS subject alternative name, and `fingerprint` the
+	// SHA-256 hash of the certificate, as forwarded by the gateway in the x-forwarded-client-cert header.
+	// +kubebuilder:validation:MinItems=1
+	Attributes []ClientCertificateAttribute `json:"attributes"`
+}
+
+// +kubebuilder:validation:Enum=cn;san;fingerprint
+type ClientCertificateAttribute string
+
+// clientCertificateSelectors are the selectors of the attributes of the client certificate in the authorization JSON.
+// Authorino resolves the subject of the certificate as the identity object; Envoy sets the subject alternative name
+// as the principal of the source, and the hash in the x-forwarded-client-cert header.
+var clientCertificateSelectors = map[ClientCertificateAttribute]string{
+	"cn":          "auth.identity.CommonName",
+	"san":         "context.source.principal",
+	"fingerprint": `context.request.http.headers.x-forwarded-client-cert.@extract:{"sep":"Hash=","pos":1}|@extract:{"sep":";"}`,
+}
+
+// ValidateClientCertificate rejects the client certificate attributes repeated, or set without any mTLS identity source
+func (s *AuthSchemeSpec) ValidateClientCertificate() error {
+	if s.ClientCertificate == nil {
+		return nil
+	}
+
+	seen := make(map[ClientCertificateAttribute]struct{}, len(s.ClientCertificate.Attributes))
+	for _, attribute := range s.ClientCertificate.Attributes {
+		if _, ok := seen[attribute]; ok {
+			return fmt.Errorf("invalid authScheme.clientCertificate. Attribute %s listed more than once", attribute)
+		}
+		seen[attribute] = struct{}{}
+	}
+
+	for _, identity := range s.Identity {
+		if identity != nil && identity.MTLS != nil {
+			return nil
+		}
+	}
+	return errors.New("invalid authScheme.clientCertificate. At least one mTLS identity source is required")
+}
+
+// Apply returns the identity sources with the attributes of the client certificate added to the extended properties
+// of the mTLS identity sources, but for the properties they set. The identity sources given are left untouched.
+func (c *ClientCertificateSpec) Apply(identities []*authorinov1beta1.Identity) []*authorinov1beta1.Identity {
+	if c == nil {
+		return identities
+	}
+
+	applied := make([]*authorinov1beta1.Identity, 0, len(identities))
+	for _, identity := range identities {
+		if identity == nil || identity.MTLS == nil {
+			applied = append(applied, identity)
+			continue
+		}
+		identity = identity.DeepCopy()
+		for _, attribute := range c.Attributes {
+			if hasExtendedProperty(identity, string(attribute)) {
+				continue
+			}
+			identity.ExtendedProperties = append(identity.ExtendedProperties, authorinov1beta1.ExtendedProperty{
+				JsonProperty: authorinov1beta1.JsonProperty{
+					Name:      string(attribute),
+					ValueFrom: authorinov1beta1.ValueFrom{AuthJSON: clientCertificateSelectors[attribute]},
+				},
+			})
+		}
+		applied = append(applied, identity)
+	}
+	return applied
+}
+
+func hasExtendedProperty(identity *authorinov1beta1.Identity, name string) bool {
+	for _, property := range identity.ExtendedProperties {
+		if property.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateIdentityOrder rejects an identity order referring to unknown or repeated identity sources, or set
+// without any identity source
+func (s *AuthSchemeSpec) ValidateIdentityOrder() error {
+	if len(s.IdentityOrder) == 0 {
+		return nil
+	}
+	if len(s.Identity) == 0 {
+		return fmt.Errorf("invalid authScheme.identityOrder. At least one identity source is required")
+	}
+
+	names := make(map[string]bool, len(s.Identity))
+	for _, identity := range s.Identity {
+		if identity != nil {
+			names[identity.Name] = false
+		}
+	}
+	for _, name := range s.IdentityOrder {
+		listed, ok := names[name]
+		if !ok {
+			return fmt.Errorf("invalid authScheme.identityOrder. Unknown identity source %s", name)
+		}
+		if listed {
+			return fmt.Errorf("invalid authScheme.identityOrder. Identity source %s listed more than once", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// IdentityReferenceError is the error of an evaluator referring to an identity source not defined by the auth scheme
+type IdentityReferenceError struct {
+	// Evaluator referring to the identity source, e.g. "metadata user-info"
+	Evaluator string
+	// IdentitySource referred to
+	IdentitySource string
+	// NotOIDC tells the identity source is defined, but not an OIDC identity source
+	NotOIDC bool
+}
+
+func (e *IdentityReferenceError) Error() string {
+	if e.NotOIDC {
+		return fmt.Sprintf("invalid authScheme.%s. Identity source %s is not an OIDC identity source", e.Evaluator, e.IdentitySource)
+	}
+	return fmt.Sprintf("invalid authScheme.%s. Unknown identity source %s", e.Evaluator, e.IdentitySource)
+}
+
+func IsIdentityReferenceError(err error) bool {
+	identityRefErr := &IdentityReferenceError{}
+	return errors.As(err, &identityRefErr)
+}
+
+// ValidateIdentityReferences rejects the evaluators referring to identity sources the auth scheme does not define,
+// i.e. the userInfo metadata sources of unknown or non-OIDC identity sources, accepted by Authorino but never resolved
+func (s *AuthSchemeSpec) ValidateIdentityReferences() error {
+	oidc := make(map[string]bool, len(s.Identity))
+	for _, identity := range s.Identity {
+		if identity != nil {
+			oidc[identity.Name] = identity.Oidc != nil
+		}
+	}
+	for _, metadata := range s.Metadata {
+		if metadata == nil || metadata.UserInfo == nil {
+			continue
+		}
+		isOIDC, ok := oidc[metadata.UserInfo.IdentitySource]
+		if !ok || !isOIDC {
+			return &IdentityReferenceError{
+				Evaluator:      fmt.Sprintf("metadata %s", metadata.Name),
+				IdentitySource: metadata.UserInfo.IdentitySource,
+				NotOIDC:        ok,
+			}
+		}
+	}
+	return nil
+}
+
+// OrderedIdentity returns the identity sources with the priorities resolved from the identity order: the listed
+// sources first, in order, then the others. The identity sources of the spec are left untouched.
+func (s *AuthSchemeSpec) OrderedIdentity() []*authorinov1beta1.Identity {
+	if len(s.IdentityOrder) == 0 {
+		return s.Identity
+	}
+
+	positions := make(map[string]int, len(s.IdentityOrder))
+	for idx, name := range s.IdentityOrder {
+		positions[name] = idx
+	}
+
+	ordered := make([]*authorinov1beta1.Identity, 0, len(s.Identity))
+	for _, identity := range s.Identity {
+		if identity == nil {
+			continue
+		}
+		identity = identity.DeepCopy()
+		identity.Priority = len(s.IdentityOrder)
+		if position, ok := positions[identity.Name]; ok {
+			identity.Priority = position
+		}
+		ordered = append(ordered, identity)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+	return ordered
+}
+
+// ValidateMissingIdentity rejects the identity sources contradicting the behaviour of the policy for the requests
+// without a valid identity: the anonymous identities without conditions when denied, letting all the requests
+// through, and the anonymous fallback without any identity source to fall back from. The name of the anonymous
+// fallback is reserved.
+func (s *AuthSchemeSpec) ValidateMissingIdentity(mode MissingIdentityMode) error {
+	for _, identity := range s.Identity {
+		if identity != nil && identity.Name == AnonymousFallbackIdentityName {
+			return fmt.Errorf("invalid authScheme.identity %s. The name is reserved to the anonymous fallback", identity.Name)
+		}
+	}
+
+	if mode == MissingIdentityAnonymous {
+		for _, identity := range s.Identity {
+			if identity != nil && identity.Anonymous == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid missingIdentity %s. At least one identity source other than anonymous is required", mode)
+	}
+
+	for _, identity := range s.Identity {
+		if identity != nil && identity.Anonymous != nil && len(identity.Conditions) == 0 {
+			return fmt.Errorf("invalid authScheme.identity %s. Anonymous identity without conditions lets all the requests through, set missingIdentity to anonymous instead", identity.Name)
+		}
+	}
+	return nil
+}
+
+// WithMissingIdentity returns the identity sources with an anonymous identity added in a priority group of its own,
+// after all the others, if the requests without a valid identity are let through. The identity sources given are
+// left untouched.
+func WithMissingIdentity(mode MissingIdentityMode, identities []*authorinov1beta1.Identity) []*authorinov1beta1.Identity {
+	if mode != MissingIdentityAnonymous {
+		return identities
+	}
+
+	priority := 0
+	for _, identity := range identities {
+		if identity != nil && identity.Priority >= priority {
+			priority = identity.Priority + 1
+		}
+	}
+	return append(append(make([]*authorinov1beta1.Identity, 0, len(identities)+1), identities...), &authorinov1beta1.Identity{
+		Name:      AnonymousFallbackIdentityName,
+		Priority:  priority,
+		Anonymous: &authorinov1beta1.Identity_Anonymous{},
+	})
+}
+
+// MissingIdentityOf returns the behaviour of an AuthConfig for the requests without a valid identity: anonymous if
+// the AuthConfig has no identity source, Authorino skipping the identity phase, or an anonymous identity without
+// conditions of its own; deny otherwise
+func MissingIdentityOf(spec authorinov1beta1.AuthConfigSpec) MissingIdentityMode {
+	if len(spec.Identity) == 0 {
+		return MissingIdentityAnonymous
+	}
+	for _, identity := range spec.Identity {
+		if identity != nil && identity.Anonymous != nil && len(identity.Conditions) == 0 {
+			return MissingIdentityAnonymous
+		}
+	}
+	return MissingIdentityDeny
+}
+
+// IdentityOrderOf returns the names of the identity sources of an AuthConfig in the order Authorino tries them,
+// i.e. by priority group
+func IdentityOrderOf(spec authorinov1beta1.AuthConfigSpec) []string {
+	identities := make([]*authorinov1beta1.Identity, 0, len(spec.Identity))
+	for _, identity := range spec.Identity {
+		if identity != nil {
+			identities = append(identities, identity)
+		}
+	}
+	sort.SliceStable(identities, func(i, j int) bool { return identities[i].Priority < identities[j].Priority })
+
+	names := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		names = append(names, identity.Name)
+	}
+	return names
+}
+
+// SecretRefs returns the sorted names of the Secrets referenced by the auth scheme, in the namespace of the policy
+func (s *AuthSchemeSpec) SecretRefs() []string {
+	uniqueNames := make(map[string]struct{})
+	addRef := func(name string) {
+		if name != "" {
+			uniqueNames[name] = struct{}{}
+		}
+	}
+	addSecretKeyRef := func(ref *authorinov1beta1.SecretKeyReference) {
+		if ref != nil {
+			addRef(ref.Name)
+		}
+	}
+
+	for _, identity := range s.Identity {
+		if identity.OAuth2 != nil && identity.OAuth2.Credentials != nil {
+			addRef(identity.OAuth2.Credentials.Name)
+		}
+	}
+	for _, metadata := range s.Metadata {
+		if metadata.UMA != nil && metadata.UMA.Credentials != nil {
+			addRef(metadata.UMA.Credentials.Name)
+		}
+		if metadata.GenericHTTP != nil {
+			addSecretKeyRef(metadata.GenericHTTP.SharedSecret)
+			if metadata.GenericHTTP.OAuth2 != nil {
+				addSecretKeyRef(&metadata.GenericHTTP.OAuth2.ClientSecret)
+			}
+		}
+	}
+	for _, authorization := range s.Authorization {
+		if authorization.OPA != nil {
+			addSecretKeyRef(authorization.OPA.ExternalRegistry.SharedSecret)
+		}
+		if authorization.Authzed != nil {
+			addSecretKeyRef(authorization.Authzed.SharedSecret)
+		}
+	}
+	for _, response := range s.Response {
+		if response.Wristband != nil {
+			for _, signingKeyRef := range response.Wristband.SigningKeyRefs {
+				if signingKeyRef != nil {
+					addRef(signingKeyRef.Name)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(uniqueNames))
+	for name := range uniqueNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 type AuthPolicySpec struct {
 	// TargetRef identifies an API object to apply policy to.
 	TargetRef gatewayapiv1alpha2.PolicyTargetReference `json:"targetRef"`
@@ -51,6 +394,261 @@ type AuthPolicySpec struct {
 
 	// AuthSchemes are embedded Authorino's AuthConfigs
 	AuthScheme AuthSchemeSpec `json:"authScheme,omitempty"`
+
+	// Exclusions describe the requests that will NOT be routed to the external authorization provider.
+	// Only supported by policies targeting a Gateway.
+	// +optional
+	Exclusions *AuthExclusions `json:"exclusions,omitempty"`
+
+	// TemplateRef is the reference to a PolicyTemplate in the same namespace whose auth scheme is inherited by the policy.
+	// +optional
+	TemplateRef *corev1.LocalObjectReference `json:"templateRef,omitempty"`
+
+	// IdentityProviders are the references to IdentityProviders in the same namespace whose issuers authenticate the
+	// requests, each added to the identity sources of the policy under the name of the provider.
+	// +optional
+	IdentityProviders []corev1.LocalObjectReference `json:"identityProviders,omitempty"`
+
+	// FailureMode tells whether the requests are let through (open) or denied (closed) when Authorino is unavailable.
+	// +kubebuilder:default:=closed
+	// +optional
+	FailureMode FailureMode `json:"failureMode,omitempty"`
+
+	// ExpiresAt is the time, in RFC 3339 format, the policy is deleted at, e.g. for a temporary access granted
+	// during an incident.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Cache sets the caching of the results of the evaluators of the auth scheme, keyed on request attributes.
+	// Applied to the evaluators that do not set a cache of their own.
+	// +optional
+	Cache *AuthCacheSpec `json:"cache,omitempty"`
+
+	// Listeners scopes the enforcement of the policy to the listeners of the gateways selected by name or by protocol,
+	// e.g. to leave out the HTTP listeners redirecting to HTTPS. If omitted, the policy is enforced on all the listeners.
+	// +optional
+	Listeners *AuthListenersSpec `json:"listeners,omitempty"`
+
+	// MissingIdentity tells whether the requests without a valid identity are denied (deny) or let through as
+	// anonymous (anonymous) to the metadata, authorization and response phases, the anonymous identity tried after
+	// all the identity sources.
+	// +kubebuilder:default:=deny
+	// +optional
+	MissingIdentity MissingIdentityMode `json:"missingIdentity,omitempty"`
+}
+
+// +kubebuilder:validation:Enum:=deny;anonymous
+type MissingIdentityMode string
+
+const (
+	// MissingIdentityDeny denies the requests none of the identity sources resolves an identity for
+	MissingIdentityDeny MissingIdentityMode = "deny"
+
+	// MissingIdentityAnonymous lets the requests none of the identity sources resolves an identity for through as
+	// anonymous
+	MissingIdentityAnonymous MissingIdentityMode = "anonymous"
+)
+
+// AnonymousFallbackIdentityName is the name of the anonymous identity added to the AuthConfig of the policies
+// letting the requests without a valid identity through
+const AnonymousFallbackIdentityName = "anonymous-fallback"
+
+type AuthListenersSpec struct {
+	// Names of the listeners the policy is enforced on
+	// +optional
+	Names []gatewayapiv1beta1.SectionName `json:"names,omitempty"`
+
+	// Protocols of the listeners the policy is enforced on, e.g. HTTPS
+	// +optional
+	Protocols []gatewayapiv1beta1.ProtocolType `json:"protocols,omitempty"`
+}
+
+// Validate rejects a scope of listeners with neither names nor protocols
+func (s *AuthListenersSpec) Validate() error {
+	if s != nil && len(s.Names) == 0 && len(s.Protocols) == 0 {
+		return errors.New("invalid listeners. At least one name or protocol must be set")
+	}
+	return nil
+}
+
+// Selects tells whether the policy is enforced on a listener, i.e. the listener matches any of the names, if any,
+// and any of the protocols, if any. All the listeners are selected by a nil scope.
+func (s *AuthListenersSpec) Selects(listener gatewayapiv1beta1.Listener) bool {
+	if s == nil {
+		return true
+	}
+	if len(s.Names) > 0 && !common.Contains(s.Names, listener.Name) {
+		return false
+	}
+	if len(s.Protocols) > 0 && !common.Contains(s.Protocols, listener.Protocol) {
+		return false
+	}
+	return true
+}
+
+type AuthCacheSpec struct {
+	// Key is the selector of the value in the authorization JSON the results are cached by, e.g.
+	// context.request.http.headers.x-api-key, or a template combining several selectors, e.g.
+	// {auth.identity.sub}-{context.request.http.path}.
+	// The selectors refer to the request (`context`) or to the resolved auth data (`auth`).
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// TTL is the duration, in seconds, of the cached results before evaluated again.
+	// If omitted, the TTL default of the Kuadrant instance applies, or Authorino's default of 60 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int `json:"ttl,omitempty"`
+
+	// Evaluators are the types of evaluators whose results are cached.
+	// If omitted, all the types are.
+	// +optional
+	Evaluators []AuthCacheEvaluator `json:"evaluators,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=identity;metadata;authorization;response
+type AuthCacheEvaluator string
+
+// AuthCacheEvaluators are the types of evaluators whose results can be cached
+var AuthCacheEvaluators = []AuthCacheEvaluator{"identity", "metadata", "authorization", "response"}
+
+// authJSONRoots are the top-level properties of the authorization JSON
+var authJSONRoots = []string{"context", "auth"}
+
+// modifierArgsRegex matches the start of the JSON arguments of a modifier of a selector, e.g. @extract:{
+var modifierArgsRegex = regexp.MustCompile(`[^@]+@\w+:{`)
+
+// Validate rejects the cache keys Authorino cannot resolve, i.e. the templates whose braces are not balanced or
+// with empty placeholders, and the selectors not referring to the authorization JSON
+func (s *AuthCacheSpec) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if strings.TrimSpace(s.Key) == "" {
+		return errors.New("invalid cache.key. The key must not be empty")
+	}
+	if strings.TrimSpace(s.Key) != s.Key {
+		return fmt.Errorf("invalid cache.key %q. The key must not start or end with whitespaces", s.Key)
+	}
+
+	selectors, reason := s.keySelectors()
+	if reason != "" {
+		return fmt.Errorf("invalid cache.key %q. %s", s.Key, reason)
+	}
+	for _, selector := range selectors {
+		root := strings.SplitN(selector, ".", 2)[0]
+		if !common.Contains(authJSONRoots, root) {
+			return fmt.Errorf("invalid cache.key %q. Selector %s must start with %s", s.Key, selector, strings.Join(authJSONRoots, " or "))
+		}
+	}
+	return nil
+}
+
+// keySelectors returns the selectors of the key: the key itself, unless a template, as told by Authorino, whose
+// braces are not all the ones of the arguments of modifiers. Returns the reason the template is malformed, if so.
+func (s *AuthCacheSpec) keySelectors() ([]string, string) {
+	if len(modifierArgsRegex.FindAllString(s.Key, -1)) == strings.Count(s.Key, "{") {
+		return []string{s.Key}, ""
+	}
+
+	var selectors []string
+	depth, start := 0, 0
+	for i := 0; i < len(s.Key); i++ {
+		switch s.Key[i] {
+		case '\\':
+			// escaped character
+			i++
+		case '{':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				return nil, "Unbalanced braces of the template"
+			}
+			depth--
+			if depth > 0 {
+				continue
+			}
+			selector := strings.TrimSpace(s.Key[start:i])
+			if selector == "" {
+				return nil, "Empty placeholder of the template"
+			}
+			selectors = append(selectors, selector)
+		}
+	}
+	if depth > 0 {
+		return nil, "Unbalanced braces of the template"
+	}
+	return selectors, ""
+}
+
+// Apply returns a copy of an AuthConfig spec with the results of the evaluators of the selected types cached by the
+// key, but for the evaluators setting a cache of their own
+func (s *AuthCacheSpec) Apply(spec authorinov1beta1.AuthConfigSpec) authorinov1beta1.AuthConfigSpec {
+	cached := spec.DeepCopy()
+	if s == nil {
+		return *cached
+	}
+
+	cache := func(evaluatorType AuthCacheEvaluator, current **authorinov1beta1.EvaluatorCaching) {
+		if *current != nil || (len(s.Evaluators) > 0 && !common.Contains(s.Evaluators, evaluatorType)) {
+			return
+		}
+		*current = &authorinov1beta1.EvaluatorCaching{
+			Key: authorinov1beta1.StaticOrDynamicValue{ValueFrom: authorinov1beta1.ValueFrom{AuthJSON: s.Key}},
+		}
+		if s.TTL != nil {
+			(*current).TTL = *s.TTL
+		}
+	}
+
+	for _, identity := range cached.Identity {
+		cache("identity", &identity.Cache)
+	}
+	for _, metadata := range cached.Metadata {
+		cache("metadata", &metadata.Cache)
+	}
+	for _, authorization := range cached.Authorization {
+		cache("authorization", &authorization.Cache)
+	}
+	for _, response := range cached.Response {
+		cache("response", &response.Cache)
+	}
+
+	return *cached
+}
+
+// +kubebuilder:validation:Enum:=open;closed
+type FailureMode string
+
+const (
+	// FailOpen lets the requests through when the external authorization service is unavailable
+	FailOpen FailureMode = "open"
+
+	// FailClosed denies the requests when the external authorization service is unavailable
+	FailClosed FailureMode = "closed"
+)
+
+type AuthExclusions struct {
+	// HTTPRoutes attached to the targeted Gateway whose requests are exempt from the policy.
+	// +optional
+	HTTPRoutes []HTTPRouteReference `json:"httpRoutes,omitempty"`
+
+	// Paths exempt from the policy, regardless of the route that matches the request.
+	// Supports the same syntax as the paths of the rules (exact, prefix `/foo*` and suffix `*/foo` matches).
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+}
+
+type HTTPRouteReference struct {
+	// Name of the HTTPRoute.
+	Name string `json:"name"`
+
+	// Namespace of the HTTPRoute. Defaults to the namespace of the policy.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
 }
 
 type AuthRule struct {
@@ -71,6 +669,168 @@ type AuthPolicyStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// ExcludedHTTPRoutes lists the HTTPRoutes exempt from the policy, i.e. the ones referred in `spec.exclusions`
+	// that are attached to the targeted Gateway.
+	// +optional
+	ExcludedHTTPRoutes []string `json:"excludedHTTPRoutes,omitempty"`
+
+	// NumAuthRules is the number of rules of the policy.
+	// +optional
+	NumAuthRules int `json:"numAuthRules,omitempty"`
+
+	// NumAuthentication is the number of identity sources of the generated AuthConfig.
+	// +optional
+	NumAuthentication int `json:"numAuthentication,omitempty"`
+
+	// NumAuthorization is the number of authorization policies of the generated AuthConfig.
+	// +optional
+	NumAuthorization int `json:"numAuthorization,omitempty"`
+
+	// NumResponse is the number of response configs of the generated AuthConfig.
+	// +optional
+	NumResponse int `json:"numResponse,omitempty"`
+
+	// UnauthenticatedResponseCode is the effective HTTP status code of the responses to unauthenticated requests.
+	// +optional
+	UnauthenticatedResponseCode int `json:"unauthenticatedResponseCode,omitempty"`
+
+	// UnauthorizedResponseCode is the effective HTTP status code of the responses to unauthorized requests.
+	// +optional
+	UnauthorizedResponseCode int `json:"unauthorizedResponseCode,omitempty"`
+
+	// Authorino is the reference to the Authorino instance serving the AuthConfig of the policy.
+	// +optional
+	Authorino *AuthorinoReference `json:"authorino,omitempty"`
+
+	// FailureMode is the failure mode of the external authorization of the requests protected by the policy.
+	// +optional
+	FailureMode FailureMode `json:"failureMode,omitempty"`
+
+	// InjectedHeaders lists the headers added by the generated AuthConfig to the requests to the upstream.
+	// +optional
+	InjectedHeaders []string `json:"injectedHeaders,omitempty"`
+
+	// ExtAuthzTimeout is the effective timeout of the ext_authz requests of the gateways to Authorino, e.g. 250ms.
+	// +optional
+	ExtAuthzTimeout string `json:"extAuthzTimeout,omitempty"`
+
+	// OutboundTimeout is the effective timeout of the requests of Authorino to the external identity and metadata
+	// providers of the policy, e.g. 200ms. Empty if not set.
+	// +optional
+	OutboundTimeout string `json:"outboundTimeout,omitempty"`
+
+	// AnonymousAccess lists the anonymous identities of the generated AuthConfig, i.e. the requests let through
+	// without credentials.
+	// +optional
+	AnonymousAccess []AnonymousAccess `json:"anonymousAccess,omitempty"`
+
+	// Hierarchy lists the role of the policy at each gateway it applies to, among the AuthPolicies of the gateway
+	// and of its HTTPRoutes.
+	// +optional
+	Hierarchy []PolicyHierarchy `json:"hierarchy,omitempty"`
+
+	// IdentityOrder lists the names of the identity sources of the generated AuthConfig in the order Authorino tries
+	// them, by priority group.
+	// +optional
+	IdentityOrder []string `json:"identityOrder,omitempty"`
+
+	// Cache lists the evaluators of the generated AuthConfig whose results are cached, with the effective key and TTL.
+	// +optional
+	Cache []EvaluatorCache `json:"cache,omitempty"`
+
+	// ClientCertificate lists the mTLS identity sources of the generated AuthConfig, with the attributes of the
+	// client certificate extracted into their identity objects.
+	// +optional
+	ClientCertificate []ClientCertificateIdentity `json:"clientCertificate,omitempty"`
+
+	// MissingIdentity is the effective behaviour of the generated AuthConfig for the requests without a valid
+	// identity: deny, or anonymous if let through, e.g. by an AuthConfig without identity sources.
+	// +optional
+	MissingIdentity MissingIdentityMode `json:"missingIdentity,omitempty"`
+
+	// Listeners are the listeners of the gateways the policy is enforced on, in the form <gateway>/<listener>,
+	// when the policy is scoped to some of the listeners
+	// +optional
+	Listeners []string `json:"listeners,omitempty"`
+}
+
+// PolicyRole is the role of an AuthPolicy among the AuthPolicies of a gateway and of its HTTPRoutes
+// +kubebuilder:validation:Enum=Default;Override;Shadowed
+type PolicyRole string
+
+const (
+	// DefaultPolicyRole is the role of a policy of a gateway, applying to the HTTPRoutes without their own policy
+	DefaultPolicyRole PolicyRole = "Default"
+
+	// OverridePolicyRole is the role of a policy of an HTTPRoute, prevailing over the policy of the gateway
+	OverridePolicyRole PolicyRole = "Override"
+
+	// ShadowedPolicyRole is the role of a policy of a gateway all the HTTPRoutes of which have their own policy or
+	// are excluded, i.e. applying to none of the requests
+	ShadowedPolicyRole PolicyRole = "Shadowed"
+)
+
+// PolicyHierarchy is the role of the policy at a gateway
+type PolicyHierarchy struct {
+	// Gateway is the namespaced name of the gateway.
+	Gateway string `json:"gateway"`
+
+	// Role of the policy at the gateway.
+	Role PolicyRole `json:"role"`
+
+	// Policies lists the namespaced names of the other AuthPolicies interacting with the policy at the gateway:
+	// the policies of the HTTPRoutes overriding the policy of the gateway, or the policy of the gateway overridden
+	// by the policy of the HTTPRoute.
+	// +optional
+	Policies []string `json:"policies,omitempty"`
+}
+
+// AnonymousAccess summarizes the requests an anonymous identity of the AuthConfig lets through without credentials
+type AnonymousAccess struct {
+	// Name of the anonymous identity.
+	Name string `json:"name"`
+
+	// Paths the anonymous access is restricted to, exact or as a regex prefixed with `~`.
+	// Empty if the access is not restricted to specific paths.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// Conditions of the anonymous access, including the ones of the whole AuthConfig, as `selector operator value`.
+	// Empty if all the requests are let through.
+	// +optional
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// ClientCertificateIdentity is an mTLS identity source of the AuthConfig
+type ClientCertificateIdentity struct {
+	// Name of the identity source.
+	Name string `json:"name"`
+
+	// Attributes of the client certificate extracted into the identity object.
+	// +optional
+	Attributes []ClientCertificateAttribute `json:"attributes,omitempty"`
+}
+
+// EvaluatorCache is the effective caching of the results of an evaluator of the AuthConfig
+type EvaluatorCache struct {
+	// Evaluator is the type and the name of the evaluator, e.g. metadata/user-info.
+	Evaluator string `json:"evaluator"`
+
+	// Key is the static key or the selector of the key in the authorization JSON.
+	Key string `json:"key"`
+
+	// TTL is the duration, in seconds, of the cached results.
+	TTL int `json:"ttl"`
+}
+
+// AuthorinoReference identifies an Authorino instance
+type AuthorinoReference struct {
+	// Name of the Authorino instance.
+	Name string `json:"name"`
+
+	// Namespace of the Authorino instance.
+	Namespace string `json:"namespace"`
 }
 
 func (s *AuthPolicyStatus) Equals(other *AuthPolicyStatus, logger logr.Logger) bool {
@@ -89,6 +849,100 @@ func (s *AuthPolicyStatus) Equals(other *AuthPolicyStatus, logger logr.Logger) b
 		return false
 	}
 
+	if !reflect.DeepEqual(s.ExcludedHTTPRoutes, other.ExcludedHTTPRoutes) {
+		diff := cmp.Diff(s.ExcludedHTTPRoutes, other.ExcludedHTTPRoutes)
+		logger.V(1).Info("ExcludedHTTPRoutes not equal", "difference", diff)
+		return false
+	}
+
+	currentCounts := []int{s.NumAuthRules, s.NumAuthentication, s.NumAuthorization, s.NumResponse}
+	otherCounts := []int{other.NumAuthRules, other.NumAuthentication, other.NumAuthorization, other.NumResponse}
+	if !reflect.DeepEqual(currentCounts, otherCounts) {
+		diff := cmp.Diff(currentCounts, otherCounts)
+		logger.V(1).Info("Compiled counts not equal", "difference", diff)
+		return false
+	}
+
+	currentCodes := []int{s.UnauthenticatedResponseCode, s.UnauthorizedResponseCode}
+	otherCodes := []int{other.UnauthenticatedResponseCode, other.UnauthorizedResponseCode}
+	if !reflect.DeepEqual(currentCodes, otherCodes) {
+		diff := cmp.Diff(currentCodes, otherCodes)
+		logger.V(1).Info("Denied response codes not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.InjectedHeaders, other.InjectedHeaders) {
+		diff := cmp.Diff(s.InjectedHeaders, other.InjectedHeaders)
+		logger.V(1).Info("InjectedHeaders not equal", "difference", diff)
+		return false
+	}
+
+	if s.FailureMode != other.FailureMode {
+		diff := cmp.Diff(s.FailureMode, other.FailureMode)
+		logger.V(1).Info("FailureMode not equal", "difference", diff)
+		return false
+	}
+
+	if s.ExtAuthzTimeout != other.ExtAuthzTimeout {
+		diff := cmp.Diff(s.ExtAuthzTimeout, other.ExtAuthzTimeout)
+		logger.V(1).Info("ExtAuthzTimeout not equal", "difference", diff)
+		return false
+	}
+
+	if s.OutboundTimeout != other.OutboundTimeout {
+		diff := cmp.Diff(s.OutboundTimeout, other.OutboundTimeout)
+		logger.V(1).Info("OutboundTimeout not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.AnonymousAccess, other.AnonymousAccess) {
+		diff := cmp.Diff(s.AnonymousAccess, other.AnonymousAccess)
+		logger.V(1).Info("AnonymousAccess not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.Hierarchy, other.Hierarchy) {
+		diff := cmp.Diff(s.Hierarchy, other.Hierarchy)
+		logger.V(1).Info("Hierarchy not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.IdentityOrder, other.IdentityOrder) {
+		diff := cmp.Diff(s.IdentityOrder, other.IdentityOrder)
+		logger.V(1).Info("IdentityOrder not equal", "difference", diff)
+		return false
+	}
+
+	if s.MissingIdentity != other.MissingIdentity {
+		diff := cmp.Diff(s.MissingIdentity, other.MissingIdentity)
+		logger.V(1).Info("MissingIdentity not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.Listeners, other.Listeners) {
+		diff := cmp.Diff(s.Listeners, other.Listeners)
+		logger.V(1).Info("Listeners not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.ClientCertificate, other.ClientCertificate) {
+		diff := cmp.Diff(s.ClientCertificate, other.ClientCertificate)
+		logger.V(1).Info("ClientCertificate not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.Cache, other.Cache) {
+		diff := cmp.Diff(s.Cache, other.Cache)
+		logger.V(1).Info("Cache not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(s.Authorino, other.Authorino) {
+		diff := cmp.Diff(s.Authorino, other.Authorino)
+		logger.V(1).Info("Authorino not equal", "difference", diff)
+		return false
+	}
+
 	return true
 }
 
@@ -116,24 +970,315 @@ func init() {
 }
 
 func (ap *AuthPolicy) Validate() error {
-	if ap.Spec.TargetRef.Group != ("gateway.networking.k8s.io") {
-		return fmt.Errorf("invalid targetRef.Group %s. The only supported group is gateway.networking.k8s.io", ap.Spec.TargetRef.Group)
+	if err := common.ValidateTargetRef(ap.Spec.TargetRef); err != nil {
+		return err
+	}
+
+	// cross-namespace references to gateways require a ReferenceGrant, checked by the controller
+	if ap.IsCrossNamespaceTargetRef() && !common.IsTargetRefGateway(ap.Spec.TargetRef) {
+		return fmt.Errorf("invalid targetRef.Namespace %s. Only references to Gateways can cross namespaces", *ap.Spec.TargetRef.Namespace)
+	}
+
+	if ap.Spec.Exclusions != nil && !common.IsTargetRefGateway(ap.Spec.TargetRef) {
+		return fmt.Errorf("invalid exclusions. Exclusions are only supported by policies targeting a Gateway")
+	}
+
+	if denyWith := ap.Spec.AuthScheme.DenyWith; denyWith != nil {
+		if err := validateDenyWithSpec("unauthenticated", denyWith.Unauthenticated); err != nil {
+			return err
+		}
+		if err := validateDenyWithSpec("unauthorized", denyWith.Unauthorized); err != nil {
+			return err
+		}
+	}
+
+	if err := validateResponseHeaders(ap.Spec.AuthScheme.Response); err != nil {
+		return err
+	}
+
+	if err := ap.Spec.Cache.Validate(); err != nil {
+		return err
+	}
+
+	if err := ap.Spec.Listeners.Validate(); err != nil {
+		return err
+	}
+
+	providers := make(map[string]struct{}, len(ap.Spec.IdentityProviders))
+	for _, ref := range ap.Spec.IdentityProviders {
+		if _, ok := providers[ref.Name]; ok {
+			return fmt.Errorf("invalid identity providers. Provider %s referenced more than once", ref.Name)
+		}
+		providers[ref.Name] = struct{}{}
+	}
+
+	// the named patterns and the identity sources of a template and of the identity providers are resolved by the
+	// controller
+	if ap.Spec.TemplateRef == nil && len(ap.Spec.IdentityProviders) == 0 {
+		if err := validateAnonymousIdentities(ap.Spec.AuthScheme); err != nil {
+			return err
+		}
+		if err := ap.Spec.AuthScheme.ValidateIdentityOrder(); err != nil {
+			return err
+		}
+		if err := ap.Spec.AuthScheme.ValidateIdentityReferences(); err != nil {
+			return err
+		}
+		if err := ap.Spec.AuthScheme.ValidateClientCertificate(); err != nil {
+			return err
+		}
+		if err := ap.Spec.AuthScheme.ValidateMissingIdentity(ap.GetMissingIdentity()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateAnonymousIdentities rejects the anonymous identities whose conditions refer to undefined named patterns,
+// which Authorino cannot enforce
+func validateAnonymousIdentities(scheme AuthSchemeSpec) error {
+	for _, identity := range scheme.Identity {
+		if identity == nil || identity.Anonymous == nil {
+			continue
+		}
+		for _, condition := range identity.Conditions {
+			if name := condition.JSONPatternName; name != "" {
+				if _, ok := scheme.Patterns[name]; !ok {
+					return fmt.Errorf("invalid authScheme.identity %s. Unknown patternRef %s", identity.Name, name)
+				}
+			}
+		}
 	}
+	return nil
+}
+
+// headerNameRegexp matches the valid HTTP header field names, i.e. the tokens of RFC 7230
+var headerNameRegexp = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
 
-	switch kind := ap.Spec.TargetRef.Kind; kind {
-	case
-		"HTTPRoute",
-		"Gateway":
+// validateResponseHeaders rejects the response configs injecting headers with invalid or duplicate names
+func validateResponseHeaders(responses []*authorinov1beta1.Response) error {
+	headers := make(map[string]string, len(responses))
+	for _, response := range responses {
+		header, ok := ResponseHeaderName(response)
+		if !ok {
+			continue
+		}
+		if !headerNameRegexp.MatchString(header) {
+			return fmt.Errorf("invalid authScheme.response %s. Invalid header name %q", response.Name, header)
+		}
+		key := strings.ToLower(header)
+		if other, ok := headers[key]; ok {
+			return fmt.Errorf("invalid authScheme.response %s. Header %s already injected by response %s", response.Name, header, other)
+		}
+		headers[key] = response.Name
+	}
+	return nil
+}
+
+// ResponseHeaderName returns the name of the header a response config injects in the requests to the upstream,
+// or false if the response is wrapped as Envoy dynamic metadata
+func ResponseHeaderName(response *authorinov1beta1.Response) (string, bool) {
+	if response == nil || (response.Wrapper != "" && response.Wrapper != "httpHeader") {
+		return "", false
+	}
+	if response.WrapperKey != "" {
+		return response.WrapperKey, true
+	}
+	return response.Name, true
+}
+
+// requestPathSelectors are the selectors of the authorization JSON resolving to the path of the request
+var requestPathSelectors = map[string]struct{}{
+	"context.request.http.path": {},
+	"request.path":              {},
+	"request.url_path":          {},
+}
+
+// catchAllPathProbes are paths a regex matching all the paths matches
+var catchAllPathProbes = []string{"/", "/admin", "/api/v1/users", "/.well-known/openid-configuration"}
+
+// AnonymousAccessOf returns the anonymous identities of an AuthConfig, with the named patterns of their conditions resolved
+func AnonymousAccessOf(spec authorinov1beta1.AuthConfigSpec) []AnonymousAccess {
+	var accesses []AnonymousAccess
+	for _, identity := range spec.Identity {
+		if identity == nil || identity.Anonymous == nil {
+			continue
+		}
+
+		access := AnonymousAccess{Name: identity.Name}
+		for _, expression := range resolveJSONPatterns(spec.Patterns, append(append([]authorinov1beta1.JSONPattern{}, spec.Conditions...), identity.Conditions...)) {
+			access.Conditions = append(access.Conditions, fmt.Sprintf("%s %s %s", expression.Selector, expression.Operator, expression.Value))
+			if _, ok := requestPathSelectors[expression.Selector]; !ok {
+				continue
+			}
+			switch expression.Operator {
+			case "eq":
+				access.Paths = append(access.Paths, expression.Value)
+			case "matches":
+				if !isCatchAllPathRegex(expression.Value) {
+					access.Paths = append(access.Paths, "~"+expression.Value)
+				}
+			}
+		}
+		accesses = append(accesses, access)
+	}
+	return accesses
+}
+
+// ClientCertificateIdentitiesOf returns the mTLS identity sources of an AuthConfig, with the attributes of the client
+// certificate extracted into their identity objects
+func ClientCertificateIdentitiesOf(spec authorinov1beta1.AuthConfigSpec) []ClientCertificateIdentity {
+	var identities []ClientCertificateIdentity
+	for _, identity := range spec.Identity {
+		if identity == nil || identity.MTLS == nil {
+			continue
+		}
+		mtls := ClientCertificateIdentity{Name: identity.Name}
+		for _, attribute := range []ClientCertificateAttribute{"cn", "san", "fingerprint"} {
+			for _, property := range identity.ExtendedProperties {
+				if property.Name == string(attribute) && property.ValueFrom.AuthJSON == clientCertificateSelectors[attribute] {
+					mtls.Attributes = append(mtls.Attributes, attribute)
+				}
+			}
+		}
+		identities = append(identities, mtls)
+	}
+	return identities
+}
+
+// authorinoDefaultCacheTTL is the TTL, in seconds, of the cached results of the evaluators omitting it
+const authorinoDefaultCacheTTL = 60
+
+// EvaluatorCachesOf returns the evaluators of an AuthConfig whose results are cached, with the effective key and TTL
+func EvaluatorCachesOf(spec authorinov1beta1.AuthConfigSpec) []EvaluatorCache {
+	var caches []EvaluatorCache
+	add := func(evaluatorType AuthCacheEvaluator, name string, cache *authorinov1beta1.EvaluatorCaching) {
+		if cache == nil {
+			return
+		}
+		key := cache.Key.Value
+		if key == "" {
+			key = cache.Key.ValueFrom.AuthJSON
+		}
+		ttl := cache.TTL
+		if ttl == 0 {
+			ttl = authorinoDefaultCacheTTL
+		}
+		caches = append(caches, EvaluatorCache{Evaluator: fmt.Sprintf("%s/%s", evaluatorType, name), Key: key, TTL: ttl})
+	}
+
+	for _, identity := range spec.Identity {
+		add("identity", identity.Name, identity.Cache)
+	}
+	for _, metadata := range spec.Metadata {
+		add("metadata", metadata.Name, metadata.Cache)
+	}
+	for _, authorization := range spec.Authorization {
+		add("authorization", authorization.Name, authorization.Cache)
+	}
+	for _, response := range spec.Response {
+		add("response", response.Name, response.Cache)
+	}
+	return caches
+}
+
+// OverlyBroad returns the reason the anonymous access is not restricted to specific paths, or empty if restricted
+func (a AnonymousAccess) OverlyBroad() string {
+	switch {
+	case len(a.Paths) > 0:
+		return ""
+	case len(a.Conditions) == 0:
+		return fmt.Sprintf("identity %s allows all the requests anonymously", a.Name)
 	default:
-		return fmt.Errorf("invalid targetRef.Kind %s. The only supported kinds are HTTPRoute and Gateway", kind)
+		return fmt.Sprintf("identity %s allows the requests of all the paths anonymously (when %s)", a.Name, strings.Join(a.Conditions, " and "))
+	}
+}
+
+// resolveJSONPatterns returns the expressions of a list of conditions, the named patterns replaced by their expressions
+func resolveJSONPatterns(patterns map[string]authorinov1beta1.JSONPatternExpressions, conditions []authorinov1beta1.JSONPattern) []authorinov1beta1.JSONPatternExpression {
+	var expressions []authorinov1beta1.JSONPatternExpression
+	for _, condition := range conditions {
+		if name := condition.JSONPatternName; name != "" {
+			expressions = append(expressions, patterns[name]...)
+			continue
+		}
+		expressions = append(expressions, condition.JSONPatternExpression)
 	}
+	return expressions
+}
+
+func isCatchAllPathRegex(value string) bool {
+	regex, err := regexp.Compile(value)
+	if err != nil {
+		return false
+	}
+	for _, path := range catchAllPathProbes {
+		if !regex.MatchString(path) {
+			return false
+		}
+	}
+	return true
+}
 
-	if ap.Spec.TargetRef.Namespace != nil && string(*ap.Spec.TargetRef.Namespace) != ap.Namespace {
-		return fmt.Errorf("invalid targetRef.Namespace %s. Currently only supporting references to the same namespace", *ap.Spec.TargetRef.Namespace)
+// validateDenyWithSpec rejects the custom denial responses that cannot be served as configured
+func validateDenyWithSpec(name string, spec *authorinov1beta1.DenyWithSpec) error {
+	if spec == nil {
+		return nil
 	}
+
+	headers := make(map[string]struct{}, len(spec.Headers))
+	for _, header := range spec.Headers {
+		if header.Name == "" {
+			return fmt.Errorf("invalid authScheme.denyWith.%s. Header names must not be empty", name)
+		}
+		key := strings.ToLower(header.Name)
+		if _, ok := headers[key]; ok {
+			return fmt.Errorf("invalid authScheme.denyWith.%s. Duplicate header %s", name, header.Name)
+		}
+		headers[key] = struct{}{}
+	}
+
+	if spec.Code == http.StatusNotModified && (spec.Body != nil || spec.Message != nil) {
+		return fmt.Errorf("invalid authScheme.denyWith.%s. Responses with status code %d cannot carry a message or a body", name, spec.Code)
+	}
+
+	if _, ok := headers["location"]; !ok && isRedirect(spec.Code) {
+		return fmt.Errorf("invalid authScheme.denyWith.%s. Redirects with status code %d require a Location header", name, spec.Code)
+	}
+
 	return nil
 }
 
+func isRedirect(code authorinov1beta1.DenyWith_Code) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// IsCrossNamespaceTargetRef tells whether the policy targets an object in another namespace
+func (ap *AuthPolicy) IsCrossNamespaceTargetRef() bool {
+	return ap.Spec.TargetRef.Namespace != nil && string(*ap.Spec.TargetRef.Namespace) != ap.Namespace
+}
+
+// GetFailureMode returns the failure mode of the policy, closed unless set
+func (ap *AuthPolicy) GetFailureMode() FailureMode {
+	if ap.Spec.FailureMode == "" {
+		return FailClosed
+	}
+	return ap.Spec.FailureMode
+}
+
+// GetMissingIdentity returns the behaviour of the policy for the requests without a valid identity, deny by default
+func (ap *AuthPolicy) GetMissingIdentity() MissingIdentityMode {
+	if ap.Spec.MissingIdentity == "" {
+		return MissingIdentityDeny
+	}
+	return ap.Spec.MissingIdentity
+}
+
 func (ap *AuthPolicy) GetTargetRef() gatewayapiv1alpha2.PolicyTargetReference {
 	return ap.Spec.TargetRef
 }
@@ -149,3 +1294,19 @@ func (ap *AuthPolicy) GetRulesHostnames() (ruleHosts []string) {
 	}
 	return
 }
+
+// ExcludedHTTPRouteKeys returns the keys of the HTTPRoutes exempt from the policy
+func (ap *AuthPolicy) ExcludedHTTPRouteKeys() []client.ObjectKey {
+	if ap.Spec.Exclusions == nil {
+		return nil
+	}
+	keys := make([]client.ObjectKey, 0, len(ap.Spec.Exclusions.HTTPRoutes))
+	for _, ref := range ap.Spec.Exclusions.HTTPRoutes {
+		namespace := ap.Namespace
+		if ref.Namespace != nil {
+			namespace = *ref.Namespace
+		}
+		keys = append(keys, client.ObjectKey{Name: ref.Name, Namespace: namespace})
+	}
+	return keys
+}
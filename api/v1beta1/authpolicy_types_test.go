@@ -0,0 +1,544 @@
+//go:build unit
+
+package v1beta1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testBuildBasicAuthPolicy(denyWith *authorinov1beta1.DenyWith) *AuthPolicy {
+	return &AuthPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "toystore", Namespace: "testNS"},
+		Spec: AuthPolicySpec{
+			TargetRef: gatewayapiv1alpha2.PolicyTargetReference{
+				Group: "gateway.networking.k8s.io",
+				Kind:  "HTTPRoute",
+				Name:  "toystore",
+			},
+			AuthScheme: AuthSchemeSpec{DenyWith: denyWith},
+		},
+	}
+}
+
+func TestAuthPolicyValidateDenyWith(t *testing.T) {
+	testCases := []struct {
+		name        string
+		denyWith    *authorinov1beta1.DenyWith
+		expectedErr string
+	}{
+		{
+			name: "default responses",
+		},
+		{
+			name: "custom codes and headers",
+			denyWith: &authorinov1beta1.DenyWith{
+				Unauthenticated: &authorinov1beta1.DenyWithSpec{Code: 302, Headers: []authorinov1beta1.JsonProperty{{Name: "Location"}}},
+				Unauthorized:    &authorinov1beta1.DenyWithSpec{Code: 404, Body: &authorinov1beta1.StaticOrDynamicValue{Value: "not found"}},
+			},
+		},
+		{
+			name: "body with a 304",
+			denyWith: &authorinov1beta1.DenyWith{
+				Unauthorized: &authorinov1beta1.DenyWithSpec{Code: 304, Body: &authorinov1beta1.StaticOrDynamicValue{Value: "cached"}},
+			},
+			expectedErr: "denyWith.unauthorized. Responses with status code 304 cannot carry a message or a body",
+		},
+		{
+			name: "redirect without location",
+			denyWith: &authorinov1beta1.DenyWith{
+				Unauthenticated: &authorinov1beta1.DenyWithSpec{Code: 307},
+			},
+			expectedErr: "denyWith.unauthenticated. Redirects with status code 307 require a Location header",
+		},
+		{
+			name: "duplicate headers",
+			denyWith: &authorinov1beta1.DenyWith{
+				Unauthenticated: &authorinov1beta1.DenyWithSpec{Headers: []authorinov1beta1.JsonProperty{{Name: "X-Reason"}, {Name: "x-reason"}}},
+			},
+			expectedErr: "denyWith.unauthenticated. Duplicate header x-reason",
+		},
+		{
+			name: "empty header name",
+			denyWith: &authorinov1beta1.DenyWith{
+				Unauthorized: &authorinov1beta1.DenyWithSpec{Headers: []authorinov1beta1.JsonProperty{{}}},
+			},
+			expectedErr: "denyWith.unauthorized. Header names must not be empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			err := testBuildBasicAuthPolicy(tc.denyWith).Validate()
+			if tc.expectedErr == "" {
+				if err != nil {
+					subT.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				subT.Fatalf(`ap.Validate() returned error "%v", wanted "%s"`, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAuthPolicyValidateResponseHeaders(t *testing.T) {
+	testCases := []struct {
+		name        string
+		response    []*authorinov1beta1.Response
+		expectedErr string
+	}{
+		{
+			name: "headers named after the configs or the wrapper keys",
+			response: []*authorinov1beta1.Response{
+				{Name: "x-user"},
+				{Name: "user-id", WrapperKey: "X-User-ID"},
+				{Name: "x-user", Wrapper: "envoyDynamicMetadata"},
+			},
+		},
+		{
+			name:        "invalid header name",
+			response:    []*authorinov1beta1.Response{{Name: "user", WrapperKey: "X User"}},
+			expectedErr: `authScheme.response user. Invalid header name "X User"`,
+		},
+		{
+			name: "duplicate header",
+			response: []*authorinov1beta1.Response{
+				{Name: "x-user"},
+				{Name: "user", WrapperKey: "X-User"},
+			},
+			expectedErr: "authScheme.response user. Header X-User already injected by response x-user",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			ap := testBuildBasicAuthPolicy(nil)
+			ap.Spec.AuthScheme.Response = tc.response
+			err := ap.Validate()
+			if tc.expectedErr == "" {
+				if err != nil {
+					subT.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				subT.Fatalf(`ap.Validate() returned error "%v", wanted "%s"`, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAuthSchemeSecretRefs(t *testing.T) {
+	authScheme := &AuthSchemeSpec{
+		Identity: []*authorinov1beta1.Identity{
+			{Name: "introspection", OAuth2: &authorinov1beta1.Identity_OAuth2Config{Credentials: &corev1.LocalObjectReference{Name: "oauth2-credentials"}}},
+			{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}},
+		},
+		Metadata: []*authorinov1beta1.Metadata{
+			{Name: "http", GenericHTTP: &authorinov1beta1.Metadata_GenericHTTP{
+				SharedSecret: &authorinov1beta1.SecretKeyReference{Name: "shared-secret", Key: "secret"},
+				OAuth2:       &authorinov1beta1.OAuth2ClientAuthentication{ClientSecret: authorinov1beta1.SecretKeyReference{Name: "oauth2-credentials", Key: "client-secret"}},
+			}},
+		},
+		Authorization: []*authorinov1beta1.Authorization{
+			{Name: "authzed", Authzed: &authorinov1beta1.Authorization_Authzed{SharedSecret: &authorinov1beta1.SecretKeyReference{Name: "authzed-token", Key: "token"}}},
+		},
+		Response: []*authorinov1beta1.Response{
+			{Name: "wristband", Wristband: &authorinov1beta1.Response_Wristband{SigningKeyRefs: []*authorinov1beta1.SigningKeyRef{{Name: "signing-key"}}}},
+		},
+	}
+
+	expected := []string{"authzed-token", "oauth2-credentials", "shared-secret", "signing-key"}
+	if refs := authScheme.SecretRefs(); !reflect.DeepEqual(refs, expected) {
+		t.Errorf("expected %v, got %v", expected, refs)
+	}
+
+	if refs := (&AuthSchemeSpec{}).SecretRefs(); len(refs) != 0 {
+		t.Errorf("expected no secret refs, got %v", refs)
+	}
+}
+
+func TestAuthPolicyValidateAnonymousIdentities(t *testing.T) {
+	ap := testBuildBasicAuthPolicy(nil)
+	ap.Spec.AuthScheme.Patterns = map[string]authorinov1beta1.JSONPatternExpressions{
+		"health": {{Selector: "context.request.http.path", Operator: "eq", Value: "/health"}},
+	}
+	ap.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{
+		{
+			Name:       "public",
+			Anonymous:  &authorinov1beta1.Identity_Anonymous{},
+			Conditions: []authorinov1beta1.JSONPattern{{JSONPatternRef: authorinov1beta1.JSONPatternRef{JSONPatternName: "health"}}},
+		},
+	}
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	ap.Spec.AuthScheme.Identity[0].Conditions[0].JSONPatternName = "metrics"
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "authScheme.identity public. Unknown patternRef metrics") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted unknown patternRef`, err)
+	}
+}
+
+func TestAnonymousAccessOf(t *testing.T) {
+	pathCondition := func(operator, value string) authorinov1beta1.JSONPattern {
+		return authorinov1beta1.JSONPattern{JSONPatternExpression: authorinov1beta1.JSONPatternExpression{
+			Selector: "context.request.http.path", Operator: authorinov1beta1.JSONPatternOperator(operator), Value: value,
+		}}
+	}
+	spec := authorinov1beta1.AuthConfigSpec{
+		Patterns: map[string]authorinov1beta1.JSONPatternExpressions{
+			"docs": {{Selector: "context.request.http.path", Operator: "matches", Value: "^/docs/.*"}},
+		},
+		Identity: []*authorinov1beta1.Identity{
+			{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}},
+			{Name: "health", Anonymous: &authorinov1beta1.Identity_Anonymous{}, Conditions: []authorinov1beta1.JSONPattern{pathCondition("eq", "/health")}},
+			{Name: "docs", Anonymous: &authorinov1beta1.Identity_Anonymous{}, Conditions: []authorinov1beta1.JSONPattern{{JSONPatternRef: authorinov1beta1.JSONPatternRef{JSONPatternName: "docs"}}}},
+			{Name: "catch-all", Anonymous: &authorinov1beta1.Identity_Anonymous{}, Conditions: []authorinov1beta1.JSONPattern{pathCondition("matches", ".*")}},
+			{Name: "all", Anonymous: &authorinov1beta1.Identity_Anonymous{}},
+		},
+	}
+
+	accesses := AnonymousAccessOf(spec)
+	expected := []AnonymousAccess{
+		{Name: "health", Paths: []string{"/health"}, Conditions: []string{"context.request.http.path eq /health"}},
+		{Name: "docs", Paths: []string{"~^/docs/.*"}, Conditions: []string{"context.request.http.path matches ^/docs/.*"}},
+		{Name: "catch-all", Conditions: []string{"context.request.http.path matches .*"}},
+		{Name: "all"},
+	}
+	if !reflect.DeepEqual(accesses, expected) {
+		t.Fatalf("expected %v, got %v", expected, accesses)
+	}
+
+	broad := make([]string, 0)
+	for _, access := range accesses {
+		if reason := access.OverlyBroad(); reason != "" {
+			broad = append(broad, access.Name)
+		}
+	}
+	if !reflect.DeepEqual(broad, []string{"catch-all", "all"}) {
+		t.Errorf("expected the catch-all and all identities overly broad, got %v", broad)
+	}
+}
+
+func TestAuthPolicyValidateIdentityOrder(t *testing.T) {
+	ap := testBuildBasicAuthPolicy(nil)
+	ap.Spec.AuthScheme.IdentityOrder = []string{"jwt"}
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "At least one identity source is required") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted missing identity sources`, err)
+	}
+
+	ap.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{{Name: "api-key"}, {Name: "jwt"}}
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	ap.Spec.AuthScheme.IdentityOrder = []string{"jwt", "mtls"}
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "Unknown identity source mtls") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted unknown identity source`, err)
+	}
+
+	ap.Spec.AuthScheme.IdentityOrder = []string{"jwt", "jwt"}
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "Identity source jwt listed more than once") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted repeated identity source`, err)
+	}
+}
+
+func TestAuthPolicyValidateIdentityReferences(t *testing.T) {
+	ap := testBuildBasicAuthPolicy(nil)
+	ap.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{
+		{Name: "keycloak", Oidc: &authorinov1beta1.Identity_OidcConfig{Endpoint: "http://keycloak/realms/kuadrant"}},
+		{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}},
+	}
+	ap.Spec.AuthScheme.Metadata = []*authorinov1beta1.Metadata{
+		{Name: "user-info", UserInfo: &authorinov1beta1.Metadata_UserInfo{IdentitySource: "keycloak"}},
+	}
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	ap.Spec.AuthScheme.Metadata[0].UserInfo.IdentitySource = "keycloack"
+	err := ap.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid authScheme.metadata user-info. Unknown identity source keycloack") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted unknown identity source`, err)
+	}
+	if !IsIdentityReferenceError(err) {
+		t.Errorf("expected an identity reference error, got %T", err)
+	}
+
+	ap.Spec.AuthScheme.Metadata[0].UserInfo.IdentitySource = "api-key"
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "Identity source api-key is not an OIDC identity source") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted non-OIDC identity source`, err)
+	}
+
+	// the identity sources of a template are only known once resolved
+	ap.Spec.TemplateRef = &corev1.LocalObjectReference{Name: "oidc"}
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	// as are the ones of the identity providers
+	ap.Spec.TemplateRef = nil
+	ap.Spec.AuthScheme.Metadata[0].UserInfo.IdentitySource = "okta"
+	ap.Spec.IdentityProviders = []corev1.LocalObjectReference{{Name: "okta"}}
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	ap.Spec.IdentityProviders = append(ap.Spec.IdentityProviders, corev1.LocalObjectReference{Name: "okta"})
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "Provider okta referenced more than once") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted provider referenced more than once`, err)
+	}
+}
+
+func TestAuthSchemeOrderedIdentity(t *testing.T) {
+	scheme := AuthSchemeSpec{
+		Identity: []*authorinov1beta1.Identity{
+			{Name: "anonymous", Priority: 5},
+			{Name: "api-key"},
+			{Name: "mtls"},
+			{Name: "jwt"},
+		},
+		IdentityOrder: []string{"mtls", "jwt"},
+	}
+
+	ordered := scheme.OrderedIdentity()
+	names := make([]string, 0, len(ordered))
+	priorities := make([]int, 0, len(ordered))
+	for _, identity := range ordered {
+		names = append(names, identity.Name)
+		priorities = append(priorities, identity.Priority)
+	}
+	if expected := []string{"mtls", "jwt", "anonymous", "api-key"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected identities %v, got %v", expected, names)
+	}
+	if expected := []int{0, 1, 2, 2}; !reflect.DeepEqual(priorities, expected) {
+		t.Errorf("expected priorities %v, got %v", expected, priorities)
+	}
+	if scheme.Identity[0].Priority != 5 {
+		t.Errorf("expected the identity sources of the spec untouched, got priority %d", scheme.Identity[0].Priority)
+	}
+
+	if order := IdentityOrderOf(authorinov1beta1.AuthConfigSpec{Identity: ordered}); !reflect.DeepEqual(order, names) {
+		t.Errorf("expected identity order %v, got %v", names, order)
+	}
+}
+
+func TestAuthPolicyValidateCache(t *testing.T) {
+	testCases := []struct {
+		key     string
+		wantErr string
+	}{
+		{key: "context.request.http.headers.x-api-key"},
+		{key: "{auth.identity.sub}-{context.request.http.path}"},
+		{key: `context.request.http.path.@extract:{"sep":"/","pos":1}`},
+		{key: `{context.request.http.path.@extract:{"sep":"/","pos":1}}`},
+		{key: " auth.identity.sub", wantErr: "must not start or end with whitespaces"},
+		{key: "{auth.identity.sub", wantErr: "Unbalanced braces of the template"},
+		{key: "auth.identity.sub}-{context.request.http.path}", wantErr: "Unbalanced braces of the template"},
+		{key: "{}-{auth.identity.sub}", wantErr: "Empty placeholder of the template"},
+		{key: "request.http.path", wantErr: "Selector request.http.path must start with context or auth"},
+		{key: "{auth.identity.sub}-{identity.sub}", wantErr: "Selector identity.sub must start with context or auth"},
+	}
+
+	for _, tc := range testCases {
+		ap := testBuildBasicAuthPolicy(nil)
+		ap.Spec.Cache = &AuthCacheSpec{Key: tc.key}
+		err := ap.Validate()
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf(`key %q: ap.Validate() returned error "%v", wanted nil`, tc.key, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf(`key %q: ap.Validate() returned error "%v", wanted "%s"`, tc.key, err, tc.wantErr)
+		}
+	}
+}
+
+func TestAuthCacheSpecApply(t *testing.T) {
+	ttl := 300
+	cache := &AuthCacheSpec{Key: "auth.identity.sub", TTL: &ttl, Evaluators: []AuthCacheEvaluator{"metadata", "authorization"}}
+	spec := authorinov1beta1.AuthConfigSpec{
+		Identity: []*authorinov1beta1.Identity{{Name: "api-key"}},
+		Metadata: []*authorinov1beta1.Metadata{
+			{Name: "user-info"},
+			{Name: "geo", Cache: &authorinov1beta1.EvaluatorCaching{Key: authorinov1beta1.StaticOrDynamicValue{Value: "static"}}},
+		},
+		Authorization: []*authorinov1beta1.Authorization{{Name: "opa"}},
+	}
+
+	cached := cache.Apply(spec)
+	if spec.Metadata[0].Cache != nil {
+		t.Fatal("cache.Apply() modified the spec")
+	}
+
+	got := EvaluatorCachesOf(cached)
+	want := []EvaluatorCache{
+		{Evaluator: "metadata/user-info", Key: "auth.identity.sub", TTL: 300},
+		{Evaluator: "metadata/geo", Key: "static", TTL: 60},
+		{Evaluator: "authorization/opa", Key: "auth.identity.sub", TTL: 300},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("EvaluatorCachesOf() = %v, wanted %v", got, want)
+	}
+
+	var none *AuthCacheSpec
+	if got := EvaluatorCachesOf(none.Apply(spec)); len(got) != 1 {
+		t.Fatalf("EvaluatorCachesOf() = %v, wanted only the cache of metadata/geo", got)
+	}
+}
+
+func TestAuthPolicyValidateClientCertificate(t *testing.T) {
+	ap := testBuildBasicAuthPolicy(nil)
+	ap.Spec.AuthScheme.ClientCertificate = &ClientCertificateSpec{Attributes: []ClientCertificateAttribute{"cn", "san"}}
+	ap.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}}}
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "At least one mTLS identity source is required") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted missing mTLS identity source`, err)
+	}
+
+	ap.Spec.AuthScheme.Identity = append(ap.Spec.AuthScheme.Identity, &authorinov1beta1.Identity{Name: "mtls", MTLS: &authorinov1beta1.Identity_MTLS{}})
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	ap.Spec.AuthScheme.ClientCertificate.Attributes = []ClientCertificateAttribute{"cn", "cn"}
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "Attribute cn listed more than once") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted repeated attribute`, err)
+	}
+}
+
+func TestClientCertificateSpecApply(t *testing.T) {
+	identities := []*authorinov1beta1.Identity{
+		{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}},
+		{
+			Name: "mtls",
+			MTLS: &authorinov1beta1.Identity_MTLS{},
+			ExtendedProperties: []authorinov1beta1.ExtendedProperty{
+				{JsonProperty: authorinov1beta1.JsonProperty{Name: "san", ValueFrom: authorinov1beta1.ValueFrom{AuthJSON: "context.request.http.headers.x-san"}}},
+			},
+		},
+	}
+	clientCertificate := &ClientCertificateSpec{Attributes: []ClientCertificateAttribute{"cn", "san", "fingerprint"}}
+
+	applied := clientCertificate.Apply(identities)
+	if len(identities[1].ExtendedProperties) != 1 {
+		t.Fatal("Apply() modified the identity sources")
+	}
+	if len(applied[0].ExtendedProperties) != 0 {
+		t.Fatalf("Apply() extended the identity source api-key: %v", applied[0].ExtendedProperties)
+	}
+
+	// the property set by the identity source prevails
+	got := ClientCertificateIdentitiesOf(authorinov1beta1.AuthConfigSpec{Identity: applied})
+	want := []ClientCertificateIdentity{{Name: "mtls", Attributes: []ClientCertificateAttribute{"cn", "fingerprint"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ClientCertificateIdentitiesOf() = %v, wanted %v", got, want)
+	}
+
+	var none *ClientCertificateSpec
+	if got := none.Apply(identities); !reflect.DeepEqual(got, identities) {
+		t.Fatalf("Apply() = %v, wanted the identity sources unchanged", got)
+	}
+}
+
+func TestAuthPolicyValidateListeners(t *testing.T) {
+	policy := testBuildBasicAuthPolicy(nil)
+	policy.Spec.Listeners = &AuthListenersSpec{}
+	if err := policy.Validate(); err == nil || !strings.Contains(err.Error(), "invalid listeners") {
+		t.Fatalf("Expected the empty listeners to be rejected, got %v", err)
+	}
+
+	policy.Spec.Listeners.Protocols = []gatewayapiv1beta1.ProtocolType{gatewayapiv1beta1.HTTPSProtocolType}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Expected the listeners to be valid, got %v", err)
+	}
+}
+
+func TestAuthListenersSpecSelects(t *testing.T) {
+	https := gatewayapiv1beta1.Listener{Name: "https", Protocol: gatewayapiv1beta1.HTTPSProtocolType, Port: 443}
+	redirect := gatewayapiv1beta1.Listener{Name: "http", Protocol: gatewayapiv1beta1.HTTPProtocolType, Port: 80}
+	internal := gatewayapiv1beta1.Listener{Name: "internal", Protocol: gatewayapiv1beta1.HTTPSProtocolType, Port: 8443}
+
+	testCases := []struct {
+		name     string
+		scope    *AuthListenersSpec
+		expected []bool
+	}{
+		{name: "all the listeners", scope: nil, expected: []bool{true, true, true}},
+		{name: "by protocol", scope: &AuthListenersSpec{Protocols: []gatewayapiv1beta1.ProtocolType{"HTTPS"}}, expected: []bool{true, false, true}},
+		{name: "by name", scope: &AuthListenersSpec{Names: []gatewayapiv1beta1.SectionName{"http"}}, expected: []bool{false, true, false}},
+		{name: "by name and protocol", scope: &AuthListenersSpec{Names: []gatewayapiv1beta1.SectionName{"http", "internal"}, Protocols: []gatewayapiv1beta1.ProtocolType{"HTTPS"}}, expected: []bool{false, false, true}},
+	}
+
+	for _, tc := range testCases {
+		for idx, listener := range []gatewayapiv1beta1.Listener{https, redirect, internal} {
+			if selected := tc.scope.Selects(listener); selected != tc.expected[idx] {
+				t.Errorf("%s: Selects(%s) = %v, want %v", tc.name, listener.Name, selected, tc.expected[idx])
+			}
+		}
+	}
+}
+
+func TestAuthPolicyValidateMissingIdentity(t *testing.T) {
+	ap := testBuildBasicAuthPolicy(nil)
+	ap.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{{Name: "public", Anonymous: &authorinov1beta1.Identity_Anonymous{}}}
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "set missingIdentity to anonymous instead") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted anonymous identity without conditions`, err)
+	}
+
+	ap.Spec.MissingIdentity = MissingIdentityAnonymous
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "At least one identity source other than anonymous is required") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted missing identity source`, err)
+	}
+
+	ap.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}}}
+	if err := ap.Validate(); err != nil {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	ap.Spec.AuthScheme.Identity[0].Name = AnonymousFallbackIdentityName
+	if err := ap.Validate(); err == nil || !strings.Contains(err.Error(), "The name is reserved") {
+		t.Fatalf(`ap.Validate() returned error "%v", wanted reserved name`, err)
+	}
+}
+
+func TestWithMissingIdentity(t *testing.T) {
+	identities := []*authorinov1beta1.Identity{
+		{Name: "jwt", Oidc: &authorinov1beta1.Identity_OidcConfig{}},
+		{Name: "api-key", APIKey: &authorinov1beta1.Identity_APIKey{}, Priority: 1},
+	}
+
+	if got := WithMissingIdentity(MissingIdentityDeny, identities); !reflect.DeepEqual(got, identities) {
+		t.Fatalf("WithMissingIdentity() = %v, wanted the identity sources unchanged", got)
+	}
+	if mode := MissingIdentityOf(authorinov1beta1.AuthConfigSpec{Identity: identities}); mode != MissingIdentityDeny {
+		t.Fatalf("MissingIdentityOf() = %s, wanted deny", mode)
+	}
+
+	applied := WithMissingIdentity(MissingIdentityAnonymous, identities)
+	if len(identities) != 2 {
+		t.Fatal("WithMissingIdentity() modified the identity sources")
+	}
+	if len(applied) != 3 || applied[2].Name != AnonymousFallbackIdentityName || applied[2].Anonymous == nil || applied[2].Priority != 2 {
+		t.Fatalf("WithMissingIdentity() = %v, wanted the anonymous fallback tried last", applied)
+	}
+	if mode := MissingIdentityOf(authorinov1beta1.AuthConfigSpec{Identity: applied}); mode != MissingIdentityAnonymous {
+		t.Fatalf("MissingIdentityOf() = %s, wanted anonymous", mode)
+	}
+
+	// Authorino skips the identity phase of an AuthConfig without identity sources
+	if mode := MissingIdentityOf(authorinov1beta1.AuthConfigSpec{}); mode != MissingIdentityAnonymous {
+		t.Fatalf("MissingIdentityOf() = %s, wanted anonymous", mode)
+	}
+}
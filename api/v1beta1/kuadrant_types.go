@@ -17,9 +17,19 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 )
@@ -29,6 +39,707 @@ import (
 
 // KuadrantSpec defines the desired state of Kuadrant
 type KuadrantSpec struct {
+	// Authorino holds the configuration of the Authorino instance managed by Kuadrant
+	// +optional
+	Authorino *AuthorinoSpec `json:"authorino,omitempty"`
+
+	// Limitador holds the configuration of the Limitador instance managed by Kuadrant
+	// +optional
+	Limitador *LimitadorSpec `json:"limitador,omitempty"`
+
+	// MaintenanceWindow defers the disruptive changes of the managed components, i.e. the ones restarting Authorino
+	// or switching the storage of Limitador, until the window. The other changes are applied immediately.
+	// If omitted, all the changes are applied immediately.
+	// +optional
+	MaintenanceWindow *MaintenanceWindowSpec `json:"maintenanceWindow,omitempty"`
+
+	// DefaultRateLimit is a safety-net rate limit of the requests to the routes of the gateways managed by Kuadrant
+	// that no RateLimitPolicy applies to. It acts as a policy of the gateways without a RateLimitPolicy of their own,
+	// thus any RateLimitPolicy of a gateway or of a route prevails. The counters are isolated per gateway.
+	// +optional
+	DefaultRateLimit *DefaultRateLimitSpec `json:"defaultRateLimit,omitempty"`
+
+	// DefaultAuthPolicy refers to an AuthPolicy in the namespace of the Kuadrant instance enforced as the policy of
+	// the gateways managed by Kuadrant without an AuthPolicy of their own, thus any AuthPolicy of a gateway prevails,
+	// and the AuthPolicies of the routes prevail for the hostnames of the routes.
+	// +optional
+	DefaultAuthPolicy *corev1.LocalObjectReference `json:"defaultAuthPolicy,omitempty"`
+
+	// GrafanaDashboard enables a ConfigMap holding a Grafana dashboard of the metrics of the operator, of Authorino and
+	// of Limitador, with panels for the policies in use, to be loaded by the dashboards sidecar of Grafana.
+	// If omitted, no dashboard is generated.
+	// +optional
+	GrafanaDashboard *GrafanaDashboardSpec `json:"grafanaDashboard,omitempty"`
+
+	// NetworkPolicies enables the NetworkPolicies letting the pods of the gateways managed by Kuadrant reach the
+	// services of Authorino and Limitador, in the clusters denying the traffic between pods by default.
+	// +optional
+	NetworkPolicies *NetworkPoliciesSpec `json:"networkPolicies,omitempty"`
+}
+
+type NetworkPoliciesSpec struct {
+	// Enabled tells whether the NetworkPolicies are created. The NetworkPolicies are deleted when disabled.
+	Enabled bool `json:"enabled"`
+}
+
+type GrafanaDashboardSpec struct {
+	// Labels of the ConfigMap of the dashboard, selected by the dashboards sidecar of Grafana.
+	// If omitted, grafana_dashboard: "1", the default label of the sidecar.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DashboardLabels returns the labels of the ConfigMap of the dashboard selected by the dashboards sidecar of Grafana
+func (s *GrafanaDashboardSpec) DashboardLabels() map[string]string {
+	if s == nil || len(s.Labels) == 0 {
+		return map[string]string{"grafana_dashboard": "1"}
+	}
+	return s.Labels
+}
+
+// DefaultRateLimitSpec is a limit of the requests, e.g. 1000 requests per 1 minute per client
+type DefaultRateLimitSpec struct {
+	// Rates of the limit
+	// +kubebuilder:validation:MinItems=1
+	Rates []DefaultRateLimitRate `json:"rates"`
+
+	// Counters qualify the counters of the limit with well-known selectors, e.g. `source.address` for a limit per
+	// client. If omitted, all the requests to a gateway count against the same counters.
+	// +optional
+	Counters []string `json:"counters,omitempty"`
+}
+
+// DefaultRateLimitRate is a rate of the default rate limit, e.g. 1000 requests per 1 minute
+type DefaultRateLimitRate struct {
+	// Limit is the max number of requests allowed in the period
+	// +kubebuilder:validation:Minimum=1
+	Limit int `json:"limit"`
+
+	// Duration of the period, in units
+	// +kubebuilder:validation:Minimum=1
+	Duration int `json:"duration"`
+
+	// Unit of the duration of the period
+	// +kubebuilder:validation:Enum=second;minute;hour;day
+	Unit string `json:"unit"`
+}
+
+type MaintenanceWindowSpec struct {
+	// Start of the window, in the format HH:MM, in UTC
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration of the window, e.g. 2h
+	Duration metav1.Duration `json:"duration"`
+
+	// Days of the week the window opens. If omitted, the window opens every day.
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceWindowDay string
+
+// Contains tells whether a time is within the window
+func (w *MaintenanceWindowSpec) Contains(t time.Time) bool {
+	t = t.UTC()
+	// the windows opened on the previous days may still be open
+	for days := 0; days <= int(w.Duration.Duration/(24*time.Hour))+1; days++ {
+		start, ok := w.startOn(t.AddDate(0, 0, -days))
+		if ok && !t.Before(start) && t.Before(start.Add(w.Duration.Duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the start of the next window after a time, or the zero time if the window is invalid
+func (w *MaintenanceWindowSpec) Next(t time.Time) time.Time {
+	t = t.UTC()
+	for days := 0; days <= 7; days++ {
+		if start, ok := w.startOn(t.AddDate(0, 0, days)); ok && start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// startOn returns the start of the window on the day of a time, if the window opens that day
+func (w *MaintenanceWindowSpec) startOn(day time.Time) (time.Time, bool) {
+	hhmm, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(w.Days) > 0 && !common.Contains(w.Days, MaintenanceWindowDay(day.Weekday().String())) {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hhmm.Hour(), hhmm.Minute(), 0, 0, time.UTC), true
+}
+
+type LimitadorSpec struct {
+	// Metrics holds the settings of the scraping of the metrics of Limitador
+	// +optional
+	Metrics *LimitadorMetricsSpec `json:"metrics,omitempty"`
+
+	// PodDisruptionBudget holds the settings of the PodDisruptionBudget of the pods of Limitador.
+	// If omitted, a PodDisruptionBudget with maxUnavailable 1 is created while Limitador runs more than one replica.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"pdb,omitempty"`
+
+	// CounterDomain is the domain of the counters of the limits of the RateLimitPolicies. The counters of a policy
+	// are shared by all the gateways enforcing it, and by the clusters whose kuadrant instances set the same domain
+	// and store the counters in the same Redis. If omitted, the counters are isolated per gateway.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	CounterDomain string `json:"counterDomain,omitempty"`
+}
+
+type PodDisruptionBudgetSpec struct {
+	// MinAvailable is the number, or the percentage, of pods that must remain available during a voluntary
+	// disruption, e.g. the drain of a node. Mutually exclusive with maxUnavailable.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number, or the percentage, of pods that can be unavailable during a voluntary
+	// disruption, e.g. the drain of a node. Mutually exclusive with minAvailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// Validate rejects the PodDisruptionBudgets setting both minAvailable and maxUnavailable
+func (s *PodDisruptionBudgetSpec) Validate() error {
+	if s != nil && s.MinAvailable != nil && s.MaxUnavailable != nil {
+		return fmt.Errorf("invalid limitador.pdb. minAvailable and maxUnavailable are mutually exclusive")
+	}
+	return nil
+}
+
+type LimitadorMetricsSpec struct {
+	// ServiceMonitor enables the creation of a ServiceMonitor of the Prometheus Operator for the metrics of Limitador.
+	// Ignored if the ServiceMonitor CRD is not installed.
+	// +optional
+	ServiceMonitor *ServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+}
+
+// AuthorinoManagementMode tells how the Authorino instance is managed by Kuadrant
+// +kubebuilder:validation:Enum=Managed;ValidateOnly
+type AuthorinoManagementMode string
+
+const (
+	// AuthorinoManaged means Kuadrant creates and updates the Authorino instance
+	AuthorinoManaged AuthorinoManagementMode = "Managed"
+
+	// AuthorinoValidateOnly means the Authorino instance is managed externally.
+	// Kuadrant only reports discrepancies between the existing instance and the one it expects.
+	AuthorinoValidateOnly AuthorinoManagementMode = "ValidateOnly"
+)
+
+type AuthorinoSpec struct {
+	// ManagementMode tells whether Kuadrant creates and updates the Authorino instance (Managed),
+	// or only validates an externally managed one (ValidateOnly).
+	// +kubebuilder:default=Managed
+	// +optional
+	ManagementMode AuthorinoManagementMode `json:"managementMode,omitempty"`
+
+	// Defaults holds the default settings applied to the Authorino instance and to the AuthConfigs.
+	// Individual AuthConfigs can still override them where Authorino allows.
+	// +optional
+	Defaults *AuthorinoDefaults `json:"defaults,omitempty"`
+
+	// OIDCServer holds the configuration of the OIDC discovery server of Authorino
+	// +optional
+	OIDCServer *AuthorinoOIDCServerSpec `json:"oidcServer,omitempty"`
+
+	// Metrics holds the settings of the metrics endpoint of Authorino and of its scraping
+	// +optional
+	Metrics *AuthorinoMetricsSpec `json:"metrics,omitempty"`
+
+	// TimeoutMilliseconds is the timeout of the ext_authz requests of the gateways to Authorino, in milliseconds.
+	// If omitted, the default of Istio applies (600s).
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=600000
+	// +optional
+	TimeoutMilliseconds *int32 `json:"timeoutMilliseconds,omitempty"`
+
+	// OutboundTimeoutMilliseconds is the timeout of the requests of Authorino to the external identity and metadata
+	// providers, in milliseconds, i.e. the deadline of the auth pipeline of each request, the calls still pending
+	// cancelled. Lower than the ext_authz timeout, for Authorino to respond before the gateways fail the requests.
+	// It caps the evaluation of each request by all the evaluators of the AuthConfigs, whatever their own timeouts.
+	// If omitted, Authorino's default applies (no timeout).
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=600000
+	// +optional
+	OutboundTimeoutMilliseconds *int32 `json:"outboundTimeoutMilliseconds,omitempty"`
+
+	// DecisionLogs enables the structured logs of Authorino recording every authorization request and decision,
+	// for the audit of all the AuthPolicies. Each request is logged in full, at a cost in CPU and log volume.
+	// +optional
+	DecisionLogs *AuthorinoDecisionLogsSpec `json:"decisionLogs,omitempty"`
+
+	// TrustedCABundle refers to the CA certificates trusted by Authorino in addition to the system ones,
+	// e.g. to fetch the OIDC discovery documents and the JWKS of identity providers using a private CA
+	// +optional
+	TrustedCABundle *TrustedCABundleReference `json:"trustedCABundle,omitempty"`
+
+	// Health enables a Service exposing the health service of Authorino, for the health checks of the gateways
+	// +optional
+	Health *AuthorinoHealthSpec `json:"health,omitempty"`
+
+	// Service holds the settings of the Service of the authorization service of Authorino, reached by the gateways
+	// +optional
+	Service *AuthorinoServiceSpec `json:"service,omitempty"`
+
+	// Volumes are mounted into the pods of Authorino, e.g. for the CA certificates, the OPA policies or the JSON
+	// schemas of custom evaluators, named with the kuadrant- prefix in the Authorino instance
+	// +optional
+	Volumes []AuthorinoVolumeSpec `json:"volumes,omitempty"`
+}
+
+type AuthorinoVolumeSpec struct {
+	// Name of the volume, unique among the volumes of the Kuadrant instance
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=54
+	Name string `json:"name"`
+
+	// MountPath is the absolute path of the directory the volume is mounted at
+	// +kubebuilder:validation:Pattern=`^/`
+	MountPath string `json:"mountPath"`
+
+	// ConfigMaps in the namespace of the Kuadrant instance whose entries are projected into the volume
+	// +optional
+	ConfigMaps []string `json:"configMaps,omitempty"`
+
+	// Secrets in the namespace of the Kuadrant instance whose entries are projected into the volume
+	// +optional
+	Secrets []string `json:"secrets,omitempty"`
+
+	// Items are the entries of the ConfigMaps and of the Secrets projected into the volume, and their paths.
+	// If omitted, all the entries are projected, at the paths named after their keys.
+	// +optional
+	Items []corev1.KeyToPath `json:"items,omitempty"`
+}
+
+// ValidateAuthorinoVolumes rejects the volumes without any ConfigMap nor Secret, and the volumes sharing a name or a
+// mount path
+func ValidateAuthorinoVolumes(volumes []AuthorinoVolumeSpec) error {
+	names := make(map[string]struct{}, len(volumes))
+	mountPaths := make(map[string]struct{}, len(volumes))
+	for _, volume := range volumes {
+		if len(volume.ConfigMaps)+len(volume.Secrets) == 0 {
+			return fmt.Errorf("invalid authorino.volumes %s. At least one ConfigMap or Secret must be set", volume.Name)
+		}
+		if _, ok := names[volume.Name]; ok {
+			return fmt.Errorf("invalid authorino.volumes %s. Name used by more than one volume", volume.Name)
+		}
+		names[volume.Name] = struct{}{}
+		if _, ok := mountPaths[volume.MountPath]; ok {
+			return fmt.Errorf("invalid authorino.volumes %s. Mount path %s used by more than one volume", volume.Name, volume.MountPath)
+		}
+		mountPaths[volume.MountPath] = struct{}{}
+	}
+	return nil
+}
+
+type AuthorinoDecisionLogsSpec struct {
+	// Enabled must be set explicitly to emit the decision logs, given their volume
+	Enabled bool `json:"enabled"`
+}
+
+type AuthorinoServiceSpec struct {
+	// Type of the Service. If omitted, ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Annotations are added to the Service, e.g. the annotations of an internal load balancer of the cloud provider.
+	// The annotations set by other sources are preserved.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// LoadBalancerClass is the class of the load balancer implementation. Only valid with the LoadBalancer type.
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// LoadBalancerSourceRanges restricts the client IPs allowed to the load balancer. Only valid with the LoadBalancer type.
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+}
+
+// Validate rejects the load balancer options with a type of Service other than LoadBalancer
+func (s *AuthorinoServiceSpec) Validate() error {
+	if s == nil || s.Type == corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	if s.LoadBalancerClass != nil {
+		return fmt.Errorf("invalid authorino.service.loadBalancerClass. Only valid with the %s type, got %s", corev1.ServiceTypeLoadBalancer, s.ServiceType())
+	}
+	if len(s.LoadBalancerSourceRanges) > 0 {
+		return fmt.Errorf("invalid authorino.service.loadBalancerSourceRanges. Only valid with the %s type, got %s", corev1.ServiceTypeLoadBalancer, s.ServiceType())
+	}
+	return nil
+}
+
+// ServiceType returns the type of the Service, ClusterIP if omitted
+func (s *AuthorinoServiceSpec) ServiceType() corev1.ServiceType {
+	if s == nil || s.Type == "" {
+		return corev1.ServiceTypeClusterIP
+	}
+	return s.Type
+}
+
+type AuthorinoHealthSpec struct {
+	// Port of the /healthz and /readyz endpoints of Authorino. If omitted, Authorino's default applies (8081).
+	// The gRPC health checking protocol is served on the port of the gRPC auth service.
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+type TrustedCABundleReference struct {
+	// Kind of the object holding the CA bundle, in the namespace of the Kuadrant instance
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the ConfigMap or of the Secret
+	Name string `json:"name"`
+
+	// Key of the entry holding the PEM-encoded CA certificates
+	// +kubebuilder:default=ca.crt
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+type AuthorinoMetricsSpec struct {
+	// Port of the metrics endpoint of Authorino. If omitted, Authorino's default applies.
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// ServiceAnnotations are added to the metrics Service of Authorino, e.g. the prometheus.io/scrape annotations.
+	// The annotations set by other sources are preserved.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// ServiceMonitor enables the creation of a ServiceMonitor of the Prometheus Operator for the metrics of Authorino.
+	// Ignored if the ServiceMonitor CRD is not installed.
+	// +optional
+	ServiceMonitor *ServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+
+	// Deep enables the evaluator-level metrics of Authorino for all the evaluators of all the AuthConfigs,
+	// labeled by the AuthConfig and by the type and the name of the evaluator.
+	// The cardinality of the metrics grows with the number of evaluators of the AuthPolicies.
+	// +optional
+	Deep *bool `json:"deep,omitempty"`
+
+	// Evaluators are the types of evaluators of the AuthConfigs the evaluator-level metrics are enabled for,
+	// in addition to the evaluators enabling them in the AuthPolicies. Ignored if Deep is enabled.
+	// +optional
+	Evaluators []AuthorinoMetricsEvaluator `json:"evaluators,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=identity;metadata;authorization;response
+type AuthorinoMetricsEvaluator string
+
+// AuthorinoMetricsEvaluators are the types of evaluators of the AuthConfigs of the AuthPolicies
+var AuthorinoMetricsEvaluators = []AuthorinoMetricsEvaluator{"identity", "metadata", "authorization", "response"}
+
+// HighCardinality returns the reason the metrics settings result in a high cardinality of the metrics of Authorino,
+// or empty if none
+func (m *AuthorinoMetricsSpec) HighCardinality() string {
+	if m == nil {
+		return ""
+	}
+	if m.Deep != nil && *m.Deep {
+		return "deep metrics enabled for all the evaluators"
+	}
+	for _, evaluator := range AuthorinoMetricsEvaluators {
+		if !common.Contains(m.Evaluators, evaluator) {
+			return ""
+		}
+	}
+	return "evaluator-level metrics enabled for all the types of evaluators"
+}
+
+// ApplyEvaluatorMetrics returns a copy of an AuthConfig spec with the metrics enabled for the evaluators of the
+// selected types, along with the list of the evaluators enabled, e.g. "metadata/user-info:metrics"
+func (m *AuthorinoMetricsSpec) ApplyEvaluatorMetrics(spec authorinov1beta1.AuthConfigSpec) (authorinov1beta1.AuthConfigSpec, []string) {
+	enabled := spec.DeepCopy()
+	applied := make([]string, 0)
+	if m == nil || (m.Deep != nil && *m.Deep) {
+		return *enabled, applied
+	}
+
+	enable := func(evaluatorType AuthorinoMetricsEvaluator, name string, metrics *bool) {
+		if !*metrics && common.Contains(m.Evaluators, evaluatorType) {
+			*metrics = true
+			applied = append(applied, fmt.Sprintf("%s/%s:metrics", evaluatorType, name))
+		}
+	}
+
+	for _, identity := range enabled.Identity {
+		enable("identity", identity.Name, &identity.Metrics)
+	}
+	for _, metadata := range enabled.Metadata {
+		enable("metadata", metadata.Name, &metadata.Metrics)
+	}
+	for _, authorization := range enabled.Authorization {
+		enable("authorization", authorization.Name, &authorization.Metrics)
+	}
+	for _, response := range enabled.Response {
+		enable("response", response.Name, &response.Metrics)
+	}
+
+	return *enabled, applied
+}
+
+type ServiceMonitorSpec struct {
+	// Labels are added to the ServiceMonitor, to be selected by the Prometheus instances
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Interval between the scrapes, e.g. 30s. If omitted, the interval of Prometheus applies.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+}
+
+type AuthorinoOIDCServerSpec struct {
+	// TLS holds the TLS settings of the OIDC discovery server listener, separate from the main listener
+	// +optional
+	TLS *AuthorinoTLSSpec `json:"tls,omitempty"`
+}
+
+type AuthorinoTLSSpec struct {
+	// CertSecretRef is the name of a kubernetes.io/tls Secret in the namespace of the Kuadrant instance.
+	// The Secret must contain the tls.crt and tls.key entries.
+	// TLS is enabled when set.
+	// +optional
+	CertSecretRef *corev1.LocalObjectReference `json:"certSecretRef,omitempty"`
+}
+
+type AuthorinoDefaults struct {
+	// Cache holds the defaults for the caching of evaluator results
+	// +optional
+	Cache *AuthorinoCacheDefaults `json:"cache,omitempty"`
+
+	// ExternalData holds the defaults for the data fetched from external sources by the AuthPolicies,
+	// applied to the AuthConfigs of the AuthPolicies that do not set them
+	// +optional
+	ExternalData *AuthorinoExternalDataDefaults `json:"externalData,omitempty"`
+
+	// SkipIf lists the conditions of the requests not subject to auth, e.g. the CORS preflight requests.
+	// A request matching any of the conditions is skipped.
+	// Applied to the AuthConfigs of the AuthPolicies whose auth scheme sets no conditions.
+	// +optional
+	SkipIf []AuthorinoSkipCondition `json:"skipIf,omitempty"`
+}
+
+type AuthorinoSkipCondition struct {
+	// Selector of the value in the authorization JSON, e.g. context.request.http.method
+	Selector string `json:"selector"`
+
+	// Operator comparing the selected value to the value of the condition
+	// +kubebuilder:validation:Enum=eq;neq;incl;excl
+	Operator authorinov1beta1.JSONPatternOperator `json:"operator"`
+
+	// Value compared to the selected value
+	Value string `json:"value"`
+}
+
+// negatedSkipOperators are the operators matching the requests not matched by each skip operator
+var negatedSkipOperators = map[authorinov1beta1.JSONPatternOperator]authorinov1beta1.JSONPatternOperator{
+	"eq":   "neq",
+	"neq":  "eq",
+	"incl": "excl",
+	"excl": "incl",
+}
+
+type AuthorinoCacheDefaults struct {
+	// Size of the cache of each evaluator, in megabytes.
+	// If omitted, Authorino's default applies.
+	// The TTL of the cached entries is defined per evaluator in the AuthPolicy.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Size *int `json:"size,omitempty"`
+}
+
+type AuthorinoExternalDataDefaults struct {
+	// HTTPHeaders are the headers sent in the requests of the HTTP metadata sources of the AuthPolicies,
+	// e.g. a tenant or a tracing header shared by the external services.
+	// Applied to the HTTP metadata sources not setting a header of the same name.
+	// +optional
+	HTTPHeaders []AuthorinoHTTPHeader `json:"httpHeaders,omitempty"`
+
+	// PollingInterval is the duration, in seconds, of the OPA policies fetched from an external registry
+	// before pulled again from the registry.
+	// Applied to the OPA policies whose external registry omits the ttl.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PollingInterval *int `json:"pollingInterval,omitempty"`
+
+	// TTL is the duration, in seconds, of the cached results of the evaluators before fetched again.
+	// Applied to the evaluators whose cache omits the ttl, instead of Authorino's default of 60 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int `json:"ttl,omitempty"`
+}
+
+type AuthorinoHTTPHeader struct {
+	// Name of the header
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value of the header
+	Value string `json:"value"`
+}
+
+// applyHTTPHeaders adds the default headers not set by an HTTP metadata source, returning whether any was added
+func (d *AuthorinoExternalDataDefaults) applyHTTPHeaders(http *authorinov1beta1.Metadata_GenericHTTP) bool {
+	isSet := func(name string) bool {
+		for _, header := range http.Headers {
+			// header names are case insensitive
+			if strings.EqualFold(header.Name, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	added := false
+	for _, header := range d.HTTPHeaders {
+		if isSet(header.Name) {
+			continue
+		}
+		value, _ := json.Marshal(header.Value)
+		http.Headers = append(http.Headers, authorinov1beta1.JsonProperty{Name: header.Name, Value: runtime.RawExtension{Raw: value}})
+		added = true
+	}
+	return added
+}
+
+// Apply returns a copy of an AuthConfig spec with the defaults set where omitted,
+// along with the list of the settings defaulted, e.g. "authorization/opa-policy:pollingInterval"
+func (d *AuthorinoExternalDataDefaults) Apply(spec authorinov1beta1.AuthConfigSpec) (authorinov1beta1.AuthConfigSpec, []string) {
+	defaulted := spec.DeepCopy()
+	applied := make([]string, 0)
+	if d == nil {
+		return *defaulted, applied
+	}
+
+	applyTTL := func(phase, name string, cache *authorinov1beta1.EvaluatorCaching) {
+		if d.TTL != nil && cache != nil && cache.TTL == 0 {
+			cache.TTL = *d.TTL
+			applied = append(applied, fmt.Sprintf("%s/%s:ttl", phase, name))
+		}
+	}
+
+	for _, identity := range defaulted.Identity {
+		applyTTL("identity", identity.Name, identity.Cache)
+	}
+	for _, metadata := range defaulted.Metadata {
+		applyTTL("metadata", metadata.Name, metadata.Cache)
+		if metadata.GenericHTTP != nil && d.applyHTTPHeaders(metadata.GenericHTTP) {
+			applied = append(applied, fmt.Sprintf("metadata/%s:headers", metadata.Name))
+		}
+	}
+	for _, authorization := range defaulted.Authorization {
+		applyTTL("authorization", authorization.Name, authorization.Cache)
+		if opa := authorization.OPA; d.PollingInterval != nil && opa != nil && opa.ExternalRegistry.Endpoint != "" && opa.ExternalRegistry.TTL == 0 {
+			opa.ExternalRegistry.TTL = *d.PollingInterval
+			applied = append(applied, fmt.Sprintf("authorization/%s:pollingInterval", authorization.Name))
+		}
+	}
+	for _, response := range defaulted.Response {
+		applyTTL("response", response.Name, response.Cache)
+	}
+
+	return *defaulted, applied
+}
+
+// Apply returns a copy of an AuthConfig spec with the defaults set where omitted,
+// along with the list of the settings defaulted
+func (d *AuthorinoDefaults) Apply(spec authorinov1beta1.AuthConfigSpec) (authorinov1beta1.AuthConfigSpec, []string) {
+	var externalData *AuthorinoExternalDataDefaults
+	if d != nil {
+		externalData = d.ExternalData
+	}
+
+	defaulted, applied := externalData.Apply(spec)
+	if d == nil || len(d.SkipIf) == 0 || len(defaulted.Conditions) > 0 {
+		return defaulted, applied
+	}
+
+	// the AuthConfig is enforced only if all its conditions match, i.e. if none of the skip conditions match
+	for _, skipIf := range d.SkipIf {
+		defaulted.Conditions = append(defaulted.Conditions, authorinov1beta1.JSONPattern{
+			JSONPatternExpression: authorinov1beta1.JSONPatternExpression{
+				Selector: skipIf.Selector,
+				Operator: negatedSkipOperators[skipIf.Operator],
+				Value:    skipIf.Value,
+			},
+		})
+	}
+
+	return defaulted, append(applied, "conditions:skipIf")
+}
+
+// AuthorinoDefaults returns the defaults applied to the AuthConfigs of the AuthPolicies, or nil if none
+func (k *Kuadrant) AuthorinoDefaults() *AuthorinoDefaults {
+	if k.Spec.Authorino == nil {
+		return nil
+	}
+	return k.Spec.Authorino.Defaults
+}
+
+// AuthorinoExternalDataDefaults returns the defaults for the external data of the AuthPolicies, or nil if none
+func (k *Kuadrant) AuthorinoExternalDataDefaults() *AuthorinoExternalDataDefaults {
+	if k.Spec.Authorino == nil || k.Spec.Authorino.Defaults == nil {
+		return nil
+	}
+	return k.Spec.Authorino.Defaults.ExternalData
+}
+
+// AuthorinoMetrics returns the settings of the metrics of Authorino, or nil if none
+func (k *Kuadrant) AuthorinoMetrics() *AuthorinoMetricsSpec {
+	if k.Spec.Authorino == nil {
+		return nil
+	}
+	return k.Spec.Authorino.Metrics
+}
+
+// AuthorinoDecisionLogsEnabled tells whether Authorino logs every authorization decision
+func (k *Kuadrant) AuthorinoDecisionLogsEnabled() bool {
+	return k.Spec.Authorino != nil && k.Spec.Authorino.DecisionLogs != nil && k.Spec.Authorino.DecisionLogs.Enabled
+}
+
+func (k *Kuadrant) LimitadorServiceMonitor() *ServiceMonitorSpec {
+	if k.Spec.Limitador == nil || k.Spec.Limitador.Metrics == nil {
+		return nil
+	}
+	return k.Spec.Limitador.Metrics.ServiceMonitor
+}
+
+// LimitadorPodDisruptionBudget returns the settings of the PodDisruptionBudget of Limitador, or nil if not set
+func (k *Kuadrant) LimitadorPodDisruptionBudget() *PodDisruptionBudgetSpec {
+	if k.Spec.Limitador == nil {
+		return nil
+	}
+	return k.Spec.Limitador.PodDisruptionBudget
+}
+
+// NetworkPoliciesEnabled tells whether the NetworkPolicies of the traffic of the gateways to Authorino and
+// Limitador are managed
+func (k *Kuadrant) NetworkPoliciesEnabled() bool {
+	return k.Spec.NetworkPolicies != nil && k.Spec.NetworkPolicies.Enabled
+}
+
+// LimitadorCounterDomain returns the domain of the counters of the limits of the RateLimitPolicies, or empty if
+// the counters are isolated per gateway
+func (k *Kuadrant) LimitadorCounterDomain() string {
+	if k.Spec.Limitador == nil {
+		return ""
+	}
+	return k.Spec.Limitador.CounterDomain
 }
 
 // KuadrantStatus defines the observed state of Kuadrant
@@ -44,6 +755,52 @@ type KuadrantStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// EffectiveConfig is the configuration resolved by the running operator from its env vars, flags and
+	// config file, indexed by the name of the env var, flag or config file field.
+	// +optional
+	EffectiveConfig map[string]string `json:"effectiveConfig,omitempty"`
+
+	// AuthorinoHealthService is the Service exposing the health service of Authorino, if enabled
+	// +optional
+	AuthorinoHealthService *HealthServiceStatus `json:"authorinoHealthService,omitempty"`
+
+	// DeferredChanges are the disruptive changes of the managed components pending the maintenance window
+	// +optional
+	DeferredChanges []DeferredChange `json:"deferredChanges,omitempty"`
+
+	// NextMaintenanceWindow is the start of the maintenance window the deferred changes are applied in
+	// +optional
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+
+	// DefaultRateLimitGateways lists the gateways the default rate limit applies to, i.e. the gateways managed by
+	// the kuadrant instance without a RateLimitPolicy of their own
+	// +optional
+	DefaultRateLimitGateways []string `json:"defaultRateLimitGateways,omitempty"`
+
+	// DefaultAuthPolicyGateways lists the gateways the default AuthPolicy applies to, i.e. the gateways managed by
+	// the kuadrant instance without an AuthPolicy of their own
+	// +optional
+	DefaultAuthPolicyGateways []string `json:"defaultAuthPolicyGateways,omitempty"`
+}
+
+type DeferredChange struct {
+	// Component changed, i.e. authorino or limitador
+	Component string `json:"component"`
+
+	// Description of the change
+	Description string `json:"description"`
+}
+
+type HealthServiceStatus struct {
+	// Name of the Service, in the namespace of the Kuadrant instance
+	Name string `json:"name"`
+
+	// GRPCPort is the port of the gRPC health checking protocol (grpc.health.v1.Health)
+	GRPCPort int32 `json:"grpcPort"`
+
+	// HTTPPort is the port of the /healthz and /readyz endpoints
+	HTTPPort int32 `json:"httpPort"`
 }
 
 func (r *KuadrantStatus) Equals(other *KuadrantStatus, logger logr.Logger) bool {
@@ -62,6 +819,42 @@ func (r *KuadrantStatus) Equals(other *KuadrantStatus, logger logr.Logger) bool
 		return false
 	}
 
+	if !reflect.DeepEqual(r.EffectiveConfig, other.EffectiveConfig) {
+		diff := cmp.Diff(r.EffectiveConfig, other.EffectiveConfig)
+		logger.V(1).Info("EffectiveConfig not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(r.AuthorinoHealthService, other.AuthorinoHealthService) {
+		diff := cmp.Diff(r.AuthorinoHealthService, other.AuthorinoHealthService)
+		logger.V(1).Info("AuthorinoHealthService not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(r.DeferredChanges, other.DeferredChanges) {
+		diff := cmp.Diff(r.DeferredChanges, other.DeferredChanges)
+		logger.V(1).Info("DeferredChanges not equal", "difference", diff)
+		return false
+	}
+
+	if !r.NextMaintenanceWindow.Equal(other.NextMaintenanceWindow) {
+		diff := cmp.Diff(r.NextMaintenanceWindow, other.NextMaintenanceWindow)
+		logger.V(1).Info("NextMaintenanceWindow not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(r.DefaultRateLimitGateways, other.DefaultRateLimitGateways) {
+		diff := cmp.Diff(r.DefaultRateLimitGateways, other.DefaultRateLimitGateways)
+		logger.V(1).Info("DefaultRateLimitGateways not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(r.DefaultAuthPolicyGateways, other.DefaultAuthPolicyGateways) {
+		diff := cmp.Diff(r.DefaultAuthPolicyGateways, other.DefaultAuthPolicyGateways)
+		logger.V(1).Info("DefaultAuthPolicyGateways not equal", "difference", diff)
+		return false
+	}
+
 	return true
 }
 
@@ -89,3 +882,85 @@ type KuadrantList struct {
 func init() {
 	SchemeBuilder.Register(&Kuadrant{}, &KuadrantList{})
 }
+
+// AuthorinoOIDCServerCertSecretRef returns the reference to the Secret of the TLS certificate of the OIDC
+// discovery server of Authorino, or nil if TLS is not enabled
+func (k *Kuadrant) AuthorinoOIDCServerCertSecretRef() *corev1.LocalObjectReference {
+	if k.Spec.Authorino == nil || k.Spec.Authorino.OIDCServer == nil || k.Spec.Authorino.OIDCServer.TLS == nil {
+		return nil
+	}
+	return k.Spec.Authorino.OIDCServer.TLS.CertSecretRef
+}
+
+// AuthorinoTrustedCABundle returns the reference to the CA bundle trusted by Authorino, or nil if not set
+func (k *Kuadrant) AuthorinoTrustedCABundle() *TrustedCABundleReference {
+	if k.Spec.Authorino == nil {
+		return nil
+	}
+	return k.Spec.Authorino.TrustedCABundle
+}
+
+// AuthorinoVolumes returns the volumes mounted into the pods of Authorino
+func (k *Kuadrant) AuthorinoVolumes() []AuthorinoVolumeSpec {
+	if k.Spec.Authorino == nil {
+		return nil
+	}
+	return k.Spec.Authorino.Volumes
+}
+
+// AuthorinoHealth returns the settings of the health service of Authorino, or nil if not enabled
+func (k *Kuadrant) AuthorinoHealth() *AuthorinoHealthSpec {
+	if k.Spec.Authorino == nil {
+		return nil
+	}
+	return k.Spec.Authorino.Health
+}
+
+// AuthorinoService returns the settings of the Service of the authorization service of Authorino, or nil if not set
+func (k *Kuadrant) AuthorinoService() *AuthorinoServiceSpec {
+	if k.Spec.Authorino == nil {
+		return nil
+	}
+	return k.Spec.Authorino.Service
+}
+
+// AuthorinoTimeout returns the timeout of the ext_authz requests to Authorino, or nil if not set
+func (k *Kuadrant) AuthorinoTimeout() *time.Duration {
+	if k.Spec.Authorino == nil || k.Spec.Authorino.TimeoutMilliseconds == nil {
+		return nil
+	}
+	timeout := time.Duration(*k.Spec.Authorino.TimeoutMilliseconds) * time.Millisecond
+	return &timeout
+}
+
+// AuthorinoOutboundTimeout returns the timeout of the requests of Authorino to the external providers, or nil if not set
+func (k *Kuadrant) AuthorinoOutboundTimeout() *time.Duration {
+	if k.Spec.Authorino == nil || k.Spec.Authorino.OutboundTimeoutMilliseconds == nil {
+		return nil
+	}
+	timeout := time.Duration(*k.Spec.Authorino.OutboundTimeoutMilliseconds) * time.Millisecond
+	return &timeout
+}
+
+// AuthorinoOutboundTimeoutExceeded returns the reason the timeout of the requests of Authorino to the external
+// providers is not lower than the timeout of the ext_authz requests, the gateways failing the requests before
+// Authorino responds, or empty if lower or not set
+func (k *Kuadrant) AuthorinoOutboundTimeoutExceeded() string {
+	outbound := k.AuthorinoOutboundTimeout()
+	if outbound == nil {
+		return ""
+	}
+	extAuthz := common.DefaultExtAuthzTimeout
+	if timeout := k.AuthorinoTimeout(); timeout != nil {
+		extAuthz = *timeout
+	}
+	if *outbound < extAuthz {
+		return ""
+	}
+	return fmt.Sprintf("the outbound timeout of Authorino (%s) is not lower than the timeout of the ext_authz requests (%s)", outbound, extAuthz)
+}
+
+// IsAuthorinoValidateOnly tells whether the Authorino instance is managed externally
+func (k *Kuadrant) IsAuthorinoValidateOnly() bool {
+	return k.Spec.Authorino != nil && k.Spec.Authorino.ManagementMode == AuthorinoValidateOnly
+}
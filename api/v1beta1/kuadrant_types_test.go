@@ -0,0 +1,340 @@
+//go:build unit
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestAuthorinoExternalDataDefaultsApply(t *testing.T) {
+	pollingInterval, ttl := 300, 120
+
+	spec := authorinov1beta1.AuthConfigSpec{
+		Metadata: []*authorinov1beta1.Metadata{
+			{Name: "user-info", Cache: &authorinov1beta1.EvaluatorCaching{}},
+			{Name: "geo", Cache: &authorinov1beta1.EvaluatorCaching{TTL: 30}},
+			{Name: "no-cache"},
+		},
+		Authorization: []*authorinov1beta1.Authorization{
+			{Name: "opa-registry", OPA: &authorinov1beta1.Authorization_OPA{ExternalRegistry: authorinov1beta1.ExternalRegistry{Endpoint: "http://opa"}}},
+			{Name: "opa-inline", OPA: &authorinov1beta1.Authorization_OPA{InlineRego: "allow = true"}},
+		},
+	}
+
+	defaults := &AuthorinoExternalDataDefaults{PollingInterval: &pollingInterval, TTL: &ttl}
+	defaulted, applied := defaults.Apply(spec)
+
+	expectedApplied := []string{"metadata/user-info:ttl", "authorization/opa-registry:pollingInterval"}
+	if !reflect.DeepEqual(applied, expectedApplied) {
+		t.Errorf("unexpected settings defaulted: got %v, want %v", applied, expectedApplied)
+	}
+	if defaulted.Metadata[0].Cache.TTL != ttl || defaulted.Metadata[1].Cache.TTL != 30 || defaulted.Metadata[2].Cache != nil {
+		t.Errorf("unexpected metadata caches: %+v", defaulted.Metadata)
+	}
+	if defaulted.Authorization[0].OPA.ExternalRegistry.TTL != pollingInterval || defaulted.Authorization[1].OPA.ExternalRegistry.TTL != 0 {
+		t.Errorf("unexpected opa policies: %+v", defaulted.Authorization)
+	}
+
+	if spec.Metadata[0].Cache.TTL != 0 || spec.Authorization[0].OPA.ExternalRegistry.TTL != 0 {
+		t.Errorf("the original spec was modified: %+v", spec)
+	}
+
+	var noDefaults *AuthorinoExternalDataDefaults
+	if defaulted, applied := noDefaults.Apply(spec); len(applied) != 0 || !reflect.DeepEqual(defaulted, spec) {
+		t.Errorf("unexpected settings defaulted without defaults: %v", applied)
+	}
+}
+
+func TestAuthorinoExternalDataDefaultsApplyHTTPHeaders(t *testing.T) {
+	spec := authorinov1beta1.AuthConfigSpec{
+		Metadata: []*authorinov1beta1.Metadata{
+			{Name: "geo", GenericHTTP: &authorinov1beta1.Metadata_GenericHTTP{Endpoint: "http://geo"}},
+			{Name: "tenant", GenericHTTP: &authorinov1beta1.Metadata_GenericHTTP{
+				Endpoint: "http://tenant",
+				Headers:  []authorinov1beta1.JsonProperty{{Name: "x-tenant", Value: runtime.RawExtension{Raw: []byte(`"acme"`)}}},
+			}},
+			{Name: "user-info", UserInfo: &authorinov1beta1.Metadata_UserInfo{IdentitySource: "keycloak"}},
+		},
+	}
+
+	defaults := &AuthorinoExternalDataDefaults{HTTPHeaders: []AuthorinoHTTPHeader{{Name: "X-Tenant", Value: "kuadrant"}}}
+	defaulted, applied := defaults.Apply(spec)
+
+	if !reflect.DeepEqual(applied, []string{"metadata/geo:headers"}) {
+		t.Errorf("unexpected settings defaulted: %v", applied)
+	}
+	expectedHeaders := []authorinov1beta1.JsonProperty{{Name: "X-Tenant", Value: runtime.RawExtension{Raw: []byte(`"kuadrant"`)}}}
+	if !reflect.DeepEqual(defaulted.Metadata[0].GenericHTTP.Headers, expectedHeaders) {
+		t.Errorf("unexpected headers: got %+v, want %+v", defaulted.Metadata[0].GenericHTTP.Headers, expectedHeaders)
+	}
+	// the headers of the policy prevail, regardless of the case
+	if !reflect.DeepEqual(defaulted.Metadata[1].GenericHTTP.Headers, spec.Metadata[1].GenericHTTP.Headers) {
+		t.Errorf("the headers of the policy were overridden: %+v", defaulted.Metadata[1].GenericHTTP.Headers)
+	}
+	if len(spec.Metadata[0].GenericHTTP.Headers) != 0 {
+		t.Errorf("the original spec was modified: %+v", spec.Metadata[0].GenericHTTP)
+	}
+}
+
+func TestAuthorinoDefaultsApplySkipIf(t *testing.T) {
+	defaults := &AuthorinoDefaults{
+		SkipIf: []AuthorinoSkipCondition{
+			{Selector: "context.request.http.method", Operator: "eq", Value: "OPTIONS"},
+			{Selector: "context.request.http.path", Operator: "neq", Value: "/api"},
+		},
+	}
+
+	defaulted, applied := defaults.Apply(authorinov1beta1.AuthConfigSpec{})
+
+	expectedConditions := []authorinov1beta1.JSONPattern{
+		{JSONPatternExpression: authorinov1beta1.JSONPatternExpression{Selector: "context.request.http.method", Operator: "neq", Value: "OPTIONS"}},
+		{JSONPatternExpression: authorinov1beta1.JSONPatternExpression{Selector: "context.request.http.path", Operator: "eq", Value: "/api"}},
+	}
+	if !reflect.DeepEqual(defaulted.Conditions, expectedConditions) {
+		t.Errorf("unexpected conditions: got %+v, want %+v", defaulted.Conditions, expectedConditions)
+	}
+	if !reflect.DeepEqual(applied, []string{"conditions:skipIf"}) {
+		t.Errorf("unexpected settings defaulted: %v", applied)
+	}
+
+	// the conditions of the policy prevail
+	conditions := []authorinov1beta1.JSONPattern{{JSONPatternRef: authorinov1beta1.JSONPatternRef{JSONPatternName: "internal"}}}
+	defaulted, applied = defaults.Apply(authorinov1beta1.AuthConfigSpec{Conditions: conditions})
+	if !reflect.DeepEqual(defaulted.Conditions, conditions) || len(applied) != 0 {
+		t.Errorf("unexpected conditions of a policy setting its own: %+v, %v", defaulted.Conditions, applied)
+	}
+
+	var noDefaults *AuthorinoDefaults
+	if defaulted, applied := noDefaults.Apply(authorinov1beta1.AuthConfigSpec{}); len(applied) != 0 || len(defaulted.Conditions) != 0 {
+		t.Errorf("unexpected settings defaulted without defaults: %v", applied)
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	// Friday 22:00 to Saturday 02:00, UTC
+	window := &MaintenanceWindowSpec{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}, Days: []MaintenanceWindowDay{"Friday"}}
+
+	testCases := []struct {
+		name     string
+		time     time.Time
+		contains bool
+		next     time.Time
+	}{
+		{name: "before the window", time: date(2023, 6, 2, 21, 59), contains: false, next: date(2023, 6, 2, 22, 0)},
+		{name: "at the start of the window", time: date(2023, 6, 2, 22, 0), contains: true, next: date(2023, 6, 9, 22, 0)},
+		{name: "after midnight within the window", time: date(2023, 6, 3, 1, 30), contains: true, next: date(2023, 6, 9, 22, 0)},
+		{name: "at the end of the window", time: date(2023, 6, 3, 2, 0), contains: false, next: date(2023, 6, 9, 22, 0)},
+		{name: "on another day", time: date(2023, 6, 5, 23, 0), contains: false, next: date(2023, 6, 9, 22, 0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if contains := window.Contains(tc.time); contains != tc.contains {
+				subT.Fatalf("expected contains %t, got %t", tc.contains, contains)
+			}
+			if next := window.Next(tc.time); !next.Equal(tc.next) {
+				subT.Fatalf("expected next window %s, got %s", tc.next, next)
+			}
+		})
+	}
+
+	daily := &MaintenanceWindowSpec{Start: "03:00", Duration: metav1.Duration{Duration: time.Hour}}
+	if next := daily.Next(date(2023, 6, 5, 4, 0)); !next.Equal(date(2023, 6, 6, 3, 0)) {
+		t.Fatalf("expected the daily window to open the next day, got %s", next)
+	}
+}
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestAuthorinoMetricsApplyEvaluatorMetrics(t *testing.T) {
+	spec := authorinov1beta1.AuthConfigSpec{
+		Identity:      []*authorinov1beta1.Identity{{Name: "api-key"}},
+		Metadata:      []*authorinov1beta1.Metadata{{Name: "user-info"}, {Name: "geo", Metrics: true}},
+		Authorization: []*authorinov1beta1.Authorization{{Name: "opa"}},
+	}
+
+	metrics := &AuthorinoMetricsSpec{Evaluators: []AuthorinoMetricsEvaluator{"metadata", "authorization"}}
+	enabled, applied := metrics.ApplyEvaluatorMetrics(spec)
+
+	expectedApplied := []string{"metadata/user-info:metrics", "authorization/opa:metrics"}
+	if !reflect.DeepEqual(applied, expectedApplied) {
+		t.Fatalf("expected %v, got %v", expectedApplied, applied)
+	}
+	if enabled.Identity[0].Metrics || !enabled.Metadata[0].Metrics || !enabled.Metadata[1].Metrics || !enabled.Authorization[0].Metrics {
+		t.Fatal("unexpected evaluator metrics")
+	}
+	if spec.Metadata[0].Metrics {
+		t.Fatal("expected the original spec to be unchanged")
+	}
+
+	deep := true
+	if _, applied := (&AuthorinoMetricsSpec{Deep: &deep, Evaluators: metrics.Evaluators}).ApplyEvaluatorMetrics(spec); len(applied) != 0 {
+		t.Fatalf("expected no evaluator enabled with deep metrics, got %v", applied)
+	}
+}
+
+func TestAuthorinoMetricsHighCardinality(t *testing.T) {
+	deep := true
+	if (&AuthorinoMetricsSpec{Deep: &deep}).HighCardinality() == "" {
+		t.Fatal("expected deep metrics to be reported")
+	}
+	if (&AuthorinoMetricsSpec{Evaluators: AuthorinoMetricsEvaluators}).HighCardinality() == "" {
+		t.Fatal("expected all the types of evaluators to be reported")
+	}
+	if reason := (&AuthorinoMetricsSpec{Evaluators: []AuthorinoMetricsEvaluator{"authorization"}}).HighCardinality(); reason != "" {
+		t.Fatalf("unexpected high cardinality: %s", reason)
+	}
+	var unset *AuthorinoMetricsSpec
+	if unset.HighCardinality() != "" {
+		t.Fatal("unexpected high cardinality of unset metrics")
+	}
+}
+
+func TestAuthorinoServiceValidate(t *testing.T) {
+	class := "service.k8s.aws/nlb"
+	testCases := []struct {
+		name  string
+		spec  *AuthorinoServiceSpec
+		valid bool
+	}{
+		{name: "unset", spec: nil, valid: true},
+		{name: "load balancer options", spec: &AuthorinoServiceSpec{Type: corev1.ServiceTypeLoadBalancer, LoadBalancerClass: &class, LoadBalancerSourceRanges: []string{"10.0.0.0/8"}}, valid: true},
+		{name: "annotations only", spec: &AuthorinoServiceSpec{Annotations: map[string]string{"foo": "bar"}}, valid: true},
+		{name: "class without load balancer", spec: &AuthorinoServiceSpec{LoadBalancerClass: &class}, valid: false},
+		{name: "source ranges with node port", spec: &AuthorinoServiceSpec{Type: corev1.ServiceTypeNodePort, LoadBalancerSourceRanges: []string{"10.0.0.0/8"}}, valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if err := tc.spec.Validate(); (err == nil) != tc.valid {
+				subT.Errorf("expected valid=%t, got %v", tc.valid, err)
+			}
+		})
+	}
+
+	var unset *AuthorinoServiceSpec
+	if unset.ServiceType() != corev1.ServiceTypeClusterIP {
+		t.Errorf("expected the ClusterIP type by default, got %s", unset.ServiceType())
+	}
+}
+
+func TestValidateAuthorinoVolumes(t *testing.T) {
+	opa := AuthorinoVolumeSpec{Name: "opa", MountPath: "/opa", ConfigMaps: []string{"opa-policies"}}
+	schemas := AuthorinoVolumeSpec{Name: "schemas", MountPath: "/schemas", Secrets: []string{"json-schemas"}}
+	testCases := []struct {
+		name    string
+		volumes []AuthorinoVolumeSpec
+		valid   bool
+	}{
+		{name: "unset", volumes: nil, valid: true},
+		{name: "configmap and secret volumes", volumes: []AuthorinoVolumeSpec{opa, schemas}, valid: true},
+		{name: "no source", volumes: []AuthorinoVolumeSpec{{Name: "empty", MountPath: "/empty"}}, valid: false},
+		{name: "same name", volumes: []AuthorinoVolumeSpec{opa, {Name: "opa", MountPath: "/other", Secrets: []string{"other"}}}, valid: false},
+		{name: "same mount path", volumes: []AuthorinoVolumeSpec{opa, {Name: "other", MountPath: "/opa", Secrets: []string{"other"}}}, valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if err := ValidateAuthorinoVolumes(tc.volumes); (err == nil) != tc.valid {
+				subT.Errorf("expected valid=%t, got %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestPodDisruptionBudgetValidate(t *testing.T) {
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	testCases := []struct {
+		name  string
+		spec  *PodDisruptionBudgetSpec
+		valid bool
+	}{
+		{name: "unset", spec: nil, valid: true},
+		{name: "min available", spec: &PodDisruptionBudgetSpec{MinAvailable: &half}, valid: true},
+		{name: "max unavailable", spec: &PodDisruptionBudgetSpec{MaxUnavailable: &one}, valid: true},
+		{name: "both", spec: &PodDisruptionBudgetSpec{MinAvailable: &half, MaxUnavailable: &one}, valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if err := tc.spec.Validate(); (err == nil) != tc.valid {
+				subT.Errorf("expected valid=%t, got %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestAuthorinoOutboundTimeoutExceeded(t *testing.T) {
+	int32Ptr := func(v int32) *int32 { return &v }
+	testCases := []struct {
+		name     string
+		extAuthz *int32
+		outbound *int32
+		exceeded bool
+	}{
+		{name: "not set", extAuthz: int32Ptr(200)},
+		{name: "lower", extAuthz: int32Ptr(200), outbound: int32Ptr(150)},
+		{name: "equal", extAuthz: int32Ptr(200), outbound: int32Ptr(200), exceeded: true},
+		{name: "higher", extAuthz: int32Ptr(200), outbound: int32Ptr(500), exceeded: true},
+		{name: "default ext_authz timeout", outbound: int32Ptr(500)},
+	}
+
+	for _, tc := range testCases {
+		kObj := &Kuadrant{Spec: KuadrantSpec{Authorino: &AuthorinoSpec{TimeoutMilliseconds: tc.extAuthz, OutboundTimeoutMilliseconds: tc.outbound}}}
+		if reason := kObj.AuthorinoOutboundTimeoutExceeded(); (reason != "") != tc.exceeded {
+			t.Errorf("%s: AuthorinoOutboundTimeoutExceeded() = %q, wanted exceeded %v", tc.name, reason, tc.exceeded)
+		}
+	}
+}
+
+func TestAuthorinoDecisionLogsEnabled(t *testing.T) {
+	kObj := &Kuadrant{}
+	if kObj.AuthorinoDecisionLogsEnabled() {
+		t.Error("decision logs enabled without the authorino spec")
+	}
+	kObj.Spec.Authorino = &AuthorinoSpec{DecisionLogs: &AuthorinoDecisionLogsSpec{}}
+	if kObj.AuthorinoDecisionLogsEnabled() {
+		t.Error("decision logs enabled without the explicit opt-in")
+	}
+	kObj.Spec.Authorino.DecisionLogs.Enabled = true
+	if !kObj.AuthorinoDecisionLogsEnabled() {
+		t.Error("decision logs not enabled with the explicit opt-in")
+	}
+}
+
+func TestGrafanaDashboardLabels(t *testing.T) {
+	var spec *GrafanaDashboardSpec
+	if labels := spec.DashboardLabels(); !reflect.DeepEqual(labels, map[string]string{"grafana_dashboard": "1"}) {
+		t.Errorf("DashboardLabels() = %v, want the default label of the sidecar", labels)
+	}
+	spec = &GrafanaDashboardSpec{Labels: map[string]string{"dashboards": "kuadrant"}}
+	if labels := spec.DashboardLabels(); !reflect.DeepEqual(labels, spec.Labels) {
+		t.Errorf("DashboardLabels() = %v, want %v", labels, spec.Labels)
+	}
+}
+
+func TestNetworkPoliciesEnabled(t *testing.T) {
+	kObj := &Kuadrant{}
+	if kObj.NetworkPoliciesEnabled() {
+		t.Error("network policies enabled without the networkPolicies spec")
+	}
+	kObj.Spec.NetworkPolicies = &NetworkPoliciesSpec{}
+	if kObj.NetworkPoliciesEnabled() {
+		t.Error("network policies enabled without the explicit opt-in")
+	}
+	kObj.Spec.NetworkPolicies.Enabled = true
+	if !kObj.NetworkPoliciesEnabled() {
+		t.Error("network policies not enabled with the explicit opt-in")
+	}
+}
@@ -23,10 +23,115 @@ package v1beta1
 
 import (
 	apiv1beta1 "github.com/kuadrant/authorino/api/v1beta1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	apisv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnonymousAccess) DeepCopyInto(out *AnonymousAccess) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnonymousAccess.
+func (in *AnonymousAccess) DeepCopy() *AnonymousAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(AnonymousAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthCacheSpec) DeepCopyInto(out *AuthCacheSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int)
+		**out = **in
+	}
+	if in.Evaluators != nil {
+		in, out := &in.Evaluators, &out.Evaluators
+		*out = make([]AuthCacheEvaluator, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthCacheSpec.
+func (in *AuthCacheSpec) DeepCopy() *AuthCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthExclusions) DeepCopyInto(out *AuthExclusions) {
+	*out = *in
+	if in.HTTPRoutes != nil {
+		in, out := &in.HTTPRoutes, &out.HTTPRoutes
+		*out = make([]HTTPRouteReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthExclusions.
+func (in *AuthExclusions) DeepCopy() *AuthExclusions {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthExclusions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthListenersSpec) DeepCopyInto(out *AuthListenersSpec) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]apisv1beta1.SectionName, len(*in))
+		copy(*out, *in)
+	}
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make([]apisv1beta1.ProtocolType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthListenersSpec.
+func (in *AuthListenersSpec) DeepCopy() *AuthListenersSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthListenersSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthPolicy) DeepCopyInto(out *AuthPolicy) {
 	*out = *in
@@ -98,6 +203,35 @@ func (in *AuthPolicySpec) DeepCopyInto(out *AuthPolicySpec) {
 		}
 	}
 	in.AuthScheme.DeepCopyInto(&out.AuthScheme)
+	if in.Exclusions != nil {
+		in, out := &in.Exclusions, &out.Exclusions
+		*out = new(AuthExclusions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.IdentityProviders != nil {
+		in, out := &in.IdentityProviders, &out.IdentityProviders
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(AuthCacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = new(AuthListenersSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthPolicySpec.
@@ -115,11 +249,62 @@ func (in *AuthPolicyStatus) DeepCopyInto(out *AuthPolicyStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedHTTPRoutes != nil {
+		in, out := &in.ExcludedHTTPRoutes, &out.ExcludedHTTPRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Authorino != nil {
+		in, out := &in.Authorino, &out.Authorino
+		*out = new(AuthorinoReference)
+		**out = **in
+	}
+	if in.InjectedHeaders != nil {
+		in, out := &in.InjectedHeaders, &out.InjectedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnonymousAccess != nil {
+		in, out := &in.AnonymousAccess, &out.AnonymousAccess
+		*out = make([]AnonymousAccess, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hierarchy != nil {
+		in, out := &in.Hierarchy, &out.Hierarchy
+		*out = make([]PolicyHierarchy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IdentityOrder != nil {
+		in, out := &in.IdentityOrder, &out.IdentityOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = make([]EvaluatorCache, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = make([]ClientCertificateIdentity, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthPolicyStatus.
@@ -196,6 +381,16 @@ func (in *AuthSchemeSpec) DeepCopyInto(out *AuthSchemeSpec) {
 			}
 		}
 	}
+	if in.IdentityOrder != nil {
+		in, out := &in.IdentityOrder, &out.IdentityOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(ClientCertificateSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = make([]*apiv1beta1.Metadata, len(*in))
@@ -247,97 +442,881 @@ func (in *AuthSchemeSpec) DeepCopy() *AuthSchemeSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kuadrant) DeepCopyInto(out *Kuadrant) {
+func (in *AuthorinoCacheDefaults) DeepCopyInto(out *AuthorinoCacheDefaults) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kuadrant.
-func (in *Kuadrant) DeepCopy() *Kuadrant {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoCacheDefaults.
+func (in *AuthorinoCacheDefaults) DeepCopy() *AuthorinoCacheDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(Kuadrant)
+	out := new(AuthorinoCacheDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Kuadrant) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoDecisionLogsSpec) DeepCopyInto(out *AuthorinoDecisionLogsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoDecisionLogsSpec.
+func (in *AuthorinoDecisionLogsSpec) DeepCopy() *AuthorinoDecisionLogsSpec {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(AuthorinoDecisionLogsSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KuadrantList) DeepCopyInto(out *KuadrantList) {
+func (in *AuthorinoDefaults) DeepCopyInto(out *AuthorinoDefaults) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Kuadrant, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(AuthorinoCacheDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalData != nil {
+		in, out := &in.ExternalData, &out.ExternalData
+		*out = new(AuthorinoExternalDataDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SkipIf != nil {
+		in, out := &in.SkipIf, &out.SkipIf
+		*out = make([]AuthorinoSkipCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoDefaults.
+func (in *AuthorinoDefaults) DeepCopy() *AuthorinoDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoExternalDataDefaults) DeepCopyInto(out *AuthorinoExternalDataDefaults) {
+	*out = *in
+	if in.HTTPHeaders != nil {
+		in, out := &in.HTTPHeaders, &out.HTTPHeaders
+		*out = make([]AuthorinoHTTPHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoExternalDataDefaults.
+func (in *AuthorinoExternalDataDefaults) DeepCopy() *AuthorinoExternalDataDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoExternalDataDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoHTTPHeader) DeepCopyInto(out *AuthorinoHTTPHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoHTTPHeader.
+func (in *AuthorinoHTTPHeader) DeepCopy() *AuthorinoHTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoHTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoHealthSpec) DeepCopyInto(out *AuthorinoHealthSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoHealthSpec.
+func (in *AuthorinoHealthSpec) DeepCopy() *AuthorinoHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoMetricsSpec) DeepCopyInto(out *AuthorinoMetricsSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deep != nil {
+		in, out := &in.Deep, &out.Deep
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Evaluators != nil {
+		in, out := &in.Evaluators, &out.Evaluators
+		*out = make([]AuthorinoMetricsEvaluator, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KuadrantList.
-func (in *KuadrantList) DeepCopy() *KuadrantList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoMetricsSpec.
+func (in *AuthorinoMetricsSpec) DeepCopy() *AuthorinoMetricsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KuadrantList)
+	out := new(AuthorinoMetricsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KuadrantList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoOIDCServerSpec) DeepCopyInto(out *AuthorinoOIDCServerSpec) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(AuthorinoTLSSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoOIDCServerSpec.
+func (in *AuthorinoOIDCServerSpec) DeepCopy() *AuthorinoOIDCServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoOIDCServerSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KuadrantSpec) DeepCopyInto(out *KuadrantSpec) {
+func (in *AuthorinoReference) DeepCopyInto(out *AuthorinoReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KuadrantSpec.
-func (in *KuadrantSpec) DeepCopy() *KuadrantSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoReference.
+func (in *AuthorinoReference) DeepCopy() *AuthorinoReference {
 	if in == nil {
 		return nil
 	}
-	out := new(KuadrantSpec)
+	out := new(AuthorinoReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KuadrantStatus) DeepCopyInto(out *KuadrantStatus) {
+func (in *AuthorinoServiceSpec) DeepCopyInto(out *AuthorinoServiceSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KuadrantStatus.
-func (in *KuadrantStatus) DeepCopy() *KuadrantStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoServiceSpec.
+func (in *AuthorinoServiceSpec) DeepCopy() *AuthorinoServiceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KuadrantStatus)
+	out := new(AuthorinoServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoSkipCondition) DeepCopyInto(out *AuthorinoSkipCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoSkipCondition.
+func (in *AuthorinoSkipCondition) DeepCopy() *AuthorinoSkipCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoSkipCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoSpec) DeepCopyInto(out *AuthorinoSpec) {
+	*out = *in
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(AuthorinoDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDCServer != nil {
+		in, out := &in.OIDCServer, &out.OIDCServer
+		*out = new(AuthorinoOIDCServerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(AuthorinoMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutMilliseconds != nil {
+		in, out := &in.TimeoutMilliseconds, &out.TimeoutMilliseconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OutboundTimeoutMilliseconds != nil {
+		in, out := &in.OutboundTimeoutMilliseconds, &out.OutboundTimeoutMilliseconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DecisionLogs != nil {
+		in, out := &in.DecisionLogs, &out.DecisionLogs
+		*out = new(AuthorinoDecisionLogsSpec)
+		**out = **in
+	}
+	if in.TrustedCABundle != nil {
+		in, out := &in.TrustedCABundle, &out.TrustedCABundle
+		*out = new(TrustedCABundleReference)
+		**out = **in
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(AuthorinoHealthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(AuthorinoServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]AuthorinoVolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoSpec.
+func (in *AuthorinoSpec) DeepCopy() *AuthorinoSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoTLSSpec) DeepCopyInto(out *AuthorinoTLSSpec) {
+	*out = *in
+	if in.CertSecretRef != nil {
+		in, out := &in.CertSecretRef, &out.CertSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoTLSSpec.
+func (in *AuthorinoTLSSpec) DeepCopy() *AuthorinoTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorinoVolumeSpec) DeepCopyInto(out *AuthorinoVolumeSpec) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1.KeyToPath, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorinoVolumeSpec.
+func (in *AuthorinoVolumeSpec) DeepCopy() *AuthorinoVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorinoVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateIdentity) DeepCopyInto(out *ClientCertificateIdentity) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]ClientCertificateAttribute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateIdentity.
+func (in *ClientCertificateIdentity) DeepCopy() *ClientCertificateIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateSpec) DeepCopyInto(out *ClientCertificateSpec) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]ClientCertificateAttribute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateSpec.
+func (in *ClientCertificateSpec) DeepCopy() *ClientCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultRateLimitRate) DeepCopyInto(out *DefaultRateLimitRate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultRateLimitRate.
+func (in *DefaultRateLimitRate) DeepCopy() *DefaultRateLimitRate {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultRateLimitRate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultRateLimitSpec) DeepCopyInto(out *DefaultRateLimitSpec) {
+	*out = *in
+	if in.Rates != nil {
+		in, out := &in.Rates, &out.Rates
+		*out = make([]DefaultRateLimitRate, len(*in))
+		copy(*out, *in)
+	}
+	if in.Counters != nil {
+		in, out := &in.Counters, &out.Counters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultRateLimitSpec.
+func (in *DefaultRateLimitSpec) DeepCopy() *DefaultRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeferredChange) DeepCopyInto(out *DeferredChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeferredChange.
+func (in *DeferredChange) DeepCopy() *DeferredChange {
+	if in == nil {
+		return nil
+	}
+	out := new(DeferredChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluatorCache) DeepCopyInto(out *EvaluatorCache) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluatorCache.
+func (in *EvaluatorCache) DeepCopy() *EvaluatorCache {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluatorCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardSpec) DeepCopyInto(out *GrafanaDashboardSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardSpec.
+func (in *GrafanaDashboardSpec) DeepCopy() *GrafanaDashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteReference) DeepCopyInto(out *HTTPRouteReference) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteReference.
+func (in *HTTPRouteReference) DeepCopy() *HTTPRouteReference {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthServiceStatus) DeepCopyInto(out *HealthServiceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthServiceStatus.
+func (in *HealthServiceStatus) DeepCopy() *HealthServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityReferenceError) DeepCopyInto(out *IdentityReferenceError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityReferenceError.
+func (in *IdentityReferenceError) DeepCopy() *IdentityReferenceError {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityReferenceError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kuadrant) DeepCopyInto(out *Kuadrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kuadrant.
+func (in *Kuadrant) DeepCopy() *Kuadrant {
+	if in == nil {
+		return nil
+	}
+	out := new(Kuadrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Kuadrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KuadrantList) DeepCopyInto(out *KuadrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Kuadrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KuadrantList.
+func (in *KuadrantList) DeepCopy() *KuadrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(KuadrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KuadrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KuadrantSpec) DeepCopyInto(out *KuadrantSpec) {
+	*out = *in
+	if in.Authorino != nil {
+		in, out := &in.Authorino, &out.Authorino
+		*out = new(AuthorinoSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limitador != nil {
+		in, out := &in.Limitador, &out.Limitador
+		*out = new(LimitadorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultRateLimit != nil {
+		in, out := &in.DefaultRateLimit, &out.DefaultRateLimit
+		*out = new(DefaultRateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultAuthPolicy != nil {
+		in, out := &in.DefaultAuthPolicy, &out.DefaultAuthPolicy
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.GrafanaDashboard != nil {
+		in, out := &in.GrafanaDashboard, &out.GrafanaDashboard
+		*out = new(GrafanaDashboardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = new(NetworkPoliciesSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KuadrantSpec.
+func (in *KuadrantSpec) DeepCopy() *KuadrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KuadrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KuadrantStatus) DeepCopyInto(out *KuadrantStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AuthorinoHealthService != nil {
+		in, out := &in.AuthorinoHealthService, &out.AuthorinoHealthService
+		*out = new(HealthServiceStatus)
+		**out = **in
+	}
+	if in.DeferredChanges != nil {
+		in, out := &in.DeferredChanges, &out.DeferredChanges
+		*out = make([]DeferredChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.DefaultRateLimitGateways != nil {
+		in, out := &in.DefaultRateLimitGateways, &out.DefaultRateLimitGateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultAuthPolicyGateways != nil {
+		in, out := &in.DefaultAuthPolicyGateways, &out.DefaultAuthPolicyGateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KuadrantStatus.
+func (in *KuadrantStatus) DeepCopy() *KuadrantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KuadrantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitadorMetricsSpec) DeepCopyInto(out *LimitadorMetricsSpec) {
+	*out = *in
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitadorMetricsSpec.
+func (in *LimitadorMetricsSpec) DeepCopy() *LimitadorMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitadorMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitadorSpec) DeepCopyInto(out *LimitadorSpec) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(LimitadorMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitadorSpec.
+func (in *LimitadorSpec) DeepCopy() *LimitadorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitadorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWindowDay, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPoliciesSpec) DeepCopyInto(out *NetworkPoliciesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPoliciesSpec.
+func (in *NetworkPoliciesSpec) DeepCopy() *NetworkPoliciesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPoliciesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyHierarchy) DeepCopyInto(out *PolicyHierarchy) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyHierarchy.
+func (in *PolicyHierarchy) DeepCopy() *PolicyHierarchy {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyHierarchy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitorSpec) DeepCopyInto(out *ServiceMonitorSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorSpec.
+func (in *ServiceMonitorSpec) DeepCopy() *ServiceMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCABundleReference) DeepCopyInto(out *TrustedCABundleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCABundleReference.
+func (in *TrustedCABundleReference) DeepCopy() *TrustedCABundleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCABundleReference)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+// IdentityProviderSpec defines the issuer of the identities of the AuthPolicies referencing the provider
+type IdentityProviderSpec struct {
+	// Oidc is the OpenID Connect issuer of the JWTs verified by the policies.
+	Oidc authorinov1beta1.Identity_OidcConfig `json:"oidc"`
+
+	// Credentials tells where the policies expect the JWTs in the requests.
+	// If omitted, the JWTs are expected in the Authorization header, prefixed by "Bearer".
+	// +optional
+	Credentials *authorinov1beta1.Credentials `json:"credentials,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IdentityProvider is the Schema for the identityproviders API
+type IdentityProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IdentityProviderSpec `json:"spec,omitempty"`
+}
+
+// Identity returns the identity source of the AuthPolicies referencing the provider, named after the provider
+func (p *IdentityProvider) Identity() *authorinov1beta1.Identity {
+	oidc := p.Spec.Oidc
+	identity := &authorinov1beta1.Identity{
+		Name: p.Name,
+		Oidc: &oidc,
+	}
+	if p.Spec.Credentials != nil {
+		identity.Credentials = *p.Spec.Credentials
+	}
+	return identity
+}
+
+// ResolveIdentityProviders returns the auth scheme with the identity sources of the providers appended, in the order
+// of the providers. The identity sources of the auth scheme must not be named after any of the providers.
+func ResolveIdentityProviders(authScheme kuadrantv1beta1.AuthSchemeSpec, providers []*IdentityProvider) (kuadrantv1beta1.AuthSchemeSpec, error) {
+	if len(providers) == 0 {
+		return authScheme, nil
+	}
+
+	identities := make([]*authorinov1beta1.Identity, 0, len(authScheme.Identity)+len(providers))
+	identities = append(identities, authScheme.Identity...)
+	for _, provider := range providers {
+		for _, identity := range identities {
+			if identity != nil && identity.Name == provider.Name {
+				return authScheme, fmt.Errorf("invalid identity providers. Identity source %s conflicts with the identity provider of the same name", provider.Name)
+			}
+		}
+		identities = append(identities, provider.Identity())
+	}
+
+	authScheme.Identity = identities
+	return authScheme, nil
+}
+
+//+kubebuilder:object:root=true
+
+// IdentityProviderList contains a list of IdentityProvider
+type IdentityProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityProvider{}, &IdentityProviderList{})
+}
@@ -0,0 +1,56 @@
+//go:build unit
+
+package v1beta2
+
+import (
+	"reflect"
+	"testing"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+func TestResolveIdentityProviders(t *testing.T) {
+	keycloak := &IdentityProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "keycloak"},
+		Spec: IdentityProviderSpec{
+			Oidc: authorinov1beta1.Identity_OidcConfig{Endpoint: "https://keycloak.example.com/realms/kuadrant"},
+		},
+	}
+	okta := &IdentityProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "okta"},
+		Spec: IdentityProviderSpec{
+			Oidc:        authorinov1beta1.Identity_OidcConfig{Endpoint: "https://example.okta.com", TTL: 3600},
+			Credentials: &authorinov1beta1.Credentials{In: "custom_header", KeySelector: "X-Okta-Token"},
+		},
+	}
+	apiKeys := &authorinov1beta1.Identity{Name: "api-keys", APIKey: &authorinov1beta1.Identity_APIKey{}}
+	authScheme := kuadrantv1beta1.AuthSchemeSpec{Identity: []*authorinov1beta1.Identity{apiKeys}}
+
+	resolved, err := ResolveIdentityProviders(authScheme, []*IdentityProvider{keycloak, okta})
+	if err != nil {
+		t.Fatalf("ResolveIdentityProviders() error = %v", err)
+	}
+	expected := []*authorinov1beta1.Identity{
+		apiKeys,
+		{Name: "keycloak", Oidc: &authorinov1beta1.Identity_OidcConfig{Endpoint: "https://keycloak.example.com/realms/kuadrant"}},
+		{
+			Name:        "okta",
+			Oidc:        &authorinov1beta1.Identity_OidcConfig{Endpoint: "https://example.okta.com", TTL: 3600},
+			Credentials: authorinov1beta1.Credentials{In: "custom_header", KeySelector: "X-Okta-Token"},
+		},
+	}
+	if !reflect.DeepEqual(resolved.Identity, expected) {
+		t.Errorf("resolved identity = %v, want %v", resolved.Identity, expected)
+	}
+	if len(authScheme.Identity) != 1 {
+		t.Errorf("auth scheme of the policy modified, identity = %v", authScheme.Identity)
+	}
+
+	authScheme.Identity = append(authScheme.Identity, &authorinov1beta1.Identity{Name: "okta", Anonymous: &authorinov1beta1.Identity_Anonymous{}})
+	if _, err := ResolveIdentityProviders(authScheme, []*IdentityProvider{okta}); err == nil {
+		t.Error("ResolveIdentityProviders() succeeded with an identity source named after a provider")
+	}
+}
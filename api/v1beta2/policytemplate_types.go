@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+// PolicyTemplateSpec defines the base config inherited by the policies referencing the template
+type PolicyTemplateSpec struct {
+	// AuthScheme is the base auth scheme of the AuthPolicies referencing the template.
+	// The configs of the policies with the same name as a config of the template override the config of the template.
+	// +optional
+	AuthScheme *kuadrantv1beta1.AuthSchemeSpec `json:"authScheme,omitempty"`
+
+	// Limits are the base limits of the RateLimitPolicies referencing the template, indexed by a unique name.
+	// The limits of the policies with the same name as a limit of the template override the limit of the template.
+	// +optional
+	Limits map[string]Limit `json:"limits,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PolicyTemplate is the Schema for the policytemplates API
+type PolicyTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolicyTemplateSpec `json:"spec,omitempty"`
+}
+
+// ResolveLimits returns the limits of the template overridden by the given limits of a policy
+func (t *PolicyTemplate) ResolveLimits(limits map[string]Limit) map[string]Limit {
+	return MergeLimits(t.Spec.Limits, limits)
+}
+
+// ResolveAuthScheme returns the auth scheme of the template overridden by the given auth scheme of a policy.
+// The conditions of the template and the policy must all match.
+func (t *PolicyTemplate) ResolveAuthScheme(authScheme kuadrantv1beta1.AuthSchemeSpec) kuadrantv1beta1.AuthSchemeSpec {
+	if t.Spec.AuthScheme == nil {
+		return authScheme
+	}
+	base := t.Spec.AuthScheme
+
+	resolved := kuadrantv1beta1.AuthSchemeSpec{
+		Conditions:        append(append([]authorinov1beta1.JSONPattern{}, base.Conditions...), authScheme.Conditions...),
+		Identity:          resolveNamedConfigs(base.Identity, authScheme.Identity, func(c *authorinov1beta1.Identity) string { return c.Name }),
+		Metadata:          resolveNamedConfigs(base.Metadata, authScheme.Metadata, func(c *authorinov1beta1.Metadata) string { return c.Name }),
+		Authorization:     resolveNamedConfigs(base.Authorization, authScheme.Authorization, func(c *authorinov1beta1.Authorization) string { return c.Name }),
+		Response:          resolveNamedConfigs(base.Response, authScheme.Response, func(c *authorinov1beta1.Response) string { return c.Name }),
+		DenyWith:          base.DenyWith,
+		IdentityOrder:     base.IdentityOrder,
+		ClientCertificate: base.ClientCertificate,
+	}
+
+	if len(base.Patterns)+len(authScheme.Patterns) > 0 {
+		resolved.Patterns = make(map[string]authorinov1beta1.JSONPatternExpressions, len(base.Patterns)+len(authScheme.Patterns))
+		for name, pattern := range base.Patterns {
+			resolved.Patterns[name] = pattern
+		}
+		for name, pattern := range authScheme.Patterns {
+			resolved.Patterns[name] = pattern
+		}
+	}
+
+	if authScheme.DenyWith != nil {
+		resolved.DenyWith = authScheme.DenyWith
+	}
+
+	if len(authScheme.IdentityOrder) > 0 {
+		resolved.IdentityOrder = authScheme.IdentityOrder
+	}
+
+	if authScheme.ClientCertificate != nil {
+		resolved.ClientCertificate = authScheme.ClientCertificate
+	}
+
+	if len(resolved.Conditions) == 0 {
+		resolved.Conditions = nil
+	}
+
+	return resolved
+}
+
+// resolveNamedConfigs replaces the base configs by the overrides with the same name,
+// keeping the order of the base configs followed by the remaining overrides
+func resolveNamedConfigs[T any](base, overrides []*T, name func(*T) string) []*T {
+	if len(base) == 0 {
+		return overrides
+	}
+
+	overridesByName := make(map[string]*T, len(overrides))
+	for _, override := range overrides {
+		overridesByName[name(override)] = override
+	}
+
+	resolved := make([]*T, 0, len(base)+len(overrides))
+	for _, config := range base {
+		if override, ok := overridesByName[name(config)]; ok {
+			resolved = append(resolved, override)
+			delete(overridesByName, name(config))
+			continue
+		}
+		resolved = append(resolved, config)
+	}
+	for _, override := range overrides {
+		if _, ok := overridesByName[name(override)]; ok {
+			resolved = append(resolved, override)
+		}
+	}
+	return resolved
+}
+
+//+kubebuilder:object:root=true
+
+// PolicyTemplateList contains a list of PolicyTemplate
+type PolicyTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyTemplate{}, &PolicyTemplateList{})
+}
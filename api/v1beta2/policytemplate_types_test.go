@@ -0,0 +1,88 @@
+//go:build unit
+
+package v1beta2
+
+import (
+	"reflect"
+	"testing"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+func TestPolicyTemplateResolveLimits(t *testing.T) {
+	rate := func(limit int) []Rate { return []Rate{{Limit: limit, Duration: 1, Unit: TimeUnit("minute")}} }
+
+	template := &PolicyTemplate{
+		Spec: PolicyTemplateSpec{
+			Limits: map[string]Limit{
+				"global": {Rates: rate(100)},
+				"toys":   {Rates: rate(50)},
+			},
+		},
+	}
+
+	resolved := template.ResolveLimits(map[string]Limit{
+		"toys":   {Rates: rate(10)},
+		"assets": {Rates: rate(5)},
+	})
+
+	expected := map[string]Limit{
+		"global": {Rates: rate(100)},
+		"toys":   {Rates: rate(10)},
+		"assets": {Rates: rate(5)},
+	}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("unexpected limits: got %v, want %v", resolved, expected)
+	}
+
+	if len(template.Spec.Limits) != 2 || template.Spec.Limits["toys"].Rates[0].Limit != 50 {
+		t.Errorf("the limits of the template were modified: %v", template.Spec.Limits)
+	}
+
+	if limits := (&PolicyTemplate{}).ResolveLimits(expected); !reflect.DeepEqual(limits, expected) {
+		t.Errorf("unexpected limits of an empty template: got %v, want %v", limits, expected)
+	}
+}
+
+func TestPolicyTemplateResolveAuthScheme(t *testing.T) {
+	identity := func(name string, credentials authorinov1beta1.Credentials_In) *authorinov1beta1.Identity {
+		return &authorinov1beta1.Identity{Name: name, Credentials: authorinov1beta1.Credentials{In: credentials}}
+	}
+	condition := func(selector string) authorinov1beta1.JSONPattern {
+		return authorinov1beta1.JSONPattern{JSONPatternExpression: authorinov1beta1.JSONPatternExpression{Selector: selector, Operator: "eq", Value: "true"}}
+	}
+	denyWith := &authorinov1beta1.DenyWith{Unauthenticated: &authorinov1beta1.DenyWithSpec{Code: 302}}
+	clientCertificate := &kuadrantv1beta1.ClientCertificateSpec{Attributes: []kuadrantv1beta1.ClientCertificateAttribute{"cn"}}
+
+	template := &PolicyTemplate{
+		Spec: PolicyTemplateSpec{
+			AuthScheme: &kuadrantv1beta1.AuthSchemeSpec{
+				Conditions:        []authorinov1beta1.JSONPattern{condition("template")},
+				Identity:          []*authorinov1beta1.Identity{identity("api-key", "authorization_header"), identity("sso", "authorization_header")},
+				DenyWith:          denyWith,
+				ClientCertificate: clientCertificate,
+			},
+		},
+	}
+
+	resolved := template.ResolveAuthScheme(kuadrantv1beta1.AuthSchemeSpec{
+		Conditions: []authorinov1beta1.JSONPattern{condition("policy")},
+		Identity:   []*authorinov1beta1.Identity{identity("anonymous", "authorization_header"), identity("api-key", "custom_header")},
+	})
+
+	expected := kuadrantv1beta1.AuthSchemeSpec{
+		Conditions:        []authorinov1beta1.JSONPattern{condition("template"), condition("policy")},
+		Identity:          []*authorinov1beta1.Identity{identity("api-key", "custom_header"), identity("sso", "authorization_header"), identity("anonymous", "authorization_header")},
+		DenyWith:          denyWith,
+		ClientCertificate: clientCertificate,
+	}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("unexpected auth scheme: got %+v, want %+v", resolved, expected)
+	}
+
+	if len(template.Spec.AuthScheme.Conditions) != 1 || template.Spec.AuthScheme.Identity[0].Credentials.In != "authorization_header" {
+		t.Errorf("the auth scheme of the template was modified: %+v", template.Spec.AuthScheme)
+	}
+}
@@ -18,10 +18,12 @@ package v1beta2
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -59,9 +61,11 @@ type TimeUnit string
 // Rate defines the actual rate limit that will be used when there is a match
 type Rate struct {
 	// Limit defines the max value allowed for a given period of time
+	// +kubebuilder:validation:Minimum=1
 	Limit int `json:"limit"`
 
 	// Duration defines the time period for which the Limit specified above applies.
+	// +kubebuilder:validation:Minimum=1
 	Duration int `json:"duration"`
 
 	// Duration defines the time uni
@@ -69,6 +73,18 @@ type Rate struct {
 	Unit TimeUnit `json:"unit"`
 }
 
+var timeUnitSeconds = map[TimeUnit]int{
+	TimeUnit("second"): 1,
+	TimeUnit("minute"): 60,
+	TimeUnit("hour"):   60 * 60,
+	TimeUnit("day"):    60 * 60 * 24,
+}
+
+// Window returns the length of the time window of the rate, in seconds
+func (r Rate) Window() int {
+	return timeUnitSeconds[r.Unit] * r.Duration
+}
+
 // RouteSelector defines semantics for matching an HTTP request based on conditions
 // https://gateway-api.sigs.k8s.io/v1alpha2/references/spec/#gateway.networking.k8s.io/v1beta1.HTTPRouteSpec
 type WhenCondition struct {
@@ -112,6 +128,44 @@ func (l Limit) CountersAsStringList() []string {
 	return common.Map(l.Counters, func(counter ContextSelector) string { return string(counter) })
 }
 
+// +kubebuilder:validation:Enum:=atomic;merge
+type DefaultsStrategy string
+
+const (
+	// AtomicDefaultsStrategy applies the limits of a policy targeting a Gateway only to the HTTPRoutes without a policy
+	AtomicDefaultsStrategy DefaultsStrategy = "atomic"
+
+	// MergeDefaultsStrategy also merges the limits of a policy targeting a Gateway into the policies of the HTTPRoutes
+	MergeDefaultsStrategy DefaultsStrategy = "merge"
+)
+
+// +kubebuilder:validation:Enum:=open;closed
+type FailureMode string
+
+const (
+	// FailOpen lets the requests through when the rate limit service is unavailable
+	FailOpen FailureMode = "open"
+
+	// FailClosed denies the requests when the rate limit service is unavailable
+	FailClosed FailureMode = "closed"
+)
+
+// MergeLimits returns the default limits overridden by the limits with the same name
+func MergeLimits(defaults, limits map[string]Limit) map[string]Limit {
+	if len(defaults) == 0 {
+		return limits
+	}
+
+	merged := make(map[string]Limit, len(defaults)+len(limits))
+	for name, limit := range defaults {
+		merged[name] = limit
+	}
+	for name, limit := range limits {
+		merged[name] = limit
+	}
+	return merged
+}
+
 // RateLimitPolicySpec defines the desired state of RateLimitPolicy
 type RateLimitPolicySpec struct {
 	// TargetRef identifies an API object to apply policy to.
@@ -120,6 +174,36 @@ type RateLimitPolicySpec struct {
 	// Limits holds the struct of limits indexed by a unique name
 	// +optional
 	Limits map[string]Limit `json:"limits,omitempty"`
+
+	// TemplateRef is the reference to a PolicyTemplate in the same namespace whose limits are inherited by the policy.
+	// +optional
+	TemplateRef *corev1.LocalObjectReference `json:"templateRef,omitempty"`
+
+	// DefaultsStrategy defines how the limits of a policy targeting a Gateway apply to the HTTPRoutes of the gateway.
+	// With `atomic`, the limits only apply to the routes not targeted by a policy of their own.
+	// With `merge`, the limits are also merged into the limits of the policies targeting the routes,
+	// the limits of the route policies with the same name taking precedence.
+	// Only supported by policies targeting a Gateway.
+	// +kubebuilder:default:=atomic
+	// +optional
+	DefaultsStrategy DefaultsStrategy `json:"defaultsStrategy,omitempty"`
+
+	// IgnoreGatewayDefaults opts the policy out of the limits merged from the policies targeting the parent gateways
+	// of the targeted HTTPRoute.
+	// Only supported by policies targeting a HTTPRoute.
+	// +optional
+	IgnoreGatewayDefaults bool `json:"ignoreGatewayDefaults,omitempty"`
+
+	// FailureMode tells whether the requests are let through (open) or denied (closed) when Limitador is unavailable.
+	// The gateways enforce a single mode for all their policies: closed if any of the policies is closed.
+	// +kubebuilder:default:=open
+	// +optional
+	FailureMode FailureMode `json:"failureMode,omitempty"`
+
+	// ExpiresAt is the time, in RFC 3339 format, the policy is deleted at, e.g. for a temporary limit set
+	// during an incident.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // RateLimitPolicyStatus defines the observed state of RateLimitPolicy
@@ -135,6 +219,33 @@ type RateLimitPolicyStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Limitador is the reference to the Limitador instance the limits of the policy are bound to.
+	// +optional
+	Limitador *LimitadorReference `json:"limitador,omitempty"`
+
+	// LimitsNamespaces are the namespaces of the limits of the policy in Limitador, one for each gateway enforcing the
+	// policy when the counters are isolated per gateway, or a single one shared by the gateways otherwise.
+	// +optional
+	LimitsNamespaces []string `json:"limitsNamespaces,omitempty"`
+
+	// CounterDomain is the effective domain of the counters of the limits of the policy, set by the kuadrant instance.
+	// Empty if the counters are isolated per gateway.
+	// +optional
+	CounterDomain string `json:"counterDomain,omitempty"`
+
+	// FailureMode is the effective failure mode of the gateways enforcing the policy, closed if any of their policies is closed.
+	// +optional
+	FailureMode FailureMode `json:"failureMode,omitempty"`
+}
+
+// LimitadorReference identifies a Limitador instance
+type LimitadorReference struct {
+	// Name of the Limitador instance.
+	Name string `json:"name"`
+
+	// Namespace of the Limitador instance.
+	Namespace string `json:"namespace"`
 }
 
 func (s *RateLimitPolicyStatus) Equals(other *RateLimitPolicyStatus, logger logr.Logger) bool {
@@ -155,6 +266,27 @@ func (s *RateLimitPolicyStatus) Equals(other *RateLimitPolicyStatus, logger logr
 		return false
 	}
 
+	if diff := cmp.Diff(s.Limitador, other.Limitador); diff != "" {
+		logger.V(1).Info("Limitador not equal", "difference", diff)
+		return false
+	}
+
+	if diff := cmp.Diff(s.LimitsNamespaces, other.LimitsNamespaces); diff != "" {
+		logger.V(1).Info("LimitsNamespaces not equal", "difference", diff)
+		return false
+	}
+
+	if diff := cmp.Diff(s.CounterDomain, other.CounterDomain); diff != "" {
+		logger.V(1).Info("CounterDomain not equal", "difference", diff)
+		return false
+	}
+
+	if s.FailureMode != other.FailureMode {
+		diff := cmp.Diff(s.FailureMode, other.FailureMode)
+		logger.V(1).Info("FailureMode not equal", "difference", diff)
+		return false
+	}
+
 	return true
 }
 
@@ -171,18 +303,22 @@ type RateLimitPolicy struct {
 }
 
 func (r *RateLimitPolicy) Validate() error {
-	if r.Spec.TargetRef.Group != ("gateway.networking.k8s.io") {
-		return fmt.Errorf("invalid targetRef.Group %s. The only supported group is gateway.networking.k8s.io", r.Spec.TargetRef.Group)
-	}
-
-	if r.Spec.TargetRef.Kind != ("HTTPRoute") && r.Spec.TargetRef.Kind != ("Gateway") {
-		return fmt.Errorf("invalid targetRef.Kind %s. The only supported kind types are HTTPRoute and Gateway", r.Spec.TargetRef.Kind)
+	if err := common.ValidateTargetRef(r.Spec.TargetRef); err != nil {
+		return err
 	}
 
 	if r.Spec.TargetRef.Namespace != nil && string(*r.Spec.TargetRef.Namespace) != r.Namespace {
 		return fmt.Errorf("invalid targetRef.Namespace %s. Currently only supporting references to the same namespace", *r.Spec.TargetRef.Namespace)
 	}
 
+	if r.Spec.TargetRef.Kind != ("Gateway") && r.Spec.DefaultsStrategy == MergeDefaultsStrategy {
+		return fmt.Errorf("defaults strategy %s only supported when targeting a Gateway", MergeDefaultsStrategy)
+	}
+
+	if r.Spec.TargetRef.Kind != ("HTTPRoute") && r.Spec.IgnoreGatewayDefaults {
+		return fmt.Errorf("ignoring the gateway defaults only supported when targeting a HTTPRoute")
+	}
+
 	// prevents usage of routeSelectors in a gateway RLP
 	if r.Spec.TargetRef.Kind == ("Gateway") {
 		for _, limit := range r.Spec.Limits {
@@ -192,6 +328,35 @@ func (r *RateLimitPolicy) Validate() error {
 		}
 	}
 
+	return validateLimitRates(r.Spec.Limits)
+}
+
+// validateLimitRates rejects the rates Limitador cannot enforce as intended:
+// non-positive limits or durations, and several rates of a limit over the same time window
+func validateLimitRates(limits map[string]Limit) error {
+	limitNames := make([]string, 0, len(limits))
+	for name := range limits {
+		limitNames = append(limitNames, name)
+	}
+	sort.Strings(limitNames)
+
+	for _, name := range limitNames {
+		windows := make(map[int]Rate)
+		for _, rate := range limits[name].Rates {
+			if rate.Limit < 1 {
+				return fmt.Errorf("invalid limit %s: rate limit %d must be greater than zero", name, rate.Limit)
+			}
+			if rate.Duration < 1 {
+				return fmt.Errorf("invalid limit %s: rate duration %d must be greater than zero", name, rate.Duration)
+			}
+			if other, ok := windows[rate.Window()]; ok {
+				return fmt.Errorf("invalid limit %s: rates %d per %d %s and %d per %d %s have the same time window of %ds",
+					name, other.Limit, other.Duration, other.Unit, rate.Limit, rate.Duration, rate.Unit, rate.Window())
+			}
+			windows[rate.Window()] = rate
+		}
+	}
+
 	return nil
 }
 
@@ -207,6 +372,14 @@ func (r *RateLimitPolicy) TargetKey() client.ObjectKey {
 	}
 }
 
+// GetFailureMode returns the failure mode of the policy, open unless set
+func (r *RateLimitPolicy) GetFailureMode() FailureMode {
+	if r.Spec.FailureMode == "" {
+		return FailOpen
+	}
+	return r.Spec.FailureMode
+}
+
 //+kubebuilder:object:root=true
 
 // RateLimitPolicyList contains a list of RateLimitPolicy
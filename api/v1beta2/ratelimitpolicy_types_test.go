@@ -91,4 +91,81 @@ func TestRateLimitPolicyValidation(t *testing.T) {
 	if !strings.Contains(err.Error(), "invalid targetRef.Namespace") {
 		t.Fatalf(`rlp.Validate() did not return expected error. Instead: %v`, err)
 	}
+
+	// valid gateway rlp merging its limits into the route rlps
+	rlp = testBuildBasicGatewayRLP(name)
+	rlp.Spec.DefaultsStrategy = MergeDefaultsStrategy
+	err = rlp.Validate()
+	if err != nil {
+		t.Fatalf(`rlp.Validate() returned error "%v", wanted nil`, err)
+	}
+
+	// merge defaults strategy in a httproute rlp
+	rlp = testBuildBasicHTTPRouteRLP(name)
+	rlp.Spec.DefaultsStrategy = MergeDefaultsStrategy
+	err = rlp.Validate()
+	if err == nil {
+		t.Fatal(`rlp.Validate() did not return error and should`)
+	}
+	if !strings.Contains(err.Error(), "only supported when targeting a Gateway") {
+		t.Fatalf(`rlp.Validate() did not return expected error. Instead: %v`, err)
+	}
+
+	// ignoring the gateway defaults in a gateway rlp
+	rlp = testBuildBasicGatewayRLP(name)
+	rlp.Spec.IgnoreGatewayDefaults = true
+	err = rlp.Validate()
+	if err == nil {
+		t.Fatal(`rlp.Validate() did not return error and should`)
+	}
+	if !strings.Contains(err.Error(), "only supported when targeting a HTTPRoute") {
+		t.Fatalf(`rlp.Validate() did not return expected error. Instead: %v`, err)
+	}
+
+	// non-positive limit
+	rlp = testBuildBasicHTTPRouteRLP(name)
+	rlp.Spec.Limits = map[string]Limit{"toys": {Rates: []Rate{{Limit: 0, Duration: 1, Unit: TimeUnit("minute")}}}}
+	err = rlp.Validate()
+	if err == nil {
+		t.Fatal(`rlp.Validate() did not return error and should`)
+	}
+	if !strings.Contains(err.Error(), "rate limit 0 must be greater than zero") {
+		t.Fatalf(`rlp.Validate() did not return expected error. Instead: %v`, err)
+	}
+
+	// non-positive duration
+	rlp = testBuildBasicHTTPRouteRLP(name)
+	rlp.Spec.Limits = map[string]Limit{"toys": {Rates: []Rate{{Limit: 5, Duration: -1, Unit: TimeUnit("minute")}}}}
+	err = rlp.Validate()
+	if err == nil {
+		t.Fatal(`rlp.Validate() did not return error and should`)
+	}
+	if !strings.Contains(err.Error(), "rate duration -1 must be greater than zero") {
+		t.Fatalf(`rlp.Validate() did not return expected error. Instead: %v`, err)
+	}
+
+	// rates over the same time window
+	rlp = testBuildBasicHTTPRouteRLP(name)
+	rlp.Spec.Limits = map[string]Limit{"toys": {Rates: []Rate{
+		{Limit: 50, Duration: 1, Unit: TimeUnit("minute")},
+		{Limit: 10, Duration: 60, Unit: TimeUnit("second")},
+	}}}
+	err = rlp.Validate()
+	if err == nil {
+		t.Fatal(`rlp.Validate() did not return error and should`)
+	}
+	if !strings.Contains(err.Error(), "have the same time window of 60s") {
+		t.Fatalf(`rlp.Validate() did not return expected error. Instead: %v`, err)
+	}
+
+	// rates over different time windows
+	rlp = testBuildBasicHTTPRouteRLP(name)
+	rlp.Spec.Limits = map[string]Limit{"toys": {Rates: []Rate{
+		{Limit: 50, Duration: 1, Unit: TimeUnit("minute")},
+		{Limit: 1000, Duration: 1, Unit: TimeUnit("hour")},
+	}}}
+	err = rlp.Validate()
+	if err != nil {
+		t.Fatalf(`rlp.Validate() returned error "%v", wanted nil`, err)
+	}
 }
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AuthConfigEventMapper is an EventHandler that maps AuthConfig events to the policy the AuthConfig was generated from
+// and to the policies whose AuthConfigs claim overlapping hosts
+type AuthConfigEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *AuthConfigEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	authConfig, ok := obj.(*authorinov1beta1.AuthConfig)
+	if !ok {
+		m.Logger.Info("MapToAuthPolicy: object is not an AuthConfig", "object", client.ObjectKeyFromObject(obj))
+		return []reconcile.Request{}
+	}
+
+	policyKey, ok := authConfigPolicyKey(authConfig)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{{NamespacedName: policyKey}}
+
+	conflicting, err := conflictingAuthConfigs(context.TODO(), m.Client, authConfig)
+	if err != nil {
+		m.Logger.V(1).Info("MapToAuthPolicy: failed to list authconfigs", "error", err)
+		return requests
+	}
+	for idx := range conflicting {
+		otherPolicyKey, _ := authConfigPolicyKey(&conflicting[idx])
+		m.Logger.V(1).Info("MapToAuthPolicy", "authconfig", client.ObjectKeyFromObject(authConfig), "authpolicy", otherPolicyKey)
+		requests = append(requests, reconcile.Request{NamespacedName: otherPolicyKey})
+	}
+
+	return requests
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// AuthConfigReadyTimeout is the delay after which an AuthConfig written by the operator and not yet ready
+// is counted as never ready, read from the AUTHCONFIG_READY_TIMEOUT_SECONDS env var
+var AuthConfigReadyTimeout = authConfigReadyTimeoutFromEnv(300)
+
+func authConfigReadyTimeoutFromEnv(def int) time.Duration {
+	seconds, err := strconv.Atoi(common.FetchEnv("AUTHCONFIG_READY_TIMEOUT_SECONDS", strconv.Itoa(def)))
+	if err != nil || seconds < 1 {
+		seconds = def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// authConfigReadiness measures the time the AuthConfigs written by the operator take to be ready in Authorino
+var authConfigReadiness = newAuthConfigReadinessTracker(AuthConfigReadyTimeout)
+
+// authConfigReadinessTracker tracks the AuthConfigs written and not yet observed ready, with the time of their last write
+type authConfigReadinessTracker struct {
+	mu      sync.Mutex
+	pending map[client.ObjectKey]time.Time
+	timeout time.Duration
+	now     func() time.Time
+}
+
+func newAuthConfigReadinessTracker(timeout time.Duration) *authConfigReadinessTracker {
+	return &authConfigReadinessTracker{
+		pending: make(map[client.ObjectKey]time.Time),
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// Written starts, or restarts, measuring the readiness of an AuthConfig created or updated by the operator
+func (t *authConfigReadinessTracker) Written(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[key] = t.now()
+}
+
+// Forget stops measuring the readiness of an AuthConfig, e.g. deleted
+func (t *authConfigReadinessTracker) Forget(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, key)
+}
+
+// Observe records the readiness of an AuthConfig. The latency is exported once the AuthConfig is ready, or the
+// timeout counted once expired. Returns the delay before the timeout expires if still pending, or 0, and whether the
+// timeout has just expired.
+func (t *authConfigReadinessTracker) Observe(key client.ObjectKey, ready bool) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	written, ok := t.pending[key]
+	if !ok {
+		return 0, false
+	}
+
+	elapsed := t.now().Sub(written)
+	switch {
+	case ready:
+		authConfigReadyLatency.Observe(elapsed.Seconds())
+	case elapsed >= t.timeout:
+		authConfigReadyTimeouts.Inc()
+		delete(t.pending, key)
+		return 0, true
+	default:
+		return t.timeout - elapsed, false
+	}
+
+	delete(t.pending, key)
+	return 0, false
+}
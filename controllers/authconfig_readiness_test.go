@@ -0,0 +1,45 @@
+//go:build unit
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAuthConfigReadinessTrackerObserve(t *testing.T) {
+	now := time.Now()
+	tracker := newAuthConfigReadinessTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+	key := client.ObjectKey{Namespace: "kuadrant", Name: "ap-ns-ap"}
+
+	if requeue, timedOut := tracker.Observe(key, false); requeue != 0 || timedOut {
+		t.Errorf("expected the authconfig not written by the operator to be ignored, got %s, %t", requeue, timedOut)
+	}
+
+	tracker.Written(key)
+	now = now.Add(20 * time.Second)
+	if requeue, timedOut := tracker.Observe(key, false); requeue != 40*time.Second || timedOut {
+		t.Errorf("expected the authconfig to be checked again on timeout, got %s, %t", requeue, timedOut)
+	}
+
+	timeouts := testutil.ToFloat64(authConfigReadyTimeouts)
+	now = now.Add(time.Minute)
+	if requeue, timedOut := tracker.Observe(key, false); requeue != 0 || !timedOut {
+		t.Errorf("expected the readiness of the authconfig to time out, got %s, %t", requeue, timedOut)
+	}
+	if count := testutil.ToFloat64(authConfigReadyTimeouts); count != timeouts+1 {
+		t.Errorf("expected the timeout to be counted once, got %v", count-timeouts)
+	}
+	if requeue, timedOut := tracker.Observe(key, false); requeue != 0 || timedOut {
+		t.Errorf("expected the timeout to be reported once, got %s, %t", requeue, timedOut)
+	}
+
+	tracker.Written(key)
+	if requeue, timedOut := tracker.Observe(key, true); requeue != 0 || timedOut {
+		t.Errorf("expected the authconfig ready to be forgotten, got %s, %t", requeue, timedOut)
+	}
+}
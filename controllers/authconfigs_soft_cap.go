@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	authorinoopapi "github.com/kuadrant/authorino-operator/api/v1beta1"
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const AuthConfigsSoftCapExceededConditionType string = "AuthConfigsSoftCapExceeded"
+
+const (
+	defaultAuthConfigsSoftCap = 1000
+
+	// maxContributingPolicies is the number of policies contributing the most AuthConfigs listed in the condition
+	maxContributingPolicies = 10
+)
+
+// AuthConfigsSoftCap is the number of AuthConfigs served by an Authorino instance above which a warning is reported,
+// read from the AUTHCONFIGS_SOFT_CAP env var. A value of zero or less disables the warning.
+var AuthConfigsSoftCap = authConfigsSoftCapFromEnv()
+
+func authConfigsSoftCapFromEnv() int {
+	softCap, err := strconv.Atoi(common.FetchEnv("AUTHCONFIGS_SOFT_CAP", strconv.Itoa(defaultAuthConfigsSoftCap)))
+	if err != nil {
+		return defaultAuthConfigsSoftCap
+	}
+	return softCap
+}
+
+// servedAuthConfigs returns the AuthConfigs watched by an Authorino instance, i.e. the ones in its namespace unless
+// cluster-wide, matching its label selectors
+func servedAuthConfigs(ctx context.Context, cli client.Client, authorino *authorinoopapi.Authorino) ([]authorinoapi.AuthConfig, error) {
+	listOptions := []client.ListOption{client.MatchingLabels(authConfigLabelsForAuthorino(authorino))}
+	if !authorino.Spec.ClusterWide {
+		listOptions = append(listOptions, client.InNamespace(authorino.Namespace))
+	}
+	authConfigList := &authorinoapi.AuthConfigList{}
+	if err := cli.List(ctx, authConfigList, listOptions...); err != nil {
+		return nil, err
+	}
+	return authConfigList.Items, nil
+}
+
+// contributingPolicies returns the AuthPolicies generating the most AuthConfigs, with the number of their AuthConfigs
+func contributingPolicies(authConfigs []authorinoapi.AuthConfig) []string {
+	counts := make(map[client.ObjectKey]int)
+	for idx := range authConfigs {
+		if policyKey, ok := authConfigPolicyKey(&authConfigs[idx]); ok {
+			counts[policyKey]++
+		}
+	}
+
+	policyKeys := make([]client.ObjectKey, 0, len(counts))
+	for policyKey := range counts {
+		policyKeys = append(policyKeys, policyKey)
+	}
+	sort.Slice(policyKeys, func(i, j int) bool {
+		if counts[policyKeys[i]] != counts[policyKeys[j]] {
+			return counts[policyKeys[i]] > counts[policyKeys[j]]
+		}
+		return policyKeys[i].String() < policyKeys[j].String()
+	})
+	if len(policyKeys) > maxContributingPolicies {
+		policyKeys = policyKeys[:maxContributingPolicies]
+	}
+
+	return common.Map(policyKeys, func(policyKey client.ObjectKey) string {
+		return fmt.Sprintf("%s (%d)", policyKey, counts[policyKey])
+	})
+}
+
+// authConfigsSoftCapExceededCondition returns a warning condition if the number of AuthConfigs served by any of the
+// Authorino instances of a kuadrant instance, including the ones pinned by its gateways, exceeds the soft cap
+func authConfigsSoftCapExceededCondition(ctx context.Context, cli client.Client, kuadrantNamespace string) (*metav1.Condition, error) {
+	if AuthConfigsSoftCap <= 0 {
+		return nil, nil
+	}
+
+	pinned, err := pinnedAuthorinoInstances(ctx, cli, kuadrantNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	exceeded := make([]string, 0)
+	for _, name := range append([]string{"authorino"}, pinned...) {
+		authorino := &authorinoopapi.Authorino{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: kuadrantNamespace}, authorino); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		authConfigs, err := servedAuthConfigs(ctx, cli, authorino)
+		if err != nil {
+			return nil, err
+		}
+		if len(authConfigs) <= AuthConfigsSoftCap {
+			continue
+		}
+		reason := fmt.Sprintf("Authorino %s serves %d AuthConfigs", client.ObjectKeyFromObject(authorino), len(authConfigs))
+		if policies := contributingPolicies(authConfigs); len(policies) > 0 {
+			reason = fmt.Sprintf("%s, mostly generated from %s", reason, strings.Join(policies, ", "))
+		}
+		exceeded = append(exceeded, reason)
+	}
+	if len(exceeded) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    AuthConfigsSoftCapExceededConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthConfigsSoftCapExceeded",
+		Message: fmt.Sprintf("Above the soft cap of %d AuthConfigs per Authorino instance: %s", AuthConfigsSoftCap, strings.Join(exceeded, "; ")),
+	}, nil
+}
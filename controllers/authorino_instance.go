@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	authorinoopapi "github.com/kuadrant/authorino-operator/api/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const APAuthorinoInstanceNotReadyConditionType string = "AuthorinoInstanceNotReady"
+
+// pinnedAuthorinoInstance returns the name of the Authorino instance, in the namespace of the kuadrant instance,
+// the AuthPolicies of a gateway are pinned to
+func pinnedAuthorinoInstance(gw client.Object) (string, bool) {
+	name, ok := gw.GetAnnotations()[common.AuthorinoInstanceAnnotation]
+	return name, ok && name != ""
+}
+
+// pinnedAuthorinoInstances returns the names of the Authorino instances pinned by the gateways of a kuadrant instance
+func pinnedAuthorinoInstances(ctx context.Context, cli client.Client, kuadrantNamespace string) ([]string, error) {
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := cli.List(ctx, gwList); err != nil {
+		return nil, err
+	}
+
+	unique := make(map[string]struct{})
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if namespace, err := common.GetKuadrantNamespace(gw); err != nil || namespace != kuadrantNamespace {
+			continue
+		}
+		if name, ok := pinnedAuthorinoInstance(gw); ok {
+			unique[name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// servingAuthorinoInstance returns the Authorino instance serving the AuthConfig of a policy: the instance pinned by
+// the targeted gateway, or the one of the kuadrant instance. Returns nil if unknown or not found.
+func (r *AuthPolicyReconciler) servingAuthorinoInstance(ctx context.Context, ap *api.AuthPolicy) (*authorinoopapi.Authorino, error) {
+	name := "authorino"
+	kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(ap)
+
+	gw, err := common.GetGatewayFromPolicyTargetRef(ctx, r.Client(), ap)
+	if err == nil {
+		if pinned, ok := pinnedAuthorinoInstance(gw); ok {
+			name = pinned
+		}
+		if !isSet {
+			kuadrantNamespace, err = common.GetKuadrantNamespace(gw)
+		}
+	}
+	if err != nil && !isSet {
+		return nil, nil
+	}
+
+	authorino := &authorinoopapi.Authorino{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: name, Namespace: kuadrantNamespace}, authorino); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return authorino, nil
+}
+
+// validatePinnedAuthorinoInstance checks the Authorino instance pinned by the gateway of a policy exists
+func (r *AuthPolicyReconciler) validatePinnedAuthorinoInstance(ctx context.Context, ap *api.AuthPolicy) error {
+	gw, err := common.GetGatewayFromPolicyTargetRef(ctx, r.Client(), ap)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	name, ok := pinnedAuthorinoInstance(gw)
+	if !ok {
+		return nil
+	}
+
+	kuadrantNamespace, err := common.GetKuadrantNamespace(gw)
+	if err != nil {
+		// the gateway is not managed by kuadrant yet
+		return nil
+	}
+
+	authorino := &authorinoopapi.Authorino{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: name, Namespace: kuadrantNamespace}, authorino); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("authorino instance %s/%s pinned by the gateway %s not found", kuadrantNamespace, name, client.ObjectKeyFromObject(gw))
+		}
+		return err
+	}
+	return nil
+}
+
+// authorinoInstanceNotReadyCondition returns a condition if the Authorino instance pinned by the gateway of a policy
+// is not ready, or nil
+func (r *AuthPolicyReconciler) authorinoInstanceNotReadyCondition(ctx context.Context, ap *api.AuthPolicy) (*metav1.Condition, error) {
+	gw, err := common.GetGatewayFromPolicyTargetRef(ctx, r.Client(), ap)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if _, ok := pinnedAuthorinoInstance(gw); !ok {
+		return nil, nil
+	}
+
+	authorino, err := r.servingAuthorinoInstance(ctx, ap)
+	if err != nil || authorino == nil || common.IsAuthorinoReady(authorino) {
+		return nil, err
+	}
+
+	return &metav1.Condition{
+		Type:    APAuthorinoInstanceNotReadyConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthorinoNotReady",
+		Message: fmt.Sprintf("The Authorino instance %s pinned by the gateway %s is not ready", client.ObjectKeyFromObject(authorino), client.ObjectKeyFromObject(gw)),
+	}, nil
+}
+
+// authConfigLabelsForAuthorino returns the labels of the AuthConfigs selected by an Authorino instance,
+// or nil if the instance selects all the AuthConfigs or uses a selector other than equality-based
+func authConfigLabelsForAuthorino(authorino *authorinoopapi.Authorino) map[string]string {
+	if authorino == nil || authorino.Spec.AuthConfigLabelSelectors == "" {
+		return nil
+	}
+	selectorLabels, err := labels.ConvertSelectorToLabelsMap(authorino.Spec.AuthConfigLabelSelectors)
+	if err != nil {
+		return nil
+	}
+	return selectorLabels
+}
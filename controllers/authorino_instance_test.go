@@ -0,0 +1,143 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	authorinoopv1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestPinnedAuthorinoInstances(t *testing.T) {
+	pinnedA := testGateway("pinned-a")
+	pinnedA.Annotations = map[string]string{common.KuadrantNamespaceLabel: "kuadrant-system", common.AuthorinoInstanceAnnotation: "tenant-a"}
+	pinnedB := testGateway("pinned-b")
+	pinnedB.Annotations = map[string]string{common.KuadrantNamespaceLabel: "kuadrant-system", common.AuthorinoInstanceAnnotation: "tenant-b"}
+	pinnedAgain := testGateway("pinned-again")
+	pinnedAgain.Annotations = map[string]string{common.KuadrantNamespaceLabel: "kuadrant-system", common.AuthorinoInstanceAnnotation: "tenant-a"}
+	otherKuadrant := testGateway("other-kuadrant")
+	otherKuadrant.Annotations = map[string]string{common.KuadrantNamespaceLabel: "other-kuadrant", common.AuthorinoInstanceAnnotation: "tenant-c"}
+	notPinned := testGateway("not-pinned")
+	notPinned.Annotations = map[string]string{common.KuadrantNamespaceLabel: "kuadrant-system", common.AuthorinoInstanceAnnotation: ""}
+
+	cl := unitTestTargetRefReconciler(pinnedA, pinnedB, pinnedAgain, otherKuadrant, notPinned).Client()
+	names, err := pinnedAuthorinoInstances(context.TODO(), cl, "kuadrant-system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"tenant-a", "tenant-b"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestServingAuthorinoInstance(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"}}
+	defaultAuthorino := &authorinoopv1beta1.Authorino{ObjectMeta: metav1.ObjectMeta{Name: "authorino", Namespace: kObj.Namespace}}
+	tenantAuthorino := &authorinoopv1beta1.Authorino{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Namespace: kObj.Namespace},
+		Spec:       authorinoopv1beta1.AuthorinoSpec{AuthConfigLabelSelectors: "tenant=a"},
+	}
+
+	gw := testGateway("gw")
+	gw.Annotations = map[string]string{common.KuadrantNamespaceLabel: kObj.Namespace}
+	pinnedGw := testGateway("pinned")
+	pinnedGw.Annotations = map[string]string{common.KuadrantNamespaceLabel: kObj.Namespace, common.AuthorinoInstanceAnnotation: "tenant-a"}
+	missingGw := testGateway("missing")
+	missingGw.Annotations = map[string]string{common.KuadrantNamespaceLabel: kObj.Namespace, common.AuthorinoInstanceAnnotation: "tenant-z"}
+	unmanagedGw := testGateway("unmanaged")
+
+	ap := testAuthPolicy("ap", gw.Namespace, gw)
+	pinnedAP := testAuthPolicy("pinned", pinnedGw.Namespace, pinnedGw)
+	missingAP := testAuthPolicy("missing", missingGw.Namespace, missingGw)
+	unmanagedAP := testAuthPolicy("unmanaged", unmanagedGw.Namespace, unmanagedGw)
+
+	r := &AuthPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(kObj, defaultAuthorino, tenantAuthorino, gw, pinnedGw, missingGw, unmanagedGw)}
+	ctx := context.TODO()
+
+	t.Run("serving instance", func(subT *testing.T) {
+		testCases := []struct {
+			ap       *kuadrantv1beta1.AuthPolicy
+			expected string
+		}{
+			{ap: ap, expected: "authorino"},
+			{ap: pinnedAP, expected: "tenant-a"},
+			{ap: missingAP, expected: ""},
+			{ap: unmanagedAP, expected: ""},
+		}
+		for _, tc := range testCases {
+			authorino, err := r.servingAuthorinoInstance(ctx, tc.ap)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			name := ""
+			if authorino != nil {
+				name = authorino.Name
+			}
+			if name != tc.expected {
+				subT.Errorf("%s: expected the authorino instance %q, got %q", tc.ap.Name, tc.expected, name)
+			}
+		}
+	})
+
+	t.Run("validation", func(subT *testing.T) {
+		for _, valid := range []*kuadrantv1beta1.AuthPolicy{ap, pinnedAP, unmanagedAP} {
+			if err := r.validatePinnedAuthorinoInstance(ctx, valid); err != nil {
+				subT.Errorf("%s: unexpected error: %v", valid.Name, err)
+			}
+		}
+		if err := r.validatePinnedAuthorinoInstance(ctx, missingAP); err == nil {
+			subT.Error("expected an error for the authorino instance pinned not found")
+		}
+	})
+
+	t.Run("not ready", func(subT *testing.T) {
+		cond, err := r.authorinoInstanceNotReadyCondition(ctx, pinnedAP)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if cond == nil || cond.Reason != "AuthorinoNotReady" {
+			subT.Errorf("expected the pinned authorino instance not ready to be reported, got %v", cond)
+		}
+		// the default instance is reported by the kuadrant instance
+		if cond, err := r.authorinoInstanceNotReadyCondition(ctx, ap); err != nil || cond != nil {
+			subT.Errorf("expected no condition for the gateway not pinning any instance, got %v, %v", cond, err)
+		}
+
+		ready := tenantAuthorino.DeepCopy()
+		ready.Status.Conditions = []authorinoopv1beta1.Condition{{Type: "Ready", Status: corev1.ConditionTrue}}
+		r := &AuthPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(kObj, ready, pinnedGw)}
+		if cond, err := r.authorinoInstanceNotReadyCondition(ctx, pinnedAP); err != nil || cond != nil {
+			subT.Errorf("expected no condition for the pinned instance ready, got %v, %v", cond, err)
+		}
+	})
+
+	t.Run("authconfig labels", func(subT *testing.T) {
+		if labels := authConfigLabelsForAuthorino(tenantAuthorino); !reflect.DeepEqual(labels, map[string]string{"tenant": "a"}) {
+			subT.Errorf("expected the labels selected by the instance, got %v", labels)
+		}
+		if labels := authConfigLabelsForAuthorino(defaultAuthorino); labels != nil {
+			subT.Errorf("expected no labels for the instance selecting all the authconfigs, got %v", labels)
+		}
+		setBased := tenantAuthorino.DeepCopy()
+		setBased.Spec.AuthConfigLabelSelectors = "tenant in (a,b)"
+		if labels := authConfigLabelsForAuthorino(setBased); labels != nil {
+			subT.Errorf("expected no labels for a set-based selector, got %v", labels)
+		}
+	})
+
+	t.Run("ext_authz provider", func(subT *testing.T) {
+		if name := extAuthProviderName(pinnedAP, pinnedGw); name != common.KuadrantInstanceAuthorizerName(kObj.Namespace, "tenant-a", false) {
+			subT.Errorf("expected the provider of the pinned instance, got %s", name)
+		}
+		if name := extAuthProviderName(ap, gw); name != KuadrantExtAuthProviderName {
+			subT.Errorf("expected the provider of the kuadrant instance, got %s", name)
+		}
+	})
+}
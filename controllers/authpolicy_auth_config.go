@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 
@@ -22,16 +25,45 @@ func (r *AuthPolicyReconciler) reconcileAuthConfigs(ctx context.Context, ap *api
 		return err
 	}
 
-	authConfig, err := r.desiredAuthConfig(ap, targetNetworkObject)
+	authConfig, err := r.desiredAuthConfig(ctx, ap, targetNetworkObject)
 	if err != nil {
+		if isAuthConfigSecretsOutOfScope(err) {
+			// not to leave an AuthConfig whose evaluators fail served
+			if delErr := r.deleteAuthConfigs(ctx, ap); delErr != nil {
+				return delErr
+			}
+		}
 		return err
 	}
 
-	err = r.ReconcileResource(ctx, &authorinoapi.AuthConfig{}, authConfig, alwaysUpdateAuthConfig)
+	// the mutator is only called on an existing authconfig
+	found, updated := false, false
+	mutator := func(existingObj, desiredObj client.Object) (bool, error) {
+		found = true
+		update, err := alwaysUpdateAuthConfig(existingObj, desiredObj)
+		updated = update
+		return update, err
+	}
+
+	err = r.ReconcileResource(ctx, &authorinoapi.AuthConfig{}, authConfig, mutator)
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		logger.Error(err, "ReconcileResource failed to create/update AuthConfig resource")
 		return err
 	}
+	if err == nil && (!found || updated) {
+		authConfigReadiness.Written(client.ObjectKeyFromObject(authConfig))
+	}
+
+	// the authconfig moves when the scope of authorino changes
+	for _, namespace := range r.authConfigCandidateNamespaces(ap) {
+		if namespace == authConfig.Namespace {
+			continue
+		}
+		if err := r.deleteAuthConfig(ctx, ap, namespace); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -43,30 +75,103 @@ func (r *AuthPolicyReconciler) deleteAuthConfigs(ctx context.Context, ap *api.Au
 
 	logger.Info("Removing Authorino's AuthConfigs")
 
+	for _, namespace := range r.authConfigCandidateNamespaces(ap) {
+		if err := r.deleteAuthConfig(ctx, ap, namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *AuthPolicyReconciler) deleteAuthConfig(ctx context.Context, ap *api.AuthPolicy, namespace string) error {
+	logger, _ := logr.FromContext(ctx)
+
 	authConfig := &authorinoapi.AuthConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      authConfigName(client.ObjectKeyFromObject(ap)),
-			Namespace: ap.Namespace,
+			Namespace: namespace,
 		},
 	}
 
+	authConfigReadiness.Forget(client.ObjectKeyFromObject(authConfig))
+
 	if err := r.DeleteResource(ctx, authConfig); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
-		logger.Error(err, "failed to delete Authorino's AuthConfig")
+		logger.Error(err, "failed to delete Authorino's AuthConfig", "namespace", namespace)
 		return err
 	}
 
 	return nil
 }
 
-func (r *AuthPolicyReconciler) desiredAuthConfig(ap *api.AuthPolicy, targetNetworkObject client.Object) (*authorinoapi.AuthConfig, error) {
+// authConfigCandidateNamespaces returns the namespaces the AuthConfig of the policy may have been created in
+func (r *AuthPolicyReconciler) authConfigCandidateNamespaces(ap *api.AuthPolicy) []string {
+	namespaces := []string{ap.Namespace}
+	if kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(ap); isSet && kuadrantNamespace != ap.Namespace {
+		namespaces = append(namespaces, kuadrantNamespace)
+	}
+	return namespaces
+}
+
+// servingAuthorino returns the reference to the Authorino instance pinned by the targeted gateway or else of the
+// kuadrant instance of the targeted gateway, or nil if unknown.
+// A namespaced Authorino instance serves the AuthConfigs of its own namespace only.
+func (r *AuthPolicyReconciler) servingAuthorino(ctx context.Context, ap *api.AuthPolicy) (*api.AuthorinoReference, bool, error) {
+	authorino, err := r.servingAuthorinoInstance(ctx, ap)
+	if err != nil || authorino == nil {
+		return nil, false, err
+	}
+
+	return &api.AuthorinoReference{Name: authorino.Name, Namespace: authorino.Namespace}, authorino.Spec.ClusterWide, nil
+}
+
+// authConfigNamespace returns the namespace of the AuthConfig of the policy: the namespace of the policy,
+// unless it is served by a namespaced Authorino instance
+func (r *AuthPolicyReconciler) authConfigNamespace(ctx context.Context, ap *api.AuthPolicy) (string, error) {
+	authorino, clusterWide, err := r.servingAuthorino(ctx, ap)
+	if err != nil {
+		return "", err
+	}
+	if authorino == nil || clusterWide {
+		return ap.Namespace, nil
+	}
+	return authorino.Namespace, nil
+}
+
+func (r *AuthPolicyReconciler) desiredAuthConfig(ctx context.Context, ap *api.AuthPolicy, targetNetworkObject client.Object) (*authorinoapi.AuthConfig, error) {
 	hosts, err := r.policyHosts(ap, targetNetworkObject)
 	if err != nil {
 		return nil, err
 	}
 
+	spec, _, err := r.resolveAuthConfigSpec(ctx, ap)
+	if err != nil {
+		return nil, err
+	}
+	spec.Hosts = hosts
+
+	namespace, err := r.authConfigNamespace(ctx, ap)
+	if err != nil {
+		return nil, err
+	}
+
+	// the Secrets of the namespace of the policy are out of the scope of a namespaced Authorino instance of another
+	// namespace
+	if namespace != ap.Namespace {
+		if refs := authConfigSecretRefs(&spec); len(refs) > 0 {
+			return nil, &authConfigSecretsOutOfScopeError{authorinoNamespace: namespace, refs: refs}
+		}
+	}
+
+	// the AuthConfig must match the selectors of the instance serving it
+	authorino, err := r.servingAuthorinoInstance(ctx, ap)
+	if err != nil {
+		return nil, err
+	}
+
 	return &authorinoapi.AuthConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "AuthConfig",
@@ -74,21 +179,58 @@ func (r *AuthPolicyReconciler) desiredAuthConfig(ap *api.AuthPolicy, targetNetwo
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      authConfigName(client.ObjectKeyFromObject(ap)),
-			Namespace: ap.Namespace,
-		},
-		Spec: authorinoapi.AuthConfigSpec{
-			Hosts:         hosts,
-			Patterns:      ap.Spec.AuthScheme.Patterns,
-			Conditions:    ap.Spec.AuthScheme.Conditions,
-			Identity:      ap.Spec.AuthScheme.Identity,
-			Metadata:      ap.Spec.AuthScheme.Metadata,
-			Authorization: ap.Spec.AuthScheme.Authorization,
-			Response:      ap.Spec.AuthScheme.Response,
-			DenyWith:      ap.Spec.AuthScheme.DenyWith,
+			Namespace: namespace,
+			Labels:    authConfigLabelsForAuthorino(authorino),
+			// reverse lookup of the policy from the AuthConfig referred in the logs of Authorino
+			Annotations: map[string]string{
+				common.AuthPolicyNamespaceAnnotation:  ap.Namespace,
+				common.AuthPolicyNameAnnotation:       ap.Name,
+				common.AuthPolicyGenerationAnnotation: strconv.FormatInt(ap.Generation, 10),
+				// the policies of a gateway and of its routes claiming the same hosts are not conflicting
+				common.AuthPolicyTargetKindAnnotation: string(ap.GetTargetRef().Kind),
+				common.AuthPolicyGatewaysAnnotation:   strings.Join(common.Map(r.TargetedGatewayKeys(ctx, targetNetworkObject), client.ObjectKey.String), ","),
+			},
 		},
+		Spec: spec,
 	}, nil
 }
 
+// authConfigSecretsOutOfScopeError is the error of a policy served by a namespaced Authorino instance of another
+// namespace, whose AuthConfig refers to Secrets of the namespace of the policy. The AuthConfig is created in the
+// namespace of Authorino, where the Secrets are looked up, and Authorino cannot read the other namespaces.
+type authConfigSecretsOutOfScopeError struct {
+	authorinoNamespace string
+	refs               []string
+}
+
+func (e *authConfigSecretsOutOfScopeError) Error() string {
+	return fmt.Sprintf("the policy is served by the namespaced Authorino instance of namespace %s, which cannot read the Secrets of the namespace of the policy referred by: %s", e.authorinoNamespace, strings.Join(e.refs, ", "))
+}
+
+func isAuthConfigSecretsOutOfScope(err error) bool {
+	outOfScopeErr := &authConfigSecretsOutOfScopeError{}
+	return errors.As(err, &outOfScopeErr)
+}
+
+// authConfigSecretRefs returns the references of an AuthConfig to Secrets of its namespace: the Secrets referred by
+// name, and the API keys and the CA certificates of the client certificates selected by labels
+func authConfigSecretRefs(spec *authorinoapi.AuthConfigSpec) []string {
+	authScheme := &api.AuthSchemeSpec{
+		Identity:      spec.Identity,
+		Metadata:      spec.Metadata,
+		Authorization: spec.Authorization,
+		Response:      spec.Response,
+	}
+
+	refs := common.Map(authScheme.SecretRefs(), func(name string) string { return fmt.Sprintf("secret %s", name) })
+	for _, identity := range spec.Identity {
+		if identity.APIKey != nil || identity.MTLS != nil {
+			refs = append(refs, fmt.Sprintf("identity %s", identity.Name))
+		}
+	}
+	return refs
+}
+
 func (r *AuthPolicyReconciler) policyHosts(ap *api.AuthPolicy, targetNetworkObject client.Object) ([]string, error) {
 	if len(ap.Spec.AuthRules) == 0 {
 		return common.TargetHostnames(targetNetworkObject)
@@ -129,8 +271,10 @@ func alwaysUpdateAuthConfig(existingObj, desiredObj client.Object) (bool, error)
 		return false, fmt.Errorf("%T is not an *authorinoapi.AuthConfig", desiredObj)
 	}
 
+	labelsUpdated := common.MergeMapStringString(&existing.Labels, desired.Labels)
+
 	if reflect.DeepEqual(existing.Spec, desired.Spec) && reflect.DeepEqual(existing.Annotations, desired.Annotations) {
-		return false, nil
+		return labelsUpdated, nil
 	}
 
 	existing.Spec = desired.Spec
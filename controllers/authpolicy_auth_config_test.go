@@ -0,0 +1,83 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	authorinoopv1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestAuthConfigSecretRefs(t *testing.T) {
+	spec := &authorinov1beta1.AuthConfigSpec{
+		Identity: []*authorinov1beta1.Identity{
+			{Name: "api-keys", APIKey: &authorinov1beta1.Identity_APIKey{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "toystore"}}}},
+			{Name: "anonymous", Anonymous: &authorinov1beta1.Identity_Anonymous{}},
+		},
+		Metadata: []*authorinov1beta1.Metadata{
+			{Name: "http", GenericHTTP: &authorinov1beta1.Metadata_GenericHTTP{SharedSecret: &authorinov1beta1.SecretKeyReference{Name: "shared", Key: "key"}}},
+		},
+	}
+
+	refs := authConfigSecretRefs(spec)
+	expected := []string{"secret shared", "identity api-keys"}
+	if len(refs) != len(expected) || refs[0] != expected[0] || refs[1] != expected[1] {
+		t.Errorf("expected %v, got %v", expected, refs)
+	}
+
+	if refs := authConfigSecretRefs(&authorinov1beta1.AuthConfigSpec{Identity: spec.Identity[1:]}); len(refs) != 0 {
+		t.Errorf("expected no references to secrets, got %v", refs)
+	}
+}
+
+func TestDesiredAuthConfigSecretsOutOfScope(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"}}
+	gw := testGateway("gw")
+	gw.Annotations = map[string]string{common.KuadrantNamespaceLabel: kObj.Namespace}
+	route := testHTTPRoute("route", gw, "api.example.com")
+
+	apiKeys := &authorinov1beta1.Identity{
+		Name:   "api-keys",
+		APIKey: &authorinov1beta1.Identity_APIKey{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "toystore"}}},
+	}
+	withSecrets := testAuthPolicy("with-secrets", "ns", route)
+	withSecrets.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{apiKeys}
+	withoutSecrets := testAuthPolicy("without-secrets", "ns", route)
+	withoutSecrets.Spec.AuthScheme.Identity = []*authorinov1beta1.Identity{{Name: "service-accounts", KubernetesAuth: &authorinov1beta1.Identity_KubernetesAuth{}}}
+
+	ctx := context.TODO()
+	for _, clusterWide := range []bool{true, false} {
+		authorino := &authorinoopv1beta1.Authorino{
+			ObjectMeta: metav1.ObjectMeta{Name: "authorino", Namespace: kObj.Namespace},
+			Spec:       authorinoopv1beta1.AuthorinoSpec{ClusterWide: clusterWide},
+		}
+		r := &AuthPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(kObj, authorino, gw, route, withSecrets, withoutSecrets)}
+
+		authConfig, err := r.desiredAuthConfig(ctx, withSecrets, route)
+		if clusterWide && (err != nil || authConfig.Namespace != withSecrets.Namespace) {
+			t.Errorf("cluster-wide authorino: expected the authconfig in the namespace of the policy, got %v, %v", authConfig, err)
+		}
+		if !clusterWide && !isAuthConfigSecretsOutOfScope(err) {
+			t.Errorf("namespaced authorino: expected the policy referring to secrets to be rejected, got %v", err)
+		}
+
+		authConfig, err = r.desiredAuthConfig(ctx, withoutSecrets, route)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !clusterWide && authConfig.Namespace != kObj.Namespace {
+			t.Errorf("namespaced authorino: expected the authconfig in the namespace of authorino, got %s", authConfig.Namespace)
+		}
+	}
+
+	cond := (&AuthPolicyReconciler{}).availableCondition("HTTPRoute", &authConfigSecretsOutOfScopeError{authorinoNamespace: kObj.Namespace, refs: []string{"identity api-keys"}}, true)
+	if cond.Status != metav1.ConditionFalse || cond.Reason != "SecretsOutOfAuthorinoScope" {
+		t.Errorf("unexpected condition %+v", cond)
+	}
+}
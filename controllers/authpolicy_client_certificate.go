@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// clientCertificateNotVerifiedError is the error of a policy with mTLS identity sources whose gateway does not
+// verify the client certificates, the certificates never forwarded to Authorino
+type clientCertificateNotVerifiedError struct {
+	gateway client.ObjectKey
+}
+
+func (e *clientCertificateNotVerifiedError) Error() string {
+	return fmt.Sprintf("gateway %s has no HTTPS listener verifying the client certificates, required by the mTLS identity sources. Set the %s option of the TLS config of a listener to MUTUAL", e.gateway, common.IstioTLSTerminateModeOption)
+}
+
+func isClientCertificateNotVerified(err error) bool {
+	notVerifiedErr := &clientCertificateNotVerifiedError{}
+	return errors.As(err, &notVerifiedErr)
+}
+
+// validateClientCertificateListeners rejects the policies with mTLS identity sources, including the ones of their
+// template, whose gateway has no listener verifying the client certificates
+func (r *AuthPolicyReconciler) validateClientCertificateListeners(ctx context.Context, ap *api.AuthPolicy) error {
+	authScheme, err := resolveAuthPolicyAuthScheme(ctx, r.Client(), ap)
+	if err != nil {
+		return err
+	}
+
+	mtls := false
+	for _, identity := range authScheme.Identity {
+		if identity != nil && identity.MTLS != nil {
+			mtls = true
+		}
+	}
+	if !mtls {
+		return nil
+	}
+
+	gw, err := common.GetGatewayFromPolicyTargetRef(ctx, r.Client(), ap)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if len((common.GatewayWrapper{Gateway: gw}).ClientCertificateListeners()) == 0 {
+		return &clientCertificateNotVerifiedError{gateway: client.ObjectKeyFromObject(gw)}
+	}
+	return nil
+}
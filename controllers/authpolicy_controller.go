@@ -5,15 +5,24 @@ import (
 	"encoding/json"
 
 	"github.com/go-logr/logr"
+	authorinoopapi "github.com/kuadrant/authorino-operator/api/v1beta1"
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	istio "istio.io/client-go/pkg/apis/security/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
 )
@@ -23,6 +32,8 @@ const authPolicyFinalizer = "authpolicy.kuadrant.io/finalizer"
 // AuthPolicyReconciler reconciles a AuthPolicy object
 type AuthPolicyReconciler struct {
 	reconcilers.TargetRefReconciler
+	// ReconcileTrigger enqueues the reconciliation of the policies on demand (optional)
+	ReconcileTrigger *ReconcileTrigger
 }
 
 //+kubebuilder:rbac:groups=kuadrant.io,resources=authpolicies,verbs=get;list;watch;create;update;patch;delete
@@ -30,9 +41,11 @@ type AuthPolicyReconciler struct {
 //+kubebuilder:rbac:groups=kuadrant.io,resources=authpolicies/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=security.istio.io,resources=authorizationpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=authorino.kuadrant.io,resources=authconfigs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch
 
 func (r *AuthPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := r.Logger().WithValues("AuthPolicy", req.NamespacedName)
+	logger := r.Logger().WithValues("AuthPolicy", req.NamespacedName, "reconcileID", controller.ReconcileIDFromContext(eventCtx))
 	logger.Info("Reconciling AuthPolicy")
 	ctx := logr.NewContext(eventCtx, logger)
 
@@ -41,6 +54,7 @@ func (r *AuthPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Requ
 	if err := r.Client().Get(ctx, req.NamespacedName, ap); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("no AuthPolicy found")
+			authConfigGetBackoff.Forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "failed to get AuthPolicy")
@@ -57,6 +71,28 @@ func (r *AuthPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Requ
 
 	markedForDeletion := ap.GetDeletionTimestamp() != nil
 
+	if !markedForDeletion {
+		// the deletion of the policy triggers a new reconciliation, cleaning up its resources
+		if expired, err := deleteExpiredPolicy(ctx, r.BaseReconciler, ap, ap.Spec.ExpiresAt); err != nil || expired {
+			return ctrl.Result{}, err
+		}
+
+		// the update of the policy triggers a new reconciliation
+		if updated, err := normalizePolicyTargetRef(ctx, r.Client(), ap, &ap.Spec.TargetRef); err != nil || updated {
+			return ctrl.Result{}, err
+		}
+
+		// a policy with an invalid targetRef is not attached to any network object
+		if err := common.ValidateTargetRef(ap.GetTargetRef()); err != nil {
+			logger.V(1).Info("Invalid targetRef. Cleaning up", "error", err.Error())
+			delResErr := r.deleteResources(ctx, ap, nil)
+			if delResErr == nil {
+				delResErr = err
+			}
+			return r.reconcileStatus(ctx, ap, delResErr)
+		}
+	}
+
 	// fetch the target network object
 	targetNetworkObject, err := r.FetchValidTargetRef(ctx, ap.GetTargetRef(), ap.Namespace)
 	if err != nil {
@@ -79,16 +115,47 @@ func (r *AuthPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Requ
 		if controllerutil.ContainsFinalizer(ap, authPolicyFinalizer) {
 			logger.V(1).Info("Handling removal of authpolicy object")
 
+			// the policy replaced stays enforced until its replacement is
+			_, replacement, err := policyReplacementKeys(ctx, r.Client(), ap, &api.AuthPolicyList{})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if replacement != nil {
+				enforced, err := r.authPolicyReplacementEnforced(ctx, *replacement, targetNetworkObject)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if !enforced {
+					if requeueAfter := policyReplacementRequeueAfter(ap); requeueAfter > 0 {
+						logger.V(1).Info("deletion pending the enforcement of the replacement", "replacement", replacement)
+						return ctrl.Result{RequeueAfter: requeueAfter}, updateAwaitingReplacementStatus(ctx, r.BaseReconciler, ap, &ap.Status.Conditions, *replacement)
+					}
+					// the replacement, e.g. invalid, is not awaited any longer
+					logger.Info("replacement not enforced in time, deleting the policy", "replacement", replacement)
+					r.EventRecorder().Eventf(ap, corev1.EventTypeWarning, "ReplacementTimedOut", "Replacement %s not enforced within %s, the policy is deleted", replacement, PolicyReplacementTimeout)
+				}
+			}
+
 			if err := r.deleteResources(ctx, ap, targetNetworkObject); err != nil {
 				return ctrl.Result{}, err
 			}
 
+			// authorino does not link on its own the hosts released to the AuthConfig of the replacement
+			if replacement != nil {
+				if err := r.relinkReplacementAuthConfig(ctx, *replacement, client.ObjectKeyFromObject(ap)); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+
 			logger.Info("removing finalizer")
 			if err := r.RemoveFinalizer(ctx, ap, authPolicyFinalizer); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
 
+		authPolicyChanges.Forget(client.ObjectKeyFromObject(ap))
+		authConfigGetBackoff.Forget(client.ObjectKeyFromObject(ap))
+
 		return ctrl.Result{}, nil
 	}
 
@@ -99,6 +166,20 @@ func (r *AuthPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Requ
 		}
 	}
 
+	// the policy replaced is left enforced as is until deleted, not to compete with its replacement for the target
+	if _, replacement, err := policyReplacementKeys(ctx, r.Client(), ap, &api.AuthPolicyList{}); err != nil {
+		return ctrl.Result{}, err
+	} else if replacement != nil {
+		logger.V(1).Info("policy replaced, skipping the reconciliation of the spec", "replacement", replacement)
+		return r.reconcileStatus(ctx, ap, nil)
+	}
+
+	// the rapid changes of the policy are coalesced, applied once the policy is stable for the cooldown
+	if applyAt, remaining := policyChangeCooldownRemaining(authPolicyChanges, ap, ap.Status.ObservedGeneration, ap.Status.Conditions); remaining > 0 {
+		logger.V(1).Info("policy changed, waiting for the cooldown before applying the changes", "generation", ap.Generation, "remaining", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, updateChangePendingStatus(ctx, r.BaseReconciler, ap, &ap.Status.Conditions, applyAt)
+	}
+
 	// reconcile the authpolicy spec
 	specErr := r.reconcileResources(ctx, ap, targetNetworkObject)
 
@@ -113,13 +194,14 @@ func (r *AuthPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Requ
 		return ctrl.Result{}, statusErr
 	}
 
-	if statusResult.Requeue {
+	if statusResult.Requeue || statusResult.RequeueAfter > 0 {
 		logger.V(1).Info("Reconciling status not finished. Requeueing.")
 		return statusResult, nil
 	}
 
 	logger.Info("AuthPolicy reconciled successfully")
-	return ctrl.Result{}, nil
+	// reconciled again on expiry
+	return ctrl.Result{RequeueAfter: policyExpiresIn(ap.Spec.ExpiresAt)}, nil
 }
 
 func (r *AuthPolicyReconciler) reconcileResources(ctx context.Context, ap *api.AuthPolicy, targetNetworkObject client.Object) error {
@@ -128,10 +210,39 @@ func (r *AuthPolicyReconciler) reconcileResources(ctx context.Context, ap *api.A
 		return err
 	}
 
+	if err := r.validateCrossNamespaceTargetRef(ctx, ap); err != nil {
+		if isCrossNamespaceForbidden(err) {
+			// clean up the resources of a reference no longer granted
+			if delErr := r.deleteResources(ctx, ap, targetNetworkObject); delErr != nil {
+				return delErr
+			}
+		}
+		return err
+	}
+
+	if err := r.validatePinnedAuthorinoInstance(ctx, ap); err != nil {
+		return err
+	}
+
+	if err := r.validateClientCertificateListeners(ctx, ap); err != nil {
+		return err
+	}
+
+	if err := r.validateListeners(ctx, ap, targetNetworkObject); err != nil {
+		return err
+	}
+
 	if err := common.ValidateHierarchicalRules(ap, targetNetworkObject); err != nil {
 		return err
 	}
 
+	// the policy of a gateway takes over from the default AuthPolicy of the kuadrant instance
+	if gw, ok := targetNetworkObject.(*gatewayapiv1beta1.Gateway); ok {
+		if err := r.deleteGatewayDefaultAuthPolicy(ctx, gw); err != nil {
+			return err
+		}
+	}
+
 	// reconcile based on gateway diffs
 	gatewayDiffObj, err := r.ComputeGatewayDiffs(ctx, ap, targetNetworkObject, &common.KuadrantAuthPolicyRefsConfig{})
 	if err != nil {
@@ -146,11 +257,23 @@ func (r *AuthPolicyReconciler) reconcileResources(ctx context.Context, ap *api.A
 		return err
 	}
 
+	// a policy replacing another takes over its target
+	if replacedKey, ok := replacedPolicyKey(ap); ok {
+		if err := r.ReplaceTargetBackReference(ctx, replacedKey, client.ObjectKeyFromObject(ap), targetNetworkObject, common.AuthPolicyBackRefAnnotation); err != nil {
+			return err
+		}
+	}
+
 	// set direct back ref - i.e. claim the target network object as taken asap
 	if err := r.reconcileNetworkResourceDirectBackReference(ctx, ap, targetNetworkObject); err != nil {
 		return err
 	}
 
+	// list the policy in the routes it affects
+	if err := r.ReconcileEffectivePoliciesAnnotation(ctx, targetNetworkObject); err != nil {
+		return err
+	}
+
 	// set annotation of policies afftecting the gateway - should be the last step, only when all the reconciliation steps succeed
 	return r.ReconcileGatewayPolicyReferences(ctx, ap, gatewayDiffObj)
 }
@@ -175,6 +298,10 @@ func (r *AuthPolicyReconciler) deleteResources(ctx context.Context, ap *api.Auth
 		if err := r.deleteNetworkResourceDirectBackReference(ctx, ap, targetNetworkObject); err != nil {
 			return err
 		}
+
+		if err := r.ReconcileEffectivePoliciesAnnotation(ctx, targetNetworkObject); err != nil {
+			return err
+		}
 	}
 
 	// update annotation of policies afftecting the gateway
@@ -187,6 +314,10 @@ func (r *AuthPolicyReconciler) reconcileNetworkResourceDirectBackReference(ctx c
 }
 
 func (r *AuthPolicyReconciler) deleteNetworkResourceDirectBackReference(ctx context.Context, ap *api.AuthPolicy, targetNetworkObject client.Object) error {
+	// the target taken over by the replacement of the policy is left to it
+	if common.ReadAnnotationsFromObject(targetNetworkObject)[common.AuthPolicyBackRefAnnotation] != client.ObjectKeyFromObject(ap).String() {
+		return nil
+	}
 	return r.DeleteTargetBackReference(ctx, client.ObjectKeyFromObject(ap), targetNetworkObject, common.AuthPolicyBackRefAnnotation)
 }
 
@@ -194,18 +325,102 @@ func (r *AuthPolicyReconciler) deleteNetworkResourceDirectBackReference(ctx cont
 func (r *AuthPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	httpRouteEventMapper := &HTTPRouteEventMapper{
 		Logger: r.Logger().WithName("httpRouteEventMapper"),
+		Client: r.Client(),
 	}
 	gatewayEventMapper := &GatewayEventMapper{
 		Logger: r.Logger().WithName("gatewayEventMapper"),
 	}
+	policyTemplateEventMapper := &PolicyTemplateEventMapper{
+		Logger: r.Logger().WithName("policyTemplateEventMapper"),
+		Client: r.Client(),
+	}
+	identityProviderEventMapper := &IdentityProviderEventMapper{
+		Logger: r.Logger().WithName("identityProviderEventMapper"),
+		Client: r.Client(),
+	}
+	kuadrantEventMapper := &KuadrantEventMapper{
+		Logger: r.Logger().WithName("kuadrantEventMapper"),
+		Client: r.Client(),
+	}
+	authConfigEventMapper := &AuthConfigEventMapper{
+		Logger: r.Logger().WithName("authConfigEventMapper"),
+		Client: r.Client(),
+	}
+	referenceGrantEventMapper := &ReferenceGrantEventMapper{
+		Logger: r.Logger().WithName("referenceGrantEventMapper"),
+		Client: r.Client(),
+	}
+	policyReplacementEventMapper := &PolicyReplacementEventMapper{
+		Logger: r.Logger().WithName("policyReplacementEventMapper"),
+		Client: r.Client(),
+	}
+	secretEventMapper := &SecretEventMapper{
+		Logger: r.Logger().WithName("secretEventMapper"),
+		Client: r.Client(),
+	}
+	iapEventMapper := &IstioAuthorizationPolicyEventMapper{
+		Logger: r.Logger().WithName("istioAuthorizationPolicyEventMapper"),
+		Client: r.Client(),
+	}
+
+	// the AuthConfigs claiming overlapping hosts are looked up by the domains of their hosts
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &authorinoapi.AuthConfig{}, authConfigHostsIndex, authConfigHostsIndexValues); err != nil {
+		return err
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&api.AuthPolicy{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: AuthPolicyReconcileWorkers, RateLimiter: AuthPolicyReconcileRateLimiter}).
 		Watches(
 			&source.Kind{Type: &gatewayapiv1beta1.HTTPRoute{}},
 			handler.EnqueueRequestsFromMapFunc(httpRouteEventMapper.MapToAuthPolicy),
 		).
 		Watches(&source.Kind{Type: &gatewayapiv1beta1.Gateway{}},
 			handler.EnqueueRequestsFromMapFunc(gatewayEventMapper.MapToAuthPolicy)).
-		Complete(r)
+		Watches(&source.Kind{Type: &kuadrantv1beta2.PolicyTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(policyTemplateEventMapper.MapToAuthPolicy)).
+		// the identity sources of the identity providers are resolved into the AuthConfigs
+		Watches(&source.Kind{Type: &kuadrantv1beta2.IdentityProvider{}},
+			handler.EnqueueRequestsFromMapFunc(identityProviderEventMapper.MapToAuthPolicy)).
+		// the defaults of the kuadrant instance are applied to the AuthConfigs
+		Watches(&source.Kind{Type: &api.Kuadrant{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToAuthPolicy),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the AuthPolicies may be stuck not ready until Authorino recovers,
+		// and the AuthConfigs follow the scope of Authorino
+		Watches(&source.Kind{Type: &authorinoopapi.Authorino{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToAuthPolicy),
+			builder.WithPredicates(predicate.Or(authorinoBecameReady, predicate.GenerationChangedPredicate{}))).
+		// the readiness of the AuthConfigs and the hosts claimed by the other policies are reflected in the status
+		Watches(&source.Kind{Type: &authorinoapi.AuthConfig{}},
+			handler.EnqueueRequestsFromMapFunc(authConfigEventMapper.MapToAuthPolicy)).
+		// the policies targeting gateways in other namespaces require a ReferenceGrant
+		Watches(&source.Kind{Type: &gatewayapiv1beta1.ReferenceGrant{}},
+			handler.EnqueueRequestsFromMapFunc(referenceGrantEventMapper.MapToAuthPolicy)).
+		// the Secrets referenced or selected by the AuthConfigs are reported missing, the metadata only of the Secrets
+		// being cached
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(secretEventMapper.MapToAuthPolicy),
+			builder.OnlyMetadata,
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		// the policy replaced and its replacement reflect each other in their status
+		Watches(&source.Kind{Type: &api.AuthPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(policyReplacementEventMapper.MapAuthPolicyToReplacementPolicies)).
+		// the AuthorizationPolicies deleted or changed, wiring the ext_authz filter of the gateways, are restored
+		Watches(&source.Kind{Type: &istio.AuthorizationPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(iapEventMapper.MapToAuthPolicy),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})))
+
+	if r.ReconcileTrigger != nil {
+		controllerBuilder = controllerBuilder.Watches(r.ReconcileTrigger.Source(&api.AuthPolicyList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	stallDetector := withReconcileStallDetector("AuthPolicy", r.Client(), r.EventRecorder(),
+		func() *api.AuthPolicy { return &api.AuthPolicy{} },
+		func(ap *api.AuthPolicy) (int64, *[]metav1.Condition) {
+			return ap.Status.ObservedGeneration, &ap.Status.Conditions
+		},
+		r)
+
+	return controllerBuilder.Complete(withLastSuccessMetric("authpolicy", stallDetector))
 }
@@ -250,6 +250,19 @@ var _ = Describe("AuthPolicy controller", func() {
 			Expect(existingAuthC.Spec.Hosts).To(Equal([]string{"*.toystore.com"}))
 		})
 
+		It("authconfig should be annotated with the source policy", func() {
+			kapKey := client.ObjectKey{Name: "toystore", Namespace: testNamespace}
+			existingAuthC := &authorinov1beta1.AuthConfig{}
+			authCKey := types.NamespacedName{Name: authConfigName(kapKey), Namespace: testNamespace}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), authCKey, existingAuthC)
+				return err == nil
+			}, 30*time.Second, 5*time.Second).Should(BeTrue())
+			Expect(existingAuthC.Annotations).To(HaveKeyWithValue(common.AuthPolicyNamespaceAnnotation, testNamespace))
+			Expect(existingAuthC.Annotations).To(HaveKeyWithValue(common.AuthPolicyNameAnnotation, "toystore"))
+			Expect(existingAuthC.Annotations).To(HaveKey(common.AuthPolicyGenerationAnnotation))
+		})
+
 		It("Istio's authorizationpolicy should include network resource hostnames on kuadrant rules without hosts", func() {
 			typedNamespace := gatewayapiv1beta1.Namespace(testNamespace)
 			targetRef := gatewayapiv1alpha2.PolicyTargetReference{
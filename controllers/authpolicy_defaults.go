@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const (
+	APAuthorinoDefaultsAppliedConditionType string = "AuthorinoDefaultsApplied"
+)
+
+// policyKuadrant returns the kuadrant instance of the gateways of the policy, or nil if unknown
+func (r *AuthPolicyReconciler) policyKuadrant(ctx context.Context, ap *api.AuthPolicy) (*api.Kuadrant, error) {
+	logger, _ := logr.FromContext(ctx)
+
+	kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(ap)
+	if !isSet {
+		var err error
+		kuadrantNamespace, err = common.GetKuadrantNamespaceFromPolicyTargetRef(ctx, r.Client(), ap)
+		if err != nil {
+			// the policy is enforced regardless of the defaults
+			logger.V(1).Info("failed to get kuadrant namespace, the authorino defaults and metrics are not applied", "error", err)
+			return nil, nil
+		}
+	}
+
+	kuadrantList := &api.KuadrantList{}
+	if err := r.Client().List(ctx, kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+		return nil, err
+	}
+	if len(kuadrantList.Items) == 0 {
+		return nil, nil
+	}
+
+	return &kuadrantList.Items[0], nil
+}
+
+// resolveAuthConfigSpec returns the spec of the AuthConfig of the policy, except the hosts, with the auth scheme
+// inherited from the template of the policy, the cache of the policy set on its evaluators, the defaults of the
+// kuadrant instance set where omitted and the evaluator-level metrics selected by the kuadrant instance enabled.
+// Returns the list of the settings defaulted.
+func (r *AuthPolicyReconciler) resolveAuthConfigSpec(ctx context.Context, ap *api.AuthPolicy) (authorinoapi.AuthConfigSpec, []string, error) {
+	authScheme, err := resolveAuthPolicyAuthScheme(ctx, r.Client(), ap)
+	if err != nil {
+		return authorinoapi.AuthConfigSpec{}, nil, err
+	}
+
+	// the identity sources of the template and of the identity providers are only known once resolved
+	if err := authScheme.ValidateIdentityOrder(); err != nil {
+		return authorinoapi.AuthConfigSpec{}, nil, err
+	}
+	if err := authScheme.ValidateIdentityReferences(); err != nil {
+		return authorinoapi.AuthConfigSpec{}, nil, err
+	}
+	if err := authScheme.ValidateClientCertificate(); err != nil {
+		return authorinoapi.AuthConfigSpec{}, nil, err
+	}
+	if err := authScheme.ValidateMissingIdentity(ap.GetMissingIdentity()); err != nil {
+		return authorinoapi.AuthConfigSpec{}, nil, err
+	}
+
+	kObj, err := r.policyKuadrant(ctx, ap)
+	if err != nil {
+		return authorinoapi.AuthConfigSpec{}, nil, err
+	}
+	var defaults *api.AuthorinoDefaults
+	var metrics *api.AuthorinoMetricsSpec
+	if kObj != nil {
+		defaults = kObj.AuthorinoDefaults()
+		metrics = kObj.AuthorinoMetrics()
+	}
+
+	// the cache of the policy is set before the defaults, the TTL defaulted where omitted
+	spec, applied := defaults.Apply(ap.Spec.Cache.Apply(authorinoapi.AuthConfigSpec{
+		Patterns:      authScheme.Patterns,
+		Conditions:    authScheme.Conditions,
+		Identity:      api.WithMissingIdentity(ap.GetMissingIdentity(), authScheme.ClientCertificate.Apply(authScheme.OrderedIdentity())),
+		Metadata:      authScheme.Metadata,
+		Authorization: authScheme.Authorization,
+		Response:      authScheme.Response,
+		DenyWith:      authScheme.DenyWith,
+	}))
+	spec, enabledMetrics := metrics.ApplyEvaluatorMetrics(spec)
+	return spec, append(applied, enabledMetrics...), nil
+}
+
+// authorinoDefaultsCondition returns a condition listing the settings of the AuthConfig of the policy
+// defaulted by the kuadrant instance, or nil if none
+func (r *AuthPolicyReconciler) authorinoDefaultsCondition(ctx context.Context, ap *api.AuthPolicy) (*metav1.Condition, error) {
+	_, applied, err := r.resolveAuthConfigSpec(ctx, ap)
+	if err != nil || len(applied) == 0 {
+		return nil, err
+	}
+
+	return &metav1.Condition{
+		Type:    APAuthorinoDefaultsAppliedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthorinoDefaultsApplied",
+		Message: fmt.Sprintf("Settings defaulted by the Kuadrant instance: %s", strings.Join(applied, ", ")),
+	}, nil
+}
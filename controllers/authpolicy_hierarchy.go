@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// policyHierarchy returns the role of a policy at each gateway it applies to. The AuthConfig of the policy of an
+// HTTPRoute has the most specific hosts, thus prevails over the AuthConfig of the policy of the gateway.
+func (r *AuthPolicyReconciler) policyHierarchy(ctx context.Context, ap *api.AuthPolicy) ([]api.PolicyHierarchy, error) {
+	targetNetworkObject, err := r.FetchValidTargetRef(ctx, ap.GetTargetRef(), ap.Namespace)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	switch obj := targetNetworkObject.(type) {
+	case *gatewayapiv1beta1.Gateway:
+		return []api.PolicyHierarchy{r.gatewayPolicyHierarchy(ctx, ap, obj)}, nil
+	case *gatewayapiv1beta1.HTTPRoute:
+		return r.routePolicyHierarchy(ctx, obj)
+	default:
+		return nil, nil
+	}
+}
+
+// gatewayPolicyHierarchy returns the role of a policy of a gateway, overridden by the policies of the HTTPRoutes
+func (r *AuthPolicyReconciler) gatewayPolicyHierarchy(ctx context.Context, ap *api.AuthPolicy, gw *gatewayapiv1beta1.Gateway) api.PolicyHierarchy {
+	gwKey := client.ObjectKeyFromObject(gw)
+	excludedKeys := ap.ExcludedHTTPRouteKeys()
+
+	overriding := make([]string, 0)
+	defaulted := 0
+	routes := r.FetchAcceptedGatewayHTTPRoutes(ctx, gwKey)
+	for idx := range routes {
+		route := &routes[idx]
+		if apRef, found := common.ReadAnnotationsFromObject(route)[common.AuthPolicyBackRefAnnotation]; found {
+			overriding = append(overriding, common.NamespacedNameToObjectKey(apRef, route.Namespace).String())
+			continue
+		}
+		if !common.Contains(excludedKeys, client.ObjectKeyFromObject(route)) {
+			defaulted++
+		}
+	}
+	sort.Strings(overriding)
+
+	hierarchy := api.PolicyHierarchy{Gateway: gwKey.String(), Role: api.DefaultPolicyRole}
+	if len(routes) > 0 && defaulted == 0 {
+		hierarchy.Role = api.ShadowedPolicyRole
+	}
+	if len(overriding) > 0 {
+		hierarchy.Policies = overriding
+	}
+	return hierarchy
+}
+
+// routePolicyHierarchy returns the role of a policy of an HTTPRoute at the parent gateways with a policy of their own
+func (r *AuthPolicyReconciler) routePolicyHierarchy(ctx context.Context, route *gatewayapiv1beta1.HTTPRoute) ([]api.PolicyHierarchy, error) {
+	hierarchy := make([]api.PolicyHierarchy, 0)
+	for _, gwKey := range r.TargetedGatewayKeys(ctx, route) {
+		gw := &gatewayapiv1beta1.Gateway{}
+		if err := r.Client().Get(ctx, gwKey, gw); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+		apRef, found := common.ReadAnnotationsFromObject(gw)[common.AuthPolicyBackRefAnnotation]
+		if !found {
+			continue
+		}
+		hierarchy = append(hierarchy, api.PolicyHierarchy{
+			Gateway:  gwKey.String(),
+			Role:     api.OverridePolicyRole,
+			Policies: []string{common.NamespacedNameToObjectKey(apRef, gw.Namespace).String()},
+		})
+	}
+	if len(hierarchy) == 0 {
+		return nil, nil
+	}
+	return hierarchy, nil
+}
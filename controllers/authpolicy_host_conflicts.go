@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const APHostConflictConditionType string = "HostConflict"
+
+// authConfigPolicyKey returns the key of the AuthPolicy an AuthConfig was generated from
+func authConfigPolicyKey(authConfig client.Object) (client.ObjectKey, bool) {
+	annotations := authConfig.GetAnnotations()
+	name, ok := annotations[common.AuthPolicyNameAnnotation]
+	if !ok {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: annotations[common.AuthPolicyNamespaceAnnotation], Name: name}, true
+}
+
+// hostsOverlap tells whether the requests to a host can match the other, i.e. either host is a subset of the other
+func hostsOverlap(host, other string) bool {
+	return common.Name(host).SubsetOf(common.Name(other)) || common.Name(other).SubsetOf(common.Name(host))
+}
+
+// authConfigHostsIndex is the index of the AuthConfigs by the domains of their hosts, to look up the AuthConfigs with
+// hosts overlapping a host without listing all the AuthConfigs
+const authConfigHostsIndex = "authConfigHosts"
+
+// hostDomain returns the domain of a host, i.e. the host without the wildcard label
+func hostDomain(host string) string {
+	if host == "*" {
+		return ""
+	}
+	return strings.TrimPrefix(host, "*.")
+}
+
+// domainSuffixes returns the domain and its parent domains, down to the root domain ""
+func domainSuffixes(domain string) []string {
+	suffixes := make([]string, 0)
+	for domain != "" {
+		suffixes = append(suffixes, domain)
+		_, parent, _ := strings.Cut(domain, ".")
+		domain = parent
+	}
+	return append(suffixes, "")
+}
+
+// authConfigHostsIndexValues indexes an AuthConfig by the domain of each of its hosts and by the parent domains of
+// those, so the hosts overlapping a wildcard host are found by its domain, and the wildcard hosts overlapping a host
+// by the parent domains of its domain
+func authConfigHostsIndexValues(obj client.Object) []string {
+	authConfig, ok := obj.(*authorinov1beta1.AuthConfig)
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0)
+	add := func(value string) {
+		if !common.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	for _, host := range authConfig.Spec.Hosts {
+		domain := hostDomain(host)
+		add("domain:" + domain)
+		for _, suffix := range domainSuffixes(domain) {
+			add("suffix:" + suffix)
+		}
+	}
+	return values
+}
+
+// authConfigHostsLookupValues returns the values of the index to look up the AuthConfigs with hosts possibly
+// overlapping a host
+func authConfigHostsLookupValues(host string) []string {
+	domain := hostDomain(host)
+	values := common.Map(domainSuffixes(domain), func(suffix string) string { return "domain:" + suffix })
+	if strings.HasPrefix(host, "*") {
+		values = append(values, "suffix:"+domain)
+	}
+	return values
+}
+
+// sameAuthPolicyHierarchy tells whether two AuthConfigs are generated from the policy of a gateway and the policy of
+// one of its routes, whose hosts are expected to overlap, the policy of the route prevailing
+func sameAuthPolicyHierarchy(authConfig, other client.Object) bool {
+	gatewayPolicy := func(gwConfig, routeConfig client.Object) bool {
+		gwAnnotations, routeAnnotations := gwConfig.GetAnnotations(), routeConfig.GetAnnotations()
+		if gwAnnotations[common.AuthPolicyTargetKindAnnotation] != "Gateway" || routeAnnotations[common.AuthPolicyTargetKindAnnotation] != common.HTTPRouteKind {
+			return false
+		}
+		gateway := gwAnnotations[common.AuthPolicyGatewaysAnnotation]
+		return gateway != "" && common.Contains(strings.Split(routeAnnotations[common.AuthPolicyGatewaysAnnotation], ","), gateway)
+	}
+	return gatewayPolicy(authConfig, other) || gatewayPolicy(other, authConfig)
+}
+
+// conflictingAuthConfigs returns the AuthConfigs generated from other AuthPolicies with hosts overlapping
+// the hosts of the given AuthConfig, other than the AuthConfigs of the same gateway and route hierarchy
+func conflictingAuthConfigs(ctx context.Context, cli client.Client, authConfig *authorinov1beta1.AuthConfig) ([]authorinov1beta1.AuthConfig, error) {
+	policyKey, _ := authConfigPolicyKey(authConfig)
+
+	candidates := make([]authorinov1beta1.AuthConfig, 0)
+	seen := make(map[client.ObjectKey]bool)
+	for _, host := range authConfig.Spec.Hosts {
+		for _, value := range authConfigHostsLookupValues(host) {
+			authConfigList := &authorinov1beta1.AuthConfigList{}
+			if err := cli.List(ctx, authConfigList, client.MatchingFields{authConfigHostsIndex: value}); err != nil {
+				return nil, err
+			}
+			for idx := range authConfigList.Items {
+				if key := client.ObjectKeyFromObject(&authConfigList.Items[idx]); !seen[key] {
+					seen[key] = true
+					candidates = append(candidates, authConfigList.Items[idx])
+				}
+			}
+		}
+	}
+
+	conflicting := make([]authorinov1beta1.AuthConfig, 0)
+	for idx := range candidates {
+		other := &candidates[idx]
+		otherPolicyKey, ok := authConfigPolicyKey(other)
+		if !ok || otherPolicyKey == policyKey || sameAuthPolicyHierarchy(authConfig, other) {
+			continue
+		}
+		if len(overlappingHosts(authConfig.Spec.Hosts, other.Spec.Hosts)) > 0 {
+			conflicting = append(conflicting, *other)
+		}
+	}
+
+	return conflicting, nil
+}
+
+// overlappingHosts returns the hosts overlapping any of the other hosts
+func overlappingHosts(hosts, otherHosts []string) []string {
+	return common.Filter(hosts, func(host string) bool {
+		for _, other := range otherHosts {
+			if hostsOverlap(host, other) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// hostConflicts returns the other AuthPolicies claiming each host of the AuthConfig of a policy overlapping theirs
+func (r *AuthPolicyReconciler) hostConflicts(ctx context.Context, authConfig *authorinov1beta1.AuthConfig) (map[string][]client.ObjectKey, error) {
+	if authConfig == nil {
+		return nil, nil
+	}
+
+	conflicting, err := conflictingAuthConfigs(ctx, r.Client(), authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make(map[string][]client.ObjectKey)
+	for idx := range conflicting {
+		otherPolicyKey, _ := authConfigPolicyKey(&conflicting[idx])
+		for _, host := range overlappingHosts(authConfig.Spec.Hosts, conflicting[idx].Spec.Hosts) {
+			conflicts[host] = append(conflicts[host], otherPolicyKey)
+		}
+	}
+
+	return conflicts, nil
+}
+
+func (r *AuthPolicyReconciler) hostConflictCondition(conflicts map[string][]client.ObjectKey) *metav1.Condition {
+	hosts := make([]string, 0, len(conflicts))
+	for host := range conflicts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	details := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		policies := common.Map(conflicts[host], func(key client.ObjectKey) string { return key.String() })
+		sort.Strings(policies)
+		details = append(details, fmt.Sprintf("%s (%s)", host, strings.Join(policies, ", ")))
+	}
+
+	return &metav1.Condition{
+		Type:    APHostConflictConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "HostClaimedByOtherPolicies",
+		Message: fmt.Sprintf("Hosts also claimed by other AuthPolicies, the most specific host prevails: %s", strings.Join(details, "; ")),
+	}
+}
@@ -0,0 +1,138 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func testAuthConfig(policy, targetKind, gateways string, hosts ...string) *authorinov1beta1.AuthConfig {
+	return &authorinov1beta1.AuthConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ap-" + policy,
+			Namespace: "default",
+			Annotations: map[string]string{
+				common.AuthPolicyNamespaceAnnotation:  "default",
+				common.AuthPolicyNameAnnotation:       policy,
+				common.AuthPolicyTargetKindAnnotation: targetKind,
+				common.AuthPolicyGatewaysAnnotation:   gateways,
+			},
+		},
+		Spec: authorinov1beta1.AuthConfigSpec{Hosts: hosts},
+	}
+}
+
+func TestConflictingAuthConfigs(t *testing.T) {
+	gwPolicy := testAuthConfig("gw", "Gateway", "istio-system/gw", "*.example.com")
+	routePolicy := testAuthConfig("route", "HTTPRoute", "istio-system/gw", "api.example.com")
+	otherGwRoutePolicy := testAuthConfig("other-gw-route", "HTTPRoute", "istio-system/other-gw", "api.example.com")
+	otherRoutePolicy := testAuthConfig("other-route", "HTTPRoute", "istio-system/gw", "api.example.com", "www.example.org")
+	catchAllPolicy := testAuthConfig("catch-all", "Gateway", "istio-system/catch-all", "*")
+	unrelatedPolicy := testAuthConfig("unrelated", "HTTPRoute", "istio-system/gw", "example.com", "toystore.io")
+	nestedPolicy := testAuthConfig("nested", "HTTPRoute", "istio-system/other-gw", "*.api.example.com")
+
+	cl := fake.NewClientBuilder().
+		WithScheme(unitTestScheme()).
+		WithObjects(gwPolicy, routePolicy, otherGwRoutePolicy, otherRoutePolicy, catchAllPolicy, unrelatedPolicy, nestedPolicy).
+		WithIndex(&authorinov1beta1.AuthConfig{}, authConfigHostsIndex, authConfigHostsIndexValues).
+		Build()
+
+	testCases := []struct {
+		name       string
+		authConfig *authorinov1beta1.AuthConfig
+		expected   []string
+	}{
+		{
+			name:       "gateway policy does not conflict with the policies of its routes",
+			authConfig: gwPolicy,
+			expected:   []string{"ap-catch-all", "ap-nested", "ap-other-gw-route"},
+		},
+		{
+			name:       "route policy conflicts with the policies of other routes",
+			authConfig: routePolicy,
+			expected:   []string{"ap-catch-all", "ap-other-gw-route", "ap-other-route"},
+		},
+		{
+			name:       "catch-all host overlaps every host",
+			authConfig: catchAllPolicy,
+			expected:   []string{"ap-gw", "ap-nested", "ap-other-gw-route", "ap-other-route", "ap-route", "ap-unrelated"},
+		},
+		{
+			name:       "hosts not overlapping",
+			authConfig: unrelatedPolicy,
+			expected:   []string{"ap-catch-all"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			conflicting, err := conflictingAuthConfigs(context.TODO(), cl, tc.authConfig)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			names := make([]string, 0, len(conflicting))
+			for idx := range conflicting {
+				names = append(names, conflicting[idx].Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tc.expected) {
+				subT.Errorf("expected conflicting authconfigs %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestAuthConfigHostsLookupValues(t *testing.T) {
+	testCases := []struct {
+		name     string
+		host     string
+		expected []string
+	}{
+		{"host", "api.example.com", []string{"domain:api.example.com", "domain:example.com", "domain:com", "domain:"}},
+		{"wildcard host", "*.example.com", []string{"domain:example.com", "domain:com", "domain:", "suffix:example.com"}},
+		{"catch-all host", "*", []string{"domain:", "suffix:"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if values := authConfigHostsLookupValues(tc.host); !reflect.DeepEqual(values, tc.expected) {
+				subT.Errorf("expected %v, got %v", tc.expected, values)
+			}
+		})
+	}
+}
+
+func TestSameAuthPolicyHierarchy(t *testing.T) {
+	gwPolicy := testAuthConfig("gw", "Gateway", "istio-system/gw", "*.example.com")
+	routePolicy := testAuthConfig("route", "HTTPRoute", "istio-system/other-gw,istio-system/gw", "api.example.com")
+	otherGwPolicy := testAuthConfig("other-gw", "Gateway", "istio-system/other", "*.example.com")
+	legacyPolicy := testAuthConfig("legacy", "", "", "api.example.com")
+
+	for _, tc := range []struct {
+		name     string
+		a, b     client.Object
+		expected bool
+	}{
+		{"gateway and route", gwPolicy, routePolicy, true},
+		{"route and gateway", routePolicy, gwPolicy, true},
+		{"route of another gateway", otherGwPolicy, routePolicy, false},
+		{"two gateways", gwPolicy, otherGwPolicy, false},
+		{"not annotated", gwPolicy, legacyPolicy, false},
+	} {
+		t.Run(tc.name, func(subT *testing.T) {
+			if same := sameAuthPolicyHierarchy(tc.a, tc.b); same != tc.expected {
+				subT.Errorf("expected %t, got %t", tc.expected, same)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 
 	"github.com/go-logr/logr"
+	"google.golang.org/protobuf/proto"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -23,6 +24,25 @@ import (
 
 var KuadrantExtAuthProviderName = common.FetchEnv("AUTH_PROVIDER", "kuadrant-authorization")
 
+// KuadrantExtAuthFailOpenProviderName is the provider of the AuthPolicies letting the requests through when Authorino is unavailable
+var KuadrantExtAuthFailOpenProviderName = common.FetchEnv("AUTH_PROVIDER_FAIL_OPEN", common.ExtAuthorizerFailOpenName)
+
+// extAuthProviderName returns the name of the ext_authz provider enforcing the failure mode of a policy,
+// sending the requests to the Authorino instance pinned by the gateway if any
+func extAuthProviderName(ap *api.AuthPolicy, gateway *gatewayapiv1beta1.Gateway) string {
+	failOpen := ap.GetFailureMode() == api.FailOpen
+	if name, ok := pinnedAuthorinoInstance(gateway); ok {
+		// the pinned instance belongs to the kuadrant instance of the gateway
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gateway); err == nil {
+			return common.KuadrantInstanceAuthorizerName(kuadrantNamespace, name, failOpen)
+		}
+	}
+	if failOpen {
+		return KuadrantExtAuthFailOpenProviderName
+	}
+	return KuadrantExtAuthProviderName
+}
+
 // reconcileIstioAuthorizationPolicies translates and reconciles `AuthRules` into an Istio AuthorizationPoilcy containing them.
 func (r *AuthPolicyReconciler) reconcileIstioAuthorizationPolicies(ctx context.Context, ap *api.AuthPolicy, targetNetworkObject client.Object, gwDiffObj *reconcilers.GatewayDiff) error {
 	if err := r.deleteIstioAuthorizationPolicies(ctx, ap, gwDiffObj); err != nil {
@@ -34,17 +54,18 @@ func (r *AuthPolicyReconciler) reconcileIstioAuthorizationPolicies(ctx context.C
 		return err
 	}
 
-	targetHostnames, err := common.TargetHostnames(targetNetworkObject)
+	toRules, err := r.istioAuthorizationPolicyToRules(ctx, ap, targetNetworkObject)
 	if err != nil {
 		return err
 	}
 
-	// TODO(guicassolato): should the rules filter only the hostnames valid for each gateway?
-	toRules := istioAuthorizationPolicyRules(ap.Spec.AuthRules, targetHostnames, targetNetworkObject)
-
 	// Create IstioAuthorizationPolicy for each gateway directly or indirectly referred by the policy (existing and new)
 	for _, gw := range append(gwDiffObj.GatewaysWithValidPolicyRef, gwDiffObj.GatewaysMissingPolicyRef...) {
-		iap := r.istioAuthorizationPolicy(ctx, gw.Gateway, ap, toRules)
+		gwToRules := toRules
+		if ap.Spec.Listeners != nil {
+			gwToRules = istioAuthorizationPolicyRulesOnPorts(toRules, selectedListenerPorts(gw.Gateway, ap.Spec.Listeners))
+		}
+		iap := r.istioAuthorizationPolicy(ctx, gw.Gateway, ap, gwToRules)
 		err := r.ReconcileResource(ctx, &istio.AuthorizationPolicy{}, iap, alwaysUpdateAuthPolicy)
 		if err != nil && !apierrors.IsAlreadyExists(err) {
 			logger.Error(err, "failed to reconcile IstioAuthorizationPolicy resource")
@@ -55,6 +76,28 @@ func (r *AuthPolicyReconciler) reconcileIstioAuthorizationPolicies(ctx context.C
 	return nil
 }
 
+// istioAuthorizationPolicyToRules returns the rules of the requests sent to the external authorization provider,
+// i.e. the rules of the policy or else of the targeted network object, minus the exclusions of the policy
+func (r *AuthPolicyReconciler) istioAuthorizationPolicyToRules(ctx context.Context, ap *api.AuthPolicy, targetNetworkObject client.Object) ([]*istiosecurity.Rule_To, error) {
+	targetHostnames, err := common.TargetHostnames(targetNetworkObject)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(guicassolato): should the rules filter only the hostnames valid for each gateway?
+	toRules := istioAuthorizationPolicyRules(ap.Spec.AuthRules, targetHostnames, targetNetworkObject)
+
+	if ap.Spec.Exclusions != nil {
+		excludedRoutes, _, err := r.excludedHTTPRoutes(ctx, ap)
+		if err != nil {
+			return nil, err
+		}
+		return common.IstioRulesExcluding(toRules, authPolicyExcludedRules(ap.Spec.Exclusions, excludedRoutes))
+	}
+
+	return toRules, nil
+}
+
 // deleteIstioAuthorizationPolicies deletes IstioAuthorizationPolicies previously created for gateways no longer targeted by the policy (directly or indirectly)
 func (r *AuthPolicyReconciler) deleteIstioAuthorizationPolicies(ctx context.Context, ap *api.AuthPolicy, gwDiffObj *reconcilers.GatewayDiff) error {
 	logger, err := logr.FromContext(ctx)
@@ -99,7 +142,7 @@ func (r *AuthPolicyReconciler) istioAuthorizationPolicy(ctx context.Context, gat
 			Selector: common.IstioWorkloadSelectorFromGateway(ctx, r.Client(), gateway),
 			ActionDetail: &istiosecurity.AuthorizationPolicy_Provider{
 				Provider: &istiosecurity.AuthorizationPolicy_ExtensionProvider{
-					Name: KuadrantExtAuthProviderName,
+					Name: extAuthProviderName(ap, gateway),
 				},
 			},
 		},
@@ -174,6 +217,46 @@ func istioAuthorizationPolicyRules(authRules []api.AuthRule, targetHostnames []s
 	return toRules
 }
 
+// excludedHTTPRoutes returns the HTTPRoutes referred in the exclusions of a policy that are attached to the targeted gateway,
+// and the keys of the ones that are not, or the error listing the HTTPRoutes
+func (r *AuthPolicyReconciler) excludedHTTPRoutes(ctx context.Context, ap *api.AuthPolicy) (attached []gatewayapiv1beta1.HTTPRoute, notAttached []client.ObjectKey, err error) {
+	excludedKeys := ap.ExcludedHTTPRouteKeys()
+	if len(excludedKeys) == 0 {
+		return
+	}
+
+	gwKey := client.ObjectKey{Name: string(ap.GetTargetRef().Name), Namespace: string(common.GetDefaultIfNil(ap.GetTargetRef().Namespace, ap.GetWrappedNamespace()))}
+	gwRoutes, err := r.ListAcceptedGatewayHTTPRoutes(ctx, gwKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range excludedKeys {
+		route, found := common.Find(gwRoutes, func(route gatewayapiv1beta1.HTTPRoute) bool {
+			return client.ObjectKeyFromObject(&route) == key
+		})
+		if !found {
+			notAttached = append(notAttached, key)
+			continue
+		}
+		attached = append(attached, *route)
+	}
+
+	return
+}
+
+// authPolicyExcludedRules translates the exclusions of a policy into the rules of the requests to exempt
+func authPolicyExcludedRules(exclusions *api.AuthExclusions, excludedRoutes []gatewayapiv1beta1.HTTPRoute) []common.HTTPRouteRule {
+	excludedRules := make([]common.HTTPRouteRule, 0)
+	if len(exclusions.Paths) > 0 {
+		excludedRules = append(excludedRules, common.HTTPRouteRule{Paths: exclusions.Paths})
+	}
+	for idx := range excludedRoutes {
+		excludedRules = append(excludedRules, common.RulesFromHTTPRoute(&excludedRoutes[idx])...)
+	}
+	return excludedRules
+}
+
 func alwaysUpdateAuthPolicy(existingObj, desiredObj client.Object) (bool, error) {
 	existing, ok := existingObj.(*istio.AuthorizationPolicy)
 	if !ok {
@@ -184,30 +267,37 @@ func alwaysUpdateAuthPolicy(existingObj, desiredObj client.Object) (bool, error)
 		return false, fmt.Errorf("%T is not an *istio.AuthorizationPolicy", desiredObj)
 	}
 
-	if reflect.DeepEqual(existing.Spec.Action, desired.Spec.Action) {
-		return false, nil
+	update := false
+
+	if existing.Spec.Action != desired.Spec.Action {
+		existing.Spec.Action = desired.Spec.Action
+		update = true
+	}
+
+	if !proto.Equal(existing.Spec.GetProvider(), desired.Spec.GetProvider()) {
+		existing.Spec.ActionDetail = desired.Spec.ActionDetail
+		update = true
 	}
-	existing.Spec.Action = desired.Spec.Action
 
-	if reflect.DeepEqual(existing.Spec.ActionDetail, desired.Spec.ActionDetail) {
-		return false, nil
+	if !common.EqualProtoSlices(existing.Spec.Rules, desired.Spec.Rules) {
+		existing.Spec.Rules = desired.Spec.Rules
+		update = true
 	}
-	existing.Spec.ActionDetail = desired.Spec.ActionDetail
 
-	if reflect.DeepEqual(existing.Spec.Rules, desired.Spec.Rules) {
-		return false, nil
+	if !proto.Equal(existing.Spec.Selector, desired.Spec.Selector) {
+		existing.Spec.Selector = desired.Spec.Selector
+		update = true
 	}
-	existing.Spec.Rules = desired.Spec.Rules
 
-	if reflect.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
-		return false, nil
+	if !reflect.DeepEqual(existing.Annotations, desired.Annotations) {
+		existing.Annotations = desired.Annotations
+		update = true
 	}
-	existing.Spec.Selector = desired.Spec.Selector
 
-	if reflect.DeepEqual(existing.Annotations, desired.Annotations) {
-		return false, nil
+	// the policy replacing another takes over the AuthorizationPolicy of the target, i.e. the back reference labels
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
 	}
-	existing.Annotations = desired.Annotations
 
-	return true, nil
+	return update, nil
 }
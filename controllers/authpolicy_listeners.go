@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	istiosecurity "istio.io/api/security/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// listenersNotFoundError is the error of a policy scoped to listeners missing from its gateways
+type listenersNotFoundError struct {
+	reason string
+}
+
+func (e *listenersNotFoundError) Error() string {
+	return e.reason
+}
+
+func isListenersNotFound(err error) bool {
+	notFoundErr := &listenersNotFoundError{}
+	return errors.As(err, &notFoundErr)
+}
+
+// selectedListeners returns the listeners of a gateway the policy is enforced on
+func selectedListeners(gateway *gatewayapiv1beta1.Gateway, scope *api.AuthListenersSpec) []gatewayapiv1beta1.Listener {
+	return common.Filter(gateway.Spec.Listeners, scope.Selects)
+}
+
+// selectedListenerPorts returns the sorted ports of the listeners of a gateway the policy is enforced on
+func selectedListenerPorts(gateway *gatewayapiv1beta1.Gateway, scope *api.AuthListenersSpec) []string {
+	ports := make([]string, 0)
+	for _, listener := range selectedListeners(gateway, scope) {
+		if port := strconv.Itoa(int(listener.Port)); !common.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// istioAuthorizationPolicyRulesOnPorts restricts the rules of the requests sent to the external authorization provider
+// to the ports of the listeners the policy is enforced on
+func istioAuthorizationPolicyRulesOnPorts(toRules []*istiosecurity.Rule_To, ports []string) []*istiosecurity.Rule_To {
+	portRules := make([]*istiosecurity.Rule_To, 0, len(toRules))
+	for _, rule := range toRules {
+		portRule, _ := proto.Clone(rule).(*istiosecurity.Rule_To)
+		if portRule.Operation == nil {
+			portRule.Operation = &istiosecurity.Operation{}
+		}
+		portRule.Operation.Ports = common.SliceCopy(ports)
+		portRules = append(portRules, portRule)
+	}
+	return portRules
+}
+
+// policyGateways returns the existing gateways of the network object targeted by the policy
+func (r *AuthPolicyReconciler) policyGateways(ctx context.Context, targetNetworkObject client.Object) ([]*gatewayapiv1beta1.Gateway, error) {
+	gateways := make([]*gatewayapiv1beta1.Gateway, 0)
+	for _, gwKey := range r.TargetedGatewayKeys(ctx, targetNetworkObject) {
+		gw := &gatewayapiv1beta1.Gateway{}
+		if err := r.Client().Get(ctx, gwKey, gw); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+		gateways = append(gateways, gw)
+	}
+	return gateways, nil
+}
+
+// validateListeners rejects the policies scoped to listeners whose names are missing from all their gateways,
+// or selecting no listener of one of their gateways, the policy not enforced on the gateway at all
+func (r *AuthPolicyReconciler) validateListeners(ctx context.Context, ap *api.AuthPolicy, targetNetworkObject client.Object) error {
+	if ap.Spec.Listeners == nil {
+		return nil
+	}
+
+	gateways, err := r.policyGateways(ctx, targetNetworkObject)
+	if err != nil {
+		return err
+	}
+
+	missingNames := common.SliceCopy(ap.Spec.Listeners.Names)
+	for _, gw := range gateways {
+		for _, listener := range gw.Spec.Listeners {
+			missingNames = common.Filter(missingNames, func(name gatewayapiv1beta1.SectionName) bool { return name != listener.Name })
+		}
+	}
+	if len(missingNames) > 0 {
+		return &listenersNotFoundError{reason: fmt.Sprintf("listeners %s not found in the gateways of the policy", strings.Join(common.Map(missingNames, func(name gatewayapiv1beta1.SectionName) string { return string(name) }), ", "))}
+	}
+
+	for _, gw := range gateways {
+		if len(selectedListeners(gw, ap.Spec.Listeners)) == 0 {
+			return &listenersNotFoundError{reason: fmt.Sprintf("no listener of gateway %s selected by the listeners of the policy", client.ObjectKeyFromObject(gw))}
+		}
+	}
+
+	return nil
+}
+
+// coveredListeners returns the listeners of the gateways a policy scoped to some of the listeners is enforced on,
+// in the form <gateway>/<listener>
+func (r *AuthPolicyReconciler) coveredListeners(ctx context.Context, ap *api.AuthPolicy) ([]string, error) {
+	if ap.Spec.Listeners == nil {
+		return nil, nil
+	}
+
+	targetNetworkObject, err := r.FetchValidTargetRef(ctx, ap.GetTargetRef(), ap.Namespace)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	gateways, err := r.policyGateways(ctx, targetNetworkObject)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]string, 0)
+	for _, gw := range gateways {
+		for _, listener := range selectedListeners(gw, ap.Spec.Listeners) {
+			listeners = append(listeners, fmt.Sprintf("%s/%s", client.ObjectKeyFromObject(gw), listener.Name))
+		}
+	}
+	sort.Strings(listeners)
+	return listeners, nil
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const APCrossNamespaceForbiddenConditionType string = "CrossNamespaceForbidden"
+
+// crossNamespaceForbiddenError is the error of a policy targeting a Gateway in another namespace not granting the reference
+type crossNamespaceForbiddenError struct {
+	gateway client.ObjectKey
+}
+
+func (e *crossNamespaceForbiddenError) Error() string {
+	return fmt.Sprintf("no ReferenceGrant in namespace %s permits AuthPolicies to target the Gateway %s", e.gateway.Namespace, e.gateway)
+}
+
+func isCrossNamespaceForbidden(err error) bool {
+	forbiddenErr := &crossNamespaceForbiddenError{}
+	return errors.As(err, &forbiddenErr)
+}
+
+// validateCrossNamespaceTargetRef checks a ReferenceGrant in the namespace of the Gateway targeted by a policy in
+// another namespace permits the reference
+func (r *AuthPolicyReconciler) validateCrossNamespaceTargetRef(ctx context.Context, ap *kuadrantv1beta1.AuthPolicy) error {
+	if !ap.IsCrossNamespaceTargetRef() {
+		return nil
+	}
+
+	gwKey := client.ObjectKey{Name: string(ap.Spec.TargetRef.Name), Namespace: string(*ap.Spec.TargetRef.Namespace)}
+
+	grantList := &gatewayapiv1beta1.ReferenceGrantList{}
+	if err := r.Client().List(ctx, grantList, client.InNamespace(gwKey.Namespace)); err != nil {
+		return err
+	}
+
+	for idx := range grantList.Items {
+		if common.ReferenceGrantPermits(&grantList.Items[idx], kuadrantv1beta1.GroupVersion.Group, "AuthPolicy", ap.Namespace, gatewayapiv1beta1.GroupName, "Gateway", gwKey.Name) {
+			return nil
+		}
+	}
+
+	return &crossNamespaceForbiddenError{gateway: gwKey}
+}
+
+func (r *AuthPolicyReconciler) crossNamespaceForbiddenCondition(specErr error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    APCrossNamespaceForbiddenConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReferenceGrantMissing",
+		Message: specErr.Error(),
+	}
+}
@@ -3,19 +3,46 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
 	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
-const APAvailableConditionType string = "Available"
+const (
+	APAvailableConditionType       string = "Available"
+	APBackendNotFoundConditionType string = "BackendNotFound"
+	APExclusionsConditionType      string = "ExcludedHTTPRoutesNotAttached"
+	APSecretMissingConditionType   string = "ReferencedSecretMissing"
+
+	APAnonymousAccessOverlyBroadConditionType string = "AnonymousAccessOverlyBroad"
+)
+
+// authConfigGetBackoff tracks, per AuthPolicy, the delay before retrying after failing to read the AuthConfig
+var authConfigGetBackoff = workqueue.NewItemExponentialFailureRateLimiter(time.Second, 5*time.Minute)
+
+// authConfigGetFailureReason returns the reason of an error reading an AuthConfig, out of the bounded set of the
+// reasons of the API errors
+func authConfigGetFailureReason(err error) string {
+	if reason := errors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return "Unknown"
+}
 
 // reconcileStatus makes sure status block of AuthPolicy is up-to-date.
 func (r *AuthPolicyReconciler) reconcileStatus(ctx context.Context, ap *kuadrantv1beta1.AuthPolicy, specErr error) (ctrl.Result, error) {
@@ -24,21 +51,143 @@ func (r *AuthPolicyReconciler) reconcileStatus(ctx context.Context, ap *kuadrant
 
 	// fetch the AuthConfig and check if it's ready.
 	isAuthConfigReady := true
+	var authConfig *authorinov1beta1.AuthConfig
+	// checks again the readiness of an authconfig recently written until it times out
+	var readinessRequeue time.Duration
 	if specErr == nil { // skip fetching authconfig if we already have a reconciliation error.
 		apKey := client.ObjectKeyFromObject(ap)
+		authConfigNamespace, err := r.authConfigNamespace(ctx, ap)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 		authConfigKey := client.ObjectKey{
-			Namespace: ap.Namespace,
+			Namespace: authConfigNamespace,
 			Name:      authConfigName(apKey),
 		}
-		authConfig := &authorinov1beta1.AuthConfig{}
+		authConfig = &authorinov1beta1.AuthConfig{}
 		if err := r.GetResource(ctx, authConfigKey, authConfig); err != nil {
+			if !errors.IsNotFound(err) {
+				// transient error reading the authconfig; retry with exponential back-off
+				authConfigGetFailures.WithLabelValues(authConfigGetFailureReason(err)).Inc()
+				requeueAfter := authConfigGetBackoff.When(apKey)
+				logger.Error(err, "failed to get AuthConfig", "requeueAfter", requeueAfter)
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
+			// missing authconfig is reflected in the status
+			isAuthConfigReady = false
+			authConfig = nil
+		} else {
+			isAuthConfigReady = authConfig.Status.Ready()
+		}
+		authConfigGetBackoff.Forget(apKey)
+		var readinessTimedOut bool
+		readinessRequeue, readinessTimedOut = authConfigReadiness.Observe(authConfigKey, isAuthConfigReady)
+		if readinessTimedOut {
+			r.EventRecorder().Eventf(ap, corev1.EventTypeWarning, "AuthConfigNotReady", "AuthConfig %s not ready within %s after its last update", authConfigKey, AuthConfigReadyTimeout)
+		}
+	}
+
+	var missingBackends []client.ObjectKey
+	if specErr == nil {
+		var err error
+		if missingBackends, err = r.missingBackends(ctx, ap); err != nil {
 			return ctrl.Result{}, err
 		}
+	}
 
-		isAuthConfigReady = authConfig.Status.Ready()
+	var excludedRoutes []gatewayapiv1beta1.HTTPRoute
+	var notAttachedExclusions []client.ObjectKey
+	if specErr == nil {
+		var err error
+		if excludedRoutes, notAttachedExclusions, err = r.excludedHTTPRoutes(ctx, ap); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	newStatus := r.calculateStatus(ap, specErr, isAuthConfigReady, missingBackends, excludedRoutes, notAttachedExclusions)
+	setAuthConfigCounts(newStatus, ap, authConfig)
+
+	if specErr == nil {
+		authorino, _, err := r.servingAuthorino(ctx, ap)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		newStatus.Authorino = authorino
+		newStatus.FailureMode = ap.GetFailureMode()
+
+		if newStatus.ExtAuthzTimeout, newStatus.OutboundTimeout, err = r.authorinoTimeouts(ctx, ap); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if newStatus.Hierarchy, err = r.policyHierarchy(ctx, ap); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if newStatus.Listeners, err = r.coveredListeners(ctx, ap); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	setDeniedResponseCodes(newStatus, authConfig)
+
+	// informational only, the anonymous access is a security-relevant choice of the policy
+	if cond := anonymousAccessOverlyBroadCondition(newStatus.AnonymousAccess); cond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *cond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, APAnonymousAccessOverlyBroadConditionType)
+	}
+
+	// informational only, the evaluators referring to the missing secrets fail
+	missingSecrets, unmatchedSelectors, err := r.missingSecrets(ctx, authConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(missingSecrets)+len(unmatchedSelectors) > 0 {
+		meta.SetStatusCondition(&newStatus.Conditions, *r.secretMissingCondition(missingSecrets, unmatchedSelectors))
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, APSecretMissingConditionType)
+	}
+	hostConflicts, err := r.hostConflicts(ctx, authConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(hostConflicts) > 0 {
+		meta.SetStatusCondition(&newStatus.Conditions, *r.hostConflictCondition(hostConflicts))
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, APHostConflictConditionType)
+	}
+	setTemplateResolvedCondition(ctx, r.Client(), &newStatus.Conditions, ap.Namespace, ap.Spec.TemplateRef)
+	setIdentityProvidersResolvedCondition(ctx, r.Client(), &newStatus.Conditions, ap.Namespace, ap.Spec.IdentityProviders)
+	replaced, replacement, err := policyReplacementKeys(ctx, r.Client(), ap, &kuadrantv1beta1.AuthPolicyList{})
+	if err != nil {
+		return ctrl.Result{}, err
 	}
+	setReplacementCondition(&newStatus.Conditions, ap, replaced, replacement)
+	if err := clearBackendRemovedCondition(ctx, r.Client(), &newStatus.Conditions, ap); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if specErr == nil {
+		defaultsCond, err := r.authorinoDefaultsCondition(ctx, ap)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if defaultsCond != nil {
+			meta.SetStatusCondition(&newStatus.Conditions, *defaultsCond)
+		} else {
+			meta.RemoveStatusCondition(&newStatus.Conditions, APAuthorinoDefaultsAppliedConditionType)
+		}
 
-	newStatus := r.calculateStatus(ap, specErr, isAuthConfigReady)
+		// informational only, the policy is enforced once the pinned instance is ready
+		notReadyCond, err := r.authorinoInstanceNotReadyCondition(ctx, ap)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if notReadyCond != nil {
+			meta.SetStatusCondition(&newStatus.Conditions, *notReadyCond)
+		} else {
+			meta.RemoveStatusCondition(&newStatus.Conditions, APAuthorinoInstanceNotReadyConditionType)
+		}
+	}
 
 	equalStatus := ap.Status.Equals(newStatus, logger)
 	logger.V(1).Info("Status", "status is different", !equalStatus)
@@ -46,7 +195,7 @@ func (r *AuthPolicyReconciler) reconcileStatus(ctx context.Context, ap *kuadrant
 	logger.V(1).Info("Status", "AuthConfig is ready", isAuthConfigReady)
 	if equalStatus && ap.Generation == ap.Status.ObservedGeneration {
 		logger.V(1).Info("Status up-to-date. No changes required.")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: readinessRequeue}, nil
 	}
 
 	// Save the generation number we acted on, otherwise we might wrongfully indicate
@@ -68,10 +217,10 @@ func (r *AuthPolicyReconciler) reconcileStatus(ctx context.Context, ap *kuadrant
 
 		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", updateErr)
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: readinessRequeue}, nil
 }
 
-func (r *AuthPolicyReconciler) calculateStatus(ap *kuadrantv1beta1.AuthPolicy, specErr error, authConfigReady bool) *kuadrantv1beta1.AuthPolicyStatus {
+func (r *AuthPolicyReconciler) calculateStatus(ap *kuadrantv1beta1.AuthPolicy, specErr error, authConfigReady bool, missingBackends []client.ObjectKey, excludedRoutes []gatewayapiv1beta1.HTTPRoute, notAttachedExclusions []client.ObjectKey) *kuadrantv1beta1.AuthPolicyStatus {
 	newStatus := &kuadrantv1beta1.AuthPolicyStatus{
 		Conditions:         common.CopyConditions(ap.Status.Conditions),
 		ObservedGeneration: ap.Status.ObservedGeneration,
@@ -82,9 +231,133 @@ func (r *AuthPolicyReconciler) calculateStatus(ap *kuadrantv1beta1.AuthPolicy, s
 
 	meta.SetStatusCondition(&newStatus.Conditions, *availableCond)
 
+	// the changes pending, if any, are applied, and the generation reconciled
+	meta.RemoveStatusCondition(&newStatus.Conditions, PolicyChangePendingConditionType)
+	meta.RemoveStatusCondition(&newStatus.Conditions, ReconcileStalledConditionType)
+
+	setTargetRefInvalidCondition(&newStatus.Conditions, specErr)
+
+	if isCrossNamespaceForbidden(specErr) {
+		meta.SetStatusCondition(&newStatus.Conditions, *r.crossNamespaceForbiddenCondition(specErr))
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, APCrossNamespaceForbiddenConditionType)
+	}
+
+	// informational only, it does not block enforcement
+	if len(missingBackends) > 0 {
+		meta.SetStatusCondition(&newStatus.Conditions, *r.backendNotFoundCondition(missingBackends))
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, APBackendNotFoundConditionType)
+	}
+
+	for idx := range excludedRoutes {
+		newStatus.ExcludedHTTPRoutes = append(newStatus.ExcludedHTTPRoutes, client.ObjectKeyFromObject(&excludedRoutes[idx]).String())
+	}
+
+	// informational only, exclusions of routes not attached to the gateway are ignored
+	if len(notAttachedExclusions) > 0 {
+		meta.SetStatusCondition(&newStatus.Conditions, *r.exclusionsNotAttachedCondition(notAttachedExclusions))
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, APExclusionsConditionType)
+	}
+
+	setExpiringCondition(&newStatus.Conditions, ap.Spec.ExpiresAt)
+
 	return newStatus
 }
 
+// setAuthConfigCounts summarizes the shape of the generated AuthConfig in the status
+func setAuthConfigCounts(status *kuadrantv1beta1.AuthPolicyStatus, ap *kuadrantv1beta1.AuthPolicy, authConfig *authorinov1beta1.AuthConfig) {
+	if authConfig == nil {
+		return
+	}
+	status.NumAuthRules = len(ap.Spec.AuthRules)
+	status.NumAuthentication = len(authConfig.Spec.Identity)
+	status.NumAuthorization = len(authConfig.Spec.Authorization)
+	status.NumResponse = len(authConfig.Spec.Response)
+
+	for _, response := range authConfig.Spec.Response {
+		if header, ok := kuadrantv1beta1.ResponseHeaderName(response); ok {
+			status.InjectedHeaders = append(status.InjectedHeaders, header)
+		}
+	}
+	sort.Strings(status.InjectedHeaders)
+
+	status.AnonymousAccess = kuadrantv1beta1.AnonymousAccessOf(authConfig.Spec)
+	status.MissingIdentity = kuadrantv1beta1.MissingIdentityOf(authConfig.Spec)
+
+	if len(authConfig.Spec.Identity) > 0 {
+		status.IdentityOrder = kuadrantv1beta1.IdentityOrderOf(authConfig.Spec)
+	}
+
+	status.Cache = kuadrantv1beta1.EvaluatorCachesOf(authConfig.Spec)
+	status.ClientCertificate = kuadrantv1beta1.ClientCertificateIdentitiesOf(authConfig.Spec)
+}
+
+// anonymousAccessOverlyBroadCondition returns a warning condition if an anonymous identity of the AuthConfig
+// is not restricted to specific paths, or nil
+func anonymousAccessOverlyBroadCondition(accesses []kuadrantv1beta1.AnonymousAccess) *metav1.Condition {
+	reasons := make([]string, 0)
+	for _, access := range accesses {
+		// the anonymous fallback is set explicitly by the missingIdentity of the policy
+		if access.Name == kuadrantv1beta1.AnonymousFallbackIdentityName {
+			continue
+		}
+		if reason := access.OverlyBroad(); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:    APAnonymousAccessOverlyBroadConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "OverlyBroadAnonymousAccess",
+		Message: fmt.Sprintf("Anonymous access not restricted to specific paths: %s", strings.Join(reasons, "; ")),
+	}
+}
+
+// setDeniedResponseCodes reflects the status codes of the denied responses of the AuthConfig, Authorino's defaults unless overridden
+func setDeniedResponseCodes(status *kuadrantv1beta1.AuthPolicyStatus, authConfig *authorinov1beta1.AuthConfig) {
+	if authConfig == nil {
+		return
+	}
+	status.UnauthenticatedResponseCode = http.StatusUnauthorized
+	status.UnauthorizedResponseCode = http.StatusForbidden
+	if denyWith := authConfig.Spec.DenyWith; denyWith != nil {
+		if denyWith.Unauthenticated != nil && denyWith.Unauthenticated.Code != 0 {
+			status.UnauthenticatedResponseCode = int(denyWith.Unauthenticated.Code)
+		}
+		if denyWith.Unauthorized != nil && denyWith.Unauthorized.Code != 0 {
+			status.UnauthorizedResponseCode = int(denyWith.Unauthorized.Code)
+		}
+	}
+}
+
+// authorinoTimeouts returns the timeout of the ext_authz requests set by the kuadrant instance of the policy, and the
+// timeout of the requests of Authorino to the external providers, empty if not set
+func (r *AuthPolicyReconciler) authorinoTimeouts(ctx context.Context, ap *kuadrantv1beta1.AuthPolicy) (string, string, error) {
+	timeout := common.DefaultExtAuthzTimeout
+	outboundTimeout := ""
+
+	if kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(ap); isSet {
+		kuadrantList := &kuadrantv1beta1.KuadrantList{}
+		if err := r.Client().List(ctx, kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+			return "", "", err
+		}
+		if len(kuadrantList.Items) > 0 && kuadrantList.Items[0].AuthorinoTimeout() != nil {
+			timeout = *kuadrantList.Items[0].AuthorinoTimeout()
+		}
+		if len(kuadrantList.Items) > 0 && kuadrantList.Items[0].AuthorinoOutboundTimeout() != nil {
+			outboundTimeout = kuadrantList.Items[0].AuthorinoOutboundTimeout().String()
+		}
+	}
+
+	return timeout.String(), outboundTimeout, nil
+}
+
 func (r *AuthPolicyReconciler) availableCondition(targetNetworkObjectectKind string, specErr error, authConfigReady bool) *metav1.Condition {
 	// Condition if there is not issue
 	cond := &metav1.Condition{
@@ -98,6 +371,22 @@ func (r *AuthPolicyReconciler) availableCondition(targetNetworkObjectectKind str
 		cond.Status = metav1.ConditionFalse
 		cond.Reason = "ReconciliationError"
 		cond.Message = specErr.Error()
+		// a dangling reference to an identity source fails silently in Authorino
+		if kuadrantv1beta1.IsIdentityReferenceError(specErr) {
+			cond.Reason = "UnknownIdentitySource"
+		}
+		// the client certificates are not forwarded to Authorino
+		if isClientCertificateNotVerified(specErr) {
+			cond.Reason = "ClientCertificateNotVerified"
+		}
+		// the policy would not be enforced on some of its gateways
+		if isListenersNotFound(specErr) {
+			cond.Reason = "ListenersNotFound"
+		}
+		// the evaluators referring to the secrets would fail
+		if isAuthConfigSecretsOutOfScope(specErr) {
+			cond.Reason = "SecretsOutOfAuthorinoScope"
+		}
 	} else if !authConfigReady {
 		cond.Status = metav1.ConditionFalse
 		cond.Reason = "AuthSchemeNotReady"
@@ -106,3 +395,161 @@ func (r *AuthPolicyReconciler) availableCondition(targetNetworkObjectectKind str
 
 	return cond
 }
+
+func (r *AuthPolicyReconciler) backendNotFoundCondition(missingBackends []client.ObjectKey) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    APBackendNotFoundConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "BackendNotFound",
+		Message: fmt.Sprintf("Backends of the protected HTTPRoute not found: %s", strings.Join(common.Map(missingBackends, func(key client.ObjectKey) string { return key.String() }), ", ")),
+	}
+}
+
+func (r *AuthPolicyReconciler) exclusionsNotAttachedCondition(notAttached []client.ObjectKey) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    APExclusionsConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "HTTPRouteNotAttached",
+		Message: fmt.Sprintf("Excluded HTTPRoutes not attached to the targeted Gateway: %s", strings.Join(common.Map(notAttached, func(key client.ObjectKey) string { return key.String() }), ", ")),
+	}
+}
+
+// missingBackends returns the keys of the Services referenced as backends by the targeted HTTPRoute that do not exist
+func (r *AuthPolicyReconciler) missingBackends(ctx context.Context, ap *kuadrantv1beta1.AuthPolicy) ([]client.ObjectKey, error) {
+	if !common.IsTargetRefHTTPRoute(ap.GetTargetRef()) {
+		return nil, nil
+	}
+
+	route := &gatewayapiv1beta1.HTTPRoute{}
+	routeKey := client.ObjectKey{Name: string(ap.GetTargetRef().Name), Namespace: ap.Namespace}
+	if err := r.Client().Get(ctx, routeKey, route); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	missingBackends := make([]client.ObjectKey, 0)
+	for _, serviceKey := range common.HTTPRouteBackendServiceKeys(route) {
+		if err := r.Client().Get(ctx, serviceKey, &corev1.Service{}); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			missingBackends = append(missingBackends, serviceKey)
+		}
+	}
+
+	return missingBackends, nil
+}
+
+func (r *AuthPolicyReconciler) secretMissingCondition(missingSecrets []client.ObjectKey, unmatchedSelectors []authConfigSecretSelector) *metav1.Condition {
+	messages := make([]string, 0, 2)
+	if len(missingSecrets) > 0 {
+		messages = append(messages, fmt.Sprintf("Secrets referenced by the policy not found: %s", strings.Join(common.Map(missingSecrets, func(key client.ObjectKey) string { return key.String() }), ", ")))
+	}
+	if len(unmatchedSelectors) > 0 {
+		messages = append(messages, fmt.Sprintf("No Secret selected by the identities of the policy: %s", strings.Join(common.Map(unmatchedSelectors, authConfigSecretSelector.String), ", ")))
+	}
+	return &metav1.Condition{
+		Type:    APSecretMissingConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SecretNotFound",
+		Message: strings.Join(messages, ". "),
+	}
+}
+
+// missingSecrets returns the keys of the Secrets referenced by the AuthConfig of the policy that do not exist, and
+// the selectors of the identities of the AuthConfig, i.e. API keys and mTLS, selecting no Secret. The metadata of the
+// Secrets is read from the cache, the Secrets being watched.
+func (r *AuthPolicyReconciler) missingSecrets(ctx context.Context, authConfig *authorinov1beta1.AuthConfig) ([]client.ObjectKey, []authConfigSecretSelector, error) {
+	if authConfig == nil {
+		return nil, nil, nil
+	}
+
+	authScheme := &kuadrantv1beta1.AuthSchemeSpec{
+		Identity:      authConfig.Spec.Identity,
+		Metadata:      authConfig.Spec.Metadata,
+		Authorization: authConfig.Spec.Authorization,
+		Response:      authConfig.Spec.Response,
+	}
+
+	missingSecrets := make([]client.ObjectKey, 0)
+	for _, name := range authScheme.SecretRefs() {
+		secretKey := client.ObjectKey{Name: name, Namespace: authConfig.Namespace}
+		if err := r.Client().Get(ctx, secretKey, secretMetadata()); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, nil, err
+			}
+			missingSecrets = append(missingSecrets, secretKey)
+		}
+	}
+
+	unmatchedSelectors := make([]authConfigSecretSelector, 0)
+	for _, secretSelector := range authConfigSecretSelectors(&authConfig.Spec) {
+		selector, err := metav1.LabelSelectorAsSelector(secretSelector.selector)
+		if err != nil {
+			// rejected by Authorino, reflected in the readiness of the AuthConfig
+			continue
+		}
+		opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}, client.Limit(1)}
+		if !secretSelector.allNamespaces {
+			opts = append(opts, client.InNamespace(authConfig.Namespace))
+		}
+		secretList := &metav1.PartialObjectMetadataList{}
+		secretList.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("SecretList"))
+		if err := r.Client().List(ctx, secretList, opts...); err != nil {
+			return nil, nil, err
+		}
+		if len(secretList.Items) == 0 {
+			unmatchedSelectors = append(unmatchedSelectors, secretSelector)
+		}
+	}
+
+	return missingSecrets, unmatchedSelectors, nil
+}
+
+// secretMetadata returns the metadata of a Secret to read, the Secrets being cached as metadata only
+func secretMetadata() *metav1.PartialObjectMetadata {
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return secret
+}
+
+// authConfigSecretSelector is the label selector of the Secrets of an identity of an AuthConfig, holding the API keys
+// or the trusted CAs of the clients
+type authConfigSecretSelector struct {
+	identity      string
+	selector      *metav1.LabelSelector
+	allNamespaces bool
+}
+
+func (s authConfigSecretSelector) String() string {
+	return fmt.Sprintf("%s (%s)", s.identity, metav1.FormatLabelSelector(s.selector))
+}
+
+// selects tells whether the selector selects the Secret, the AuthConfig of the selector being in the given namespace
+func (s authConfigSecretSelector) selects(authConfigNamespace string, secret client.Object) bool {
+	if !s.allNamespaces && secret.GetNamespace() != authConfigNamespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(s.selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(secret.GetLabels()))
+}
+
+// authConfigSecretSelectors returns the label selectors of the Secrets of the API key and mTLS identities of an
+// AuthConfig
+func authConfigSecretSelectors(spec *authorinov1beta1.AuthConfigSpec) []authConfigSecretSelector {
+	selectors := make([]authConfigSecretSelector, 0)
+	for _, identity := range spec.Identity {
+		if identity == nil {
+			continue
+		}
+		if identity.APIKey != nil && identity.APIKey.Selector != nil {
+			selectors = append(selectors, authConfigSecretSelector{identity: identity.Name, selector: identity.APIKey.Selector, allNamespaces: identity.APIKey.AllNamespaces})
+		}
+		if identity.MTLS != nil && identity.MTLS.Selector != nil {
+			selectors = append(selectors, authConfigSecretSelector{identity: identity.Name, selector: identity.MTLS.Selector, allNamespaces: identity.MTLS.AllNamespaces})
+		}
+	}
+	return selectors
+}
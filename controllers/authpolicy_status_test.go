@@ -0,0 +1,57 @@
+//go:build unit
+
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// the status written by a previous version of the operator is recomputed by the first reconciliation of the policy,
+// with no migration step
+func TestAuthPolicyCalculateStatusReplacesStaleConditions(t *testing.T) {
+	route := testHTTPRoute("route-a", testGateway("gw"), "a.example.com")
+	ap := testAuthPolicy("ap", route.Namespace, route)
+	ap.Status.ObservedGeneration = 1
+	ap.Status.Conditions = []metav1.Condition{
+		{Type: APAvailableConditionType, Status: metav1.ConditionFalse, Reason: "LegacyReason", Message: "set by a previous version"},
+		{Type: PolicyChangePendingConditionType, Status: metav1.ConditionTrue, Reason: "CoolingDown"},
+		{Type: ReconcileStalledConditionType, Status: metav1.ConditionTrue, Reason: "ObservedGenerationBehind"},
+	}
+
+	status := (&AuthPolicyReconciler{}).calculateStatus(ap, nil, true, nil, nil, nil)
+
+	available := meta.FindStatusCondition(status.Conditions, APAvailableConditionType)
+	if available == nil || available.Status != metav1.ConditionTrue || available.Reason != "HTTPRouteProtected" {
+		t.Fatalf("expected the available condition recomputed, got %+v", available)
+	}
+	for _, condType := range []string{PolicyChangePendingConditionType, ReconcileStalledConditionType} {
+		if meta.FindStatusCondition(status.Conditions, condType) != nil {
+			t.Errorf("expected the %s condition removed", condType)
+		}
+	}
+	if len(ap.Status.Conditions) != 3 || ap.Status.Conditions[0].Reason != "LegacyReason" {
+		t.Error("expected the conditions of the policy left untouched")
+	}
+}
+
+// the failures are counted per reason of the error, not per policy, to bound the number of series
+func TestAuthConfigGetFailureReason(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		reason string
+	}{
+		{apierrors.NewForbidden(schema.GroupResource{Resource: "authconfigs"}, "ac", errors.New("denied")), "Forbidden"},
+		{apierrors.NewTimeoutError("timeout", 1), "Timeout"},
+		{errors.New("connection refused"), "Unknown"},
+	} {
+		if reason := authConfigGetFailureReason(tc.err); reason != tc.reason {
+			t.Errorf("expected reason %s for %v, got %s", tc.reason, tc.err, reason)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
+)
+
+// effectiveConfig returns the configuration resolved by the running operator, indexed by the name of the env var,
+// flag or config file field it is read from. The values are the ones in use, i.e. the defaults when a value is
+// missing or invalid.
+func (r *KuadrantReconciler) effectiveConfig() map[string]string {
+	config := map[string]string{
+		"AUTH_PROVIDER":                     KuadrantExtAuthProviderName,
+		"AUTH_PROVIDER_FAIL_OPEN":           KuadrantExtAuthFailOpenProviderName,
+		"RELATED_IMAGE_WASMSHIM":            rlptools.WASMFilterImageURL,
+		"LIMITADOR_LIMITS_SOFT_CAP":         strconv.Itoa(rlptools.LimitsSoftCap),
+		"AUTHCONFIGS_SOFT_CAP":              strconv.Itoa(AuthConfigsSoftCap),
+		"ISTIOOPERATOR_NAME":                controlPlaneProviderName(),
+		"ISTIOOPERATOR_NAMESPACE":           controlPlaneProviderNamespace(),
+		"ISTIOCONFIGMAP_NAME":               controlPlaneConfigMapName(),
+		"OPERATOR_NAMESPACE":                operatorNamespace(),
+		"AUTHPOLICY_RECONCILE_WORKERS":      strconv.Itoa(AuthPolicyReconcileWorkers),
+		"RATELIMITPOLICY_RECONCILE_WORKERS": strconv.Itoa(RateLimitPolicyReconcileWorkers),
+		"KUADRANT_RECONCILE_WORKERS":        strconv.Itoa(KuadrantReconcileWorkers),
+		"AUTHCONFIG_READY_TIMEOUT_SECONDS":  strconv.Itoa(int(AuthConfigReadyTimeout.Seconds())),
+		"KUADRANT_RECONCILE_TASKS":          kuadrantReconcileTaskOrderString(),
+		"NORMALIZE_POLICY_TARGETREFS":       strconv.FormatBool(NormalizePolicyTargetRefs),
+	}
+	for key, value := range reconcileRates {
+		config[key] = value
+	}
+	for key, value := range r.StartupConfig {
+		config[key] = value
+	}
+	// the operator config reloaded at runtime prevails over the startup config
+	operatorConfig, _ := activeOperatorConfig.Get()
+	config["LOG_LEVEL"] = operatorConfig.LogLevel
+	config["operatorConfig.dryRun"] = strconv.FormatBool(operatorConfig.DryRun)
+	return config
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// EffectivePoliciesReconciler keeps the list of the policies affecting an HTTPRoute, annotated in the route, up to
+// date with the parent gateways of the route, e.g. attached to or detached from a gateway with policies after the
+// policies were reconciled
+type EffectivePoliciesReconciler struct {
+	reconcilers.TargetRefReconciler
+}
+
+func (r *EffectivePoliciesReconciler) Reconcile(eventCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger().WithValues("HTTPRoute", req.NamespacedName, "reconcileID", controller.ReconcileIDFromContext(eventCtx))
+	ctx := logr.NewContext(eventCtx, logger)
+
+	route := &gatewayapiv1beta1.HTTPRoute{}
+	if err := r.Client().Get(ctx, req.NamespacedName, route); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return ctrl.Result{}, r.ReconcileEffectivePoliciesAnnotation(ctx, route)
+}
+
+// mapGatewayToHTTPRoutes maps a gateway to the HTTPRoutes referring to it as parent
+func (r *EffectivePoliciesReconciler) mapGatewayToHTTPRoutes(obj client.Object) []reconcile.Request {
+	routeList := &gatewayapiv1beta1.HTTPRouteList{}
+	if err := r.Client().List(context.Background(), routeList); err != nil {
+		r.Logger().V(1).Info("mapGatewayToHTTPRoutes: failed to list httproutes", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range routeList.Items {
+		route := &routeList.Items[idx]
+		for _, gwKey := range r.TargetedGatewayKeys(context.Background(), route) {
+			if gwKey == client.ObjectKeyFromObject(obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EffectivePoliciesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("effectivepolicies").
+		// the parent gateways of the routes are in their spec, the annotation written is not a change
+		For(&gatewayapiv1beta1.HTTPRoute{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the policies targeting the gateways are back references annotated in the gateways
+		Watches(&source.Kind{Type: &gatewayapiv1beta1.Gateway{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapGatewayToHTTPRoutes),
+			builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Complete(withLastSuccessMetric("effectivepolicies", r))
+}
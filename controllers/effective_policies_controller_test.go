@@ -0,0 +1,63 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestEffectivePoliciesReconciler(t *testing.T) {
+	gw := testGateway("gw")
+	gw.Annotations = map[string]string{common.RateLimitPolicyBackRefAnnotation: "gw-ns/gw-rlp"}
+	route := testHTTPRoute("route", gw, "api.example.com")
+	route.Annotations = map[string]string{common.AuthPolicyBackRefAnnotation: "ns/route-ap"}
+
+	r := &EffectivePoliciesReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route)}
+	effectivePolicies := func() string {
+		existing := &gatewayapiv1beta1.HTTPRoute{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(route), existing); err != nil {
+			t.Fatal(err)
+		}
+		return existing.Annotations[common.EffectivePoliciesAnnotation]
+	}
+
+	// attached after the policy of the gateway was reconciled
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+		t.Fatal(err)
+	}
+	if policies := effectivePolicies(); policies != "authpolicy/ns/route-ap,ratelimitpolicy/gw-ns/gw-rlp" {
+		t.Errorf("expected the policies of the route and of the gateway, got %q", policies)
+	}
+
+	// detached from the gateway
+	existing := &gatewayapiv1beta1.HTTPRoute{}
+	if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(route), existing); err != nil {
+		t.Fatal(err)
+	}
+	existing.Spec.ParentRefs = nil
+	if err := r.Client().Update(context.TODO(), existing); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+		t.Fatal(err)
+	}
+	if policies := effectivePolicies(); policies != "authpolicy/ns/route-ap" {
+		t.Errorf("expected the policy of the route only, got %q", policies)
+	}
+
+	// the routes of a gateway are reconciled on the changes of its policies
+	otherRoute := testHTTPRoute("other", testGateway("other-gw"), "other.example.com")
+	r = &EffectivePoliciesReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route, otherRoute)}
+	requests := r.mapGatewayToHTTPRoutes(gw)
+	if len(requests) != 1 || requests[0] != (reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}) {
+		t.Errorf("expected the route of the gateway enqueued, got %v", requests)
+	}
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	istioclientnetworkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	kuadrantistioutils "github.com/kuadrant/kuadrant-operator/pkg/istio"
+)
+
+// EnvoyClustersPath is the path of the endpoint rendering the clusters the gateways are expected to send the
+// ext_authz and rate limit requests to
+const EnvoyClustersPath = "/envoy-clusters"
+
+const DataPlaneMismatchConditionType string = "DataPlaneConfigMismatch"
+
+// ExpectedEnvoyClusters are the clusters the gateways managed by a Kuadrant instance are expected to have,
+// along with the differences with the actual services and EnvoyFilters
+type ExpectedEnvoyClusters struct {
+	Kuadrant   string                `json:"kuadrant"`
+	ExtAuthz   *ExpectedEnvoyCluster `json:"extAuthz,omitempty"`
+	RateLimit  *ExpectedEnvoyCluster `json:"rateLimit,omitempty"`
+	Mismatches []string              `json:"mismatches"`
+}
+
+// ExpectedEnvoyCluster is the definition of a cluster expected in the config of Envoy
+type ExpectedEnvoyCluster struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	// ClusterIP is the address the service resolves to, for comparison with the endpoints of the cluster in Envoy
+	ClusterIP      string `json:"clusterIP,omitempty"`
+	ConnectTimeout string `json:"connectTimeout,omitempty"`
+}
+
+// expectedEnvoyClusters renders the clusters expected for a Kuadrant instance from the managed Authorino and Limitador services
+func expectedEnvoyClusters(ctx context.Context, cli client.Client, kObj *kuadrantv1beta1.Kuadrant) (*ExpectedEnvoyClusters, error) {
+	expected := &ExpectedEnvoyClusters{
+		Kuadrant:   client.ObjectKeyFromObject(kObj).String(),
+		Mismatches: make([]string, 0),
+	}
+
+	// ext_authz, as registered in the mesh config; the cluster is generated by istio
+	if provider := common.NewKuadrantAuthorizer(kObj.Namespace).GetExtensionProvider().GetEnvoyExtAuthzGrpc(); provider != nil {
+		expected.ExtAuthz = &ExpectedEnvoyCluster{
+			Name:    fmt.Sprintf("outbound|%d||%s", provider.GetPort(), provider.GetService()),
+			Address: provider.GetService(),
+			Port:    int(provider.GetPort()),
+		}
+		clusterIP, mismatch, err := serviceClusterIP(ctx, cli, provider.GetService(), expected.ExtAuthz.Port)
+		if err != nil {
+			return nil, err
+		}
+		expected.ExtAuthz.ClusterIP = clusterIP
+		if mismatch != "" {
+			expected.Mismatches = append(expected.Mismatches, fmt.Sprintf("ext_authz: %s", mismatch))
+		}
+	}
+
+	// rate limit, as patched in the EnvoyFilters of the gateways
+	limitador := &limitadorv1alpha1.Limitador{}
+	err := cli.Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: kObj.Namespace}, limitador)
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	if err != nil || limitador.Status.Service == nil || limitador.Status.Service.Host == "" {
+		return expected, nil
+	}
+
+	expected.RateLimit = &ExpectedEnvoyCluster{
+		Name:           common.KuadrantRateLimitClusterName,
+		Address:        limitador.Status.Service.Host,
+		Port:           int(limitador.Status.Service.Ports.GRPC),
+		ConnectTimeout: "1s",
+	}
+	clusterIP, mismatch, err := serviceClusterIP(ctx, cli, expected.RateLimit.Address, expected.RateLimit.Port)
+	if err != nil {
+		return nil, err
+	}
+	expected.RateLimit.ClusterIP = clusterIP
+	if mismatch != "" {
+		expected.Mismatches = append(expected.Mismatches, fmt.Sprintf("rate limit: %s", mismatch))
+	}
+
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := cli.List(ctx, gwList); err != nil {
+		return nil, err
+	}
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+
+		ef := &istioclientnetworkingv1alpha3.EnvoyFilter{}
+		efKey := client.ObjectKey{Name: fmt.Sprintf("kuadrant-ratelimiting-cluster-%s", gw.Name), Namespace: gw.Namespace}
+		if err := cli.Get(ctx, efKey, ef); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				// no rate limit policy in play for the gateway
+				continue
+			}
+			return nil, err
+		}
+		address, port, found := kuadrantistioutils.LimitadorClusterAddress(ef)
+		if found && (address != expected.RateLimit.Address || port != expected.RateLimit.Port) {
+			expected.Mismatches = append(expected.Mismatches, fmt.Sprintf("rate limit: EnvoyFilter %s sends to %s:%d instead of %s:%d", efKey, address, port, expected.RateLimit.Address, expected.RateLimit.Port))
+		}
+	}
+
+	return expected, nil
+}
+
+// serviceClusterIP returns the cluster IP of the service of a host of the form <name>.<namespace>.svc..., or a
+// description of the mismatch if the service is missing or does not expose the port
+func serviceClusterIP(ctx context.Context, cli client.Client, host string, port int) (string, string, error) {
+	labels := strings.Split(host, ".")
+	if len(labels) < 3 || labels[2] != "svc" {
+		return "", "", nil
+	}
+
+	service := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: labels[0], Namespace: labels[1]}, service); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Sprintf("service %s/%s not found", labels[1], labels[0]), nil
+		}
+		return "", "", err
+	}
+
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) == port {
+			return service.Spec.ClusterIP, "", nil
+		}
+	}
+
+	return service.Spec.ClusterIP, fmt.Sprintf("service %s/%s does not expose port %d", labels[1], labels[0], port), nil
+}
+
+// dataPlaneMismatchCondition returns a condition listing the differences between the expected clusters and the actual
+// services and EnvoyFilters, or nil if none
+func (r *KuadrantReconciler) dataPlaneMismatchCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*metav1.Condition, error) {
+	expected, err := expectedEnvoyClusters(ctx, r.Client(), kObj)
+	if err != nil {
+		return nil, err
+	}
+	if len(expected.Mismatches) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    DataPlaneMismatchConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ClustersMismatch",
+		Message: fmt.Sprintf("The gateways may not reach the services of Kuadrant: %s", strings.Join(expected.Mismatches, "; ")),
+	}, nil
+}
+
+// EnvoyClusters renders the clusters expected in the config of Envoy for each Kuadrant instance
+type EnvoyClusters struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewEnvoyClusters(c client.Client, logger logr.Logger) *EnvoyClusters {
+	return &EnvoyClusters{client: c, logger: logger}
+}
+
+// ServeHTTP renders the expected clusters in response to a GET request.
+// The requests must be authenticated with a bearer token of a subject allowed to get the path of the endpoint.
+func (e *EnvoyClusters) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if statusCode, err := authorizeNonResourceRequest(e.client, req, EnvoyClustersPath, "get"); err != nil {
+		e.logger.Info("unauthorized envoy clusters request", "reason", err.Error())
+		http.Error(rw, http.StatusText(statusCode), statusCode)
+		return
+	}
+
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := e.client.List(req.Context(), kuadrantList); err != nil {
+		e.logger.Error(err, "failed to list kuadrant instances")
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	result := make([]*ExpectedEnvoyClusters, 0, len(kuadrantList.Items))
+	for idx := range kuadrantList.Items {
+		expected, err := expectedEnvoyClusters(req.Context(), e.client, &kuadrantList.Items[idx])
+		if err != nil {
+			e.logger.Error(err, "failed to render the expected envoy clusters")
+			http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		result = append(result, expected)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(result); err != nil {
+		e.logger.Error(err, "failed to write the expected envoy clusters")
+	}
+}
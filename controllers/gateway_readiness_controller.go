@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// GatewayKuadrantReadyConditionType is the condition of the gateways managed by Kuadrant telling whether all the
+// policies affecting the gateway are enforced, for external tooling to wait on during rollouts
+const GatewayKuadrantReadyConditionType string = "kuadrant.io/Ready"
+
+// GatewayReadinessReconciler reports in the status of the gateways managed by Kuadrant whether the AuthPolicies and
+// the RateLimitPolicies affecting them are enforced
+type GatewayReadinessReconciler struct {
+	*reconcilers.BaseReconciler
+}
+
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gateways/status,verbs=get;update;patch
+
+func (r *GatewayReadinessReconciler) Reconcile(eventCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger().WithValues("Gateway", req.NamespacedName, "reconcileID", controller.ReconcileIDFromContext(eventCtx))
+	ctx := logr.NewContext(eventCtx, logger)
+
+	gw := &gatewayapiv1beta1.Gateway{}
+	if err := r.Client().Get(ctx, req.NamespacedName, gw); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	conditions := common.CopyConditions(gw.Status.Conditions)
+	if _, err := common.GetKuadrantNamespace(gw); err != nil {
+		// no longer managed by kuadrant
+		meta.RemoveStatusCondition(&conditions, GatewayKuadrantReadyConditionType)
+	} else {
+		cond, err := r.kuadrantReadyCondition(ctx, gw)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		meta.SetStatusCondition(&conditions, *cond)
+	}
+
+	// marshalling sorts by condition type
+	currentJSON, _ := common.ConditionMarshal(gw.Status.Conditions)
+	desiredJSON, _ := common.ConditionMarshal(conditions)
+	if string(currentJSON) == string(desiredJSON) {
+		return ctrl.Result{}, nil
+	}
+
+	gw.Status.Conditions = conditions
+	if err := r.UpdateResourceStatus(ctx, gw); err != nil {
+		// the gateway controller writes the status of the gateway too
+		if apierrors.IsConflict(err) {
+			logger.V(1).Info("failed to update gateway status: resource might just be outdated")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// kuadrantReadyCondition returns the condition telling whether the policies affecting a gateway are enforced, i.e.
+// available for their current generation
+func (r *GatewayReadinessReconciler) kuadrantReadyCondition(ctx context.Context, gw *gatewayapiv1beta1.Gateway) (*metav1.Condition, error) {
+	pending := make([]string, 0)
+	total := 0
+
+	for _, apKey := range (common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantAuthPolicyRefsConfig{}}).PolicyRefs() {
+		total++
+		ap := &kuadrantv1beta1.AuthPolicy{}
+		if err := r.Client().Get(ctx, apKey, ap); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			pending = append(pending, fmt.Sprintf("AuthPolicy %s", apKey))
+			continue
+		}
+		if !policyEnforced(ap, ap.Status.ObservedGeneration, ap.Status.Conditions) {
+			pending = append(pending, fmt.Sprintf("AuthPolicy %s", apKey))
+		}
+	}
+
+	for _, rlpKey := range (common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}).PolicyRefs() {
+		total++
+		rlp := &kuadrantv1beta2.RateLimitPolicy{}
+		if err := r.Client().Get(ctx, rlpKey, rlp); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			pending = append(pending, fmt.Sprintf("RateLimitPolicy %s", rlpKey))
+			continue
+		}
+		if !policyEnforced(rlp, rlp.Status.ObservedGeneration, rlp.Status.Conditions) {
+			pending = append(pending, fmt.Sprintf("RateLimitPolicy %s", rlpKey))
+		}
+	}
+
+	cond := &metav1.Condition{
+		Type:               GatewayKuadrantReadyConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "PoliciesEnforced",
+		Message:            fmt.Sprintf("All the %d policies affecting the gateway are enforced", total),
+		ObservedGeneration: gw.Generation,
+	}
+	if len(pending) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "PoliciesNotEnforced"
+		cond.Message = fmt.Sprintf("Policies not enforced yet: %s", strings.Join(pending, ", "))
+	}
+	return cond, nil
+}
+
+// policyEnforced tells whether a policy is available for its current generation
+func policyEnforced(policy client.Object, observedGeneration int64, conditions []metav1.Condition) bool {
+	return observedGeneration == policy.GetGeneration() && meta.IsStatusConditionTrue(conditions, "Available")
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayReadinessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	policyGatewayEventMapper := &PolicyGatewayEventMapper{
+		Logger: r.Logger().WithName("policyGatewayEventMapper"),
+		Client: r.Client(),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("gatewayreadiness").
+		// the policy refs of the gateways are annotations
+		For(&gatewayapiv1beta1.Gateway{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(
+			&source.Kind{Type: &kuadrantv1beta1.AuthPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(policyGatewayEventMapper.MapAuthPolicyToGateway),
+		).
+		Watches(
+			&source.Kind{Type: &kuadrantv1beta2.RateLimitPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(policyGatewayEventMapper.MapRateLimitPolicyToGateway),
+		).
+		Complete(withLastSuccessMetric("gatewayreadiness", r))
+}
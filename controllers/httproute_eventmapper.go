@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 )
@@ -11,6 +14,8 @@ import (
 // HTTPRouteEventMapper is an EventHandler that maps HTTPRoute object events to Policy events.
 type HTTPRouteEventMapper struct {
 	Logger logr.Logger
+	// Client reads the parent gateways of the HTTPRoutes, to map to the policies of the gateways as well (optional)
+	Client client.Client
 }
 
 func (m *HTTPRouteEventMapper) MapToRateLimitPolicy(obj client.Object) []reconcile.Request {
@@ -18,7 +23,9 @@ func (m *HTTPRouteEventMapper) MapToRateLimitPolicy(obj client.Object) []reconci
 }
 
 func (m *HTTPRouteEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
-	return m.mapToPolicyRequest(obj, "authpolicy", common.AuthPolicyBackRefAnnotation)
+	requests := m.mapToPolicyRequest(obj, "authpolicy", common.AuthPolicyBackRefAnnotation)
+	// the hierarchy of the policies of the gateways depends on the HTTPRoutes attached to them
+	return append(requests, m.mapToGatewayPolicyRequests(obj, "authpolicy", common.AuthPolicyBackRefAnnotation)...)
 }
 
 func (m *HTTPRouteEventMapper) mapToPolicyRequest(obj client.Object, policyKind, policyBackRefAnnotationName string) []reconcile.Request {
@@ -33,3 +40,28 @@ func (m *HTTPRouteEventMapper) mapToPolicyRequest(obj client.Object, policyKind,
 
 	return []reconcile.Request{{NamespacedName: policyKey}}
 }
+
+// mapToGatewayPolicyRequests maps to the policies of the parent gateways of an HTTPRoute
+func (m *HTTPRouteEventMapper) mapToGatewayPolicyRequests(obj client.Object, policyKind, policyBackRefAnnotationName string) []reconcile.Request {
+	route, ok := obj.(*gatewayapiv1beta1.HTTPRoute)
+	if !ok || m.Client == nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, parentRef := range route.Spec.ParentRefs {
+		gwKey := client.ObjectKey{Name: string(parentRef.Name), Namespace: string(common.GetDefaultIfNil(parentRef.Namespace, gatewayapiv1beta1.Namespace(route.Namespace)))}
+		gw := &gatewayapiv1beta1.Gateway{}
+		if err := m.Client.Get(context.Background(), gwKey, gw); err != nil {
+			continue
+		}
+		policyRef, found := common.ReadAnnotationsFromObject(gw)[policyBackRefAnnotationName]
+		if !found {
+			continue
+		}
+		policyKey := common.NamespacedNameToObjectKey(policyRef, gw.Namespace)
+		m.Logger.V(1).Info("Processing parent gateway", "object", client.ObjectKeyFromObject(obj), "gateway", gwKey, policyKind, policyKey)
+		requests = append(requests, reconcile.Request{NamespacedName: policyKey})
+	}
+	return requests
+}
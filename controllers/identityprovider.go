@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+const (
+	IdentityProvidersResolvedConditionType string = "IdentityProvidersResolved"
+)
+
+//+kubebuilder:rbac:groups=kuadrant.io,resources=identityproviders,verbs=get;list;watch
+
+// fetchIdentityProviders returns the IdentityProviders referenced by a policy of the given namespace, in the order of
+// the references
+func fetchIdentityProviders(ctx context.Context, cli client.Client, namespace string, providerRefs []corev1.LocalObjectReference) ([]*kuadrantv1beta2.IdentityProvider, error) {
+	providers := make([]*kuadrantv1beta2.IdentityProvider, 0, len(providerRefs))
+	for _, providerRef := range providerRefs {
+		provider := &kuadrantv1beta2.IdentityProvider{}
+		providerKey := client.ObjectKey{Name: providerRef.Name, Namespace: namespace}
+		if err := cli.Get(ctx, providerKey, provider); err != nil {
+			return nil, fmt.Errorf("failed to resolve identity provider %s: %w", providerKey, err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// resolveAuthPolicyAuthScheme returns the auth scheme of the AuthPolicy inherited from its template, overridden by the
+// auth scheme of the policy, with the identity sources of its identity providers appended
+func resolveAuthPolicyAuthScheme(ctx context.Context, cli client.Client, ap *api.AuthPolicy) (api.AuthSchemeSpec, error) {
+	authScheme := ap.Spec.AuthScheme
+	template, err := fetchPolicyTemplate(ctx, cli, ap.Namespace, ap.Spec.TemplateRef)
+	if err != nil {
+		return api.AuthSchemeSpec{}, err
+	}
+	if template != nil {
+		authScheme = template.ResolveAuthScheme(authScheme)
+	}
+
+	providers, err := fetchIdentityProviders(ctx, cli, ap.Namespace, ap.Spec.IdentityProviders)
+	if err != nil {
+		return api.AuthSchemeSpec{}, err
+	}
+	return kuadrantv1beta2.ResolveIdentityProviders(authScheme, providers)
+}
+
+// setIdentityProvidersResolvedCondition reflects the resolution of the identity providers referenced by a policy, and
+// their issuers, in its status conditions
+func setIdentityProvidersResolvedCondition(ctx context.Context, cli client.Client, conditions *[]metav1.Condition, namespace string, providerRefs []corev1.LocalObjectReference) {
+	if len(providerRefs) == 0 {
+		meta.RemoveStatusCondition(conditions, IdentityProvidersResolvedConditionType)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:   IdentityProvidersResolvedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: "IdentityProvidersResolved",
+	}
+
+	providers, err := fetchIdentityProviders(ctx, cli, namespace, providerRefs)
+	switch {
+	case apierrors.IsNotFound(err):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "IdentityProviderNotFound"
+		cond.Message = err.Error()
+	case err != nil:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "ReconcilliationError"
+		cond.Message = err.Error()
+	default:
+		resolved := make([]string, 0, len(providers))
+		for _, provider := range providers {
+			resolved = append(resolved, fmt.Sprintf("%s (issuer %s, generation %d)", provider.Name, provider.Spec.Oidc.Endpoint, provider.Generation))
+		}
+		cond.Message = fmt.Sprintf("IdentityProviders resolved: %s", strings.Join(resolved, ", "))
+	}
+
+	meta.SetStatusCondition(conditions, cond)
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+// IdentityProviderEventMapper is an EventHandler that maps IdentityProvider events to the AuthPolicies referencing
+// the provider
+type IdentityProviderEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *IdentityProviderEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := m.Client.List(context.TODO(), apList, client.InNamespace(obj.GetNamespace())); err != nil {
+		m.Logger.V(1).Info("MapToAuthPolicy: failed to list authpolicies", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		for _, providerRef := range ap.Spec.IdentityProviders {
+			if providerRef.Name == obj.GetName() {
+				m.Logger.V(1).Info("MapToAuthPolicy", "identityprovider", client.ObjectKeyFromObject(obj), "authpolicy", client.ObjectKeyFromObject(ap))
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ap)})
+				break
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// IstioAuthorizationPolicyEventMapper is an EventHandler that maps the events of the Istio AuthorizationPolicies
+// wiring the ext_authz filter of the gateways to the AuthPolicy they enforce and to the kuadrant instance of the gateway
+type IstioAuthorizationPolicyEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+// MapToAuthPolicy maps an AuthorizationPolicy to the AuthPolicy it was generated from, set in its labels
+func (m *IstioAuthorizationPolicyEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels[common.AuthPolicyBackRefAnnotation], labels[fmt.Sprintf("%s-namespace", common.AuthPolicyBackRefAnnotation)]
+	if name == "" || namespace == "" {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: name, Namespace: namespace}}}
+}
+
+// MapToKuadrant maps an AuthorizationPolicy to the kuadrant instances of the gateway it applies to, set in its labels
+func (m *IstioAuthorizationPolicyEventMapper) MapToKuadrant(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	gwKey := client.ObjectKey{Name: labels["gateway"], Namespace: labels["gateway-namespace"]}
+	if gwKey.Name == "" || gwKey.Namespace == "" {
+		return []reconcile.Request{}
+	}
+
+	gw := &gatewayapiv1beta1.Gateway{}
+	if err := m.Client.Get(context.TODO(), gwKey, gw); err != nil {
+		m.Logger.V(1).Info("MapToKuadrant: failed to get gateway", "gateway", gwKey, "error", err)
+		return []reconcile.Request{}
+	}
+
+	kuadrantEventMapper := &KuadrantEventMapper{Logger: m.Logger, Client: m.Client}
+	return kuadrantEventMapper.MapGatewayToKuadrant(gw)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	istiosecurity "istio.io/api/security/v1beta1"
+	istioclientsecurity "istio.io/client-go/pkg/apis/security/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	maistrav2 "github.com/kuadrant/kuadrant-operator/api/external/maistra/v2"
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/istio"
+)
+
+const AuthFilterMissingConditionType string = "AuthFilterMissing"
+
+// missingAuthFilters holds, per kuadrant instance, the gateways found without the ext_authz filter of their
+// AuthPolicies by the last check
+var missingAuthFilters = newInstanceFindingsTracker()
+
+// checkAuthFilters verifies the ext_authz filter of the AuthPolicies attached to the gateways managed by a kuadrant
+// instance is wired, i.e. the gateway has the Istio AuthorizationPolicy of each policy, with the CUSTOM action of a
+// provider registered in the mesh config. Istio generates the filter out of the two; the AuthConfig of a policy may be
+// ready while the requests are not authorized. The AuthorizationPolicies deleted or changed are restored by the
+// AuthPolicy controller, and the providers of Kuadrant registered by the external-authorizer task; the other
+// providers, e.g. set by the AUTH_PROVIDER env var, are not managed by the operator.
+func (r *KuadrantReconciler) checkAuthFilters(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	providers, err := r.registeredExtAuthzProviders(ctx)
+	if err != nil {
+		return err
+	}
+
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := r.Client().List(ctx, gwList); err != nil {
+		return err
+	}
+
+	missing := make([]string, 0)
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		gwKey := client.ObjectKeyFromObject(gw)
+
+		// the AuthPolicies are listed in the gateway once their resources are reconciled
+		for _, apKey := range (common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantAuthPolicyRefsConfig{}}).PolicyRefs() {
+			ap := &kuadrantv1beta1.AuthPolicy{}
+			if err := r.Client().Get(ctx, apKey, ap); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+
+			iap := &istioclientsecurity.AuthorizationPolicy{}
+			iapKey := client.ObjectKey{Name: istioAuthorizationPolicyName(gw.Name, ap.GetTargetRef()), Namespace: gw.Namespace}
+			if err := r.Client().Get(ctx, iapKey, iap); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				missing = append(missing, fmt.Sprintf("gateway %s: AuthorizationPolicy %s of AuthPolicy %s not found", gwKey, iapKey, apKey))
+				continue
+			}
+
+			provider := iap.Spec.GetProvider().GetName()
+			if iap.Spec.Action != istiosecurity.AuthorizationPolicy_CUSTOM || provider == "" {
+				missing = append(missing, fmt.Sprintf("gateway %s: AuthorizationPolicy %s of AuthPolicy %s sets no ext_authz provider", gwKey, iapKey, apKey))
+				continue
+			}
+			if _, registered := providers[provider]; providers != nil && !registered {
+				missing = append(missing, fmt.Sprintf("gateway %s: ext_authz provider %s of AuthPolicy %s not registered in the mesh config", gwKey, provider, apKey))
+			}
+		}
+	}
+
+	missingAuthFilters.Set(client.ObjectKeyFromObject(kObj), missing)
+	return nil
+}
+
+// registeredExtAuthzProviders returns the names of the extension providers registered in the mesh config read by
+// the control plane, the istio ConfigMap or the ServiceMeshControlPlane, or nil if none is found
+func (r *KuadrantReconciler) registeredExtAuthzProviders(ctx context.Context) (map[string]struct{}, error) {
+	logger, _ := logr.FromContext(ctx)
+
+	var config common.ConfigWrapper
+	configs, err := r.getIstioConfigObjects(ctx, logger)
+	switch {
+	case err != nil && !apierrors.IsNotFound(err):
+		return nil, err
+	case len(configs) > 0:
+		// the IstioOperator is rendered into the ConfigMap, listed last
+		config = configs[len(configs)-1]
+	case err == nil:
+		smcp := &maistrav2.ServiceMeshControlPlane{}
+		smcpKey := client.ObjectKey{Name: controlPlaneProviderName(), Namespace: controlPlaneProviderNamespace()}
+		if err := r.Client().Get(ctx, smcpKey, smcp); err != nil {
+			if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		config = istio.NewOSSMControlPlaneWrapper(smcp)
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	meshConfig, err := config.GetMeshConfig()
+	if err != nil {
+		return nil, err
+	}
+	providers := make(map[string]struct{}, len(meshConfig.ExtensionProviders))
+	for _, provider := range meshConfig.ExtensionProviders {
+		providers[provider.Name] = struct{}{}
+	}
+	return providers, nil
+}
+
+// authFilterMissingCondition returns a condition listing the gateways missing the ext_authz filter of their
+// AuthPolicies, or nil
+func authFilterMissingCondition(kObj *kuadrantv1beta1.Kuadrant) *metav1.Condition {
+	missing := missingAuthFilters.Get(client.ObjectKeyFromObject(kObj))
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:    AuthFilterMissingConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthPoliciesNotEnforced",
+		Message: fmt.Sprintf("The gateways do not send the requests to Authorino: %s", strings.Join(missing, "; ")),
+	}
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const (
+	authorinoHealthServiceName = "authorino-health"
+	// authorinoDefaultHealthPort is the default port of the health probe endpoints of Authorino
+	authorinoDefaultHealthPort int32 = 8081
+
+	authorinoHealthGRPCPortName = "grpc-health"
+	authorinoHealthHTTPPortName = "http-health"
+)
+
+// reconcileAuthorinoHealth reconciles the Service exposing the health service of Authorino,
+// deleted when the health service is not enabled
+func (r *KuadrantReconciler) reconcileAuthorinoHealth(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	authorino := &authorinov1beta1.Authorino{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: "authorino", Namespace: kObj.Namespace}, authorino); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// reconciled again once authorino is deployed
+		authorino = nil
+	}
+
+	service := desiredAuthorinoHealthService(kObj, authorino)
+
+	if err := r.setManagedOwnerReference(kObj, service); err != nil {
+		return err
+	}
+
+	return r.ReconcileResource(ctx, &corev1.Service{}, service, authorinoHealthServiceMutator)
+}
+
+// desiredAuthorinoHealthService returns the Service selecting the pods of Authorino on the port of the gRPC health
+// checking protocol, served by the gRPC auth service, and on the port of the health probe endpoints
+func desiredAuthorinoHealthService(kObj *kuadrantv1beta1.Kuadrant, authorino *authorinov1beta1.Authorino) *corev1.Service {
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      authorinoHealthServiceName,
+			Namespace: kObj.Namespace,
+			Labels:    common.ManagedResourceLabels(kObj.Name, "authorino"),
+		},
+	}
+
+	if kObj.AuthorinoHealth() == nil || authorino == nil {
+		common.TagObjectToDelete(service)
+		return service
+	}
+
+	grpcPort := common.AuthorinoGRPCPort(authorino)
+	healthPort := authorinoHealthPort(authorino)
+
+	service.Spec = corev1.ServiceSpec{
+		// the labels of the pods of authorino set by the authorino operator
+		Selector: map[string]string{
+			"control-plane":      "controller-manager",
+			"authorino-resource": authorino.Name,
+		},
+		Ports: []corev1.ServicePort{
+			{Name: authorinoHealthGRPCPortName, Protocol: corev1.ProtocolTCP, Port: grpcPort, TargetPort: intstr.FromInt(int(grpcPort))},
+			{Name: authorinoHealthHTTPPortName, Protocol: corev1.ProtocolTCP, Port: healthPort, TargetPort: intstr.FromInt(int(healthPort))},
+		},
+	}
+
+	return service
+}
+
+func authorinoHealthPort(authorino *authorinov1beta1.Authorino) int32 {
+	if authorino.Spec.Healthz.Port != nil {
+		return *authorino.Spec.Healthz.Port
+	}
+	return authorinoDefaultHealthPort
+}
+
+// authorinoHealthServiceMutator reconciles the selector and the ports of the Service, along with the managed labels
+// and the owner references
+func authorinoHealthServiceMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*corev1.Service)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.Service", existingObj)
+	}
+	desired, ok := desiredObj.(*corev1.Service)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.Service", desiredObj)
+	}
+
+	update := false
+
+	// labels added by the users are preserved
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
+	}
+
+	if common.UpdateStaleOwnerReferences(existing, desired) {
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
+		existing.Spec.Selector = desired.Spec.Selector
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.Ports, desired.Spec.Ports) {
+		existing.Spec.Ports = desired.Spec.Ports
+		update = true
+	}
+
+	return update, nil
+}
+
+// authorinoHealthServiceStatus returns the ports exposed by the Service of the health service of Authorino,
+// or nil if not enabled
+func (r *KuadrantReconciler) authorinoHealthServiceStatus(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*kuadrantv1beta1.HealthServiceStatus, error) {
+	if kObj.AuthorinoHealth() == nil {
+		return nil, nil
+	}
+
+	service := &corev1.Service{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: authorinoHealthServiceName, Namespace: kObj.Namespace}, service); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	status := &kuadrantv1beta1.HealthServiceStatus{Name: service.Name}
+	for _, port := range service.Spec.Ports {
+		switch port.Name {
+		case authorinoHealthGRPCPortName:
+			status.GRPCPort = port.Port
+		case authorinoHealthHTTPPortName:
+			status.HTTPPort = port.Port
+		}
+	}
+	return status, nil
+}
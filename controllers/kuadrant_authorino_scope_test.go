@@ -0,0 +1,87 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	authorinoopv1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func testAuthPolicy(name, namespace string, target client.Object) *kuadrantv1beta1.AuthPolicy {
+	targetNamespace := gatewayapiv1alpha2.Namespace(target.GetNamespace())
+	kind := "HTTPRoute"
+	if _, ok := target.(*gatewayapiv1beta1.Gateway); ok {
+		kind = "Gateway"
+	}
+	return &kuadrantv1beta1.AuthPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1},
+		Spec: kuadrantv1beta1.AuthPolicySpec{
+			TargetRef: gatewayapiv1alpha2.PolicyTargetReference{
+				Group:     gatewayapiv1beta1.GroupName,
+				Kind:      gatewayapiv1alpha2.Kind(kind),
+				Name:      gatewayapiv1alpha2.ObjectName(target.GetName()),
+				Namespace: &targetNamespace,
+			},
+		},
+	}
+}
+
+func TestAuthorinoScopeMismatch(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"}}
+	otherKObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "other-kuadrant"}}
+	authorino := &authorinoopv1beta1.Authorino{
+		ObjectMeta: metav1.ObjectMeta{Name: "authorino", Namespace: kObj.Namespace},
+		Spec:       authorinoopv1beta1.AuthorinoSpec{ClusterWide: false},
+	}
+	gw := testGateway("gw")
+	gw.Annotations = map[string]string{common.KuadrantNamespaceLabel: kObj.Namespace}
+	route := testHTTPRoute("route", gw, "api.example.com")
+
+	outOfScope := testAuthPolicy("out-of-scope", "ns", route)
+	moved := testAuthPolicy("moved", "ns", gw)
+	moved.Status.Authorino = &kuadrantv1beta1.AuthorinoReference{Name: "authorino", Namespace: kObj.Namespace}
+
+	r := &KuadrantReconciler{BaseReconciler: unitTestTargetRefReconciler(kObj, otherKObj, authorino, gw, route, outOfScope, moved).BaseReconciler}
+	ctx := context.TODO()
+
+	t.Run("condition", func(subT *testing.T) {
+		cond, err := r.authorinoScopeMismatchCondition(ctx, kObj)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if cond == nil || !strings.Contains(cond.Message, "ns/out-of-scope") || strings.Contains(cond.Message, "ns/moved") {
+			subT.Errorf("expected the authpolicy out of the scope of authorino to be reported only, got %v", cond)
+		}
+	})
+
+	t.Run("mapper", func(subT *testing.T) {
+		mapper := &KuadrantEventMapper{Logger: logr.Discard(), Client: r.Client()}
+		expected := []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(kObj)}}
+		if requests := mapper.MapAuthPolicyToKuadrant(outOfScope); !reflect.DeepEqual(requests, expected) {
+			subT.Errorf("expected %v, got %v", expected, requests)
+		}
+	})
+
+	t.Run("predicate", func(subT *testing.T) {
+		if !authPolicyAuthorinoChanged.Update(event.UpdateEvent{ObjectOld: outOfScope, ObjectNew: moved}) {
+			subT.Error("expected the change of the authorino instance of the authpolicy to be watched")
+		}
+		if authPolicyAuthorinoChanged.Update(event.UpdateEvent{ObjectOld: moved, ObjectNew: moved.DeepCopy()}) {
+			subT.Error("expected the other updates of the authpolicy to be filtered out")
+		}
+	})
+}
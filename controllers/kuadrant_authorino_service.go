@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// authorinoAuthorizationServiceName is the name of the Service of the authorization service of the Authorino instance
+// of a kuadrant instance, created by the authorino operator
+const authorinoAuthorizationServiceName = "authorino-authorino-authorization"
+
+// reconcileAuthorinoService reconciles the type, the annotations and the load balancer options of the Service of
+// the authorization service of Authorino
+func (r *KuadrantReconciler) reconcileAuthorinoService(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	if kObj.IsAuthorinoValidateOnly() {
+		// externally managed
+		return nil
+	}
+
+	spec := kObj.AuthorinoService()
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	// the service is created by the authorino operator, which only reconciles its selector and ports
+	service := &corev1.Service{}
+	serviceKey := client.ObjectKey{Name: authorinoAuthorizationServiceName, Namespace: kObj.Namespace}
+	if err := r.Client().Get(ctx, serviceKey, service); err != nil {
+		// reconciled again once authorino is deployed
+		return client.IgnoreNotFound(err)
+	}
+
+	if !mutateAuthorinoService(service, spec) {
+		return nil
+	}
+	return r.UpdateResource(ctx, service)
+}
+
+// mutateAuthorinoService applies the settings of the Service of Authorino, returning whether the Service changed
+func mutateAuthorinoService(service *corev1.Service, spec *kuadrantv1beta1.AuthorinoServiceSpec) bool {
+	update := false
+
+	if serviceType := spec.ServiceType(); service.Spec.Type != serviceType {
+		service.Spec.Type = serviceType
+		update = true
+	}
+
+	var loadBalancerClass *string
+	var loadBalancerSourceRanges []string
+	if spec != nil {
+		loadBalancerClass = spec.LoadBalancerClass
+		loadBalancerSourceRanges = spec.LoadBalancerSourceRanges
+		if common.MergeMapStringString(&service.Annotations, spec.Annotations) {
+			update = true
+		}
+	}
+
+	if !reflect.DeepEqual(service.Spec.LoadBalancerClass, loadBalancerClass) {
+		service.Spec.LoadBalancerClass = loadBalancerClass
+		update = true
+	}
+
+	if !reflect.DeepEqual(service.Spec.LoadBalancerSourceRanges, loadBalancerSourceRanges) {
+		service.Spec.LoadBalancerSourceRanges = loadBalancerSourceRanges
+		update = true
+	}
+
+	return update
+}
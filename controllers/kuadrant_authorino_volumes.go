@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+// managedVolumePrefix is the prefix of the names of the volumes of the Authorino instance set by the kuadrant
+// instance. The volumes without the prefix are set by other sources.
+const managedVolumePrefix = "kuadrant-"
+
+func isManagedVolume(name string) bool {
+	return strings.HasPrefix(name, managedVolumePrefix)
+}
+
+// authorinoVolumes returns the volumes of the Authorino instance mounting the volumes of the kuadrant instance
+func authorinoVolumes(kObj *kuadrantv1beta1.Kuadrant) []authorinov1beta1.VolumeSpec {
+	volumes := make([]authorinov1beta1.VolumeSpec, 0, len(kObj.AuthorinoVolumes()))
+	for _, volume := range kObj.AuthorinoVolumes() {
+		volume := volume.DeepCopy()
+		volumes = append(volumes, authorinov1beta1.VolumeSpec{
+			Name:       managedVolumePrefix + volume.Name,
+			MountPath:  volume.MountPath,
+			ConfigMaps: volume.ConfigMaps,
+			Secrets:    volume.Secrets,
+			Items:      volume.Items,
+		})
+	}
+	return volumes
+}
+
+// validateAuthorinoVolumes checks the volumes of the kuadrant instance are valid, do not clash with the volume of the
+// trusted CA bundle, and their ConfigMaps and Secrets exist and hold the entries of their items
+func (r *KuadrantReconciler) validateAuthorinoVolumes(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	volumes := kObj.AuthorinoVolumes()
+	if err := kuadrantv1beta1.ValidateAuthorinoVolumes(volumes); err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if managedVolumePrefix+volume.Name == trustedCABundleVolumeName {
+			return fmt.Errorf("invalid authorino.volumes %s. Name reserved for the trusted CA bundle", volume.Name)
+		}
+		if kObj.AuthorinoTrustedCABundle() != nil && volume.MountPath == trustedCABundleMountPath {
+			return fmt.Errorf("invalid authorino.volumes %s. Mount path %s used by the trusted CA bundle", volume.Name, volume.MountPath)
+		}
+
+		// read directly from the API server, the configmaps and the secrets are not cached
+		for _, name := range volume.ConfigMaps {
+			configMap := &corev1.ConfigMap{}
+			if err := r.APIClientReader().Get(ctx, client.ObjectKey{Name: name, Namespace: kObj.Namespace}, configMap); err != nil {
+				return fmt.Errorf("failed to read configmap %s of authorino volume %s: %w", name, volume.Name, err)
+			}
+			for _, item := range volume.Items {
+				if _, ok := configMap.Data[item.Key]; ok {
+					continue
+				}
+				if _, ok := configMap.BinaryData[item.Key]; !ok {
+					return fmt.Errorf("configmap %s of authorino volume %s has no %s entry", name, volume.Name, item.Key)
+				}
+			}
+		}
+		for _, name := range volume.Secrets {
+			secret := &corev1.Secret{}
+			if err := r.APIClientReader().Get(ctx, client.ObjectKey{Name: name, Namespace: kObj.Namespace}, secret); err != nil {
+				return fmt.Errorf("failed to read secret %s of authorino volume %s: %w", name, volume.Name, err)
+			}
+			for _, item := range volume.Items {
+				if _, ok := secret.Data[item.Key]; !ok {
+					return fmt.Errorf("secret %s of authorino volume %s has no %s entry", name, volume.Name, item.Key)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// reconcileManagedVolumes sets the volumes of an existing Authorino set by the kuadrant instance, the trusted CA
+// bundle included, to the desired ones, removing the ones no longer desired. The other volumes are preserved.
+func reconcileManagedVolumes(existing, desired *authorinov1beta1.Authorino) bool {
+	update := false
+
+	for idx := len(existing.Spec.Volumes.Items) - 1; idx >= 0; idx-- {
+		name := existing.Spec.Volumes.Items[idx].Name
+		if isManagedVolume(name) && findVolume(desired.Spec.Volumes.Items, name) < 0 {
+			existing.Spec.Volumes.Items = append(existing.Spec.Volumes.Items[:idx], existing.Spec.Volumes.Items[idx+1:]...)
+			update = true
+		}
+	}
+
+	for _, volume := range desired.Spec.Volumes.Items {
+		if !isManagedVolume(volume.Name) {
+			continue
+		}
+		switch idx := findVolume(existing.Spec.Volumes.Items, volume.Name); {
+		case idx < 0:
+			existing.Spec.Volumes.Items = append(existing.Spec.Volumes.Items, volume)
+			update = true
+		case !reflect.DeepEqual(existing.Spec.Volumes.Items[idx], volume):
+			existing.Spec.Volumes.Items[idx] = volume
+			update = true
+		}
+	}
+
+	return update
+}
+
+// managedVolumesDiscrepant tells whether any of the volumes set by the kuadrant instance is missing from an existing
+// Authorino or differs from the desired one
+func managedVolumesDiscrepant(existing, desired *authorinov1beta1.Authorino) bool {
+	for _, volume := range desired.Spec.Volumes.Items {
+		if !isManagedVolume(volume.Name) {
+			continue
+		}
+		if idx := findVolume(existing.Spec.Volumes.Items, volume.Name); idx < 0 || !reflect.DeepEqual(existing.Spec.Volumes.Items[idx], volume) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+//go:build unit
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestDesiredKuadrantAuthorizers(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"}}
+
+	t.Run("fail closed and fail open providers", func(subT *testing.T) {
+		authorizers := desiredKuadrantAuthorizers(kObj, nil)
+		if len(authorizers) != 2 {
+			subT.Fatalf("expected 2 providers, got %d", len(authorizers))
+		}
+		failClosed := authorizers[0].GetExtensionProvider()
+		if failClosed.Name != common.ExtAuthorizerName || failClosed.GetEnvoyExtAuthzGrpc().FailOpen {
+			subT.Errorf("expected the provider %s failing closed, got %s failing open: %t", common.ExtAuthorizerName, failClosed.Name, failClosed.GetEnvoyExtAuthzGrpc().FailOpen)
+		}
+		failOpen := authorizers[1].GetExtensionProvider()
+		if failOpen.Name != common.ExtAuthorizerFailOpenName || !failOpen.GetEnvoyExtAuthzGrpc().FailOpen {
+			subT.Errorf("expected the provider %s failing open, got %s failing open: %t", common.ExtAuthorizerFailOpenName, failOpen.Name, failOpen.GetEnvoyExtAuthzGrpc().FailOpen)
+		}
+		if failClosed.GetEnvoyExtAuthzGrpc().Service != failOpen.GetEnvoyExtAuthzGrpc().Service {
+			subT.Errorf("expected both providers to target the same service, got %s and %s", failClosed.GetEnvoyExtAuthzGrpc().Service, failOpen.GetEnvoyExtAuthzGrpc().Service)
+		}
+	})
+
+	t.Run("pinned authorino instances", func(subT *testing.T) {
+		authorizers := desiredKuadrantAuthorizers(kObj, []string{"team-a"})
+		if len(authorizers) != 4 {
+			subT.Fatalf("expected 4 providers, got %d", len(authorizers))
+		}
+		for i, failOpen := range []bool{false, true} {
+			provider := authorizers[2+i].GetExtensionProvider()
+			if name := common.KuadrantInstanceAuthorizerName(kObj.Namespace, "team-a", failOpen); provider.Name != name {
+				subT.Errorf("expected the provider %s, got %s", name, provider.Name)
+			}
+			if provider.GetEnvoyExtAuthzGrpc().FailOpen != failOpen {
+				subT.Errorf("expected the provider %s failing open: %t", provider.Name, failOpen)
+			}
+		}
+	})
+
+	t.Run("timeout", func(subT *testing.T) {
+		kObj := kObj.DeepCopy()
+		timeout := int32(250)
+		kObj.Spec.Authorino = &kuadrantv1beta1.AuthorinoSpec{TimeoutMilliseconds: &timeout}
+		for _, authorizer := range desiredKuadrantAuthorizers(kObj, []string{"team-a"}) {
+			provider := authorizer.GetExtensionProvider()
+			if got := provider.GetEnvoyExtAuthzGrpc().GetTimeout().AsDuration(); got != 250*time.Millisecond {
+				subT.Errorf("expected the timeout of the provider %s to be 250ms, got %s", provider.Name, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestMapManagedResourceToKuadrant(t *testing.T) {
+	mapper := &KuadrantEventMapper{Logger: logr.Discard()}
+
+	managed := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      authorinoHealthServiceName,
+		Namespace: "kuadrant-system",
+		Labels:    common.ManagedResourceLabels("kuadrant", "authorino"),
+	}}
+	expected := []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: "kuadrant-system", Name: "kuadrant"}}}
+	if requests := mapper.MapManagedResourceToKuadrant(managed); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected %v, got %v", expected, requests)
+	}
+
+	notManaged := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      "other",
+		Namespace: "kuadrant-system",
+		Labels:    map[string]string{common.AppInstanceLabel: "kuadrant"},
+	}}
+	if requests := mapper.MapManagedResourceToKuadrant(notManaged); len(requests) != 0 {
+		t.Errorf("expected the resources not managed by the operator to be ignored, got %v", requests)
+	}
+}
+
+func TestExplicitCleanupMode(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system", UID: "kuadrant-uid"}}
+	limitador := &limitadorv1alpha1.Limitador{ObjectMeta: metav1.ObjectMeta{
+		Name:      common.LimitadorName,
+		Namespace: kObj.Namespace,
+		Labels:    common.ManagedResourceLabels(kObj.Name, "limitador"),
+	}}
+	otherLimitador := &limitadorv1alpha1.Limitador{ObjectMeta: metav1.ObjectMeta{Name: common.LimitadorName, Namespace: "other"}}
+
+	baseReconciler := unitTestTargetRefReconciler(kObj, limitador, otherLimitador).BaseReconciler
+	ctx := context.TODO()
+
+	t.Run("owner references", func(subT *testing.T) {
+		for mode, expectedOwners := range map[ChildCleanupMode]int{OwnerRefCleanupMode: 1, ExplicitCleanupMode: 0} {
+			r := &KuadrantReconciler{BaseReconciler: baseReconciler, ChildCleanupMode: mode}
+			obj := limitador.DeepCopy()
+			if err := r.setManagedOwnerReference(kObj, obj); err != nil {
+				subT.Fatal(err)
+			}
+			if len(obj.OwnerReferences) != expectedOwners {
+				subT.Errorf("%s: expected %d owner references, got %v", mode, expectedOwners, obj.OwnerReferences)
+			}
+		}
+	})
+
+	t.Run("deletion of the managed resources", func(subT *testing.T) {
+		r := &KuadrantReconciler{BaseReconciler: baseReconciler, ChildCleanupMode: ExplicitCleanupMode}
+		if err := r.deleteManagedResources(ctx, kObj); err != nil {
+			subT.Fatal(err)
+		}
+		if err := r.Client().Get(ctx, client.ObjectKeyFromObject(limitador), &limitadorv1alpha1.Limitador{}); !apierrors.IsNotFound(err) {
+			subT.Errorf("expected the limitador instance to be deleted, got %v", err)
+		}
+		if err := r.Client().Get(ctx, client.ObjectKeyFromObject(otherLimitador), &limitadorv1alpha1.Limitador{}); err != nil {
+			subT.Errorf("expected the limitador instance of the other namespace to be kept, got %v", err)
+		}
+	})
+}
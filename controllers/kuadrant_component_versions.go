@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const ComponentVersionUnsupportedConditionType string = "ComponentVersionUnsupported"
+
+// componentVersions returns the releases of the components installed for a kuadrant instance, read from the images
+// of the deployments of Authorino and Limitador, and from the CRDs of the Gateway API
+func (r *KuadrantReconciler) componentVersions(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (map[string]string, error) {
+	versions := map[string]string{
+		common.GatewayAPIComponent: r.GatewayAPIVersion,
+	}
+
+	deployments := map[string]string{
+		common.AuthorinoComponent: "authorino",
+		common.LimitadorComponent: common.LimitadorName,
+	}
+	for component, name := range deployments {
+		deployment := &appsv1.Deployment{}
+		if err := r.Client().Get(ctx, client.ObjectKey{Name: name, Namespace: kObj.Namespace}, deployment); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+		if containers := deployment.Spec.Template.Spec.Containers; len(containers) > 0 {
+			versions[component] = common.ImageTag(containers[0].Image)
+		}
+	}
+
+	return versions, nil
+}
+
+// componentVersionUnsupportedCondition returns a warning condition listing the components installed whose release
+// is out of the supported matrix, or nil
+func (r *KuadrantReconciler) componentVersionUnsupportedCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*metav1.Condition, error) {
+	versions, err := r.componentVersions(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+
+	unsupported := common.UnsupportedComponentVersions(versions)
+	if len(unsupported) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    ComponentVersionUnsupportedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "UnsupportedVersions",
+		Message: fmt.Sprintf("Components out of the supported versions: %s", strings.Join(unsupported, "; ")),
+	}, nil
+}
@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -27,19 +29,29 @@ import (
 	maistrav1 "github.com/kuadrant/kuadrant-operator/api/external/maistra/v1"
 	maistrav2 "github.com/kuadrant/kuadrant-operator/api/external/maistra/v2"
 	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
+	istiosecurityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 	iopv1alpha1 "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 	"github.com/kuadrant/kuadrant-operator/pkg/istio"
 	"github.com/kuadrant/kuadrant-operator/pkg/log"
@@ -48,12 +60,44 @@ import (
 
 const (
 	kuadrantFinalizer = "kuadrant.io/finalizer"
+
+	authorinoDecisionLogLevel = "debug"
+	authorinoDecisionLogMode  = "production"
+)
+
+// ChildCleanupMode defines how the resources managed for a Kuadrant instance are removed
+type ChildCleanupMode string
+
+const (
+	// OwnerRefCleanupMode relies on the garbage collector, following the owner references set on the managed resources
+	OwnerRefCleanupMode ChildCleanupMode = "owner-ref"
+	// ExplicitCleanupMode deletes the managed resources when the Kuadrant instance is removed
+	ExplicitCleanupMode ChildCleanupMode = "explicit"
 )
 
 // KuadrantReconciler reconciles a Kuadrant object
 type KuadrantReconciler struct {
 	*reconcilers.BaseReconciler
 	Scheme *runtime.Scheme
+	// ChildCleanupMode defaults to OwnerRefCleanupMode
+	ChildCleanupMode ChildCleanupMode
+	// ReconcileTrigger enqueues the reconciliation of the kuadrant instances on demand (optional)
+	ReconcileTrigger *ReconcileTrigger
+	// StartupConfig is the configuration resolved at startup from the flags and the config file,
+	// reported in the status of the kuadrant instances (optional)
+	StartupConfig map[string]string
+	// LimitadorRolloutOnConfigChange enables the rollout of the deployment of Limitador
+	// when the storage config of the Limitador instance changes
+	LimitadorRolloutOnConfigChange bool
+	// DeletionPolicy tells how the removal of a kuadrant instance still backing policies is handled.
+	// Defaults to MarkPoliciesDeletionPolicy
+	DeletionPolicy KuadrantDeletionPolicy
+	// GatewayAPIVersion is the release of the Gateway API detected at startup, reported in the status
+	// of the kuadrant instances
+	GatewayAPIVersion string
+	// ObserverMode tells the writes of the resources managed by the operator are dropped, reported in the status
+	// of the kuadrant instances
+	ObserverMode bool
 }
 
 //+kubebuilder:rbac:groups=kuadrant.io,resources=kuadrants,verbs=get;list;watch;create;update;patch;delete
@@ -63,7 +107,10 @@ type KuadrantReconciler struct {
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts;configmaps;services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=coordination.k8s.io,resources=configmaps;leases,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
 //+kubebuilder:rbac:groups="",resources=leases,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gateways,verbs=get;list;watch;create;update;delete;patch
 //+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes,verbs=get;list;patch;update;watch
@@ -71,13 +118,16 @@ type KuadrantReconciler struct {
 //+kubebuilder:rbac:groups=install.istio.io,resources=istiooperators,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups=maistra.io,resources=servicemeshcontrolplanes,verbs=get;list;watch;update;use;patch
 //+kubebuilder:rbac:groups=maistra.io,resources=servicemeshmembers,verbs=get;list;watch;create;update;delete;patch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *KuadrantReconciler) Reconcile(eventCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := log.Log.WithValues("kuadrant", req.NamespacedName)
+	logger := log.Log.WithValues("kuadrant", req.NamespacedName, "reconcileID", controller.ReconcileIDFromContext(eventCtx))
 	logger.Info("Reconciling")
 	ctx := logr.NewContext(eventCtx, logger)
 
@@ -102,14 +152,40 @@ func (r *KuadrantReconciler) Reconcile(eventCtx context.Context, req ctrl.Reques
 	if kObj.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(kObj, kuadrantFinalizer) {
 		logger.V(1).Info("Handling removal of kuadrant object")
 
+		blocked, err := r.handlePoliciesOnDeletion(ctx, kObj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if blocked {
+			return ctrl.Result{RequeueAfter: deletionBlockedRequeueDelay}, nil
+		}
+
 		if err := r.unregisterExternalAuthorizer(ctx, kObj); err != nil {
 			return ctrl.Result{}, err
 		}
 
+		// before the gateways are released, lifting the default rate limit from the ones without any policy
+		if err := r.reconcileDefaultRateLimit(ctx, kObj); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileDefaultAuthPolicy(ctx, kObj); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		if err := r.removeAnnotationFromGateways(ctx, kObj); err != nil {
 			return ctrl.Result{}, err
 		}
 
+		if r.ChildCleanupMode == ExplicitCleanupMode {
+			if err := r.deleteManagedResources(ctx, kObj); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		unprotectedGateways.DeletePartialMatch(prometheus.Labels{"kuadrant_namespace": kObj.Namespace})
+		unreachableServices.Set(client.ObjectKeyFromObject(kObj), nil)
+		missingAuthFilters.Set(client.ObjectKeyFromObject(kObj), nil)
+
 		logger.Info("removing finalizer")
 		controllerutil.RemoveFinalizer(kObj, kuadrantFinalizer)
 		if err := r.Client().Update(ctx, kObj); client.IgnoreNotFound(err) != nil {
@@ -156,6 +232,11 @@ func (r *KuadrantReconciler) Reconcile(eventCtx context.Context, req ctrl.Reques
 		return statusResult, nil
 	}
 
+	if requeueAfter := maintenanceWindowRequeue(kObj); requeueAfter > 0 {
+		logger.Info("successfully reconciled, disruptive changes deferred until the maintenance window", "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	logger.Info("successfully reconciled")
 	return ctrl.Result{}, nil
 }
@@ -163,10 +244,13 @@ func (r *KuadrantReconciler) Reconcile(eventCtx context.Context, req ctrl.Reques
 func (r *KuadrantReconciler) unregisterExternalAuthorizer(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
 	logger, _ := logr.FromContext(ctx)
 
-	isIstioInstalled, err := r.unregisterExternalAuthorizerIstio(ctx, kObj)
+	// the ext_authz providers of the pinned authorino instances are removed along with the ones of the kuadrant instance
+	authorizers := desiredKuadrantAuthorizers(kObj, nil)
+
+	isIstioInstalled, err := r.unregisterExternalAuthorizerIstio(ctx, kObj.Namespace, authorizers)
 
 	if err == nil && !isIstioInstalled {
-		err = r.unregisterExternalAuthorizerOSSM(ctx, kObj)
+		err = r.unregisterExternalAuthorizerOSSM(ctx, kObj.Namespace, authorizers)
 	}
 
 	if err != nil {
@@ -176,7 +260,7 @@ func (r *KuadrantReconciler) unregisterExternalAuthorizer(ctx context.Context, k
 	return err
 }
 
-func (r *KuadrantReconciler) unregisterExternalAuthorizerIstio(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (bool, error) {
+func (r *KuadrantReconciler) unregisterExternalAuthorizerIstio(ctx context.Context, namespace string, authorizers []*common.KuadrantAuthorizer) (bool, error) {
 	logger, _ := logr.FromContext(ctx)
 	configsToUpdate, err := r.getIstioConfigObjects(ctx, logger)
 	isIstioInstalled := configsToUpdate != nil
@@ -185,18 +269,24 @@ func (r *KuadrantReconciler) unregisterExternalAuthorizerIstio(ctx context.Conte
 		return isIstioInstalled, err
 	}
 
-	kuadrantAuthorizer := common.NewKuadrantAuthorizer(kObj.Namespace)
-
 	for _, config := range configsToUpdate {
-		hasKuadrantAuthorizer, err := common.HasKuadrantAuthorizer(config, *kuadrantAuthorizer)
+		updated, err := common.UnregisterStaleKuadrantInstanceAuthorizers(config, namespace, nil)
 		if err != nil {
 			return true, err
 		}
-		if hasKuadrantAuthorizer {
-			if err = common.UnregisterKuadrantAuthorizer(config, kuadrantAuthorizer); err != nil {
+		for _, kuadrantAuthorizer := range authorizers {
+			hasKuadrantAuthorizer, err := common.HasKuadrantAuthorizer(config, *kuadrantAuthorizer)
+			if err != nil {
 				return true, err
 			}
-
+			if hasKuadrantAuthorizer {
+				if err = common.UnregisterKuadrantAuthorizer(config, kuadrantAuthorizer); err != nil {
+					return true, err
+				}
+				updated = true
+			}
+		}
+		if updated {
 			logger.Info("remove external authorizer from istio meshconfig")
 			if err = r.UpdateResource(ctx, config.GetConfigObject()); err != nil {
 				return true, err
@@ -206,7 +296,7 @@ func (r *KuadrantReconciler) unregisterExternalAuthorizerIstio(ctx context.Conte
 	return true, nil
 }
 
-func (r *KuadrantReconciler) unregisterExternalAuthorizerOSSM(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+func (r *KuadrantReconciler) unregisterExternalAuthorizerOSSM(ctx context.Context, namespace string, authorizers []*common.KuadrantAuthorizer) error {
 	logger, _ := logr.FromContext(ctx)
 
 	smcp := &maistrav2.ServiceMeshControlPlane{}
@@ -218,17 +308,24 @@ func (r *KuadrantReconciler) unregisterExternalAuthorizerOSSM(ctx context.Contex
 	}
 
 	smcpWrapper := istio.NewOSSMControlPlaneWrapper(smcp)
-	kuadrantAuthorizer := common.NewKuadrantAuthorizer(kObj.Namespace)
 
-	hasKuadrantAuthorizer, err := common.HasKuadrantAuthorizer(smcpWrapper, *kuadrantAuthorizer)
+	updated, err := common.UnregisterStaleKuadrantInstanceAuthorizers(smcpWrapper, namespace, nil)
 	if err != nil {
 		return err
 	}
-	if hasKuadrantAuthorizer {
-		err = common.UnregisterKuadrantAuthorizer(smcpWrapper, kuadrantAuthorizer)
+	for _, kuadrantAuthorizer := range authorizers {
+		hasKuadrantAuthorizer, err := common.HasKuadrantAuthorizer(smcpWrapper, *kuadrantAuthorizer)
 		if err != nil {
 			return err
 		}
+		if hasKuadrantAuthorizer {
+			if err := common.UnregisterKuadrantAuthorizer(smcpWrapper, kuadrantAuthorizer); err != nil {
+				return err
+			}
+			updated = true
+		}
+	}
+	if updated {
 		logger.Info("removing external authorizer from  OSSM meshconfig")
 		if err := r.UpdateResource(ctx, smcpWrapper.GetConfigObject()); err != nil {
 			return err
@@ -241,10 +338,16 @@ func (r *KuadrantReconciler) unregisterExternalAuthorizerOSSM(ctx context.Contex
 func (r *KuadrantReconciler) registerExternalAuthorizer(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
 	logger, _ := logr.FromContext(ctx)
 
-	isIstioInstalled, err := r.registerExternalAuthorizerIstio(ctx, kObj)
+	pinnedInstances, err := pinnedAuthorinoInstances(ctx, r.Client(), kObj.Namespace)
+	if err != nil {
+		return err
+	}
+	authorizers := desiredKuadrantAuthorizers(kObj, pinnedInstances)
+
+	isIstioInstalled, err := r.registerExternalAuthorizerIstio(ctx, kObj.Namespace, authorizers)
 
 	if err == nil && !isIstioInstalled {
-		err = r.registerExternalAuthorizerOSSM(ctx, kObj)
+		err = r.registerExternalAuthorizerOSSM(ctx, kObj, authorizers)
 	}
 
 	if err != nil {
@@ -254,7 +357,7 @@ func (r *KuadrantReconciler) registerExternalAuthorizer(ctx context.Context, kOb
 	return err
 }
 
-func (r *KuadrantReconciler) registerExternalAuthorizerIstio(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (bool, error) {
+func (r *KuadrantReconciler) registerExternalAuthorizerIstio(ctx context.Context, namespace string, authorizers []*common.KuadrantAuthorizer) (bool, error) {
 	logger, _ := logr.FromContext(ctx)
 	configsToUpdate, err := r.getIstioConfigObjects(ctx, logger)
 	isIstioInstalled := configsToUpdate != nil
@@ -263,18 +366,25 @@ func (r *KuadrantReconciler) registerExternalAuthorizerIstio(ctx context.Context
 		return isIstioInstalled, err
 	}
 
-	kuadrantAuthorizer := common.NewKuadrantAuthorizer(kObj.Namespace)
 	for _, config := range configsToUpdate {
-		hasKuadrantAuthorizer, err := common.HasKuadrantAuthorizer(config, *kuadrantAuthorizer)
+		updated, err := common.UnregisterStaleKuadrantInstanceAuthorizers(config, namespace, kuadrantAuthorizersList(authorizers))
 		if err != nil {
 			return true, err
 		}
-		if !hasKuadrantAuthorizer {
-			err = common.RegisterKuadrantAuthorizer(config, kuadrantAuthorizer)
+		for _, kuadrantAuthorizer := range authorizers {
+			upToDate, err := common.IsKuadrantAuthorizerUpToDate(config, kuadrantAuthorizer)
 			if err != nil {
 				return true, err
 			}
-			logger.Info("adding external authorizer to istio meshconfig")
+			if !upToDate {
+				if err = common.RegisterKuadrantAuthorizer(config, kuadrantAuthorizer); err != nil {
+					return true, err
+				}
+				updated = true
+			}
+		}
+		if updated {
+			logger.Info("adding or updating external authorizer in istio meshconfig")
 			if err = r.UpdateResource(ctx, config.GetConfigObject()); err != nil {
 				return true, err
 			}
@@ -284,7 +394,7 @@ func (r *KuadrantReconciler) registerExternalAuthorizerIstio(ctx context.Context
 	return true, nil
 }
 
-func (r *KuadrantReconciler) registerExternalAuthorizerOSSM(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+func (r *KuadrantReconciler) registerExternalAuthorizerOSSM(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant, authorizers []*common.KuadrantAuthorizer) error {
 	logger, _ := logr.FromContext(ctx)
 
 	if err := r.registerServiceMeshMember(ctx, kObj); err != nil {
@@ -299,18 +409,24 @@ func (r *KuadrantReconciler) registerExternalAuthorizerOSSM(ctx context.Context,
 		return err
 	}
 	smcpWrapper := istio.NewOSSMControlPlaneWrapper(smcp)
-	kuadrantAuthorizer := common.NewKuadrantAuthorizer(kObj.Namespace)
-
-	hasKuadrantAuthorizer, err := common.HasKuadrantAuthorizer(smcpWrapper, *kuadrantAuthorizer)
+	updated, err := common.UnregisterStaleKuadrantInstanceAuthorizers(smcpWrapper, kObj.Namespace, kuadrantAuthorizersList(authorizers))
 	if err != nil {
 		return err
 	}
-	if !hasKuadrantAuthorizer {
-		err = common.RegisterKuadrantAuthorizer(smcpWrapper, kuadrantAuthorizer)
+	for _, kuadrantAuthorizer := range authorizers {
+		upToDate, err := common.IsKuadrantAuthorizerUpToDate(smcpWrapper, kuadrantAuthorizer)
 		if err != nil {
 			return err
 		}
-		logger.Info("adding external authorizer to OSSM meshconfig")
+		if !upToDate {
+			if err := common.RegisterKuadrantAuthorizer(smcpWrapper, kuadrantAuthorizer); err != nil {
+				return err
+			}
+			updated = true
+		}
+	}
+	if updated {
+		logger.Info("adding or updating external authorizer in OSSM meshconfig")
 		if err := r.UpdateResource(ctx, smcpWrapper.GetConfigObject()); err != nil {
 			return err
 		}
@@ -319,6 +435,33 @@ func (r *KuadrantReconciler) registerExternalAuthorizerOSSM(ctx context.Context,
 	return nil
 }
 
+// desiredKuadrantAuthorizers returns the ext_authz providers expected by the kuadrant instance and by the Authorino
+// instances pinned by its gateways, failing closed and failing open when Authorino is unavailable
+func desiredKuadrantAuthorizers(kObj *kuadrantv1beta1.Kuadrant, pinnedInstances []string) []*common.KuadrantAuthorizer {
+	kuadrantAuthorizers := []*common.KuadrantAuthorizer{
+		common.NewKuadrantAuthorizer(kObj.Namespace),
+		common.NewKuadrantFailOpenAuthorizer(kObj.Namespace),
+	}
+	for _, name := range pinnedInstances {
+		kuadrantAuthorizers = append(kuadrantAuthorizers,
+			common.NewKuadrantInstanceAuthorizer(kObj.Namespace, name, false),
+			common.NewKuadrantInstanceAuthorizer(kObj.Namespace, name, true),
+		)
+	}
+	if timeout := kObj.AuthorinoTimeout(); timeout != nil {
+		for _, kuadrantAuthorizer := range kuadrantAuthorizers {
+			kuadrantAuthorizer.WithTimeout(*timeout)
+		}
+	}
+	return kuadrantAuthorizers
+}
+
+func kuadrantAuthorizersList(kuadrantAuthorizers []*common.KuadrantAuthorizer) []common.Authorizer {
+	return common.Map(kuadrantAuthorizers, func(kuadrantAuthorizer *common.KuadrantAuthorizer) common.Authorizer {
+		return kuadrantAuthorizer
+	})
+}
+
 func (r *KuadrantReconciler) getIstioConfigObjects(ctx context.Context, logger logr.Logger) ([]common.ConfigWrapper, error) {
 	var configsToUpdate []common.ConfigWrapper
 
@@ -348,7 +491,7 @@ func (r *KuadrantReconciler) getIstioConfigObjects(ctx context.Context, logger l
 
 func (r *KuadrantReconciler) registerServiceMeshMember(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
 	member := buildServiceMeshMember(kObj)
-	err := r.SetOwnerReference(kObj, member)
+	err := r.setManagedOwnerReference(kObj, member)
 	if err != nil {
 		return err
 	}
@@ -357,21 +500,31 @@ func (r *KuadrantReconciler) registerServiceMeshMember(ctx context.Context, kObj
 }
 
 func (r *KuadrantReconciler) reconcileSpec(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (ctrl.Result, error) {
-	if err := r.registerExternalAuthorizer(ctx, kObj); err != nil {
+	terminating, err := r.namespaceTerminating(ctx, kObj.Namespace)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if err := r.reconcileLimitador(ctx, kObj); err != nil {
-		return ctrl.Result{}, err
-	}
+	// the disruptive changes are deferred again until the maintenance window
+	maintenanceDeferrals.Reset(client.ObjectKeyFromObject(kObj))
 
-	if err := r.reconcileAuthorino(ctx, kObj); err != nil {
+	if err := r.runReconcileTasks(ctx, kObj, terminating); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// namespaceTerminating tells whether the namespace is pending deletion, in which case new resources cannot be created in it
+func (r *KuadrantReconciler) namespaceTerminating(ctx context.Context, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	// read directly from the API server, the namespaces are not cached
+	if err := r.APIClientReader().Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return ns.Status.Phase == corev1.NamespaceTerminating, nil
+}
+
 func controlPlaneProviderName() string {
 	return common.FetchEnv("ISTIOOPERATOR_NAME", "istiocontrolplane")
 }
@@ -476,19 +629,120 @@ func (r *KuadrantReconciler) reconcileLimitador(ctx context.Context, kObj *kuadr
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      common.LimitadorName,
 			Namespace: kObj.Namespace,
+			Labels:    common.ManagedResourceLabels(kObj.Name, "limitador"),
 		},
 		Spec: limitadorv1alpha1.LimitadorSpec{},
 	}
 
-	err := r.SetOwnerReference(kObj, limitador)
+	overrides, err := r.componentOverrides(ctx)
 	if err != nil {
 		return err
 	}
+	if limitador.Spec, err = common.ApplyMergePatches(limitador.Spec, overrides["limitador"]); err != nil {
+		return fmt.Errorf("failed to apply the limitador overrides: %w", err)
+	}
+
+	err = r.setManagedOwnerReference(kObj, limitador)
+	if err != nil {
+		return err
+	}
+
+	mutator := withMaintenanceWindow(kObj, "limitador", withLimitadorOverrides(limitadorMutator, overrides["limitador"]), deferLimitadorStorageChange)
+	return r.ReconcileResource(ctx, &limitadorv1alpha1.Limitador{}, limitador, mutator)
+}
+
+// limitadorMutator enforces the managed labels and owner references of the Limitador instance.
+// The spec is not reconciled once created.
+func limitadorMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*limitadorv1alpha1.Limitador)
+	if !ok {
+		return false, fmt.Errorf("%T is not an *limitadorv1alpha1.Limitador", existingObj)
+	}
+	desired, ok := desiredObj.(*limitadorv1alpha1.Limitador)
+	if !ok {
+		return false, fmt.Errorf("%T is not an *limitadorv1alpha1.Limitador", desiredObj)
+	}
+
+	update := false
+
+	// labels added by the users are preserved
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
+	}
 
-	return r.ReconcileResource(ctx, &limitadorv1alpha1.Limitador{}, limitador, reconcilers.CreateOnlyMutator)
+	// the kuadrant instance may have been recreated
+	if common.UpdateStaleOwnerReferences(existing, desired) {
+		update = true
+	}
+
+	return update, nil
 }
 
 func (r *KuadrantReconciler) reconcileAuthorino(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	if kObj.IsAuthorinoValidateOnly() {
+		// externally managed; discrepancies are reported in the status
+		return nil
+	}
+
+	if err := r.validateCertSecret(ctx, kObj.Namespace, kObj.AuthorinoOIDCServerCertSecretRef()); err != nil {
+		return err
+	}
+
+	if err := r.validateTrustedCABundle(ctx, kObj.Namespace, kObj.AuthorinoTrustedCABundle()); err != nil {
+		return err
+	}
+
+	if err := r.validateAuthorinoVolumes(ctx, kObj); err != nil {
+		return err
+	}
+
+	authorino := desiredAuthorino(kObj)
+
+	// the overrides prevail over the fields of the kuadrant instance
+	overrides, err := r.componentOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	if authorino.Spec, err = common.ApplyMergePatches(authorino.Spec, overrides["authorino"]); err != nil {
+		return fmt.Errorf("failed to apply the authorino overrides: %w", err)
+	}
+
+	err = r.setManagedOwnerReference(kObj, authorino)
+	if err != nil {
+		return err
+	}
+
+	mutator := withMaintenanceWindow(kObj, "authorino", withAuthorinoOverrides(authorinoMutator, overrides["authorino"]), deferAuthorinoSpecChange)
+	if err := r.ReconcileResource(ctx, &authorinov1beta1.Authorino{}, authorino, mutator); err != nil {
+		return err
+	}
+
+	return r.reconcileAuthorinoTrustedCACertDirs(ctx, kObj)
+}
+
+// validateCertSecret checks the Secret of a TLS certificate exists and holds the certificate and the key
+func (r *KuadrantReconciler) validateCertSecret(ctx context.Context, namespace string, secretRef *corev1.LocalObjectReference) error {
+	if secretRef == nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	// read directly from the API server, the secrets are not cached
+	if err := r.APIClientReader().Get(ctx, client.ObjectKey{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
+		return fmt.Errorf("failed to read TLS secret %s: %w", secretRef.Name, err)
+	}
+
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if len(secret.Data[key]) == 0 {
+			return fmt.Errorf("TLS secret %s has no %s entry", secretRef.Name, key)
+		}
+	}
+
+	return nil
+}
+
+// desiredAuthorino returns the Authorino instance expected by the kuadrant instance
+func desiredAuthorino(kObj *kuadrantv1beta1.Kuadrant) *authorinov1beta1.Authorino {
 	tmpFalse := false
 	authorino := &authorinov1beta1.Authorino{
 		TypeMeta: metav1.TypeMeta{
@@ -498,6 +752,7 @@ func (r *KuadrantReconciler) reconcileAuthorino(ctx context.Context, kObj *kuadr
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "authorino",
 			Namespace: kObj.Namespace,
+			Labels:    common.ManagedResourceLabels(kObj.Name, "authorino"),
 		},
 		Spec: authorinov1beta1.AuthorinoSpec{
 			ClusterWide: true,
@@ -514,20 +769,296 @@ func (r *KuadrantReconciler) reconcileAuthorino(ctx context.Context, kObj *kuadr
 		},
 	}
 
-	err := r.SetOwnerReference(kObj, authorino)
-	if err != nil {
-		return err
+	if kObj.Spec.Authorino != nil && kObj.Spec.Authorino.Defaults != nil && kObj.Spec.Authorino.Defaults.Cache != nil {
+		authorino.Spec.EvaluatorCacheSize = kObj.Spec.Authorino.Defaults.Cache.Size
+	}
+
+	// the deadline of the auth pipeline bounds the outbound calls of the evaluators
+	if kObj.Spec.Authorino != nil && kObj.Spec.Authorino.OutboundTimeoutMilliseconds != nil {
+		timeout := int(*kObj.Spec.Authorino.OutboundTimeoutMilliseconds)
+		authorino.Spec.Listener.Timeout = &timeout
+	}
+
+	// authorino logs the full request and the decision at the debug level, structured in the production mode
+	if kObj.AuthorinoDecisionLogsEnabled() {
+		authorino.Spec.LogLevel = authorinoDecisionLogLevel
+		authorino.Spec.LogMode = authorinoDecisionLogMode
+	}
+
+	if metrics := kObj.AuthorinoMetrics(); metrics != nil && metrics.Port != nil {
+		port := *metrics.Port
+		authorino.Spec.Metrics.Port = &port
+	}
+
+	if metrics := kObj.AuthorinoMetrics(); metrics != nil && metrics.Deep != nil {
+		deep := *metrics.Deep
+		authorino.Spec.Metrics.DeepMetricsEnabled = &deep
+	}
+
+	if certSecretRef := kObj.AuthorinoOIDCServerCertSecretRef(); certSecretRef != nil {
+		tmpTrue := true
+		authorino.Spec.OIDCServer.Tls = authorinov1beta1.Tls{
+			Enabled:    &tmpTrue,
+			CertSecret: certSecretRef.DeepCopy(),
+		}
+	}
+
+	if health := kObj.AuthorinoHealth(); health != nil && health.Port != nil {
+		port := *health.Port
+		authorino.Spec.Healthz.Port = &port
+	}
+
+	if trustedCABundle := kObj.AuthorinoTrustedCABundle(); trustedCABundle != nil {
+		authorino.Spec.Volumes.Items = append(authorino.Spec.Volumes.Items, trustedCABundleVolume(trustedCABundle))
+	}
+
+	authorino.Spec.Volumes.Items = append(authorino.Spec.Volumes.Items, authorinoVolumes(kObj)...)
+
+	return authorino
+}
+
+// authorinoDiscrepancies returns the fields of an existing Authorino that differ from the desired one
+func authorinoDiscrepancies(existing, desired *authorinov1beta1.Authorino) []string {
+	discrepancies := make([]string, 0)
+
+	if existing.Spec.ClusterWide != desired.Spec.ClusterWide {
+		discrepancies = append(discrepancies, "spec.clusterWide")
+	}
+
+	if !reflect.DeepEqual(existing.Spec.Listener.Tls.Enabled, desired.Spec.Listener.Tls.Enabled) {
+		discrepancies = append(discrepancies, "spec.listener.tls.enabled")
+	}
+
+	if !reflect.DeepEqual(existing.Spec.OIDCServer.Tls.Enabled, desired.Spec.OIDCServer.Tls.Enabled) {
+		discrepancies = append(discrepancies, "spec.oidcServer.tls.enabled")
+	}
+
+	if !reflect.DeepEqual(existing.Spec.OIDCServer.Tls.CertSecret, desired.Spec.OIDCServer.Tls.CertSecret) {
+		discrepancies = append(discrepancies, "spec.oidcServer.tls.certSecretRef")
+	}
+
+	if !reflect.DeepEqual(existing.Spec.EvaluatorCacheSize, desired.Spec.EvaluatorCacheSize) {
+		discrepancies = append(discrepancies, "spec.evaluatorCacheSize")
+	}
+
+	if !reflect.DeepEqual(existing.Spec.Listener.Timeout, desired.Spec.Listener.Timeout) {
+		discrepancies = append(discrepancies, "spec.listener.timeout")
+	}
+
+	if authorinoLogSettingsOutdated(existing, desired) {
+		discrepancies = append(discrepancies, "spec.logLevel", "spec.logMode")
+	}
+
+	if desired.Spec.Metrics.Port != nil && !reflect.DeepEqual(existing.Spec.Metrics.Port, desired.Spec.Metrics.Port) {
+		discrepancies = append(discrepancies, "spec.metrics.port")
+	}
+
+	if desired.Spec.Metrics.DeepMetricsEnabled != nil && !reflect.DeepEqual(existing.Spec.Metrics.DeepMetricsEnabled, desired.Spec.Metrics.DeepMetricsEnabled) {
+		discrepancies = append(discrepancies, "spec.metrics.deep")
+	}
+
+	if desired.Spec.Healthz.Port != nil && !reflect.DeepEqual(existing.Spec.Healthz.Port, desired.Spec.Healthz.Port) {
+		discrepancies = append(discrepancies, "spec.healthz.port")
+	}
+
+	if managedVolumesDiscrepant(existing, desired) {
+		discrepancies = append(discrepancies, "spec.volumes.items")
+	}
+
+	return discrepancies
+}
+
+// authorinoLogSettingsOutdated tells whether the logging settings of an existing Authorino differ from the ones of the
+// decision logs when enabled, or still are the ones of the decision logs when disabled.
+// The logging settings set by other sources are preserved while the decision logs are disabled.
+func authorinoLogSettingsOutdated(existing, desired *authorinov1beta1.Authorino) bool {
+	decisionLogs := existing.Spec.LogLevel == authorinoDecisionLogLevel && existing.Spec.LogMode == authorinoDecisionLogMode
+	if desired.Spec.LogLevel == "" {
+		return decisionLogs
+	}
+	return !decisionLogs
+}
+
+// authorinoMutator reconciles the managed labels, the owner references and the fields of the Authorino spec configurable from the Kuadrant CR
+func authorinoMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*authorinov1beta1.Authorino)
+	if !ok {
+		return false, fmt.Errorf("%T is not an *authorinov1beta1.Authorino", existingObj)
+	}
+	desired, ok := desiredObj.(*authorinov1beta1.Authorino)
+	if !ok {
+		return false, fmt.Errorf("%T is not an *authorinov1beta1.Authorino", desiredObj)
+	}
+
+	update := false
+
+	// labels added by the users are preserved
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
+	}
+
+	// the kuadrant instance may have been recreated
+	if common.UpdateStaleOwnerReferences(existing, desired) {
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.EvaluatorCacheSize, desired.Spec.EvaluatorCacheSize) {
+		existing.Spec.EvaluatorCacheSize = desired.Spec.EvaluatorCacheSize
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.Listener.Timeout, desired.Spec.Listener.Timeout) {
+		existing.Spec.Listener.Timeout = desired.Spec.Listener.Timeout
+		update = true
+	}
+
+	if authorinoLogSettingsOutdated(existing, desired) {
+		existing.Spec.LogLevel = desired.Spec.LogLevel
+		existing.Spec.LogMode = desired.Spec.LogMode
+		update = true
+	}
+
+	// the port is left to authorino's default when omitted in the kuadrant instance
+	if desired.Spec.Metrics.Port != nil && !reflect.DeepEqual(existing.Spec.Metrics.Port, desired.Spec.Metrics.Port) {
+		existing.Spec.Metrics.Port = desired.Spec.Metrics.Port
+		update = true
+	}
+
+	// the deep metrics are left to authorino's default when omitted in the kuadrant instance
+	if desired.Spec.Metrics.DeepMetricsEnabled != nil && !reflect.DeepEqual(existing.Spec.Metrics.DeepMetricsEnabled, desired.Spec.Metrics.DeepMetricsEnabled) {
+		existing.Spec.Metrics.DeepMetricsEnabled = desired.Spec.Metrics.DeepMetricsEnabled
+		update = true
+	}
+
+	// the port is left to authorino's default when omitted in the kuadrant instance
+	if desired.Spec.Healthz.Port != nil && !reflect.DeepEqual(existing.Spec.Healthz.Port, desired.Spec.Healthz.Port) {
+		existing.Spec.Healthz.Port = desired.Spec.Healthz.Port
+		update = true
+	}
+
+	// rotating the secret reference of the OIDC server re-applies the TLS settings
+	if !reflect.DeepEqual(existing.Spec.OIDCServer.Tls, desired.Spec.OIDCServer.Tls) {
+		existing.Spec.OIDCServer.Tls = desired.Spec.OIDCServer.Tls
+		update = true
 	}
 
-	return r.ReconcileResource(ctx, &authorinov1beta1.Authorino{}, authorino, reconcilers.CreateOnlyMutator)
+	// the volumes set by other sources are preserved
+	if reconcileManagedVolumes(existing, desired) {
+		update = true
+	}
+
+	return update, nil
+}
+
+// setManagedOwnerReference sets the kuadrant instance as owner of a managed resource,
+// unless the managed resources are removed explicitly
+func (r *KuadrantReconciler) setManagedOwnerReference(kObj *kuadrantv1beta1.Kuadrant, obj client.Object) error {
+	if r.ChildCleanupMode == ExplicitCleanupMode {
+		return nil
+	}
+	return r.SetOwnerReference(kObj, obj)
+}
+
+// deleteManagedResources deletes the resources created for the kuadrant instance
+func (r *KuadrantReconciler) deleteManagedResources(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	managedResources := []client.Object{
+		&limitadorv1alpha1.Limitador{ObjectMeta: metav1.ObjectMeta{Name: common.LimitadorName, Namespace: kObj.Namespace}},
+		&maistrav1.ServiceMeshMember{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: kObj.Namespace}},
+	}
+	if !kObj.IsAuthorinoValidateOnly() {
+		managedResources = append(managedResources, &authorinov1beta1.Authorino{ObjectMeta: metav1.ObjectMeta{Name: "authorino", Namespace: kObj.Namespace}})
+	}
+	managedResources = append(managedResources, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: authorinoHealthServiceName, Namespace: kObj.Namespace}})
+	managedResources = append(managedResources, &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: limitadorPodDisruptionBudgetName, Namespace: kObj.Namespace}})
+	managedResources = append(managedResources, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: grafanaDashboardName, Namespace: kObj.Namespace}})
+
+	for _, obj := range managedResources {
+		if err := r.DeleteResource(ctx, obj); err != nil && !apierrors.IsNotFound(err) && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+	}
+
+	// the NetworkPolicies of the gateways live in the namespaces of the gateways
+	return r.deleteNetworkPolicies(ctx, kObj, nil)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KuadrantReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if kuadrantReconcileTaskOrderErr != nil {
+		r.Logger().Info("invalid KUADRANT_RECONCILE_TASKS, using the default order", "error", kuadrantReconcileTaskOrderErr.Error(), "order", kuadrantReconcileTaskOrderString())
+	}
+
+	kuadrantEventMapper := &KuadrantEventMapper{
+		Logger: r.Logger().WithName("kuadrantEventMapper"),
+		Client: r.Client(),
+	}
+
+	iapEventMapper := &IstioAuthorizationPolicyEventMapper{
+		Logger: r.Logger().WithName("istioAuthorizationPolicyEventMapper"),
+		Client: r.Client(),
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&kuadrantv1beta1.Kuadrant{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&limitadorv1alpha1.Limitador{}).
-		Owns(&authorinov1beta1.Authorino{}).
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: KuadrantReconcileWorkers, RateLimiter: KuadrantReconcileRateLimiter})
+
+	// the managed resources have no owner references when removed explicitly, mapped by their labels instead
+	for _, managedType := range []client.Object{&appsv1.Deployment{}, &limitadorv1alpha1.Limitador{}, &authorinov1beta1.Authorino{}, &corev1.Service{}, &policyv1.PodDisruptionBudget{}} {
+		if r.ChildCleanupMode == ExplicitCleanupMode {
+			controllerBuilder = controllerBuilder.Watches(&source.Kind{Type: managedType}, handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapManagedResourceToKuadrant))
+		} else {
+			controllerBuilder = controllerBuilder.Owns(managedType)
+		}
+	}
+
+	controllerBuilder = controllerBuilder.
+		// the deployment of Limitador is owned by the Limitador instance
+		Watches(&source.Kind{Type: &appsv1.Deployment{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToKuadrant),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == common.LimitadorName
+			}))).
+		// the overrides of the specs of the managed components apply to all the kuadrant instances; the operator config
+		// is reloaded by its own controller
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToAllKuadrants),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetNamespace() == operatorNamespace() && obj.GetLabels()[common.ComponentOverridesLabel] == "true"
+			}))).
+		// the gateways pinning authorino instances add ext_authz providers to the mesh config
+		Watches(&source.Kind{Type: &gatewayapiv1beta1.Gateway{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapGatewayToKuadrant),
+			builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		// the default rate limit applies to the rules of the routes of the gateways without any policy
+		Watches(&source.Kind{Type: &gatewayapiv1beta1.HTTPRoute{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapHTTPRouteToKuadrant),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the default AuthPolicy applies to the gateways without an AuthPolicy of their own
+		Watches(&source.Kind{Type: &kuadrantv1beta1.AuthPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToAllKuadrants),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the AuthPolicies out of the scope of a namespaced Authorino are reported in the ScopeMismatch condition
+		Watches(&source.Kind{Type: &kuadrantv1beta1.AuthPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapAuthPolicyToKuadrant),
+			builder.WithPredicates(authPolicyAuthorinoChanged)).
+		// the dashboard has a panel per RateLimitPolicy, set once its limits are in Limitador
+		Watches(&source.Kind{Type: &kuadrantv1beta2.RateLimitPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToAllKuadrants),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, rateLimitPolicyLimitsNamespacesChanged))).
+		// the NetworkPolicies of the namespaces of the gateways may block the gateways from reaching the services of
+		// Authorino and Limitador; the managed NetworkPolicies are restored on changes
+		Watches(&source.Kind{Type: &networkingv1.NetworkPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapNetworkPolicyToKuadrant)).
+		// the managed NetworkPolicies follow the selectors and the target ports of the services of Authorino and Limitador
+		Watches(&source.Kind{Type: &corev1.Service{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToKuadrant)).
+		// the AuthorizationPolicies of the AuthPolicies wire the ext_authz filter of the gateways
+		Watches(&source.Kind{Type: &istiosecurityv1beta1.AuthorizationPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(iapEventMapper.MapToKuadrant))
+
+	if r.ReconcileTrigger != nil {
+		controllerBuilder = controllerBuilder.Watches(r.ReconcileTrigger.Source(&kuadrantv1beta1.KuadrantList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	return controllerBuilder.Complete(withLastSuccessMetric("kuadrant", r))
 }
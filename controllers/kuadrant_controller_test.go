@@ -0,0 +1,91 @@
+//go:build integration
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	authorinoopv1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+var _ = Describe("Kuadrant controller", func() {
+	var (
+		testNamespace string
+	)
+
+	BeforeEach(func() {
+		CreateNamespace(&testNamespace)
+		ApplyKuadrantCR(testNamespace)
+	})
+
+	AfterEach(DeleteNamespaceCallback(&testNamespace))
+
+	Context("Recreate the Kuadrant CR", func() {
+		It("Should update the stale owner reference of the Authorino", func() {
+			kuadrantKey := client.ObjectKey{Name: "kuadrant-sample", Namespace: testNamespace}
+			authorinoKey := client.ObjectKey{Name: "authorino", Namespace: testNamespace}
+
+			authorinoOwnerUID := func() types.UID {
+				authorino := &authorinoopv1beta1.Authorino{}
+				if err := k8sClient.Get(context.Background(), authorinoKey, authorino); err != nil {
+					logf.Log.V(1).Info("[WARN] Getting authorino failed", "error", err)
+					return ""
+				}
+				for _, ownerRef := range authorino.GetOwnerReferences() {
+					if ownerRef.Kind == "Kuadrant" && ownerRef.Name == kuadrantKey.Name {
+						return ownerRef.UID
+					}
+				}
+				return ""
+			}
+
+			kuadrant := &kuadrantv1beta1.Kuadrant{}
+			err := k8sClient.Get(context.Background(), kuadrantKey, kuadrant)
+			Expect(err).ToNot(HaveOccurred())
+			oldUID := kuadrant.GetUID()
+			Eventually(authorinoOwnerUID, 30*time.Second, 5*time.Second).Should(Equal(oldUID))
+
+			// there is no garbage collector in the test environment, the authorino is kept
+			err = k8sClient.Delete(context.Background(), kuadrant)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), kuadrantKey, &kuadrantv1beta1.Kuadrant{})
+				return apierrors.IsNotFound(err)
+			}, 30*time.Second, 5*time.Second).Should(BeTrue())
+
+			ApplyKuadrantCR(testNamespace)
+
+			err = k8sClient.Get(context.Background(), kuadrantKey, kuadrant)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kuadrant.GetUID()).ToNot(Equal(oldUID))
+			Eventually(authorinoOwnerUID, 30*time.Second, 5*time.Second).Should(Equal(kuadrant.GetUID()))
+		})
+	})
+
+	Context("Effective config", func() {
+		It("Should be reported in the status", func() {
+			kuadrantKey := client.ObjectKey{Name: "kuadrant-sample", Namespace: testNamespace}
+
+			Eventually(func() map[string]string {
+				kuadrant := &kuadrantv1beta1.Kuadrant{}
+				if err := k8sClient.Get(context.Background(), kuadrantKey, kuadrant); err != nil {
+					logf.Log.V(1).Info("[WARN] Getting kuadrant failed", "error", err)
+					return nil
+				}
+				return kuadrant.Status.EffectiveConfig
+			}, 30*time.Second, 5*time.Second).Should(And(
+				HaveKeyWithValue("KUADRANT_RECONCILE_WORKERS", "1"),
+				HaveKeyWithValue("ISTIOOPERATOR_NAMESPACE", "istio-system"),
+			))
+		})
+	})
+})
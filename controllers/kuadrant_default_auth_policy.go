@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	istio "istio.io/client-go/pkg/apis/security/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// defaultAuthPolicyKey returns the key of the policy the default AuthPolicy of a kuadrant instance is rendered as
+// for a gateway, naming the AuthConfig enforcing it
+func defaultAuthPolicyKey(kuadrantNamespace string, gwKey client.ObjectKey) client.ObjectKey {
+	return client.ObjectKey{Namespace: kuadrantNamespace, Name: fmt.Sprintf("kuadrant-default-%s-%s", gwKey.Namespace, gwKey.Name)}
+}
+
+// defaultIstioAuthorizationPolicyName returns the name of the AuthorizationPolicy enforcing the default AuthPolicy
+// on a gateway, distinct from the one of a policy of the gateway, the two coexisting while the policy takes over
+func defaultIstioAuthorizationPolicyName(gwName string) string {
+	return fmt.Sprintf("on-%s-default", gwName)
+}
+
+// gatewayDefaultAuthPolicy renders the default AuthPolicy of a kuadrant instance as a policy targeting a gateway.
+// The rendered policy lives in the namespace of the default one, where its template is resolved.
+func gatewayDefaultAuthPolicy(ap *kuadrantv1beta1.AuthPolicy, gw *gatewayapiv1beta1.Gateway) *kuadrantv1beta1.AuthPolicy {
+	key := defaultAuthPolicyKey(ap.Namespace, client.ObjectKeyFromObject(gw))
+	gwNamespace := gatewayapiv1alpha2.Namespace(gw.Namespace)
+
+	defaulted := &kuadrantv1beta1.AuthPolicy{
+		TypeMeta: ap.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        key.Name,
+			Namespace:   key.Namespace,
+			Generation:  ap.Generation,
+			Annotations: map[string]string{common.KuadrantNamespaceLabel: ap.Namespace},
+		},
+		Spec: *ap.Spec.DeepCopy(),
+	}
+	defaulted.Spec.TargetRef = gatewayapiv1alpha2.PolicyTargetReference{
+		Group:     gatewayapiv1alpha2.GroupName,
+		Kind:      "Gateway",
+		Name:      gatewayapiv1alpha2.ObjectName(gw.Name),
+		Namespace: &gwNamespace,
+	}
+	defaulted.Spec.ExpiresAt = nil
+	return defaulted
+}
+
+// defaultAuthPolicyGateways returns the default AuthPolicy of a kuadrant instance, or nil if not set or not found,
+// and the gateways managed by the instance it applies to, i.e. the gateways without an AuthPolicy targeting them
+func (r *KuadrantReconciler) defaultAuthPolicyGateways(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*kuadrantv1beta1.AuthPolicy, []*gatewayapiv1beta1.Gateway, error) {
+	// the default AuthPolicy is lifted along with the kuadrant instance
+	if kObj.Spec.DefaultAuthPolicy == nil || kObj.GetDeletionTimestamp() != nil {
+		return nil, nil, nil
+	}
+
+	ap := &kuadrantv1beta1.AuthPolicy{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: kObj.Spec.DefaultAuthPolicy.Name, Namespace: kObj.Namespace}, ap); err != nil {
+		// reconciled again once the policy is created
+		return nil, nil, client.IgnoreNotFound(err)
+	}
+	if ap.GetDeletionTimestamp() != nil {
+		return nil, nil, nil
+	}
+
+	// the policies claim their gateways by the back reference only once their resources are reconciled
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := r.Client().List(ctx, apList); err != nil {
+		return nil, nil, err
+	}
+	targeted := make(map[client.ObjectKey]struct{})
+	for idx := range apList.Items {
+		targetRef := apList.Items[idx].GetTargetRef()
+		if common.IsTargetRefGateway(targetRef) {
+			targeted[client.ObjectKey{Name: string(targetRef.Name), Namespace: string(common.GetDefaultIfNil(targetRef.Namespace, apList.Items[idx].GetWrappedNamespace()))}] = struct{}{}
+		}
+	}
+
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := r.Client().List(ctx, gwList); err != nil {
+		return nil, nil, err
+	}
+
+	logger, _ := logr.FromContext(ctx)
+	defaulted := make([]*gatewayapiv1beta1.Gateway, 0)
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		if _, found := common.ReadAnnotationsFromObject(gw)[common.AuthPolicyBackRefAnnotation]; found {
+			continue
+		}
+		if _, found := targeted[client.ObjectKeyFromObject(gw)]; found {
+			continue
+		}
+		// e.g. the hosts of the rules of the default policy are not hostnames of the gateway
+		gwPolicy := gatewayDefaultAuthPolicy(ap, gw)
+		if err := gwPolicy.Validate(); err != nil {
+			logger.V(1).Info("default AuthPolicy not applicable to the gateway", "gateway", client.ObjectKeyFromObject(gw), "reason", err.Error())
+			continue
+		}
+		if err := common.ValidateHierarchicalRules(gwPolicy, gw); err != nil {
+			logger.V(1).Info("default AuthPolicy not applicable to the gateway", "gateway", client.ObjectKeyFromObject(gw), "reason", err.Error())
+			continue
+		}
+		defaulted = append(defaulted, gw)
+	}
+
+	return ap, defaulted, nil
+}
+
+// reconcileDefaultAuthPolicy reconciles the AuthConfig and the Istio AuthorizationPolicy enforcing the default
+// AuthPolicy of a kuadrant instance on each gateway without an AuthPolicy of its own, and deletes the ones of the
+// other gateways. The AuthPolicies of the routes prevail for the hostnames of the routes, as over a policy of the gateway.
+func (r *KuadrantReconciler) reconcileDefaultAuthPolicy(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	ap, gateways, err := r.defaultAuthPolicyGateways(ctx, kObj)
+	if err != nil {
+		return err
+	}
+
+	// the resources are rendered as the ones of a policy targeting the gateway
+	apReconciler := &AuthPolicyReconciler{TargetRefReconciler: reconcilers.TargetRefReconciler{BaseReconciler: r.BaseReconciler}}
+	desiredAuthConfigs := make(map[client.ObjectKey]struct{}, len(gateways))
+	desiredIAPs := make(map[client.ObjectKey]struct{}, len(gateways))
+	for _, gw := range gateways {
+		gwPolicy := gatewayDefaultAuthPolicy(ap, gw)
+
+		authConfig, err := apReconciler.desiredAuthConfig(ctx, gwPolicy, gw)
+		if err != nil {
+			return err
+		}
+		if authConfig.Labels == nil {
+			authConfig.Labels = make(map[string]string)
+		}
+		authConfig.Labels[common.DefaultAuthPolicyLabel] = kObj.Namespace
+		if err := r.ReconcileResource(ctx, &authorinoapi.AuthConfig{}, authConfig, alwaysUpdateAuthConfig); err != nil {
+			return err
+		}
+		desiredAuthConfigs[client.ObjectKeyFromObject(authConfig)] = struct{}{}
+
+		toRules, err := apReconciler.istioAuthorizationPolicyToRules(ctx, gwPolicy, gw)
+		if err != nil {
+			return err
+		}
+		iap := apReconciler.istioAuthorizationPolicy(ctx, gw, gwPolicy, toRules)
+		iap.Name = defaultIstioAuthorizationPolicyName(gw.Name)
+		iap.Labels[common.DefaultAuthPolicyLabel] = kObj.Namespace
+		if err := r.ReconcileResource(ctx, &istio.AuthorizationPolicy{}, iap, alwaysUpdateAuthPolicy); err != nil {
+			return err
+		}
+		desiredIAPs[client.ObjectKeyFromObject(iap)] = struct{}{}
+	}
+
+	// the resources of the gateways no longer defaulted
+	selector := client.MatchingLabels{common.DefaultAuthPolicyLabel: kObj.Namespace}
+
+	authConfigList := &authorinoapi.AuthConfigList{}
+	if err := r.Client().List(ctx, authConfigList, selector); err != nil {
+		return err
+	}
+	for idx := range authConfigList.Items {
+		if _, found := desiredAuthConfigs[client.ObjectKeyFromObject(&authConfigList.Items[idx])]; found {
+			continue
+		}
+		if err := r.DeleteResource(ctx, &authConfigList.Items[idx]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	iapList := &istio.AuthorizationPolicyList{}
+	if err := r.Client().List(ctx, iapList, selector); err != nil {
+		return err
+	}
+	for _, iap := range iapList.Items {
+		if _, found := desiredIAPs[client.ObjectKeyFromObject(iap)]; found {
+			continue
+		}
+		if err := r.DeleteResource(ctx, iap); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// defaultAuthPolicyGatewaysStatus returns the sorted keys of the gateways the default AuthPolicy of a kuadrant
+// instance applies to, or nil if not set
+func (r *KuadrantReconciler) defaultAuthPolicyGatewaysStatus(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]string, error) {
+	_, defaulted, err := r.defaultAuthPolicyGateways(ctx, kObj)
+	if err != nil || len(defaulted) == 0 {
+		return nil, err
+	}
+
+	gateways := common.Map(defaulted, func(gw *gatewayapiv1beta1.Gateway) string { return client.ObjectKeyFromObject(gw).String() })
+	sort.Strings(gateways)
+	return gateways, nil
+}
+
+// deleteGatewayDefaultAuthPolicy deletes the resources enforcing the default AuthPolicy on a gateway taken over by a
+// policy of its own, before the resources of the policy are reconciled, not to conflict on the hostnames of the gateway
+func (r *AuthPolicyReconciler) deleteGatewayDefaultAuthPolicy(ctx context.Context, gw *gatewayapiv1beta1.Gateway) error {
+	kuadrantNamespace, err := common.GetKuadrantNamespace(gw)
+	if err != nil {
+		return nil
+	}
+
+	resources := []client.Object{
+		&istio.AuthorizationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultIstioAuthorizationPolicyName(gw.Name), Namespace: gw.Namespace},
+		},
+		&authorinoapi.AuthConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      authConfigName(defaultAuthPolicyKey(kuadrantNamespace, client.ObjectKeyFromObject(gw))),
+				Namespace: kuadrantNamespace,
+			},
+		},
+	}
+	for _, obj := range resources {
+		// checked first, the policies of the gateways being reconciled far more often than taking over
+		if err := r.Client().Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		if obj.GetLabels()[common.DefaultAuthPolicyLabel] == "" {
+			continue
+		}
+		if err := r.DeleteResource(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
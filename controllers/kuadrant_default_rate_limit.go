@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	istioclientgoextensionv1alpha1 "istio.io/client-go/pkg/apis/extensions/v1alpha1"
+	istioclientnetworkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	kuadrantistioutils "github.com/kuadrant/kuadrant-operator/pkg/istio"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
+)
+
+// gatewayDefaultRateLimit returns the default rate limit of the kuadrant instance managing a gateway,
+// or nil if not set or the gateway is not managed by any kuadrant instance
+func gatewayDefaultRateLimit(ctx context.Context, cl client.Client, gw client.Object) (*kuadrantv1beta1.DefaultRateLimitSpec, error) {
+	kuadrantNamespace, err := common.GetKuadrantNamespace(gw)
+	if err != nil {
+		return nil, nil
+	}
+
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := cl.List(ctx, kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+		return nil, err
+	}
+
+	for idx := range kuadrantList.Items {
+		kObj := &kuadrantList.Items[idx]
+		// the default rate limit is lifted along with the kuadrant instance
+		if kObj.GetDeletionTimestamp() == nil && kObj.Spec.DefaultRateLimit != nil {
+			return kObj.Spec.DefaultRateLimit, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// defaultRateLimitGateways returns the gateways managed by a kuadrant instance without a RateLimitPolicy targeting
+// the gateway, i.e. the gateways the default rate limit applies to, and, among them, the ones without any
+// RateLimitPolicy, i.e. neither targeting their routes
+func (r *KuadrantReconciler) defaultRateLimitGateways(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]*gatewayapiv1beta1.Gateway, []*gatewayapiv1beta1.Gateway, error) {
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := r.Client().List(ctx, gwList); err != nil {
+		return nil, nil, err
+	}
+
+	defaulted := make([]*gatewayapiv1beta1.Gateway, 0)
+	withoutPolicies := make([]*gatewayapiv1beta1.Gateway, 0)
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		if _, found := common.ReadAnnotationsFromObject(gw)[common.RateLimitPolicyBackRefAnnotation]; found {
+			continue
+		}
+		defaulted = append(defaulted, gw)
+		if len(common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}.PolicyRefs()) == 0 {
+			withoutPolicies = append(withoutPolicies, gw)
+		}
+	}
+	return defaulted, withoutPolicies, nil
+}
+
+// reconcileDefaultRateLimit reconciles the limits of the default rate limit of a kuadrant instance in Limitador and,
+// for the gateways without any RateLimitPolicy, the wasm config and the rate limiting cluster enforcing it.
+// The wasm config of the other gateways is reconciled along with their policies.
+func (r *KuadrantReconciler) reconcileDefaultRateLimit(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	defaulted, withoutPolicies, err := r.defaultRateLimitGateways(ctx, kObj)
+	if err != nil {
+		return err
+	}
+
+	limitador := &limitadorv1alpha1.Limitador{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: kObj.Namespace}, limitador); err != nil {
+		// reconciled again once limitador is created
+		return client.IgnoreNotFound(err)
+	}
+
+	rateLimitIndex := rlptools.NewRateLimitIndex()
+	if kObj.GetDeletionTimestamp() == nil {
+		gwKeys := common.Map(defaulted, func(gw *gatewayapiv1beta1.Gateway) client.ObjectKey { return client.ObjectKeyFromObject(gw) })
+		rateLimitIndex.Set(client.ObjectKeyFromObject(kObj), rlptools.LimitadorDefaultRateLimits(kObj.Spec.DefaultRateLimit, gwKeys))
+	}
+	// the limits of the policies and of other sources are left untouched
+	rateLimits := rateLimitIndex.MergeInto(limitador.Spec.Limits, rlptools.IsDefaultRateLimit)
+	if !rlptools.Equal(rateLimits, limitador.Spec.Limits) {
+		limitador.Spec.Limits = rateLimits
+		if err := r.UpdateResource(ctx, limitador); err != nil {
+			return err
+		}
+	}
+
+	if !meta.IsStatusConditionTrue(limitador.Status.Conditions, "Ready") {
+		// reconciled again once limitador is ready
+		return nil
+	}
+
+	// the wasm config and the rate limiting cluster are rendered as the ones of a gateway whose last policy is removed
+	rlpReconciler := &RateLimitPolicyReconciler{TargetRefReconciler: reconcilers.TargetRefReconciler{BaseReconciler: r.BaseReconciler}}
+	for _, gw := range withoutPolicies {
+		ef, err := rlpReconciler.gatewayRateLimitingClusterEnvoyFilter(ctx, gw, nil)
+		if err != nil {
+			return err
+		}
+		if err := r.ReconcileResource(ctx, &istioclientnetworkingv1alpha3.EnvoyFilter{}, ef, kuadrantistioutils.AlwaysUpdateEnvoyFilter); err != nil {
+			return err
+		}
+
+		wp, err := rlpReconciler.gatewayWASMPlugin(ctx, common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}, nil)
+		if err != nil {
+			return err
+		}
+		if err := r.ReconcileResource(ctx, &istioclientgoextensionv1alpha1.WasmPlugin{}, wp, rlptools.WASMPluginMutator); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// defaultRateLimitGatewaysStatus returns the sorted keys of the gateways the default rate limit of a kuadrant
+// instance applies to, or nil if not set
+func (r *KuadrantReconciler) defaultRateLimitGatewaysStatus(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]string, error) {
+	if kObj.Spec.DefaultRateLimit == nil {
+		return nil, nil
+	}
+
+	defaulted, _, err := r.defaultRateLimitGateways(ctx, kObj)
+	if err != nil || len(defaulted) == 0 {
+		return nil, err
+	}
+
+	gateways := common.Map(defaulted, func(gw *gatewayapiv1beta1.Gateway) string { return client.ObjectKeyFromObject(gw).String() })
+	sort.Strings(gateways)
+	return gateways, nil
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// KuadrantDeletionPolicy defines how the removal of a Kuadrant instance still backing policies is handled
+type KuadrantDeletionPolicy string
+
+const (
+	// MarkPoliciesDeletionPolicy lets the Kuadrant instance be removed, after flagging the policies it backs
+	// with the BackendRemoved condition
+	MarkPoliciesDeletionPolicy KuadrantDeletionPolicy = "mark-policies"
+	// BlockDeletionPolicy keeps the Kuadrant instance until the policies it backs are deleted
+	BlockDeletionPolicy KuadrantDeletionPolicy = "block"
+)
+
+const (
+	DeletionBlockedConditionType string = "DeletionBlocked"
+	BackendRemovedConditionType  string = "BackendRemoved"
+)
+
+// deletionBlockedRequeueDelay is the delay before checking again for the policies blocking the removal of a Kuadrant instance
+const deletionBlockedRequeueDelay = 30 * time.Second
+
+// handlePoliciesOnDeletion applies the deletion policy to the policies backed by a Kuadrant instance being removed.
+// Returns true if the removal must wait, i.e. blocked by the policies, or on any error, the removal not proceeding
+// until the policies are listed and their status, or the status of the Kuadrant instance, updated. The objects deleted
+// in the meantime are skipped.
+func (r *KuadrantReconciler) handlePoliciesOnDeletion(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (bool, error) {
+	logger, _ := logr.FromContext(ctx)
+
+	apList, rlpList, err := r.backedPolicies(ctx, kObj)
+	if err != nil {
+		return true, err
+	}
+	if len(apList)+len(rlpList) == 0 {
+		return false, nil
+	}
+
+	if r.DeletionPolicy == BlockDeletionPolicy {
+		policyKeys := make([]string, 0, len(apList)+len(rlpList))
+		for idx := range apList {
+			policyKeys = append(policyKeys, fmt.Sprintf("AuthPolicy %s", client.ObjectKeyFromObject(&apList[idx])))
+		}
+		for idx := range rlpList {
+			policyKeys = append(policyKeys, fmt.Sprintf("RateLimitPolicy %s", client.ObjectKeyFromObject(&rlpList[idx])))
+		}
+		logger.Info("deletion blocked by existing policies", "policies", policyKeys)
+
+		cond := metav1.Condition{
+			Type:    DeletionBlockedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "PoliciesExist",
+			Message: fmt.Sprintf("Policies backed by the Kuadrant instance must be deleted first: %s", strings.Join(policyKeys, ", ")),
+		}
+		if err := r.updateDeletionCondition(ctx, kObj, &kObj.Status.Conditions, cond); err != nil {
+			return true, fmt.Errorf("failed to update the status of the kuadrant instance: %w", err)
+		}
+		return true, nil
+	}
+
+	cond := metav1.Condition{
+		Type:    BackendRemovedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "KuadrantDeleted",
+		Message: fmt.Sprintf("Kuadrant instance %s removed, the policy is no longer enforced", client.ObjectKeyFromObject(kObj)),
+	}
+	// all the policies are marked, whatever the failures, the removal waiting for the ones failed only
+	failed := make([]string, 0)
+	var lastErr error
+	for idx := range apList {
+		if err := r.updateDeletionCondition(ctx, &apList[idx], &apList[idx].Status.Conditions, cond); err != nil {
+			failed = append(failed, fmt.Sprintf("AuthPolicy %s", client.ObjectKeyFromObject(&apList[idx])))
+			lastErr = err
+		}
+	}
+	for idx := range rlpList {
+		if err := r.updateDeletionCondition(ctx, &rlpList[idx], &rlpList[idx].Status.Conditions, cond); err != nil {
+			failed = append(failed, fmt.Sprintf("RateLimitPolicy %s", client.ObjectKeyFromObject(&rlpList[idx])))
+			lastErr = err
+		}
+	}
+	if len(failed) > 0 {
+		return true, fmt.Errorf("failed to mark the policies as backend removed: %s: %w", strings.Join(failed, ", "), lastErr)
+	}
+	logger.Info("policies marked as backend removed", "authpolicies", len(apList), "ratelimitpolicies", len(rlpList))
+
+	return false, nil
+}
+
+// updateDeletionCondition sets a condition reflecting the removal of a Kuadrant instance in the status of an object,
+// updated only if the condition changed. The object deleted in the meantime is skipped.
+func (r *KuadrantReconciler) updateDeletionCondition(ctx context.Context, obj client.Object, conditions *[]metav1.Condition, cond metav1.Condition) error {
+	current, _ := common.ConditionMarshal(*conditions)
+	meta.SetStatusCondition(conditions, cond)
+	desired, _ := common.ConditionMarshal(*conditions)
+	if string(current) == string(desired) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Client().Status().Update(ctx, obj))
+}
+
+// backedPolicies returns the policies bound to the namespace of the Kuadrant instance
+func (r *KuadrantReconciler) backedPolicies(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]kuadrantv1beta1.AuthPolicy, []kuadrantv1beta2.RateLimitPolicy, error) {
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := r.Client().List(ctx, apList); err != nil {
+		return nil, nil, err
+	}
+	rlpList := &kuadrantv1beta2.RateLimitPolicyList{}
+	if err := r.Client().List(ctx, rlpList); err != nil {
+		return nil, nil, err
+	}
+
+	aps := make([]kuadrantv1beta1.AuthPolicy, 0)
+	for idx := range apList.Items {
+		if isBackedBy(&apList.Items[idx], kObj) {
+			aps = append(aps, apList.Items[idx])
+		}
+	}
+	rlps := make([]kuadrantv1beta2.RateLimitPolicy, 0)
+	for idx := range rlpList.Items {
+		if isBackedBy(&rlpList.Items[idx], kObj) {
+			rlps = append(rlps, rlpList.Items[idx])
+		}
+	}
+
+	return aps, rlps, nil
+}
+
+func isBackedBy(policy common.KuadrantPolicy, kObj *kuadrantv1beta1.Kuadrant) bool {
+	if policy.GetDeletionTimestamp() != nil {
+		return false
+	}
+	kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(policy)
+	return isSet && kuadrantNamespace == kObj.Namespace
+}
+
+// clearBackendRemovedCondition removes the BackendRemoved condition of a policy once a Kuadrant instance
+// exists again in the kuadrant namespace of the policy
+func clearBackendRemovedCondition(ctx context.Context, cli client.Client, conditions *[]metav1.Condition, policy common.KuadrantPolicy) error {
+	if meta.FindStatusCondition(*conditions, BackendRemovedConditionType) == nil {
+		return nil
+	}
+
+	kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(policy)
+	if !isSet {
+		return nil
+	}
+
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := cli.List(ctx, kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+		return err
+	}
+	for idx := range kuadrantList.Items {
+		if kuadrantList.Items[idx].GetDeletionTimestamp() == nil {
+			meta.RemoveStatusCondition(conditions, BackendRemovedConditionType)
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// failingStatusClient fails the status updates of the objects of the given name
+type failingStatusClient struct {
+	client.Client
+	name string
+}
+
+func (c *failingStatusClient) Status() client.StatusWriter {
+	return &failingStatusWriter{StatusWriter: c.Client.Status(), name: c.name}
+}
+
+type failingStatusWriter struct {
+	client.StatusWriter
+	name string
+}
+
+func (w *failingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if obj.GetName() == w.name {
+		return errors.New("status update failed")
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestHandlePoliciesOnDeletion(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"}}
+	backed := func(obj client.Object) client.Object {
+		obj.SetAnnotations(map[string]string{common.KuadrantNamespaceLabel: kObj.Namespace})
+		return obj
+	}
+	gw := testGateway("gw")
+	ap := backed(testAuthPolicy("ap", "ns", gw))
+	rlp := backed(testRateLimitPolicy("rlp", gw, 10))
+	otherRLP := testRateLimitPolicy("other", gw, 10)
+
+	newReconciler := func(policy KuadrantDeletionPolicy, failFor string, objs ...client.Object) *KuadrantReconciler {
+		objs = append(objs, kObj.DeepCopy())
+		var cl client.Client = fake.NewClientBuilder().WithScheme(unitTestScheme()).WithObjects(objs...).Build()
+		if failFor != "" {
+			cl = &failingStatusClient{Client: cl, name: failFor}
+		}
+		return &KuadrantReconciler{
+			BaseReconciler: reconcilers.NewBaseReconciler(cl, cl.Scheme(), cl, logr.Discard(), record.NewFakeRecorder(10)),
+			DeletionPolicy: policy,
+		}
+	}
+	// the kuadrant instance as read by the reconciliation
+	currentKuadrant := func(r *KuadrantReconciler) *kuadrantv1beta1.Kuadrant {
+		existing := &kuadrantv1beta1.Kuadrant{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(kObj), existing); err != nil {
+			t.Fatal(err)
+		}
+		return existing
+	}
+	backendRemoved := func(r *KuadrantReconciler, policy client.Object) bool {
+		existing := &kuadrantv1beta2.RateLimitPolicy{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(policy), existing); err != nil {
+			t.Fatal(err)
+		}
+		return meta.IsStatusConditionTrue(existing.Status.Conditions, BackendRemovedConditionType)
+	}
+
+	t.Run("no policy backed", func(subT *testing.T) {
+		r := newReconciler(MarkPoliciesDeletionPolicy, "", otherRLP.DeepCopy())
+		if blocked, err := r.handlePoliciesOnDeletion(context.TODO(), currentKuadrant(r)); blocked || err != nil {
+			subT.Errorf("expected the removal to proceed, got %t, %v", blocked, err)
+		}
+	})
+
+	t.Run("blocked", func(subT *testing.T) {
+		r := newReconciler(BlockDeletionPolicy, "", ap.DeepCopyObject().(client.Object), rlp.DeepCopyObject().(client.Object))
+		if blocked, err := r.handlePoliciesOnDeletion(context.TODO(), currentKuadrant(r)); !blocked || err != nil {
+			subT.Fatalf("expected the removal blocked, got %t, %v", blocked, err)
+		}
+		if !meta.IsStatusConditionTrue(currentKuadrant(r).Status.Conditions, DeletionBlockedConditionType) {
+			subT.Error("expected the DeletionBlocked condition")
+		}
+	})
+
+	t.Run("blocked, failing to update the kuadrant instance", func(subT *testing.T) {
+		r := newReconciler(BlockDeletionPolicy, kObj.Name, rlp.DeepCopyObject().(client.Object))
+		if blocked, err := r.handlePoliciesOnDeletion(context.TODO(), currentKuadrant(r)); !blocked || err == nil {
+			subT.Errorf("expected the removal blocked with an error, got %t, %v", blocked, err)
+		}
+	})
+
+	t.Run("policies marked", func(subT *testing.T) {
+		r := newReconciler(MarkPoliciesDeletionPolicy, "", ap.DeepCopyObject().(client.Object), rlp.DeepCopyObject().(client.Object), otherRLP.DeepCopy())
+		if blocked, err := r.handlePoliciesOnDeletion(context.TODO(), currentKuadrant(r)); blocked || err != nil {
+			subT.Fatalf("expected the removal to proceed, got %t, %v", blocked, err)
+		}
+		if !backendRemoved(r, rlp) {
+			subT.Error("expected the policy backed marked")
+		}
+		if backendRemoved(r, otherRLP) {
+			subT.Error("expected the policy not backed left as is")
+		}
+		existingAP := &kuadrantv1beta1.AuthPolicy{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(ap), existingAP); err != nil {
+			subT.Fatal(err)
+		}
+		if !meta.IsStatusConditionTrue(existingAP.Status.Conditions, BackendRemovedConditionType) {
+			subT.Error("expected the authpolicy backed marked")
+		}
+	})
+
+	t.Run("failing to mark a policy", func(subT *testing.T) {
+		r := newReconciler(MarkPoliciesDeletionPolicy, ap.GetName(), ap.DeepCopyObject().(client.Object), rlp.DeepCopyObject().(client.Object))
+		blocked, err := r.handlePoliciesOnDeletion(context.TODO(), currentKuadrant(r))
+		if !blocked || err == nil {
+			subT.Fatalf("expected the removal to wait with an error, got %t, %v", blocked, err)
+		}
+		// the other policies are marked nonetheless
+		if !backendRemoved(r, rlp) {
+			subT.Error("expected the other policy marked")
+		}
+	})
+}
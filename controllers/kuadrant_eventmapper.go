@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// KuadrantEventMapper is an EventHandler that maps the events of the resources of a kuadrant instance
+// to the policies enforced by the kuadrant instance
+type KuadrantEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+// MapToAuthPolicy maps to the AuthPolicies of the kuadrant instance of the namespace of the object.
+// The policies not yet bound to a kuadrant instance are included.
+func (m *KuadrantEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := m.Client.List(context.TODO(), apList); err != nil {
+		m.Logger.V(1).Info("MapToAuthPolicy: failed to list authpolicies", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		if kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(ap); isSet && kuadrantNamespace != obj.GetNamespace() {
+			continue
+		}
+		m.Logger.V(1).Info("MapToAuthPolicy", "object", client.ObjectKeyFromObject(obj), "authpolicy", client.ObjectKeyFromObject(ap))
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ap)})
+	}
+
+	return requests
+}
+
+// MapToRateLimitPolicy maps to the RateLimitPolicies of the kuadrant instance of the namespace of the object
+func (m *KuadrantEventMapper) MapToRateLimitPolicy(obj client.Object) []reconcile.Request {
+	rlpList := &kuadrantv1beta2.RateLimitPolicyList{}
+	if err := m.Client.List(context.TODO(), rlpList); err != nil {
+		m.Logger.V(1).Info("MapToRateLimitPolicy: failed to list ratelimitpolicies", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range rlpList.Items {
+		rlp := &rlpList.Items[idx]
+		if kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(rlp); !isSet || kuadrantNamespace != obj.GetNamespace() {
+			continue
+		}
+		m.Logger.V(1).Info("MapToRateLimitPolicy", "object", client.ObjectKeyFromObject(obj), "ratelimitpolicy", client.ObjectKeyFromObject(rlp))
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rlp)})
+	}
+
+	return requests
+}
+
+// MapToKuadrant maps to the kuadrant instances of the namespace of the object
+func (m *KuadrantEventMapper) MapToKuadrant(obj client.Object) []reconcile.Request {
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := m.Client.List(context.TODO(), kuadrantList, client.InNamespace(obj.GetNamespace())); err != nil {
+		m.Logger.V(1).Info("MapToKuadrant: failed to list kuadrants", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(kuadrantList.Items))
+	for idx := range kuadrantList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&kuadrantList.Items[idx])})
+	}
+
+	return requests
+}
+
+// MapManagedResourceToKuadrant maps a resource managed by the operator to its kuadrant instance, told by its labels,
+// in place of the owner references not set when the managed resources are removed explicitly
+func (m *KuadrantEventMapper) MapManagedResourceToKuadrant(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[common.AppManagedByLabel] != common.KuadrantOperatorName || labels[common.AppInstanceLabel] == "" {
+		return []reconcile.Request{}
+	}
+	kuadrantKey := client.ObjectKey{Namespace: obj.GetNamespace(), Name: labels[common.AppInstanceLabel]}
+	m.Logger.V(1).Info("MapManagedResourceToKuadrant", "object", client.ObjectKeyFromObject(obj), "kuadrant", kuadrantKey)
+	return []reconcile.Request{{NamespacedName: kuadrantKey}}
+}
+
+// MapGatewayToKuadrant maps a gateway to the kuadrant instances of its kuadrant namespace
+func (m *KuadrantEventMapper) MapGatewayToKuadrant(obj client.Object) []reconcile.Request {
+	kuadrantNamespace, err := common.GetKuadrantNamespace(obj)
+	if err != nil {
+		// the gateway is not managed by any kuadrant instance
+		return []reconcile.Request{}
+	}
+
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := m.Client.List(context.TODO(), kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+		m.Logger.V(1).Info("MapGatewayToKuadrant: failed to list kuadrants", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(kuadrantList.Items))
+	for idx := range kuadrantList.Items {
+		m.Logger.V(1).Info("MapGatewayToKuadrant", "gateway", client.ObjectKeyFromObject(obj), "kuadrant", client.ObjectKeyFromObject(&kuadrantList.Items[idx]))
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&kuadrantList.Items[idx])})
+	}
+
+	return requests
+}
+
+// MapAuthPolicyToKuadrant maps an AuthPolicy to the kuadrant instances of the kuadrant namespace of its target
+func (m *KuadrantEventMapper) MapAuthPolicyToKuadrant(obj client.Object) []reconcile.Request {
+	ap, ok := obj.(*kuadrantv1beta1.AuthPolicy)
+	if !ok {
+		m.Logger.V(1).Info("MapAuthPolicyToKuadrant: AuthPolicy not received", "error", fmt.Sprintf("%T is not a *kuadrantv1beta1.AuthPolicy", obj))
+		return []reconcile.Request{}
+	}
+
+	kuadrantNamespace, err := common.GetKuadrantNamespaceFromPolicyTargetRef(context.TODO(), m.Client, ap)
+	if err != nil {
+		m.Logger.V(1).Info("MapAuthPolicyToKuadrant: failed to get the kuadrant namespace of the authpolicy", "authpolicy", client.ObjectKeyFromObject(ap), "error", err)
+		return []reconcile.Request{}
+	}
+
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := m.Client.List(context.TODO(), kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+		m.Logger.V(1).Info("MapAuthPolicyToKuadrant: failed to list kuadrants", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(kuadrantList.Items))
+	for idx := range kuadrantList.Items {
+		m.Logger.V(1).Info("MapAuthPolicyToKuadrant", "authpolicy", client.ObjectKeyFromObject(ap), "kuadrant", client.ObjectKeyFromObject(&kuadrantList.Items[idx]))
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&kuadrantList.Items[idx])})
+	}
+
+	return requests
+}
+
+// MapHTTPRouteToKuadrant maps an HTTPRoute to the kuadrant instances of the kuadrant namespaces of its parent gateways
+func (m *KuadrantEventMapper) MapHTTPRouteToKuadrant(obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gatewayapiv1beta1.HTTPRoute)
+	if !ok {
+		m.Logger.V(1).Info("MapHTTPRouteToKuadrant: HTTPRoute not received", "error", fmt.Sprintf("%T is not a *gatewayapiv1beta1.HTTPRoute", obj))
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, parentRef := range route.Spec.ParentRefs {
+		gwKey := client.ObjectKey{Name: string(parentRef.Name), Namespace: route.Namespace}
+		if parentRef.Namespace != nil {
+			gwKey.Namespace = string(*parentRef.Namespace)
+		}
+		gw := &gatewayapiv1beta1.Gateway{}
+		if err := m.Client.Get(context.TODO(), gwKey, gw); err != nil {
+			m.Logger.V(1).Info("MapHTTPRouteToKuadrant: failed to get parent gateway", "gateway", gwKey, "error", err)
+			continue
+		}
+		requests = append(requests, m.MapGatewayToKuadrant(gw)...)
+	}
+
+	return requests
+}
+
+// MapNetworkPolicyToKuadrant maps a NetworkPolicy managed by the operator to its kuadrant instance, told by its labels,
+// and the other NetworkPolicies to the kuadrant instances whose namespace, or the namespace of any of whose gateways,
+// contains the NetworkPolicy, i.e. the NetworkPolicies that may block the gateways from reaching Authorino and Limitador
+func (m *KuadrantEventMapper) MapNetworkPolicyToKuadrant(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[common.AppManagedByLabel] == common.KuadrantOperatorName && labels[common.AppComponentLabel] == networkPolicyComponent {
+		if labels[common.AppInstanceLabel] == "" || labels[common.KuadrantNamespaceLabel] == "" {
+			return []reconcile.Request{}
+		}
+		kuadrantKey := client.ObjectKey{Namespace: labels[common.KuadrantNamespaceLabel], Name: labels[common.AppInstanceLabel]}
+		m.Logger.V(1).Info("MapNetworkPolicyToKuadrant", "networkpolicy", client.ObjectKeyFromObject(obj), "kuadrant", kuadrantKey)
+		return []reconcile.Request{{NamespacedName: kuadrantKey}}
+	}
+
+	// the kuadrant namespaces of the gateways of the namespace of the network policy
+	kuadrantNamespaces := []string{obj.GetNamespace()}
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := m.Client.List(context.TODO(), gwList, client.InNamespace(obj.GetNamespace())); err != nil {
+		m.Logger.V(1).Info("MapNetworkPolicyToKuadrant: failed to list gateways", "error", err)
+		return []reconcile.Request{}
+	}
+	for idx := range gwList.Items {
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(&gwList.Items[idx]); err == nil && !common.Contains(kuadrantNamespaces, kuadrantNamespace) {
+			kuadrantNamespaces = append(kuadrantNamespaces, kuadrantNamespace)
+		}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, kuadrantNamespace := range kuadrantNamespaces {
+		kuadrantList := &kuadrantv1beta1.KuadrantList{}
+		if err := m.Client.List(context.TODO(), kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+			m.Logger.V(1).Info("MapNetworkPolicyToKuadrant: failed to list kuadrants", "error", err)
+			return []reconcile.Request{}
+		}
+		for idx := range kuadrantList.Items {
+			m.Logger.V(1).Info("MapNetworkPolicyToKuadrant", "networkpolicy", client.ObjectKeyFromObject(obj), "kuadrant", client.ObjectKeyFromObject(&kuadrantList.Items[idx]))
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&kuadrantList.Items[idx])})
+		}
+	}
+
+	return requests
+}
+
+// MapToAllKuadrants maps to all the kuadrant instances of the cluster
+func (m *KuadrantEventMapper) MapToAllKuadrants(obj client.Object) []reconcile.Request {
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := m.Client.List(context.TODO(), kuadrantList); err != nil {
+		m.Logger.V(1).Info("MapToAllKuadrants: failed to list kuadrants", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(kuadrantList.Items))
+	for idx := range kuadrantList.Items {
+		m.Logger.V(1).Info("MapToAllKuadrants", "object", client.ObjectKeyFromObject(obj), "kuadrant", client.ObjectKeyFromObject(&kuadrantList.Items[idx]))
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&kuadrantList.Items[idx])})
+	}
+
+	return requests
+}
+
+// authorinoBecameReady filters the updates of the Authorino instances to the transitions to Ready,
+// after which the status of the AuthConfigs, and so of the AuthPolicies, may have changed
+var authorinoBecameReady = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldAuthorino, ok := e.ObjectOld.(*authorinov1beta1.Authorino)
+		if !ok {
+			return false
+		}
+		newAuthorino, ok := e.ObjectNew.(*authorinov1beta1.Authorino)
+		if !ok {
+			return false
+		}
+		return !common.IsAuthorinoReady(oldAuthorino) && common.IsAuthorinoReady(newAuthorino)
+	},
+}
+
+// authPolicyAuthorinoChanged filters the updates of the AuthPolicies to the changes of the Authorino instance their
+// AuthConfig is moved to, reported in their status, which tells whether the policies are out of the scope of Authorino
+var authPolicyAuthorinoChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldAP, ok := e.ObjectOld.(*kuadrantv1beta1.AuthPolicy)
+		if !ok {
+			return false
+		}
+		newAP, ok := e.ObjectNew.(*kuadrantv1beta1.AuthPolicy)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldAP.Status.Authorino, newAP.Status.Authorino)
+	},
+}
+
+// rateLimitPolicyLimitsNamespacesChanged filters the updates of the RateLimitPolicies to the changes of the namespaces
+// of their limits in Limitador, reported in their status
+var rateLimitPolicyLimitsNamespacesChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRLP, ok := e.ObjectOld.(*kuadrantv1beta2.RateLimitPolicy)
+		if !ok {
+			return false
+		}
+		newRLP, ok := e.ObjectNew.(*kuadrantv1beta2.RateLimitPolicy)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldRLP.Status.LimitsNamespaces, newRLP.Status.LimitsNamespaces)
+	},
+}
@@ -0,0 +1,49 @@
+//go:build unit
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestMapNetworkPolicyToKuadrant(t *testing.T) {
+	kuadrantA := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-a"}}
+	kuadrantB := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-b"}}
+	gw := testGateway("gw")
+	gw.Annotations = map[string]string{common.KuadrantNamespaceLabel: kuadrantA.Namespace}
+	mapper := &KuadrantEventMapper{Logger: logr.Discard(), Client: unitTestTargetRefReconciler(kuadrantA, kuadrantB, gw).Client()}
+
+	networkPolicy := func(namespace string, labels map[string]string) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "np", Namespace: namespace, Labels: labels}}
+	}
+	requestFor := func(kObj *kuadrantv1beta1.Kuadrant) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(kObj)}}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		np       *networkingv1.NetworkPolicy
+		expected []reconcile.Request
+	}{
+		{"namespace of a gateway", networkPolicy(gw.Namespace, nil), requestFor(kuadrantA)},
+		{"namespace of the kuadrant instance", networkPolicy(kuadrantB.Namespace, nil), requestFor(kuadrantB)},
+		{"unrelated namespace", networkPolicy("other", nil), []reconcile.Request{}},
+		{"managed", networkPolicy(gw.Namespace, networkPolicyLabels(kuadrantB)), requestFor(kuadrantB)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if requests := mapper.MapNetworkPolicyToKuadrant(tc.np); !reflect.DeepEqual(requests, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const UnprotectedGatewaysConditionType string = "UnprotectedGateways"
+
+// gatewaysWithoutPolicies returns the gateways managed by a kuadrant instance that no AuthPolicy nor RateLimitPolicy
+// applies to, neither targeting the gateway nor its routes
+func (r *KuadrantReconciler) gatewaysWithoutPolicies(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]client.ObjectKey, error) {
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := r.Client().List(ctx, gwList); err != nil {
+		return nil, err
+	}
+
+	unprotected := make([]client.ObjectKey, 0)
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		authPolicies := common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantAuthPolicyRefsConfig{}}.PolicyRefs()
+		rateLimitPolicies := common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}.PolicyRefs()
+		if len(authPolicies) == 0 && len(rateLimitPolicies) == 0 {
+			unprotected = append(unprotected, client.ObjectKeyFromObject(gw))
+		}
+	}
+	return unprotected, nil
+}
+
+// unprotectedGatewaysCondition returns a warning condition listing the gateways of a kuadrant instance without any
+// policy, or nil, and records them in the kuadrant_gateway_unprotected metric
+func (r *KuadrantReconciler) unprotectedGatewaysCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*metav1.Condition, error) {
+	unprotected, err := r.gatewaysWithoutPolicies(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+
+	unprotectedGateways.DeletePartialMatch(prometheus.Labels{"kuadrant_namespace": kObj.Namespace})
+	for _, gwKey := range unprotected {
+		unprotectedGateways.WithLabelValues(gwKey.Namespace, gwKey.Name, kObj.Namespace).Set(1)
+	}
+
+	if len(unprotected) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    UnprotectedGatewaysConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "NoPoliciesAttached",
+		Message: fmt.Sprintf("Gateways without any AuthPolicy or RateLimitPolicy: %s", strings.Join(common.Map(unprotected, client.ObjectKey.String), ", ")),
+	}, nil
+}
@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const (
+	// grafanaDashboardName is the name of the ConfigMap of the dashboard
+	grafanaDashboardName = "kuadrant-grafana-dashboard"
+	// grafanaDashboardKey is the key of the dashboard JSON in the ConfigMap, loaded as a file by the sidecar
+	grafanaDashboardKey = "kuadrant.json"
+)
+
+// dashboardPanel is a time series panel of the dashboard
+type dashboardPanel struct {
+	title       string
+	description string
+	expr        string
+	legend      string
+	unit        string
+}
+
+// reconcileGrafanaDashboard reconciles the ConfigMap of the Grafana dashboard of the kuadrant instance, with panels
+// for the AuthPolicies and the RateLimitPolicies in use
+func (r *KuadrantReconciler) reconcileGrafanaDashboard(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      grafanaDashboardName,
+			Namespace: kObj.Namespace,
+		},
+	}
+
+	if kObj.Spec.GrafanaDashboard == nil {
+		common.TagObjectToDelete(configMap)
+		return r.ReconcileResource(ctx, &corev1.ConfigMap{}, configMap, grafanaDashboardMutator)
+	}
+
+	panels, err := r.grafanaDashboardPanels(ctx, kObj)
+	if err != nil {
+		return err
+	}
+	dashboard, err := json.MarshalIndent(grafanaDashboard(panels), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	labels := common.ManagedResourceLabels(kObj.Name, "grafana-dashboard")
+	for key, value := range kObj.Spec.GrafanaDashboard.DashboardLabels() {
+		labels[key] = value
+	}
+	configMap.Labels = labels
+	configMap.Data = map[string]string{grafanaDashboardKey: string(dashboard)}
+
+	if err := r.setManagedOwnerReference(kObj, configMap); err != nil {
+		return err
+	}
+
+	return r.ReconcileResource(ctx, &corev1.ConfigMap{}, configMap, grafanaDashboardMutator)
+}
+
+// grafanaDashboardPanels returns the panels of the components, followed by a panel per AuthPolicy and per
+// RateLimitPolicy of the kuadrant instance, sorted by policy
+func (r *KuadrantReconciler) grafanaDashboardPanels(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]dashboardPanel, error) {
+	panels := []dashboardPanel{
+		{
+			title:       "Unprotected gateways",
+			description: "Gateways managed by Kuadrant without any AuthPolicy nor RateLimitPolicy",
+			expr:        fmt.Sprintf(`kuadrant_gateway_unprotected{kuadrant_namespace=%q} == 1`, kObj.Namespace),
+			legend:      "{{namespace}}/{{name}}",
+		},
+		{
+			title:       "AuthConfig ready latency (p95)",
+			description: "Time for the AuthConfigs of the AuthPolicies to be ready in Authorino after a change",
+			expr:        "histogram_quantile(0.95, sum(rate(kuadrant_authpolicy_authconfig_ready_latency_seconds_bucket[5m])) by (le))",
+			legend:      "p95",
+			unit:        "s",
+		},
+		{
+			title:       "Authorino responses",
+			description: "Responses of Authorino to the ext_authz requests of the gateways, by status",
+			expr:        "sum(rate(auth_server_response_status[5m])) by (status)",
+			legend:      "{{status}}",
+			unit:        "reqps",
+		},
+		{
+			title:       "Limitador decisions",
+			description: "Requests checked by Limitador, authorized and limited",
+			expr:        limitadorDecisionsExpr(""),
+			legend:      "{{decision}}",
+			unit:        "reqps",
+		},
+	}
+
+	authConfigList := &authorinoapi.AuthConfigList{}
+	if err := r.Client().List(ctx, authConfigList); err != nil {
+		return nil, err
+	}
+	authConfigs := make(map[client.ObjectKey][]client.ObjectKey)
+	for idx := range authConfigList.Items {
+		if policyKey, ok := authConfigPolicyKey(&authConfigList.Items[idx]); ok {
+			authConfigs[policyKey] = append(authConfigs[policyKey], client.ObjectKeyFromObject(&authConfigList.Items[idx]))
+		}
+	}
+
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := r.Client().List(ctx, apList); err != nil {
+		return nil, err
+	}
+	sort.Slice(apList.Items, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&apList.Items[i]).String() < client.ObjectKeyFromObject(&apList.Items[j]).String()
+	})
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		keys := authConfigs[client.ObjectKeyFromObject(ap)]
+		if namespace, ok := common.GetKuadrantNamespaceFromPolicy(ap); !ok || namespace != kObj.Namespace || len(keys) == 0 {
+			continue
+		}
+		panels = append(panels, dashboardPanel{
+			title:       fmt.Sprintf("AuthPolicy %s → %s %s", client.ObjectKeyFromObject(ap), ap.GetTargetRef().Kind, ap.GetTargetRef().Name),
+			description: "Responses of the AuthConfigs of the policy, by status. Requires the AuthConfig-level metrics of Authorino",
+			expr:        fmt.Sprintf("sum(rate(auth_server_authconfig_response_status{%s}[5m])) by (status)", authConfigsMatcher(keys)),
+			legend:      "{{status}}",
+			unit:        "reqps",
+		})
+	}
+
+	rlpList := &kuadrantv1beta2.RateLimitPolicyList{}
+	if err := r.Client().List(ctx, rlpList); err != nil {
+		return nil, err
+	}
+	sort.Slice(rlpList.Items, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&rlpList.Items[i]).String() < client.ObjectKeyFromObject(&rlpList.Items[j]).String()
+	})
+	for idx := range rlpList.Items {
+		rlp := &rlpList.Items[idx]
+		if namespace, ok := common.GetKuadrantNamespaceFromPolicy(rlp); !ok || namespace != kObj.Namespace || len(rlp.Status.LimitsNamespaces) == 0 {
+			continue
+		}
+		panels = append(panels, dashboardPanel{
+			title:       fmt.Sprintf("RateLimitPolicy %s → %s %s", client.ObjectKeyFromObject(rlp), rlp.GetTargetRef().Kind, rlp.GetTargetRef().Name),
+			description: "Requests to the limits of the policy, authorized and limited",
+			expr:        limitadorDecisionsExpr(fmt.Sprintf("limitador_namespace=~%q", promRegexAlternation(rlp.Status.LimitsNamespaces))),
+			legend:      "{{decision}}",
+			unit:        "reqps",
+		})
+	}
+
+	return panels, nil
+}
+
+// limitadorDecisionsExpr returns the rates of the requests authorized and limited by Limitador, labeled by decision
+func limitadorDecisionsExpr(matcher string) string {
+	return fmt.Sprintf(`label_replace(sum(rate(authorized_calls{%s}[5m])), "decision", "authorized", "", "") or label_replace(sum(rate(limited_calls{%s}[5m])), "decision", "limited", "", "")`, matcher, matcher)
+}
+
+// authConfigsMatcher returns the label matchers of the Authorino metrics of a set of AuthConfigs
+func authConfigsMatcher(keys []client.ObjectKey) string {
+	namespaces := make([]string, 0)
+	names := make([]string, 0)
+	for _, key := range keys {
+		if !common.Contains(namespaces, key.Namespace) {
+			namespaces = append(namespaces, key.Namespace)
+		}
+		names = append(names, key.Name)
+	}
+	return fmt.Sprintf("namespace=~%q,authconfig=~%q", promRegexAlternation(namespaces), promRegexAlternation(names))
+}
+
+// promRegexAlternation returns a regular expression matching any of the sorted values literally
+func promRegexAlternation(values []string) string {
+	quoted := common.Map(values, regexp.QuoteMeta)
+	sort.Strings(quoted)
+	return strings.Join(quoted, "|")
+}
+
+// grafanaDashboard returns the model of the dashboard, with two panels per row. The datasource is a
+// variable of the dashboard, set to the default Prometheus datasource of Grafana.
+func grafanaDashboard(panels []dashboardPanel) map[string]interface{} {
+	models := make([]interface{}, 0, len(panels))
+	for idx, panel := range panels {
+		fieldConfig := map[string]interface{}{}
+		if panel.unit != "" {
+			fieldConfig["unit"] = panel.unit
+		}
+		models = append(models, map[string]interface{}{
+			"id":          idx + 1,
+			"type":        "timeseries",
+			"title":       panel.title,
+			"description": panel.description,
+			"datasource":  map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+			"gridPos":     map[string]interface{}{"h": 8, "w": 12, "x": (idx % 2) * 12, "y": (idx / 2) * 8},
+			"fieldConfig": map[string]interface{}{"defaults": fieldConfig, "overrides": []interface{}{}},
+			"targets": []interface{}{
+				map[string]interface{}{
+					"refId":        "A",
+					"expr":         panel.expr,
+					"legendFormat": panel.legend,
+					"datasource":   map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+				},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"uid":           "kuadrant",
+		"title":         "Kuadrant",
+		"tags":          []interface{}{"kuadrant"},
+		"editable":      false,
+		"schemaVersion": 36,
+		"refresh":       "30s",
+		"time":          map[string]interface{}{"from": "now-1h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"name": "datasource", "label": "Datasource", "type": "datasource", "query": "prometheus"},
+			},
+		},
+		"panels": models,
+	}
+}
+
+// grafanaDashboardMutator reconciles the dashboard and the labels of the ConfigMap. The labels added by the users
+// are preserved.
+func grafanaDashboardMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*corev1.ConfigMap)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.ConfigMap", existingObj)
+	}
+	desired, ok := desiredObj.(*corev1.ConfigMap)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.ConfigMap", desiredObj)
+	}
+
+	update := false
+
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Data, desired.Data) {
+		existing.Data = desired.Data
+		update = true
+	}
+
+	// the kuadrant instance may have been recreated
+	if common.UpdateStaleOwnerReferences(existing, desired) {
+		update = true
+	}
+
+	return update, nil
+}
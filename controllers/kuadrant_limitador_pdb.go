@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// limitadorPodDisruptionBudgetName is the name of the PodDisruptionBudget of the pods of Limitador
+const limitadorPodDisruptionBudgetName = "kuadrant-limitador"
+
+// reconcileLimitadorPodDisruptionBudget reconciles the PodDisruptionBudget of the pods of Limitador, which the
+// limitador operator does not manage
+func (r *KuadrantReconciler) reconcileLimitadorPodDisruptionBudget(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	spec := kObj.LimitadorPodDisruptionBudget()
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	limitador := &limitadorv1alpha1.Limitador{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: kObj.Namespace}, limitador); err != nil {
+		// reconciled again once limitador is created
+		return client.IgnoreNotFound(err)
+	}
+
+	pdb := desiredLimitadorPodDisruptionBudget(kObj, spec, limitador)
+	if err := r.setManagedOwnerReference(kObj, pdb); err != nil {
+		return err
+	}
+
+	return r.ReconcileResource(ctx, &policyv1.PodDisruptionBudget{}, pdb, podDisruptionBudgetMutator)
+}
+
+// desiredLimitadorPodDisruptionBudget returns the PodDisruptionBudget of the pods of Limitador. Without settings,
+// the budget allows one pod unavailable at a time while Limitador runs more than one replica, and is tagged to be
+// deleted otherwise, as it would block the drain of the node of the only pod.
+func desiredLimitadorPodDisruptionBudget(kObj *kuadrantv1beta1.Kuadrant, spec *kuadrantv1beta1.PodDisruptionBudgetSpec, limitador *limitadorv1alpha1.Limitador) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: "policy/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      limitadorPodDisruptionBudgetName,
+			Namespace: kObj.Namespace,
+			Labels:    common.ManagedResourceLabels(kObj.Name, "limitador"),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			// the pods created by the limitador operator are labeled app=limitador, one limitador per kuadrant namespace
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "limitador"}},
+		},
+	}
+
+	switch {
+	case spec != nil && (spec.MinAvailable != nil || spec.MaxUnavailable != nil):
+		pdb.Spec.MinAvailable = spec.MinAvailable
+		pdb.Spec.MaxUnavailable = spec.MaxUnavailable
+	case limitador.Spec.Replicas != nil && *limitador.Spec.Replicas > 1:
+		maxUnavailable := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	default:
+		common.TagObjectToDelete(pdb)
+	}
+
+	return pdb
+}
+
+// podDisruptionBudgetMutator reconciles the budget and the selector of a PodDisruptionBudget.
+// The labels added by the users are preserved.
+func podDisruptionBudgetMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*policyv1.PodDisruptionBudget)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *policyv1.PodDisruptionBudget", existingObj)
+	}
+	desired, ok := desiredObj.(*policyv1.PodDisruptionBudget)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *policyv1.PodDisruptionBudget", desiredObj)
+	}
+
+	update := false
+
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.MinAvailable, desired.Spec.MinAvailable) {
+		existing.Spec.MinAvailable = desired.Spec.MinAvailable
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.MaxUnavailable, desired.Spec.MaxUnavailable) {
+		existing.Spec.MaxUnavailable = desired.Spec.MaxUnavailable
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
+		existing.Spec.Selector = desired.Spec.Selector
+		update = true
+	}
+
+	// the kuadrant instance may have been recreated
+	if common.UpdateStaleOwnerReferences(existing, desired) {
+		update = true
+	}
+
+	return update, nil
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"github.com/kuadrant/limitador-operator/pkg/limitador"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const (
+	LimitadorConfigAppliedConditionType string = "LimitadorConfigApplied"
+)
+
+// The Limitador operator only updates the replicas and the image of the deployment of Limitador once created,
+// so the changes of the storage of a Limitador instance are not effective until the deployment is rolled out
+// with the command rendered for the new storage. The deployment is owned by the Limitador operator, so it is not
+// updated by the kuadrant operator: the deployment is deleted, orphaning its pods, for the Limitador operator to
+// create it again with the command of the current config. The new deployment adopts the pods of the one deleted,
+// matching its selector, and rolls them out.
+
+// limitadorRollout holds the command of the Limitador container rendered by the Limitador operator for the current
+// config of the Limitador instance, along with the deployment running it
+type limitadorRollout struct {
+	deployment *appsv1.Deployment
+	command    []string
+}
+
+// required tells whether the deployment runs a command other than the one of the current config
+func (l *limitadorRollout) required() bool {
+	container := limitadorContainer(l.deployment)
+	return container != nil && !reflect.DeepEqual(container.Command, l.command)
+}
+
+// inProgress tells whether the pods of the deployment are not all updated yet
+func (l *limitadorRollout) inProgress() bool {
+	status := l.deployment.Status
+	replicas := int32(1)
+	if l.deployment.Spec.Replicas != nil {
+		replicas = *l.deployment.Spec.Replicas
+	}
+	return status.ObservedGeneration < l.deployment.Generation || status.UpdatedReplicas < replicas || status.Replicas > status.UpdatedReplicas
+}
+
+func limitadorContainer(deployment *appsv1.Deployment) *corev1.Container {
+	for idx := range deployment.Spec.Template.Spec.Containers {
+		if container := &deployment.Spec.Template.Spec.Containers[idx]; container.Name == "limitador" {
+			return container
+		}
+	}
+	return nil
+}
+
+// limitadorRollout returns the rollout of the Limitador instance of the kuadrant instance,
+// or nil if the command of the current config cannot be rendered, e.g. the Limitador operator fails to read the storage
+func (r *KuadrantReconciler) limitadorRollout(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*limitadorRollout, error) {
+	limitadorObj := &limitadorv1alpha1.Limitador{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: kObj.Namespace}, limitadorObj); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Client().Get(ctx, client.ObjectKeyFromObject(limitadorObj), deployment); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	var storageConfigSecret *corev1.Secret
+	if storage := limitadorObj.Spec.Storage; storage != nil {
+		// the options of the cached storage are mandatory to render the command
+		if !storage.Validate() || storage.RedisCached != nil && storage.RedisCached.Options == nil {
+			return nil, nil
+		}
+		secretRef := storage.SecretRef()
+		secretKey := client.ObjectKey{Name: secretRef.Name, Namespace: secretRef.Namespace}
+		if secretKey.Namespace == "" {
+			secretKey.Namespace = limitadorObj.Namespace
+		}
+		storageConfigSecret = &corev1.Secret{}
+		// read directly from the API server, the secrets are not cached
+		if err := r.APIClientReader().Get(ctx, secretKey, storageConfigSecret); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		if storageConfigSecret.Data["URL"] == nil {
+			return nil, nil
+		}
+	}
+
+	desired := limitador.LimitadorDeployment(limitadorObj, storageConfigSecret)
+	container := limitadorContainer(desired)
+	if container == nil {
+		return nil, nil
+	}
+
+	return &limitadorRollout{deployment: deployment, command: container.Command}, nil
+}
+
+// reconcileLimitadorRollout has the deployment of Limitador recreated by the Limitador operator with the command of
+// the current config of the Limitador instance, if enabled
+func (r *KuadrantReconciler) reconcileLimitadorRollout(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	if !r.LimitadorRolloutOnConfigChange {
+		// reported in the status
+		return nil
+	}
+
+	rollout, err := r.limitadorRollout(ctx, kObj)
+	if err != nil || rollout == nil || !rollout.required() {
+		return err
+	}
+
+	logger, _ := logr.FromContext(ctx)
+	logger.Info("recreating the deployment of limitador with the current storage config", "deployment", client.ObjectKeyFromObject(rollout.deployment))
+
+	// only the deployment read, not the one possibly recreated in the meantime
+	uid := rollout.deployment.GetUID()
+	resourceVersion := rollout.deployment.GetResourceVersion()
+	err = r.DeleteResource(ctx, rollout.deployment,
+		client.PropagationPolicy(metav1.DeletePropagationOrphan),
+		client.Preconditions{UID: &uid, ResourceVersion: &resourceVersion},
+	)
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		// already recreated, reconciled again by the watch of the deployment
+		return nil
+	}
+	return err
+}
+
+// limitadorConfigAppliedCondition reflects whether the config of the Limitador instance is effective.
+// The condition is only reported once a rollout is required, until the config is applied.
+func (r *KuadrantReconciler) limitadorConfigAppliedCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant, conditions []metav1.Condition) (*metav1.Condition, error) {
+	rollout, err := r.limitadorRollout(ctx, kObj)
+	if err != nil || rollout == nil {
+		return nil, err
+	}
+
+	if !rollout.required() && meta.FindStatusCondition(conditions, LimitadorConfigAppliedConditionType) == nil {
+		// no rollout was required
+		return nil, nil
+	}
+
+	cond := &metav1.Condition{
+		Type:    LimitadorConfigAppliedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ConfigApplied",
+		Message: "Limitador runs with the current config",
+	}
+
+	switch {
+	case rollout.required() && r.LimitadorRolloutOnConfigChange:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "RolloutPending"
+		cond.Message = "the deployment of Limitador is pending to be rolled out with the current storage config"
+	case rollout.required():
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "RolloutRequired"
+		cond.Message = fmt.Sprintf("the deployment of Limitador runs with an outdated storage config; recreate the deployment %s or enable the rollout of the config changes in the operator", rollout.deployment.Name)
+	case rollout.inProgress():
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "RolloutInProgress"
+		cond.Message = "the deployment of Limitador is being rolled out"
+	}
+
+	return cond, nil
+}
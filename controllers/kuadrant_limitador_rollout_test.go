@@ -0,0 +1,85 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"github.com/kuadrant/limitador-operator/pkg/limitador"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestReconcileLimitadorRollout(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"}}
+	limitadorObj := &limitadorv1alpha1.Limitador{ObjectMeta: metav1.ObjectMeta{Name: common.LimitadorName, Namespace: kObj.Namespace}}
+	ctx := context.TODO()
+
+	currentDeployment := func() *appsv1.Deployment {
+		return limitador.LimitadorDeployment(limitadorObj, nil)
+	}
+	outdatedDeployment := func() *appsv1.Deployment {
+		deployment := currentDeployment()
+		limitadorContainer(deployment).Command = []string{"limitador-server", "/home/limitador/etc/limitador-config.yaml", "redis", "redis://old:6379"}
+		return deployment
+	}
+
+	testCases := []struct {
+		name       string
+		enabled    bool
+		deployment *appsv1.Deployment
+		deleted    bool
+		reason     string
+	}{
+		{name: "current config", enabled: true, deployment: currentDeployment(), deleted: false},
+		{name: "outdated config", enabled: true, deployment: outdatedDeployment(), deleted: true, reason: "RolloutPending"},
+		{name: "outdated config, rollout disabled", enabled: false, deployment: outdatedDeployment(), deleted: false, reason: "RolloutRequired"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			r := &KuadrantReconciler{
+				BaseReconciler:                 unitTestTargetRefReconciler(kObj, limitadorObj.DeepCopy(), tc.deployment).BaseReconciler,
+				LimitadorRolloutOnConfigChange: tc.enabled,
+			}
+
+			cond, err := r.limitadorConfigAppliedCondition(ctx, kObj, nil)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if tc.reason == "" && cond != nil {
+				subT.Errorf("expected no condition, got %+v", cond)
+			}
+			if tc.reason != "" && (cond == nil || cond.Reason != tc.reason) {
+				subT.Errorf("expected the condition with reason %s, got %+v", tc.reason, cond)
+			}
+
+			if err := r.reconcileLimitadorRollout(ctx, kObj); err != nil {
+				subT.Fatal(err)
+			}
+
+			deployment := &appsv1.Deployment{}
+			err = r.Client().Get(ctx, client.ObjectKeyFromObject(tc.deployment), deployment)
+			if tc.deleted && !apierrors.IsNotFound(err) {
+				subT.Errorf("expected the deployment to be deleted for the limitador operator to recreate it, got %v", err)
+			}
+			if !tc.deleted {
+				if err != nil {
+					subT.Fatal(err)
+				}
+				// never updated by the kuadrant operator
+				if command := limitadorContainer(deployment).Command; len(command) != len(limitadorContainer(tc.deployment).Command) {
+					subT.Errorf("expected the deployment not to be changed, got the command %v", command)
+				}
+			}
+		})
+	}
+
+}
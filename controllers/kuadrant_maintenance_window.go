@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// maintenanceDeferrals holds, per kuadrant instance, the disruptive changes deferred by the last reconciliation
+var maintenanceDeferrals = &deferredChangesTracker{changes: make(map[client.ObjectKey][]kuadrantv1beta1.DeferredChange)}
+
+type deferredChangesTracker struct {
+	mu      sync.Mutex
+	changes map[client.ObjectKey][]kuadrantv1beta1.DeferredChange
+}
+
+// Reset forgets the changes deferred for a kuadrant instance, before reconciling it again
+func (t *deferredChangesTracker) Reset(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.changes, key)
+}
+
+func (t *deferredChangesTracker) Add(key client.ObjectKey, change kuadrantv1beta1.DeferredChange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.changes[key] = append(t.changes[key], change)
+}
+
+func (t *deferredChangesTracker) Get(key client.ObjectKey) []kuadrantv1beta1.DeferredChange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]kuadrantv1beta1.DeferredChange(nil), t.changes[key]...)
+}
+
+// deferChangeFn reverts the disruptive changes made to an object, returning their description, or empty if none
+type deferChangeFn func(before, after client.Object) string
+
+// withMaintenanceWindow returns a mutator deferring the disruptive changes made by the given mutator outside the
+// maintenance window of the kuadrant instance. The other changes are applied immediately.
+func withMaintenanceWindow(kObj *kuadrantv1beta1.Kuadrant, component string, mutator reconcilers.MutateFn, deferChange deferChangeFn) reconcilers.MutateFn {
+	window := kObj.Spec.MaintenanceWindow
+	if window == nil {
+		return mutator
+	}
+
+	return func(existingObj, desiredObj client.Object) (bool, error) {
+		before, ok := existingObj.DeepCopyObject().(client.Object)
+		if !ok {
+			return mutator(existingObj, desiredObj)
+		}
+
+		update, err := mutator(existingObj, desiredObj)
+		if err != nil || !update || window.Contains(time.Now()) {
+			return update, err
+		}
+
+		description := deferChange(before, existingObj)
+		if description == "" {
+			return update, nil
+		}
+		maintenanceDeferrals.Add(client.ObjectKeyFromObject(kObj), kuadrantv1beta1.DeferredChange{Component: component, Description: description})
+
+		return !equality.Semantic.DeepEqual(before, existingObj), nil
+	}
+}
+
+// deferAuthorinoSpecChange defers the changes of the spec of Authorino, rolling out new pods of Authorino
+func deferAuthorinoSpecChange(before, after client.Object) string {
+	existing, ok := after.(*authorinov1beta1.Authorino)
+	if !ok {
+		return ""
+	}
+	original, ok := before.(*authorinov1beta1.Authorino)
+	if !ok || reflect.DeepEqual(original.Spec, existing.Spec) {
+		return ""
+	}
+	existing.Spec = original.Spec
+	return "update of the spec of the Authorino instance, restarting Authorino"
+}
+
+// deferLimitadorStorageChange defers the switches of the storage of Limitador
+func deferLimitadorStorageChange(before, after client.Object) string {
+	existing, ok := after.(*limitadorv1alpha1.Limitador)
+	if !ok {
+		return ""
+	}
+	original, ok := before.(*limitadorv1alpha1.Limitador)
+	if !ok || reflect.DeepEqual(original.Spec.Storage, existing.Spec.Storage) {
+		return ""
+	}
+	existing.Spec.Storage = original.Spec.Storage
+	return "switch of the storage of Limitador"
+}
+
+// maintenanceWindowStatus returns the changes deferred for a kuadrant instance and the start of the window they
+// are applied in, or nil if none
+func maintenanceWindowStatus(kObj *kuadrantv1beta1.Kuadrant) ([]kuadrantv1beta1.DeferredChange, *time.Time) {
+	changes := maintenanceDeferrals.Get(client.ObjectKeyFromObject(kObj))
+	if len(changes) == 0 || kObj.Spec.MaintenanceWindow == nil {
+		return nil, nil
+	}
+	next := kObj.Spec.MaintenanceWindow.Next(time.Now())
+	if next.IsZero() {
+		return changes, nil
+	}
+	return changes, &next
+}
+
+// maintenanceWindowRequeue returns the delay before the next maintenance window if changes are deferred, or 0
+func maintenanceWindowRequeue(kObj *kuadrantv1beta1.Kuadrant) time.Duration {
+	if _, next := maintenanceWindowStatus(kObj); next != nil {
+		return time.Until(*next)
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// The ServiceMonitors are handled as unstructured objects, the Prometheus Operator is an optional dependency
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// reconcileAuthorinoMetrics annotates the metrics Service of Authorino and reconciles its ServiceMonitor
+func (r *KuadrantReconciler) reconcileAuthorinoMetrics(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	metrics := kObj.AuthorinoMetrics()
+
+	var serviceMonitorSpec *kuadrantv1beta1.ServiceMonitorSpec
+	if metrics != nil {
+		serviceMonitorSpec = metrics.ServiceMonitor
+	}
+
+	// the metrics service is created by the authorino operator, which only reconciles its selector and ports
+	service := &corev1.Service{}
+	serviceKey := client.ObjectKey{Name: "authorino-controller-metrics", Namespace: kObj.Namespace}
+	if err := r.Client().Get(ctx, serviceKey, service); err != nil {
+		// reconciled again once authorino is deployed
+		return client.IgnoreNotFound(err)
+	}
+
+	update := false
+	if metrics != nil && common.MergeMapStringString(&service.Annotations, metrics.ServiceAnnotations) {
+		update = true
+	}
+	// selected by the ServiceMonitor, the services of authorino share the same labels
+	if serviceMonitorSpec != nil && common.MergeMapStringString(&service.Labels, map[string]string{common.MetricsServiceLabel: "authorino"}) {
+		update = true
+	}
+	if update {
+		if err := r.UpdateResource(ctx, service); err != nil {
+			return err
+		}
+	}
+
+	endpoints := []interface{}{
+		map[string]interface{}{"port": "http", "path": "/metrics"},
+		map[string]interface{}{"port": "http", "path": "/server-metrics"},
+	}
+	selector := map[string]string{common.MetricsServiceLabel: "authorino"}
+	serviceMonitor, err := r.desiredServiceMonitor(kObj, "authorino", selector, endpoints, serviceMonitorSpec)
+	if err != nil {
+		return err
+	}
+	return r.reconcileServiceMonitor(ctx, serviceMonitor)
+}
+
+// reconcileLimitadorMetrics reconciles the ServiceMonitor of Limitador
+func (r *KuadrantReconciler) reconcileLimitadorMetrics(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	// the service created by the limitador operator is labeled app=limitador, one limitador per kuadrant namespace
+	selector := map[string]string{"app": "limitador"}
+	endpoints := []interface{}{
+		map[string]interface{}{"port": "http", "path": "/metrics"},
+	}
+	serviceMonitor, err := r.desiredServiceMonitor(kObj, "limitador", selector, endpoints, kObj.LimitadorServiceMonitor())
+	if err != nil {
+		return err
+	}
+	return r.reconcileServiceMonitor(ctx, serviceMonitor)
+}
+
+// desiredServiceMonitor returns the ServiceMonitor of the metrics Service of a component of the kuadrant instance,
+// matching the Service by the selector labels. A nil spec tags the ServiceMonitor to be deleted.
+func (r *KuadrantReconciler) desiredServiceMonitor(kObj *kuadrantv1beta1.Kuadrant, component string, selector map[string]string, endpoints []interface{}, spec *kuadrantv1beta1.ServiceMonitorSpec) (*unstructured.Unstructured, error) {
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	serviceMonitor.SetName(fmt.Sprintf("kuadrant-%s", component))
+	serviceMonitor.SetNamespace(kObj.Namespace)
+
+	if spec == nil {
+		common.TagObjectToDelete(serviceMonitor)
+		return serviceMonitor, nil
+	}
+
+	labels := common.ManagedResourceLabels(kObj.Name, component)
+	for key, value := range spec.Labels {
+		labels[key] = value
+	}
+	serviceMonitor.SetLabels(labels)
+
+	if spec.Interval != "" {
+		for _, endpoint := range endpoints {
+			endpoint.(map[string]interface{})["interval"] = spec.Interval
+		}
+	}
+
+	matchLabels := make(map[string]interface{}, len(selector))
+	for key, value := range selector {
+		matchLabels[key] = value
+	}
+
+	serviceMonitor.Object["spec"] = map[string]interface{}{
+		"endpoints": endpoints,
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{kObj.Namespace},
+		},
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+		},
+	}
+
+	if err := r.setManagedOwnerReference(kObj, serviceMonitor); err != nil {
+		return nil, err
+	}
+
+	return serviceMonitor, nil
+}
+
+// reconcileServiceMonitor creates, updates or deletes a ServiceMonitor, if the ServiceMonitor CRD is installed
+func (r *KuadrantReconciler) reconcileServiceMonitor(ctx context.Context, desired *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(serviceMonitorGVK)
+
+	err := r.ReconcileResource(ctx, existing, desired, serviceMonitorMutator)
+	if apimeta.IsNoMatchError(err) {
+		if !common.IsObjectTaggedToDelete(desired) {
+			logger, _ := logr.FromContext(ctx)
+			logger.Info("ServiceMonitor CRD not installed, skipping", "servicemonitor", client.ObjectKeyFromObject(desired))
+		}
+		return nil
+	}
+	return err
+}
+
+// serviceMonitorMutator reconciles the spec of a ServiceMonitor. The labels added by the users are preserved.
+func serviceMonitorMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*unstructured.Unstructured)
+	if !ok {
+		return false, fmt.Errorf("%T is not an *unstructured.Unstructured", existingObj)
+	}
+	desired, ok := desiredObj.(*unstructured.Unstructured)
+	if !ok {
+		return false, fmt.Errorf("%T is not an *unstructured.Unstructured", desiredObj)
+	}
+
+	update := false
+
+	labels := existing.GetLabels()
+	if common.MergeMapStringString(&labels, desired.GetLabels()) {
+		existing.SetLabels(labels)
+		update = true
+	}
+
+	if !equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		existing.Object["spec"] = desired.Object["spec"]
+		update = true
+	}
+
+	// the kuadrant instance may have been recreated
+	if common.UpdateStaleOwnerReferences(existing, desired) {
+		update = true
+	}
+
+	return update, nil
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// networkPolicyComponent is the component of the NetworkPolicies of the traffic of the gateways to Authorino and
+// Limitador, in their labels
+const networkPolicyComponent = "network-policy"
+
+// namespaceNameLabel is the label set by Kubernetes to the name of every namespace
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// networkPolicyLabels returns the labels of the NetworkPolicies of a kuadrant instance. The NetworkPolicies of the
+// gateways live in the namespaces of the gateways, so the labels tell the namespace of the kuadrant instance.
+func networkPolicyLabels(kObj *kuadrantv1beta1.Kuadrant) map[string]string {
+	labels := common.ManagedResourceLabels(kObj.Name, networkPolicyComponent)
+	labels[common.KuadrantNamespaceLabel] = kObj.Namespace
+	return labels
+}
+
+// reconcileNetworkPolicies reconciles the NetworkPolicies letting the pods of the gateways managed by the kuadrant
+// instance reach the services of Authorino and Limitador, on the target ports of the services. The NetworkPolicies
+// no longer needed, e.g. of the gateways deleted, or all of them when disabled, are deleted.
+func (r *KuadrantReconciler) reconcileNetworkPolicies(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	desired := make([]*networkingv1.NetworkPolicy, 0)
+	if kObj.NetworkPoliciesEnabled() {
+		var err error
+		if desired, err = r.desiredNetworkPolicies(ctx, kObj); err != nil {
+			return err
+		}
+	}
+
+	if err := r.deleteNetworkPolicies(ctx, kObj, desired); err != nil {
+		return err
+	}
+
+	for _, policy := range desired {
+		if err := r.ReconcileResource(ctx, &networkingv1.NetworkPolicy{}, policy, networkPolicyMutator); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// desiredNetworkPolicies returns a NetworkPolicy allowing the egress of the pods of each gateway managed by the
+// kuadrant instance to the services of Authorino and Limitador, and a NetworkPolicy allowing the ingress from the
+// pods of the gateways for each service
+func (r *KuadrantReconciler) desiredNetworkPolicies(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]*networkingv1.NetworkPolicy, error) {
+	services, err := r.dataPlaneServices(ctx, kObj)
+	if err != nil || len(services) == 0 {
+		return nil, err
+	}
+
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := r.Client().List(ctx, gwList); err != nil {
+		return nil, err
+	}
+	sort.Slice(gwList.Items, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&gwList.Items[i]).String() < client.ObjectKeyFromObject(&gwList.Items[j]).String()
+	})
+
+	policies := make([]*networkingv1.NetworkPolicy, 0)
+	gatewayPeers := make([]networkingv1.NetworkPolicyPeer, 0)
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		// an empty selector would open the traffic of all the pods of the namespace
+		podLabels := common.IstioWorkloadSelectorFromGateway(ctx, r.Client(), gw).MatchLabels
+		if len(podLabels) == 0 {
+			continue
+		}
+		gatewayPeer := networkPolicyPeer(gw.Namespace, podLabels)
+		gatewayPeers = append(gatewayPeers, gatewayPeer)
+
+		egress := make([]networkingv1.NetworkPolicyEgressRule, 0, len(services))
+		for _, svc := range services {
+			egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{networkPolicyPeer(svc.service.Namespace, svc.service.Spec.Selector)},
+				Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(svc)},
+			})
+		}
+		policy := desiredNetworkPolicy(kObj, fmt.Sprintf("kuadrant-gateway-%s-egress", gw.Name), gw.Namespace, podLabels)
+		policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+		policy.Spec.Egress = egress
+		policies = append(policies, policy)
+	}
+	if len(gatewayPeers) == 0 {
+		return policies, nil
+	}
+
+	for _, svc := range services {
+		policy := desiredNetworkPolicy(kObj, fmt.Sprintf("kuadrant-%s-ingress", svc.component), svc.service.Namespace, svc.service.Spec.Selector)
+		policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+			{
+				From:  gatewayPeers,
+				Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(svc)},
+			},
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func desiredNetworkPolicy(kObj *kuadrantv1beta1.Kuadrant, name, namespace string, podLabels map[string]string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    networkPolicyLabels(kObj),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podLabels},
+		},
+	}
+}
+
+// networkPolicyPeer returns the peer of the pods matching the labels in a namespace
+func networkPolicyPeer(namespace string, podLabels map[string]string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: namespace}},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: podLabels},
+	}
+}
+
+// networkPolicyPort returns the port of the pods of a service
+func networkPolicyPort(svc dataPlaneService) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	port := svc.targetPort
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+// deleteNetworkPolicies deletes the NetworkPolicies of the kuadrant instance in all the namespaces, except the
+// desired ones
+func (r *KuadrantReconciler) deleteNetworkPolicies(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant, desired []*networkingv1.NetworkPolicy) error {
+	existingList := &networkingv1.NetworkPolicyList{}
+	if err := r.Client().List(ctx, existingList, client.MatchingLabels(networkPolicyLabels(kObj))); err != nil {
+		return err
+	}
+	for idx := range existingList.Items {
+		existing := &existingList.Items[idx]
+		if _, found := common.Find(desired, func(policy *networkingv1.NetworkPolicy) bool {
+			return client.ObjectKeyFromObject(policy) == client.ObjectKeyFromObject(existing)
+		}); found {
+			continue
+		}
+		if err := r.DeleteResource(ctx, existing); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// networkPolicyMutator reconciles the spec and the labels of a NetworkPolicy. The labels added by the users are
+// preserved.
+func networkPolicyMutator(existingObj, desiredObj client.Object) (bool, error) {
+	existing, ok := existingObj.(*networkingv1.NetworkPolicy)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *networkingv1.NetworkPolicy", existingObj)
+	}
+	desired, ok := desiredObj.(*networkingv1.NetworkPolicy)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *networkingv1.NetworkPolicy", desiredObj)
+	}
+
+	update := false
+
+	if common.MergeMapStringString(&existing.Labels, desired.Labels) {
+		update = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec, desired.Spec) {
+		existing.Spec = desired.Spec
+		update = true
+	}
+
+	return update, nil
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// operatorNamespace returns the namespace the overrides of the specs of the managed components are read from
+func operatorNamespace() string {
+	return common.FetchEnv("OPERATOR_NAMESPACE", "kuadrant-system")
+}
+
+// componentOverrides returns the merge patches of the specs of the managed components, indexed by component
+// (authorino, limitador), read from the ConfigMaps of the namespace of the operator labeled
+// kuadrant.io/component-overrides=true. The patches are sorted by the name of their ConfigMap.
+func (r *KuadrantReconciler) componentOverrides(ctx context.Context) (map[string][][]byte, error) {
+	cmList := &corev1.ConfigMapList{}
+	if err := r.Client().List(ctx, cmList, client.InNamespace(operatorNamespace()), client.MatchingLabels{common.ComponentOverridesLabel: "true"}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(cmList.Items, func(i, j int) bool { return cmList.Items[i].Name < cmList.Items[j].Name })
+
+	overrides := make(map[string][][]byte)
+	for idx := range cmList.Items {
+		cm := &cmList.Items[idx]
+		for _, component := range []string{"authorino", "limitador"} {
+			value, ok := cm.Data[component]
+			if !ok {
+				continue
+			}
+			// the overrides are written in yaml or json
+			patch, err := yaml.YAMLToJSON([]byte(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s overrides in configmap %s: %w", component, client.ObjectKeyFromObject(cm), err)
+			}
+			overrides[component] = append(overrides[component], patch)
+		}
+	}
+
+	return overrides, nil
+}
+
+// withAuthorinoOverrides returns a mutator enforcing the overrides of the spec of Authorino after the given mutator
+func withAuthorinoOverrides(mutator reconcilers.MutateFn, patches [][]byte) reconcilers.MutateFn {
+	if len(patches) == 0 {
+		return mutator
+	}
+
+	return func(existingObj, desiredObj client.Object) (bool, error) {
+		update, err := mutator(existingObj, desiredObj)
+		if err != nil {
+			return false, err
+		}
+
+		existing, ok := existingObj.(*authorinov1beta1.Authorino)
+		if !ok {
+			return false, fmt.Errorf("%T is not an *authorinov1beta1.Authorino", existingObj)
+		}
+
+		spec, err := common.ApplyMergePatches(existing.Spec, patches)
+		if err != nil {
+			return false, fmt.Errorf("failed to apply the authorino overrides: %w", err)
+		}
+		if !reflect.DeepEqual(existing.Spec, spec) {
+			existing.Spec = spec
+			update = true
+		}
+
+		return update, nil
+	}
+}
+
+// withLimitadorOverrides returns a mutator enforcing the overrides of the spec of Limitador after the given mutator
+func withLimitadorOverrides(mutator reconcilers.MutateFn, patches [][]byte) reconcilers.MutateFn {
+	if len(patches) == 0 {
+		return mutator
+	}
+
+	return func(existingObj, desiredObj client.Object) (bool, error) {
+		update, err := mutator(existingObj, desiredObj)
+		if err != nil {
+			return false, err
+		}
+
+		existing, ok := existingObj.(*limitadorv1alpha1.Limitador)
+		if !ok {
+			return false, fmt.Errorf("%T is not an *limitadorv1alpha1.Limitador", existingObj)
+		}
+
+		spec, err := common.ApplyMergePatches(existing.Spec, patches)
+		if err != nil {
+			return false, fmt.Errorf("failed to apply the limitador overrides: %w", err)
+		}
+		if !reflect.DeepEqual(existing.Spec, spec) {
+			existing.Spec = spec
+			update = true
+		}
+
+		return update, nil
+	}
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// kuadrantReconcileTask is a step of the reconciliation of the spec of a Kuadrant instance
+type kuadrantReconcileTask struct {
+	name string
+	// namespaced tasks write resources in the namespace of the Kuadrant instance, skipped when it is terminating
+	namespaced bool
+	reconcile  func(r *KuadrantReconciler, ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error
+}
+
+// kuadrantReconcileTasks are the tasks of the reconciliation of the spec of a Kuadrant instance, in their default order
+var kuadrantReconcileTasks = []kuadrantReconcileTask{
+	{name: "external-authorizer", reconcile: (*KuadrantReconciler).registerExternalAuthorizer},
+	{name: "limitador", namespaced: true, reconcile: (*KuadrantReconciler).reconcileLimitador},
+	{name: "limitador-metrics", namespaced: true, reconcile: (*KuadrantReconciler).reconcileLimitadorMetrics},
+	{name: "limitador-rollout", namespaced: true, reconcile: (*KuadrantReconciler).reconcileLimitadorRollout},
+	{name: "limitador-pdb", namespaced: true, reconcile: (*KuadrantReconciler).reconcileLimitadorPodDisruptionBudget},
+	{name: "default-rate-limit", namespaced: true, reconcile: (*KuadrantReconciler).reconcileDefaultRateLimit},
+	{name: "authorino", namespaced: true, reconcile: (*KuadrantReconciler).reconcileAuthorino},
+	{name: "authorino-metrics", namespaced: true, reconcile: (*KuadrantReconciler).reconcileAuthorinoMetrics},
+	{name: "authorino-health", namespaced: true, reconcile: (*KuadrantReconciler).reconcileAuthorinoHealth},
+	{name: "authorino-service", namespaced: true, reconcile: (*KuadrantReconciler).reconcileAuthorinoService},
+	{name: "default-auth-policy", namespaced: true, reconcile: (*KuadrantReconciler).reconcileDefaultAuthPolicy},
+	{name: "grafana-dashboard", namespaced: true, reconcile: (*KuadrantReconciler).reconcileGrafanaDashboard},
+	{name: "network-policies", namespaced: true, reconcile: (*KuadrantReconciler).reconcileNetworkPolicies},
+	{name: "service-connectivity", reconcile: (*KuadrantReconciler).checkServiceConnectivity},
+	{name: "auth-filter", reconcile: (*KuadrantReconciler).checkAuthFilters},
+}
+
+// kuadrantReconcileTaskDependencies are the tasks each task requires to run before it
+var kuadrantReconcileTaskDependencies = map[string][]string{
+	"limitador-metrics":    {"limitador"},
+	"limitador-rollout":    {"limitador"},
+	"limitador-pdb":        {"limitador"},
+	"default-rate-limit":   {"limitador"},
+	"authorino-metrics":    {"authorino"},
+	"authorino-health":     {"authorino"},
+	"authorino-service":    {"authorino"},
+	"default-auth-policy":  {"authorino"},
+	"network-policies":     {"limitador", "authorino"},
+	"service-connectivity": {"limitador", "authorino"},
+	"auth-filter":          {"external-authorizer"},
+}
+
+// KuadrantReconcileTaskOrder is the order of the enabled tasks of the reconciliation of the Kuadrant instances,
+// read from the comma-separated KUADRANT_RECONCILE_TASKS env var. The tasks omitted are disabled.
+// The default order applies when the env var is missing or invalid.
+var KuadrantReconcileTaskOrder, kuadrantReconcileTaskOrderErr = kuadrantReconcileTaskOrderFromEnv()
+
+func kuadrantReconcileTaskOrderFromEnv() ([]string, error) {
+	defaults := common.Map(kuadrantReconcileTasks, func(task kuadrantReconcileTask) string { return task.name })
+	order, err := common.TaskOrder(defaults, kuadrantReconcileTaskDependencies, common.FetchEnv("KUADRANT_RECONCILE_TASKS", ""))
+	if err != nil {
+		return defaults, err
+	}
+	return order, nil
+}
+
+// runReconcileTasks runs the enabled tasks in order, stopping at the first failure
+func (r *KuadrantReconciler) runReconcileTasks(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant, terminating bool) error {
+	if terminating {
+		// the resources cannot be created in the namespace, reported in the status
+		logger, _ := logr.FromContext(ctx)
+		logger.V(1).Info("namespace terminating, skipping managed resources", "namespace", kObj.Namespace)
+	}
+
+	tasks := make(map[string]kuadrantReconcileTask, len(kuadrantReconcileTasks))
+	for _, task := range kuadrantReconcileTasks {
+		tasks[task.name] = task
+	}
+
+	for _, name := range KuadrantReconcileTaskOrder {
+		task := tasks[name]
+		if task.namespaced && terminating {
+			continue
+		}
+		if err := task.reconcile(r, ctx, kObj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func kuadrantReconcileTaskOrderString() string {
+	return strings.Join(KuadrantReconcileTaskOrder, ",")
+}
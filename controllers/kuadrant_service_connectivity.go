@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const ServiceUnreachableConditionType string = "ServiceUnreachable"
+
+// unreachableServices holds, per kuadrant instance, the services found blocked by the last connectivity check
+var unreachableServices = newInstanceFindingsTracker()
+
+// instanceFindingsTracker holds, per kuadrant instance, the findings of the last run of a check reported in the status
+type instanceFindingsTracker struct {
+	mu       sync.Mutex
+	findings map[client.ObjectKey][]string
+}
+
+func newInstanceFindingsTracker() *instanceFindingsTracker {
+	return &instanceFindingsTracker{findings: make(map[client.ObjectKey][]string)}
+}
+
+func (t *instanceFindingsTracker) Set(key client.ObjectKey, findings []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(findings) == 0 {
+		delete(t.findings, key)
+		return
+	}
+	t.findings[key] = findings
+}
+
+func (t *instanceFindingsTracker) Get(key client.ObjectKey) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.findings[key]...)
+}
+
+// dataPlaneService is a service the gateways send requests to, e.g. the ext_authz service of Authorino
+type dataPlaneService struct {
+	component string
+	service   *corev1.Service
+	// targetPort is the port of the pods of the service, the one the NetworkPolicies refer to
+	targetPort intstr.IntOrString
+}
+
+// checkServiceConnectivity analyses the NetworkPolicies between the gateways managed by a kuadrant instance and
+// the services of Authorino and Limitador, recording the connections blocked for the ServiceUnreachable condition.
+// Unlike a probe, the analysis tells which side blocks the connection and does not require running pods.
+func (r *KuadrantReconciler) checkServiceConnectivity(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	kKey := client.ObjectKeyFromObject(kObj)
+
+	services, err := r.dataPlaneServices(ctx, kObj)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		unreachableServices.Set(kKey, nil)
+		return nil
+	}
+
+	networkPolicies := &networkingv1.NetworkPolicyList{}
+	if err := r.Client().List(ctx, networkPolicies); err != nil {
+		return err
+	}
+	if len(networkPolicies.Items) == 0 {
+		unreachableServices.Set(kKey, nil)
+		return nil
+	}
+
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := r.Client().List(ctx, gwList); err != nil {
+		return err
+	}
+
+	namespaceLabels := make(map[string]map[string]string)
+	endpoint := func(namespace string, podLabels map[string]string) (common.NetworkPolicyEndpoint, error) {
+		if _, ok := namespaceLabels[namespace]; !ok {
+			ns := &corev1.Namespace{}
+			// read directly from the API server, the namespaces are not cached
+			if err := r.APIClientReader().Get(ctx, client.ObjectKey{Name: namespace}, ns); client.IgnoreNotFound(err) != nil {
+				return common.NetworkPolicyEndpoint{}, err
+			}
+			namespaceLabels[namespace] = ns.Labels
+		}
+		return common.NetworkPolicyEndpoint{Namespace: namespace, NamespaceLabels: namespaceLabels[namespace], PodLabels: podLabels}, nil
+	}
+
+	unreachable := make([]string, 0)
+	for idx := range gwList.Items {
+		gw := &gwList.Items[idx]
+		if kuadrantNamespace, err := common.GetKuadrantNamespace(gw); err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		src, err := endpoint(gw.Namespace, common.IstioWorkloadSelectorFromGateway(ctx, r.Client(), gw).MatchLabels)
+		if err != nil {
+			return err
+		}
+		for _, svc := range services {
+			dst, err := endpoint(svc.service.Namespace, svc.service.Spec.Selector)
+			if err != nil {
+				return err
+			}
+			var blockedBy []string
+			if !common.NetworkPoliciesAllowEgress(networkPolicies.Items, src, dst, svc.targetPort) {
+				blockedBy = append(blockedBy, fmt.Sprintf("egress of namespace %s", src.Namespace))
+			}
+			if !common.NetworkPoliciesAllowIngress(networkPolicies.Items, src, dst, svc.targetPort) {
+				blockedBy = append(blockedBy, fmt.Sprintf("ingress of namespace %s", dst.Namespace))
+			}
+			if len(blockedBy) > 0 {
+				unreachable = append(unreachable, fmt.Sprintf("%s (%s) from gateway %s, blocked by the NetworkPolicies of the %s",
+					svc.component, client.ObjectKeyFromObject(svc.service), client.ObjectKeyFromObject(gw), strings.Join(blockedBy, " and ")))
+			}
+		}
+	}
+
+	unreachableServices.Set(kKey, unreachable)
+	return nil
+}
+
+// dataPlaneServices returns the services of Authorino and Limitador the gateways send the ext_authz and the rate
+// limit requests to, skipping the ones not deployed yet
+func (r *KuadrantReconciler) dataPlaneServices(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) ([]dataPlaneService, error) {
+	expected, err := expectedEnvoyClusters(ctx, r.Client(), kObj)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]dataPlaneService, 0, 2)
+	clusters := []struct {
+		component string
+		cluster   *ExpectedEnvoyCluster
+	}{
+		{"authorino", expected.ExtAuthz},
+		{"limitador", expected.RateLimit},
+	}
+	for _, c := range clusters {
+		component, cluster := c.component, c.cluster
+		if cluster == nil {
+			continue
+		}
+		labels := strings.Split(cluster.Address, ".")
+		if len(labels) < 3 || labels[2] != "svc" {
+			continue
+		}
+		service := &corev1.Service{}
+		if err := r.Client().Get(ctx, client.ObjectKey{Name: labels[0], Namespace: labels[1]}, service); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			// reported by the DataPlaneConfigMismatch condition
+			continue
+		}
+		if len(service.Spec.Selector) == 0 {
+			// the endpoints are not pods selected by the NetworkPolicies
+			continue
+		}
+		for _, servicePort := range service.Spec.Ports {
+			if int(servicePort.Port) != cluster.Port {
+				continue
+			}
+			targetPort := servicePort.TargetPort
+			if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+				targetPort = intstr.FromInt(int(servicePort.Port))
+			}
+			services = append(services, dataPlaneService{component: component, service: service, targetPort: targetPort})
+		}
+	}
+
+	return services, nil
+}
+
+// serviceUnreachableCondition returns a warning condition listing the services of Authorino and Limitador the
+// NetworkPolicies prevent the gateways from reaching, or nil
+func serviceUnreachableCondition(kObj *kuadrantv1beta1.Kuadrant) *metav1.Condition {
+	unreachable := unreachableServices.Get(client.ObjectKeyFromObject(kObj))
+	if len(unreachable) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:    ServiceUnreachableConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "BlockedByNetworkPolicy",
+		Message: fmt.Sprintf("Services unreachable from the gateways: %s", strings.Join(unreachable, "; ")),
+	}
+}
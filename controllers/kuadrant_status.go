@@ -3,9 +3,9 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
@@ -17,10 +17,19 @@ import (
 	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
 	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
 )
 
 const (
-	ReadyConditionType string = "Ready"
+	ReadyConditionType                   string = "Ready"
+	AuthorinoCompatibleConditionType     string = "AuthorinoCompatible"
+	ScopeMismatchConditionType           string = "ScopeMismatch"
+	ListenerMismatchConditionType        string = "AuthorinoListenerMismatch"
+	GatewayAPICompatibleConditionType    string = "GatewayAPICompatible"
+	HighMetricsCardinalityConditionType  string = "AuthorinoMetricsHighCardinality"
+	ObserverModeConditionType            string = "ObserverMode"
+	OutboundTimeoutExceededConditionType string = "AuthorinoOutboundTimeoutExceeded"
+	DecisionLogsConditionType            string = "AuthorinoDecisionLogs"
 )
 
 func (r *KuadrantReconciler) reconcileStatus(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant, specErr error) (ctrl.Result, error) {
@@ -66,6 +75,7 @@ func (r *KuadrantReconciler) calculateStatus(ctx context.Context, kObj *kuadrant
 		// Copy initial conditions. Otherwise, status will always be updated
 		Conditions:         common.CopyConditions(kObj.Status.Conditions),
 		ObservedGeneration: kObj.Status.ObservedGeneration,
+		EffectiveConfig:    r.effectiveConfig(),
 	}
 
 	availableCond, err := r.readyCondition(ctx, kObj, specErr)
@@ -75,6 +85,213 @@ func (r *KuadrantReconciler) calculateStatus(ctx context.Context, kObj *kuadrant
 
 	meta.SetStatusCondition(&newStatus.Conditions, *availableCond)
 
+	// informational only, the limits are enforced regardless
+	limitador := &limitadorv1alpha1.Limitador{}
+	err = r.Client().Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: kObj.Namespace}, limitador)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if cond := limitsSoftCapExceededCondition(limitador); cond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *cond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, LimitsSoftCapExceededConditionType)
+	}
+
+	// informational only, the AuthConfigs are served regardless
+	authConfigsCond, err := authConfigsSoftCapExceededCondition(ctx, r.Client(), kObj.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if authConfigsCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *authConfigsCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, AuthConfigsSoftCapExceededConditionType)
+	}
+
+	// informational only, the AuthPolicies out of the scope of Authorino are not enforced
+	scopeMismatchCond, err := r.authorinoScopeMismatchCondition(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	if scopeMismatchCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *scopeMismatchCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, ScopeMismatchConditionType)
+	}
+
+	// the AuthPolicies are not enforced if the gateways cannot reach Authorino
+	listenerMismatchCond, err := r.authorinoListenerMismatchCondition(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	if listenerMismatchCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *listenerMismatchCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, ListenerMismatchConditionType)
+	}
+
+	// the clusters of the gateways are derived from the services of Authorino and Limitador
+	dataPlaneMismatchCond, err := r.dataPlaneMismatchCondition(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	if dataPlaneMismatchCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *dataPlaneMismatchCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, DataPlaneMismatchConditionType)
+	}
+
+	if newStatus.AuthorinoHealthService, err = r.authorinoHealthServiceStatus(ctx, kObj); err != nil {
+		return nil, err
+	}
+
+	// informational only, the metrics settings are applied regardless
+	if reason := kObj.AuthorinoMetrics().HighCardinality(); reason != "" {
+		meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+			Type:    HighMetricsCardinalityConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HighCardinality",
+			Message: fmt.Sprintf("The metrics of Authorino may have a high cardinality: %s", reason),
+		})
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, HighMetricsCardinalityConditionType)
+	}
+
+	// informational only, the requests waiting for the external providers fail at the gateways
+	if reason := kObj.AuthorinoOutboundTimeoutExceeded(); reason != "" {
+		meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+			Type:    OutboundTimeoutExceededConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "OutboundTimeoutExceeded",
+			Message: fmt.Sprintf("The requests of Authorino to slow external providers fail at the gateways: %s", reason),
+		})
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, OutboundTimeoutExceededConditionType)
+	}
+
+	// informational only, the volume of the logs grows with the traffic of all the protected routes
+	if kObj.AuthorinoDecisionLogsEnabled() {
+		meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+			Type:    DecisionLogsConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "DecisionLogsEnabled",
+			Message: "Authorino logs every authorization request and decision",
+		})
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, DecisionLogsConditionType)
+	}
+
+	// the previous operator config stays active while the current one is invalid
+	if _, configErr := activeOperatorConfig.Get(); configErr != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+			Type:    OperatorConfigRejectedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "InvalidOperatorConfig",
+			Message: configErr.Error(),
+		})
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, OperatorConfigRejectedConditionType)
+	}
+
+	// informational only, the unsupported versions are not prevented from running
+	versionCond, err := r.componentVersionUnsupportedCondition(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	if versionCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *versionCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, ComponentVersionUnsupportedConditionType)
+	}
+
+	// informational only, the traffic of the gateways without any policy is let through unprotected
+	unprotectedCond, err := r.unprotectedGatewaysCondition(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	if unprotectedCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *unprotectedCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, UnprotectedGatewaysConditionType)
+	}
+
+	// informational only, the gateways fail open or closed according to the policies when a service is unreachable
+	if unreachableCond := serviceUnreachableCondition(kObj); unreachableCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *unreachableCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, ServiceUnreachableConditionType)
+	}
+
+	// informational only, the informers retry on their own
+	if watcherCond := watcherSyncFailedCondition(); watcherCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *watcherCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, WatcherSyncFailedConditionType)
+	}
+
+	// the AuthConfigs may be ready while the gateways do not send the requests to Authorino
+	if authFilterCond := authFilterMissingCondition(kObj); authFilterCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *authFilterCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, AuthFilterMissingConditionType)
+	}
+
+	defaultRateLimitGateways, err := r.defaultRateLimitGatewaysStatus(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	newStatus.DefaultRateLimitGateways = defaultRateLimitGateways
+
+	defaultAuthPolicyGateways, err := r.defaultAuthPolicyGatewaysStatus(ctx, kObj)
+	if err != nil {
+		return nil, err
+	}
+	newStatus.DefaultAuthPolicyGateways = defaultAuthPolicyGateways
+
+	// the disruptive changes pending the maintenance window
+	deferredChanges, nextWindow := maintenanceWindowStatus(kObj)
+	newStatus.DeferredChanges = deferredChanges
+	if nextWindow != nil {
+		next := metav1.NewTime(*nextWindow)
+		newStatus.NextMaintenanceWindow = &next
+	}
+
+	// the watches of the gateway api resources fail on an older gateway api
+	meta.SetStatusCondition(&newStatus.Conditions, *r.gatewayAPICompatibleCondition())
+
+	// the resources not written in observer mode are reported missing, not to be mistaken for a stuck operator
+	if r.ObserverMode {
+		meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+			Type:    ObserverModeConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ObserverMode",
+			Message: "The operator runs in observer mode: the status is computed but the resources managed for the Kuadrant instance and the policies are not written",
+		})
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, ObserverModeConditionType)
+	}
+
+	// the storage config of Limitador is not effective until rolled out
+	limitadorConfigCond, err := r.limitadorConfigAppliedCondition(ctx, kObj, newStatus.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	if limitadorConfigCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *limitadorConfigCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, LimitadorConfigAppliedConditionType)
+	}
+
+	if kObj.IsAuthorinoValidateOnly() {
+		compatibleCond, err := r.authorinoCompatibleCondition(ctx, kObj)
+		if err != nil {
+			return nil, err
+		}
+		meta.SetStatusCondition(&newStatus.Conditions, *compatibleCond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, AuthorinoCompatibleConditionType)
+	}
+
 	return newStatus, nil
 }
 
@@ -93,6 +310,17 @@ func (r *KuadrantReconciler) readyCondition(ctx context.Context, kObj *kuadrantv
 		return cond, nil
 	}
 
+	terminating, err := r.namespaceTerminating(ctx, kObj.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if terminating {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "NamespaceTerminating"
+		cond.Message = fmt.Sprintf("namespace %s is terminating, the managed resources are not created", kObj.Namespace)
+		return cond, nil
+	}
+
 	reason, err := r.checkLimitadorAvailable(ctx, kObj)
 	if err != nil {
 		return nil, err
@@ -118,32 +346,127 @@ func (r *KuadrantReconciler) readyCondition(ctx context.Context, kObj *kuadrantv
 	return cond, nil
 }
 
-func (r *KuadrantReconciler) checkLimitadorAvailable(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*string, error) {
-	// Should be implemented reading the Limitador CR's status conditions.
-	// Not implemented yet in the limitador's operator
-	deployment := &appsv1.Deployment{}
-	dKey := client.ObjectKey{Name: "limitador", Namespace: kObj.Namespace}
-	err := r.Client().Get(ctx, dKey, deployment)
+// authorinoCompatibleCondition compares the externally managed Authorino instance with the one expected by Kuadrant
+func (r *KuadrantReconciler) authorinoCompatibleCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*metav1.Condition, error) {
+	cond := &metav1.Condition{
+		Type:    AuthorinoCompatibleConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthorinoCompatible",
+		Message: "Authorino matches the configuration expected by Kuadrant",
+	}
+
+	desired := desiredAuthorino(kObj)
+	existing := &authorinov1beta1.Authorino{}
+	err := r.Client().Get(ctx, client.ObjectKeyFromObject(desired), existing)
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, err
 	}
 
-	if err != nil && errors.IsNotFound(err) {
-		tmp := err.Error()
-		return &tmp, nil
+	if errors.IsNotFound(err) {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "AuthorinoNotFound"
+		cond.Message = err.Error()
+		return cond, nil
 	}
 
-	availableCondition := common.FindDeploymentStatusCondition(deployment.Status.Conditions, "Available")
-	if availableCondition == nil {
-		tmp := "Available condition not found"
-		return &tmp, nil
+	if discrepancies := authorinoDiscrepancies(existing, desired); len(discrepancies) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "AuthorinoDrift"
+		cond.Message = fmt.Sprintf("Authorino differs from the configuration expected by Kuadrant: %s", strings.Join(discrepancies, ", "))
+	}
+
+	return cond, nil
+}
+
+// gatewayAPICompatibleCondition reflects whether the release of the Gateway API detected at startup is supported
+func (r *KuadrantReconciler) gatewayAPICompatibleCondition() *metav1.Condition {
+	cond := &metav1.Condition{
+		Type:    GatewayAPICompatibleConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "GatewayAPISupported",
+		Message: fmt.Sprintf("Gateway API %s installed, required %s or newer", r.GatewayAPIVersion, common.GatewayAPIMinVersion),
 	}
 
-	if availableCondition.Status != corev1.ConditionTrue {
-		return &availableCondition.Message, nil
+	if err := common.CheckGatewayAPIVersion(r.GatewayAPIVersion); common.IsGatewayAPIVersionUnknown(err) {
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = "GatewayAPIVersionUnknown"
+		cond.Message = err.Error()
+	} else if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "GatewayAPIUnsupported"
+		cond.Message = err.Error()
 	}
 
-	return nil, nil
+	return cond
+}
+
+// authorinoScopeMismatchCondition returns a warning condition listing the AuthPolicies of the kuadrant instance
+// whose AuthConfigs are out of the scope of a namespaced Authorino instance
+func (r *KuadrantReconciler) authorinoScopeMismatchCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*metav1.Condition, error) {
+	authorino := &authorinov1beta1.Authorino{}
+	err := r.Client().Get(ctx, client.ObjectKey{Name: "authorino", Namespace: kObj.Namespace}, authorino)
+	if err != nil || authorino.Spec.ClusterWide {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := r.Client().List(ctx, apList); err != nil {
+		return nil, err
+	}
+
+	outOfScope := make([]string, 0)
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		// the authconfig of a policy is created in the namespace of the policy, moved to the namespace
+		// of authorino when the policy is reconciled
+		if ap.Namespace == authorino.Namespace || (ap.Status.Authorino != nil && ap.Status.Authorino.Namespace == authorino.Namespace) {
+			continue
+		}
+		kuadrantNamespace, err := common.GetKuadrantNamespaceFromPolicyTargetRef(ctx, r.Client(), ap)
+		if err != nil || kuadrantNamespace != kObj.Namespace {
+			continue
+		}
+		outOfScope = append(outOfScope, client.ObjectKeyFromObject(ap).String())
+	}
+
+	if len(outOfScope) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    ScopeMismatchConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthPoliciesOutOfScope",
+		Message: fmt.Sprintf("Authorino only watches the namespace %s. AuthPolicies out of its scope: %s", authorino.Namespace, strings.Join(outOfScope, ", ")),
+	}, nil
+}
+
+// authorinoListenerMismatchCondition returns a condition listing the settings of the listener of Authorino
+// that break the wiring with the ext_authz provider registered by Kuadrant, or nil if none
+func (r *KuadrantReconciler) authorinoListenerMismatchCondition(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*metav1.Condition, error) {
+	authorino := &authorinov1beta1.Authorino{}
+	err := r.Client().Get(ctx, client.ObjectKey{Name: "authorino", Namespace: kObj.Namespace}, authorino)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	mismatches := common.AuthorizerListenerMismatches(common.NewKuadrantAuthorizer(kObj.Namespace), authorino)
+	if len(mismatches) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    ListenerMismatchConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthPoliciesNotEnforced",
+		Message: fmt.Sprintf("The gateways cannot reach Authorino: %s", strings.Join(mismatches, "; ")),
+	}, nil
+}
+
+func (r *KuadrantReconciler) checkLimitadorAvailable(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*string, error) {
+	// Should be implemented reading the Limitador CR's status conditions.
+	// Not implemented yet in the limitador's operator
+	return limitadorDeploymentNotAvailableReason(ctx, r.Client(), kObj.Namespace)
 }
 
 func (r *KuadrantReconciler) checkAuthorinoAvailable(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) (*string, error) {
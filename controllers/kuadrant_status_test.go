@@ -0,0 +1,33 @@
+//go:build unit
+
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGatewayAPICompatibleCondition(t *testing.T) {
+	testCases := []struct {
+		name     string
+		detected string
+		status   metav1.ConditionStatus
+		reason   string
+	}{
+		{name: "supported", detected: "v0.6.2", status: metav1.ConditionTrue, reason: "GatewayAPISupported"},
+		{name: "older", detected: "v0.5.1", status: metav1.ConditionFalse, reason: "GatewayAPIUnsupported"},
+		{name: "not annotated", detected: "", status: metav1.ConditionUnknown, reason: "GatewayAPIVersionUnknown"},
+		{name: "invalid", detected: "latest", status: metav1.ConditionUnknown, reason: "GatewayAPIVersionUnknown"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			r := &KuadrantReconciler{GatewayAPIVersion: tc.detected}
+			cond := r.gatewayAPICompatibleCondition()
+			if cond.Status != tc.status || cond.Reason != tc.reason {
+				subT.Errorf("expected %s/%s, got %s/%s: %s", tc.status, tc.reason, cond.Status, cond.Reason, cond.Message)
+			}
+		})
+	}
+}
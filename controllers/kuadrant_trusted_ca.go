@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+const (
+	// trustedCABundleVolumeName is the name of the volume of the Authorino instance holding the trusted CA bundle
+	trustedCABundleVolumeName = "kuadrant-trusted-ca-bundle"
+	// trustedCABundleMountPath is the directory of the trusted CA bundle in the Authorino container. Not one of the
+	// directories of the system CA certificates, hidden by the mount otherwise.
+	trustedCABundleMountPath = "/etc/kuadrant/trusted-ca"
+	// trustedCABundleFileName is the name of the file of the trusted CA bundle in the mounted directory
+	trustedCABundleFileName = "kuadrant-trusted-ca-bundle.crt"
+	// trustedCABundleCertDirEnvVar is the env var of the Authorino container listing the directories of the CA
+	// certificates loaded, in addition to the system bundle file
+	trustedCABundleCertDirEnvVar = "SSL_CERT_DIR"
+	// trustedCABundleCertDirs are the directories of the system CA certificates, followed by the one of the trusted
+	// CA bundle
+	trustedCABundleCertDirs = "/etc/ssl/certs:/etc/pki/tls/certs:" + trustedCABundleMountPath
+)
+
+// validateTrustedCABundle checks the ConfigMap or the Secret of the trusted CA bundle exists and holds the bundle
+func (r *KuadrantReconciler) validateTrustedCABundle(ctx context.Context, namespace string, ref *kuadrantv1beta1.TrustedCABundleReference) error {
+	if ref == nil {
+		return nil
+	}
+
+	key := trustedCABundleKey(ref)
+	objKey := client.ObjectKey{Name: ref.Name, Namespace: namespace}
+
+	var bundle []byte
+	// read directly from the API server, the configmaps and the secrets are not cached
+	if ref.Kind == "Secret" {
+		secret := &corev1.Secret{}
+		if err := r.APIClientReader().Get(ctx, objKey, secret); err != nil {
+			return fmt.Errorf("failed to read trusted CA bundle secret %s: %w", ref.Name, err)
+		}
+		bundle = secret.Data[key]
+	} else {
+		configMap := &corev1.ConfigMap{}
+		if err := r.APIClientReader().Get(ctx, objKey, configMap); err != nil {
+			return fmt.Errorf("failed to read trusted CA bundle configmap %s: %w", ref.Name, err)
+		}
+		bundle = []byte(configMap.Data[key])
+	}
+
+	if len(bundle) == 0 {
+		return fmt.Errorf("trusted CA bundle %s %s has no %s entry", ref.Kind, ref.Name, key)
+	}
+
+	return nil
+}
+
+func trustedCABundleKey(ref *kuadrantv1beta1.TrustedCABundleReference) string {
+	if ref.Key == "" {
+		return "ca.crt"
+	}
+	return ref.Key
+}
+
+// trustedCABundleVolume returns the volume of the Authorino instance mounting the trusted CA bundle
+func trustedCABundleVolume(ref *kuadrantv1beta1.TrustedCABundleReference) authorinov1beta1.VolumeSpec {
+	volume := authorinov1beta1.VolumeSpec{
+		Name:      trustedCABundleVolumeName,
+		MountPath: trustedCABundleMountPath,
+		Items: []corev1.KeyToPath{
+			{Key: trustedCABundleKey(ref), Path: trustedCABundleFileName},
+		},
+	}
+	if ref.Kind == "Secret" {
+		volume.Secrets = []string{ref.Name}
+	} else {
+		volume.ConfigMaps = []string{ref.Name}
+	}
+	return volume
+}
+
+// reconcileAuthorinoTrustedCACertDirs sets the directories of the CA certificates loaded by Authorino, in the env of
+// the container of the deployment of the Authorino instance, as the Authorino CR has no field for it. Only that env
+// var is touched, ignored by the authorino-operator when comparing the deployment, and set again whenever the
+// authorino-operator rewrites the deployment, which the authorino-operator reflects in the status of the Authorino
+// instance.
+func (r *KuadrantReconciler) reconcileAuthorinoTrustedCACertDirs(ctx context.Context, kObj *kuadrantv1beta1.Kuadrant) error {
+	deployment := &appsv1.Deployment{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: "authorino", Namespace: kObj.Namespace}, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			// not created yet by the authorino-operator, reflected in the status of the Authorino instance
+			return nil
+		}
+		return err
+	}
+
+	if !setTrustedCACertDirs(deployment, kObj.AuthorinoTrustedCABundle() != nil) {
+		return nil
+	}
+
+	return r.Client().Update(ctx, deployment)
+}
+
+// setTrustedCACertDirs sets, or removes, the env var of the directories of the CA certificates in the containers of
+// the deployment of Authorino. Returns whether the deployment changed.
+func setTrustedCACertDirs(deployment *appsv1.Deployment, enabled bool) bool {
+	changed := false
+	containers := deployment.Spec.Template.Spec.Containers
+	for idx := range containers {
+		env := make([]corev1.EnvVar, 0, len(containers[idx].Env)+1)
+		found := false
+		for _, envVar := range containers[idx].Env {
+			if envVar.Name != trustedCABundleCertDirEnvVar {
+				env = append(env, envVar)
+				continue
+			}
+			if !enabled {
+				// only removes the value set by the operator
+				if envVar.Value != trustedCABundleCertDirs {
+					env = append(env, envVar)
+				} else {
+					changed = true
+				}
+				continue
+			}
+			found = true
+			if envVar.Value != trustedCABundleCertDirs || envVar.ValueFrom != nil {
+				envVar = corev1.EnvVar{Name: trustedCABundleCertDirEnvVar, Value: trustedCABundleCertDirs}
+				changed = true
+			}
+			env = append(env, envVar)
+		}
+		if enabled && !found {
+			env = append(env, corev1.EnvVar{Name: trustedCABundleCertDirEnvVar, Value: trustedCABundleCertDirs})
+			changed = true
+		}
+		containers[idx].Env = env
+	}
+	return changed
+}
+
+// findVolume returns the index of the volume with the given name, or -1
+func findVolume(volumes []authorinov1beta1.VolumeSpec, name string) int {
+	for idx := range volumes {
+		if volumes[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
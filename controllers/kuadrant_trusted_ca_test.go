@@ -0,0 +1,122 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+func testAuthorinoDeployment(namespace string, env ...corev1.EnvVar) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "authorino", Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "authorino", Image: "quay.io/kuadrant/authorino:latest", Env: env}},
+				},
+			},
+		},
+	}
+}
+
+func TestTrustedCABundleVolume(t *testing.T) {
+	volume := trustedCABundleVolume(&kuadrantv1beta1.TrustedCABundleReference{Kind: "ConfigMap", Name: "ca"})
+	for _, systemDir := range []string{"/etc/ssl/certs", "/etc/pki/tls/certs"} {
+		if volume.MountPath == systemDir {
+			t.Errorf("expected the trusted CA bundle not to be mounted in the system directory %s", systemDir)
+		}
+	}
+	if len(volume.ConfigMaps) != 1 || volume.ConfigMaps[0] != "ca" || len(volume.Secrets) != 0 {
+		t.Errorf("unexpected sources of the volume %+v", volume)
+	}
+}
+
+func TestSetTrustedCACertDirs(t *testing.T) {
+	other := corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}
+
+	t.Run("added", func(subT *testing.T) {
+		deployment := testAuthorinoDeployment("kuadrant-system", other)
+		if !setTrustedCACertDirs(deployment, true) {
+			subT.Fatal("expected the deployment to change")
+		}
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		if len(env) != 2 || env[0] != other || env[1].Name != trustedCABundleCertDirEnvVar || env[1].Value != trustedCABundleCertDirs {
+			subT.Errorf("unexpected env %+v", env)
+		}
+		if setTrustedCACertDirs(deployment, true) {
+			subT.Error("expected the deployment not to change again")
+		}
+	})
+
+	t.Run("fixed", func(subT *testing.T) {
+		deployment := testAuthorinoDeployment("kuadrant-system", corev1.EnvVar{Name: trustedCABundleCertDirEnvVar, Value: "/etc/ssl/certs"})
+		if !setTrustedCACertDirs(deployment, true) {
+			subT.Fatal("expected the deployment to change")
+		}
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		if len(env) != 1 || env[0].Value != trustedCABundleCertDirs {
+			subT.Errorf("unexpected env %+v", env)
+		}
+	})
+
+	t.Run("removed", func(subT *testing.T) {
+		deployment := testAuthorinoDeployment("kuadrant-system", other, corev1.EnvVar{Name: trustedCABundleCertDirEnvVar, Value: trustedCABundleCertDirs})
+		if !setTrustedCACertDirs(deployment, false) {
+			subT.Fatal("expected the deployment to change")
+		}
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		if len(env) != 1 || env[0] != other {
+			subT.Errorf("unexpected env %+v", env)
+		}
+	})
+
+	t.Run("not set by the operator", func(subT *testing.T) {
+		custom := corev1.EnvVar{Name: trustedCABundleCertDirEnvVar, Value: "/custom"}
+		deployment := testAuthorinoDeployment("kuadrant-system", custom)
+		if setTrustedCACertDirs(deployment, false) {
+			subT.Error("expected the deployment not to change")
+		}
+	})
+}
+
+func TestReconcileAuthorinoTrustedCACertDirs(t *testing.T) {
+	kObj := &kuadrantv1beta1.Kuadrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "kuadrant", Namespace: "kuadrant-system"},
+		Spec: kuadrantv1beta1.KuadrantSpec{
+			Authorino: &kuadrantv1beta1.AuthorinoSpec{
+				TrustedCABundle: &kuadrantv1beta1.TrustedCABundleReference{Kind: "ConfigMap", Name: "ca"},
+			},
+		},
+	}
+	ctx := context.TODO()
+
+	t.Run("deployment not created yet", func(subT *testing.T) {
+		r := &KuadrantReconciler{BaseReconciler: unitTestTargetRefReconciler(kObj).BaseReconciler}
+		if err := r.reconcileAuthorinoTrustedCACertDirs(ctx, kObj); err != nil {
+			subT.Fatal(err)
+		}
+	})
+
+	t.Run("deployment updated", func(subT *testing.T) {
+		r := &KuadrantReconciler{BaseReconciler: unitTestTargetRefReconciler(kObj, testAuthorinoDeployment(kObj.Namespace)).BaseReconciler}
+		if err := r.reconcileAuthorinoTrustedCACertDirs(ctx, kObj); err != nil {
+			subT.Fatal(err)
+		}
+		deployment := &appsv1.Deployment{}
+		if err := r.Client().Get(ctx, client.ObjectKey{Name: "authorino", Namespace: kObj.Namespace}, deployment); err != nil {
+			subT.Fatal(err)
+		}
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		if len(env) != 1 || env[0].Name != trustedCABundleCertDirEnvVar || env[0].Value != trustedCABundleCertDirs {
+			subT.Errorf("unexpected env %+v", env)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	authConfigGetFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kuadrant_authpolicy_authconfig_get_failures_total",
+			Help: "Number of failed attempts to read the AuthConfig of an AuthPolicy while reconciling its status, per reason of the error",
+		},
+		[]string{"reason"},
+	)
+
+	authConfigReadyLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kuadrant_authpolicy_authconfig_ready_latency_seconds",
+			Help:    "Time from the creation or update of the AuthConfig of an AuthPolicy to the AuthConfig being ready in Authorino",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+	)
+
+	authConfigReadyTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "kuadrant_authpolicy_authconfig_ready_timeouts_total",
+			Help: "Number of AuthConfigs of AuthPolicies not ready within the timeout after their creation or update",
+		},
+	)
+
+	policyReconcileStalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kuadrant_policy_reconcile_stalls_total",
+			Help: "Number of generations of policies not reconciled within the stall threshold",
+		},
+		[]string{"kind"},
+	)
+
+	reconcilerLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kuadrant_reconciler_last_success_timestamp_seconds",
+			Help: "Unix timestamp of the last reconciliation completed without error, per reconciler",
+		},
+		[]string{"reconciler"},
+	)
+
+	unprotectedGateways = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kuadrant_gateway_unprotected",
+			Help: "Gateways managed by Kuadrant without any AuthPolicy nor RateLimitPolicy, set to 1 while unprotected",
+		},
+		[]string{"namespace", "name", "kuadrant_namespace"},
+	)
+
+	watcherSyncFailed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kuadrant_watcher_sync_failed",
+			Help: "Watched kinds the operator fails to list from the API server, set to 1 while failing",
+		},
+		[]string{"kind"},
+	)
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(
+		authConfigGetFailures,
+		authConfigReadyLatency,
+		authConfigReadyTimeouts,
+		policyReconcileStalls,
+		reconcilerLastSuccess,
+		unprotectedGateways,
+		watcherSyncFailed,
+	)
+}
+
+// lastSuccessRecorder records the time of the last reconciliation completed without error of the wrapped reconciler,
+// so a reconciler no longer making progress can be alerted on
+type lastSuccessRecorder struct {
+	reconcile.Reconciler
+	name string
+}
+
+func withLastSuccessMetric(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &lastSuccessRecorder{Reconciler: r, name: name}
+}
+
+func (r *lastSuccessRecorder) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := r.Reconciler.Reconcile(ctx, req)
+	if err == nil {
+		reconcilerLastSuccess.WithLabelValues(r.name).SetToCurrentTime()
+	}
+	return result, err
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/log"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+const (
+	OperatorConfigRejectedConditionType string = "OperatorConfigRejected"
+
+	operatorConfigLogLevelKey = "logLevel"
+	operatorConfigDryRunKey   = "dryRun"
+)
+
+// OperatorConfig holds the toggles of the operator reloaded without restarting it, from the ConfigMaps of the
+// namespace of the operator labeled kuadrant.io/operator-config=true
+type OperatorConfig struct {
+	// LogLevel is the minimum enabled logging level
+	LogLevel string
+	// DryRun submits the writes of the managed resources in dry-run mode
+	DryRun bool
+}
+
+func defaultOperatorConfig() OperatorConfig {
+	return OperatorConfig{
+		LogLevel: common.FetchEnv("LOG_LEVEL", "info"),
+		DryRun:   false,
+	}
+}
+
+// parseOperatorConfig validates the entries of the operator config ConfigMaps, sorted by name, the last ones
+// prevailing. Unknown keys are rejected.
+func parseOperatorConfig(cms []corev1.ConfigMap) (OperatorConfig, error) {
+	config := defaultOperatorConfig()
+	for idx := range cms {
+		cm := &cms[idx]
+		for key, value := range cm.Data {
+			switch key {
+			case operatorConfigLogLevelKey:
+				if _, err := log.ParseLevel(value); err != nil {
+					return config, fmt.Errorf("invalid %s %q in configmap %s: %w", key, value, client.ObjectKeyFromObject(cm), err)
+				}
+				config.LogLevel = value
+			case operatorConfigDryRunKey:
+				dryRun, err := strconv.ParseBool(value)
+				if err != nil {
+					return config, fmt.Errorf("invalid %s %q in configmap %s: %w", key, value, client.ObjectKeyFromObject(cm), err)
+				}
+				config.DryRun = dryRun
+			default:
+				return config, fmt.Errorf("unknown key %s in configmap %s", key, client.ObjectKeyFromObject(cm))
+			}
+		}
+	}
+	return config, nil
+}
+
+// activeOperatorConfig is the last valid operator config, and the error of the last config rejected, if any
+var activeOperatorConfig = &operatorConfigStore{config: defaultOperatorConfig()}
+
+type operatorConfigStore struct {
+	mu     sync.RWMutex
+	config OperatorConfig
+	err    error
+}
+
+func (s *operatorConfigStore) Get() (OperatorConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config, s.err
+}
+
+// Apply activates a valid config, or keeps the previous one if invalid
+func (s *operatorConfigStore) Apply(config OperatorConfig, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	if err != nil {
+		return
+	}
+	s.config = config
+	log.UpdateLevel(log.ToLevel(config.LogLevel))
+}
+
+// DryRunEnabled returns whether the writes of the managed resources are submitted in dry-run mode
+func DryRunEnabled() bool {
+	config, _ := activeOperatorConfig.Get()
+	return config.DryRun
+}
+
+// OperatorConfigReconciler reloads the operator config on the changes of the operator config ConfigMaps, whether any
+// kuadrant instance exists or not
+type OperatorConfigReconciler struct {
+	*reconcilers.BaseReconciler
+	// ReconcileTrigger enqueues the reconciliation of the kuadrant instances reporting the config (optional)
+	ReconcileTrigger *ReconcileTrigger
+}
+
+func (r *OperatorConfigReconciler) Reconcile(eventCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger().WithValues("configmap", req.NamespacedName)
+	ctx := logr.NewContext(eventCtx, logger)
+
+	changed, err := r.reloadOperatorConfig(ctx)
+	if err != nil {
+		logger.Error(err, "failed to read the operator config")
+		return ctrl.Result{}, err
+	}
+
+	// the kuadrant instances report the active config, or the config rejected, in their status
+	if changed && r.ReconcileTrigger != nil {
+		if _, err := r.ReconcileTrigger.TriggerFor(ctx, []client.ObjectList{&kuadrantv1beta1.KuadrantList{}}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reloadOperatorConfig reads the operator config ConfigMaps and activates their config if valid. Returns whether the
+// active config, or the error of the config rejected, changed.
+func (r *OperatorConfigReconciler) reloadOperatorConfig(ctx context.Context) (bool, error) {
+	logger, _ := logr.FromContext(ctx)
+
+	cmList := &corev1.ConfigMapList{}
+	if err := r.Client().List(ctx, cmList, client.InNamespace(operatorNamespace()), client.MatchingLabels{common.OperatorConfigLabel: "true"}); err != nil {
+		return false, err
+	}
+	sort.Slice(cmList.Items, func(i, j int) bool { return cmList.Items[i].Name < cmList.Items[j].Name })
+
+	previous, previousErr := activeOperatorConfig.Get()
+	config, err := parseOperatorConfig(cmList.Items)
+	activeOperatorConfig.Apply(config, err)
+	if err != nil {
+		logger.Error(err, "operator config rejected, keeping the previous config")
+		config = previous
+	} else if config != previous {
+		logger.Info("operator config reloaded", "logLevel", config.LogLevel, "dryRun", config.DryRun)
+	}
+	return config != previous || fmt.Sprint(err) != fmt.Sprint(previousErr), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("operatorconfig").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == operatorNamespace() && obj.GetLabels()[common.OperatorConfigLabel] == "true"
+		}))).
+		Complete(withLastSuccessMetric("operatorconfig", r))
+}
@@ -0,0 +1,64 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// the operator config is reloaded with no kuadrant instance in the cluster, e.g. to toggle the dry-run mode of the
+// writes of the policies
+func TestOperatorConfigReconcilerWithoutKuadrant(t *testing.T) {
+	defer func(config OperatorConfig, err error) { activeOperatorConfig.Apply(config, err) }(activeOperatorConfig.Get())
+	activeOperatorConfig.Apply(defaultOperatorConfig(), nil)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: operatorNamespace(), Labels: map[string]string{common.OperatorConfigLabel: "true"}},
+		Data:       map[string]string{operatorConfigDryRunKey: "true"},
+	}
+	r := &OperatorConfigReconciler{BaseReconciler: unitTestTargetRefReconciler(cm).BaseReconciler}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cm)}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if !DryRunEnabled() {
+		t.Fatal("expected the dry-run mode enabled")
+	}
+
+	changed, err := r.reloadOperatorConfig(ctx)
+	if err != nil || changed {
+		t.Errorf("expected the config unchanged, got %v, %v", changed, err)
+	}
+
+	cm.Data[operatorConfigDryRunKey] = "maybe"
+	if err := r.Client().Update(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = r.reloadOperatorConfig(ctx)
+	if err != nil || !changed {
+		t.Errorf("expected the config rejected to be reported, got %v, %v", changed, err)
+	}
+	if _, configErr := activeOperatorConfig.Get(); configErr == nil || !DryRunEnabled() {
+		t.Errorf("expected the previous config kept, got %v", configErr)
+	}
+
+	if err := r.Client().Delete(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, configErr := activeOperatorConfig.Get(); configErr != nil || DryRunEnabled() {
+		t.Errorf("expected the default config restored, got %v", configErr)
+	}
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// PolicyChangePendingConditionType is the condition of a policy whose last changes are not applied yet, waiting for
+// the policy to be stable for the cooldown
+const PolicyChangePendingConditionType string = "ChangePending"
+
+// PolicyChangeCooldown is the time a policy changed must be stable for before its changes are applied. 0 disables
+// the cooldown.
+var PolicyChangeCooldown = policyChangeCooldownFromEnv(0)
+
+func policyChangeCooldownFromEnv(def int) time.Duration {
+	seconds, err := strconv.Atoi(common.FetchEnv("POLICY_CHANGE_COOLDOWN_SECONDS", strconv.Itoa(def)))
+	if err != nil || seconds < 0 {
+		seconds = def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// PolicyChangeMaxDelay is the maximum time the changes of a policy can be held by the cooldown, however often the
+// policy keeps changing. Never less than the cooldown.
+var PolicyChangeMaxDelay = policyChangeMaxDelayFromEnv(300)
+
+func policyChangeMaxDelayFromEnv(def int) time.Duration {
+	seconds, err := strconv.Atoi(common.FetchEnv("POLICY_CHANGE_MAX_DELAY_SECONDS", strconv.Itoa(def)))
+	if err != nil || seconds < 0 {
+		seconds = def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// policyChangeMaxDelay returns the maximum time the changes of a policy can be held, or 0 if the cooldown is disabled
+func policyChangeMaxDelay() time.Duration {
+	if PolicyChangeMaxDelay < PolicyChangeCooldown {
+		return PolicyChangeCooldown
+	}
+	if PolicyChangeCooldown == 0 {
+		return 0
+	}
+	return PolicyChangeMaxDelay
+}
+
+// authPolicyChanges and rateLimitPolicyChanges hold, per policy, the last generation seen not applied yet
+var (
+	authPolicyChanges      = &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+	rateLimitPolicyChanges = &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+)
+
+type policyChange struct {
+	generation   int64
+	changedAt    time.Time
+	pendingSince time.Time
+}
+
+type policyChangesTracker struct {
+	mu      sync.Mutex
+	changes map[client.ObjectKey]policyChange
+}
+
+// Observe returns the time the policy was first seen at the given generation, reset by every new generation, and the
+// time the changes of the policy have been pending since, which is not. The latter is initialized with pendingSince,
+// if known, e.g. from the status of the policy after a restart of the operator.
+func (t *policyChangesTracker) Observe(key client.ObjectKey, generation int64, now, pendingSince time.Time) (time.Time, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	change, ok := t.changes[key]
+	if !ok {
+		if pendingSince.IsZero() || pendingSince.After(now) {
+			pendingSince = now
+		}
+		change = policyChange{generation: generation, changedAt: now, pendingSince: pendingSince}
+		t.changes[key] = change
+	} else if change.generation != generation {
+		change.generation = generation
+		change.changedAt = now
+		t.changes[key] = change
+	}
+	return change.changedAt, change.pendingSince
+}
+
+// Forget forgets the changes seen of a policy, once applied or the policy deleted
+func (t *policyChangesTracker) Forget(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.changes, key)
+}
+
+// policyChangeCooldownRemaining returns the time left before the changes of a policy are applied, i.e. until the
+// policy has been stable for the cooldown, or pending for the max delay, or 0 if the changes can be applied now. The
+// policies never applied before, and the generations already applied, are not delayed. The time the changes have
+// been pending since survives restarts of the operator as the last transition of the ChangePending condition.
+func policyChangeCooldownRemaining(tracker *policyChangesTracker, policy client.Object, observedGeneration int64, conditions []metav1.Condition) (time.Time, time.Duration) {
+	key := client.ObjectKeyFromObject(policy)
+	if PolicyChangeCooldown == 0 || observedGeneration == 0 || policy.GetGeneration() == observedGeneration {
+		tracker.Forget(key)
+		return time.Time{}, 0
+	}
+
+	var pendingSince time.Time
+	if cond := meta.FindStatusCondition(conditions, PolicyChangePendingConditionType); cond != nil && cond.Status == metav1.ConditionTrue {
+		pendingSince = cond.LastTransitionTime.Time
+	}
+
+	now := time.Now()
+	changedAt, pendingSince := tracker.Observe(key, policy.GetGeneration(), now, pendingSince)
+	applyAt := changedAt.Add(PolicyChangeCooldown)
+	if maxApplyAt := pendingSince.Add(policyChangeMaxDelay()); maxApplyAt.Before(applyAt) {
+		applyAt = maxApplyAt
+	}
+	if !applyAt.After(now) {
+		return applyAt, 0
+	}
+	return applyAt, applyAt.Sub(now)
+}
+
+// updateChangePendingStatus sets the condition of the changes of a policy pending in its status, leaving the
+// observed generation as is, as the changes are not applied yet
+func updateChangePendingStatus(ctx context.Context, r *reconcilers.BaseReconciler, policy client.Object, conditions *[]metav1.Condition, applyAt time.Time) error {
+	current, _ := common.ConditionMarshal(*conditions)
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:   PolicyChangePendingConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: "CoolingDown",
+		// the message is stable for a given generation, not to update the status at every reconciliation
+		Message: fmt.Sprintf("generation %d is applied at %s if not changed again", policy.GetGeneration(), applyAt.UTC().Format(time.RFC3339)),
+	})
+	desired, _ := common.ConditionMarshal(*conditions)
+	if string(current) == string(desired) {
+		return nil
+	}
+	if err := r.UpdateResourceStatus(ctx, policy); err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools/wasm"
+)
+
+func withPolicyChangeCooldown(cooldown, maxDelay time.Duration) func() {
+	currentCooldown, currentMaxDelay := PolicyChangeCooldown, PolicyChangeMaxDelay
+	PolicyChangeCooldown, PolicyChangeMaxDelay = cooldown, maxDelay
+	return func() {
+		PolicyChangeCooldown, PolicyChangeMaxDelay = currentCooldown, currentMaxDelay
+	}
+}
+
+func TestPolicyChangeCooldownRemaining(t *testing.T) {
+	defer withPolicyChangeCooldown(time.Minute, 5*time.Minute)()
+
+	newPolicy := func(generation, observedGeneration int64) *kuadrantv1beta2.RateLimitPolicy {
+		rlp := testRateLimitPolicy("rlp", testGateway("gw"), 10)
+		rlp.Generation = generation
+		rlp.Status.ObservedGeneration = observedGeneration
+		return rlp
+	}
+
+	t.Run("policy never applied", func(subT *testing.T) {
+		tracker := &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+		if _, remaining := policyChangeCooldownRemaining(tracker, newPolicy(1, 0), 0, nil); remaining != 0 {
+			subT.Errorf("expected no cooldown, got %s", remaining)
+		}
+	})
+
+	t.Run("generation applied", func(subT *testing.T) {
+		tracker := &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+		rlp := newPolicy(2, 2)
+		if _, remaining := policyChangeCooldownRemaining(tracker, rlp, 2, nil); remaining != 0 {
+			subT.Errorf("expected no cooldown, got %s", remaining)
+		}
+		if len(tracker.changes) != 0 {
+			subT.Errorf("expected the policy to be forgotten, got %v", tracker.changes)
+		}
+	})
+
+	t.Run("cooldown disabled", func(subT *testing.T) {
+		defer withPolicyChangeCooldown(0, 5*time.Minute)()
+		tracker := &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+		if _, remaining := policyChangeCooldownRemaining(tracker, newPolicy(2, 1), 1, nil); remaining != 0 {
+			subT.Errorf("expected no cooldown, got %s", remaining)
+		}
+	})
+
+	t.Run("generation changed", func(subT *testing.T) {
+		tracker := &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+		_, remaining := policyChangeCooldownRemaining(tracker, newPolicy(2, 1), 1, nil)
+		if remaining <= 0 || remaining > time.Minute {
+			subT.Errorf("expected the cooldown, got %s", remaining)
+		}
+	})
+
+	t.Run("changes pending for the max delay", func(subT *testing.T) {
+		rlp := newPolicy(7, 1)
+		now := time.Now()
+		tracker := &policyChangesTracker{changes: map[client.ObjectKey]policyChange{
+			client.ObjectKeyFromObject(rlp): {generation: 6, changedAt: now.Add(-30 * time.Second), pendingSince: now.Add(-5 * time.Minute)},
+		}}
+		if applyAt, remaining := policyChangeCooldownRemaining(tracker, rlp, 1, nil); remaining != 0 {
+			subT.Errorf("expected the changes to be applied after the max delay, got %s (at %s)", remaining, applyAt)
+		}
+	})
+
+	t.Run("changes pending before a restart", func(subT *testing.T) {
+		tracker := &policyChangesTracker{changes: make(map[client.ObjectKey]policyChange)}
+		pendingSince := time.Now().Add(-4*time.Minute - 30*time.Second)
+		conditions := []metav1.Condition{{
+			Type:               PolicyChangePendingConditionType,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(pendingSince),
+		}}
+		_, remaining := policyChangeCooldownRemaining(tracker, newPolicy(3, 1), 1, conditions)
+		if remaining <= 0 || remaining > 30*time.Second {
+			subT.Errorf("expected the changes to be applied at the max delay since pending before the restart, got %s", remaining)
+		}
+	})
+
+	t.Run("max delay less than the cooldown", func(subT *testing.T) {
+		defer withPolicyChangeCooldown(time.Minute, time.Second)()
+		if maxDelay := policyChangeMaxDelay(); maxDelay != time.Minute {
+			subT.Errorf("expected the max delay to be the cooldown, got %s", maxDelay)
+		}
+	})
+}
+
+func TestRateLimitPolicyChangePendingSiblings(t *testing.T) {
+	defer withPolicyChangeCooldown(time.Minute, 5*time.Minute)()
+
+	gw := testGateway("gw")
+	route := testHTTPRoute("route", gw, "api.example.com")
+	gwRLP := testRateLimitPolicy("gw-rlp", gw, 100)
+	routeRLP := testRateLimitPolicy("route-rlp", route, 10)
+	// the policy changed, its changes held by the cooldown
+	routeRLP.Generation = 2
+	routeRLP.Spec.FailureMode = kuadrantv1beta2.FailClosed
+	defer rateLimitPolicyChanges.Forget(client.ObjectKeyFromObject(routeRLP))
+
+	configuredRouteRLP := routeRLP.DeepCopy()
+	configuredRouteRLP.Spec.Limits["global"] = kuadrantv1beta2.Limit{Rates: []kuadrantv1beta2.Rate{{Limit: 5, Duration: 1, Unit: "minute"}}}
+
+	r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route, gwRLP, routeRLP)}
+	rlpRefs := []client.ObjectKey{client.ObjectKeyFromObject(gwRLP), client.ObjectKeyFromObject(routeRLP)}
+	ctx := context.TODO()
+
+	t.Run("limits", func(subT *testing.T) {
+		index, err := r.buildRateLimitIndex(ctx, rlpRefs, testRateLimitPolicyLimits(configuredRouteRLP, gw))
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if limits, _ := index.Get(client.ObjectKeyFromObject(gwRLP)); !rlptools.Equal(limits, testRateLimitPolicyLimits(gwRLP, gw)) {
+			subT.Errorf("expected the limits of the gateway policy to be applied, got %v", limits)
+		}
+		if limits, _ := index.Get(client.ObjectKeyFromObject(routeRLP)); !rlptools.Equal(limits, testRateLimitPolicyLimits(configuredRouteRLP, gw)) {
+			subT.Errorf("expected the limits configured of the policy changed to be kept, got %v", limits)
+		}
+	})
+
+	t.Run("wasm config", func(subT *testing.T) {
+		configuredRouteRLPConfig := wasm.RateLimitPolicy{Name: client.ObjectKeyFromObject(routeRLP).String(), Domain: "ns/route-rlp"}
+		config, err := r.wasmPluginConfig(ctx, common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}, rlpRefs, &wasm.Plugin{
+			FailureMode:       wasm.FailureModeAllow,
+			RateLimitPolicies: []wasm.RateLimitPolicy{configuredRouteRLPConfig},
+		})
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if config.FailureMode != wasm.FailureModeAllow {
+			subT.Errorf("expected the failure mode in place to be kept, got %s", config.FailureMode)
+		}
+		if len(config.RateLimitPolicies) != 1 || config.RateLimitPolicies[0].Name != configuredRouteRLPConfig.Name {
+			subT.Errorf("expected the config in place of the policy changed only, got %v", config.RateLimitPolicies)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+const PolicyExpiringConditionType string = "Expiring"
+
+// policyExpiresIn returns the time left before a policy expires, or 0 if the policy does not expire or is expired
+func policyExpiresIn(expiresAt *metav1.Time) time.Duration {
+	if expiresAt == nil {
+		return 0
+	}
+	if expiresIn := time.Until(expiresAt.Time); expiresIn > 0 {
+		return expiresIn
+	}
+	return 0
+}
+
+// deleteExpiredPolicy deletes a policy past its expiry, returning whether the policy was expired.
+// The resources of the policy are cleaned up on the reconciliation of the deletion, as for any policy deleted.
+func deleteExpiredPolicy(ctx context.Context, r *reconcilers.BaseReconciler, policy client.Object, expiresAt *metav1.Time) (bool, error) {
+	if expiresAt == nil || policyExpiresIn(expiresAt) > 0 {
+		return false, nil
+	}
+
+	logger, _ := logr.FromContext(ctx)
+	logger.Info("policy expired, deleting", "expiresAt", expiresAt.UTC().Format(time.RFC3339))
+	if err := r.DeleteResource(ctx, policy); client.IgnoreNotFound(err) != nil {
+		return true, err
+	}
+	r.EventRecorder().Eventf(policy, corev1.EventTypeNormal, "Expired", "Policy deleted on expiry at %s", expiresAt.UTC().Format(time.RFC3339))
+	return true, nil
+}
+
+// setExpiringCondition reflects the expiry of a policy in its status conditions. The message holds the absolute time
+// only, stable until the expiry, not to update the status on every reconciliation.
+func setExpiringCondition(conditions *[]metav1.Condition, expiresAt *metav1.Time) {
+	if expiresAt == nil {
+		meta.RemoveStatusCondition(conditions, PolicyExpiringConditionType)
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    PolicyExpiringConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ExpiryScheduled",
+		Message: fmt.Sprintf("The policy is deleted at %s", expiresAt.UTC().Format(time.RFC3339)),
+	})
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+func TestSetExpiringCondition(t *testing.T) {
+	expiresAt := metav1.NewTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	conditions := []metav1.Condition{}
+	setExpiringCondition(&conditions, &expiresAt)
+	cond := meta.FindStatusCondition(conditions, PolicyExpiringConditionType)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "ExpiryScheduled" {
+		t.Fatalf("expected the expiring condition, got %+v", cond)
+	}
+	if cond.Message != "The policy is deleted at 2030-01-01T00:00:00Z" {
+		t.Errorf("unexpected message: %s", cond.Message)
+	}
+
+	// the message holds no relative time, the status is left as is by the next reconciliations
+	previous := *cond
+	setExpiringCondition(&conditions, &expiresAt)
+	if cond := meta.FindStatusCondition(conditions, PolicyExpiringConditionType); *cond != previous {
+		t.Errorf("expected the condition unchanged, got %+v", cond)
+	}
+
+	setExpiringCondition(&conditions, nil)
+	if meta.FindStatusCondition(conditions, PolicyExpiringConditionType) != nil {
+		t.Error("expected the expiring condition removed")
+	}
+}
+
+func TestDeleteExpiredPolicy(t *testing.T) {
+	newPolicy := func(expiresIn time.Duration) *kuadrantv1beta2.RateLimitPolicy {
+		rlp := testRateLimitPolicy("rlp", testGateway("gw"), 10)
+		if expiresIn != 0 {
+			expiresAt := metav1.NewTime(time.Now().Add(expiresIn))
+			rlp.Spec.ExpiresAt = &expiresAt
+		}
+		return rlp
+	}
+
+	testCases := []struct {
+		name      string
+		expiresIn time.Duration
+		expired   bool
+	}{
+		{name: "no expiry", expiresIn: 0, expired: false},
+		{name: "not expired", expiresIn: time.Hour, expired: false},
+		{name: "expired", expiresIn: -time.Minute, expired: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			rlp := newPolicy(tc.expiresIn)
+			r := unitTestTargetRefReconciler(rlp).BaseReconciler
+			expired, err := deleteExpiredPolicy(context.TODO(), r, rlp, rlp.Spec.ExpiresAt)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if expired != tc.expired {
+				subT.Errorf("expected expired %t, got %t", tc.expired, expired)
+			}
+			getErr := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(rlp), &kuadrantv1beta2.RateLimitPolicy{})
+			if tc.expired != apierrors.IsNotFound(getErr) {
+				subT.Errorf("expected deleted %t, got %v", tc.expired, getErr)
+			}
+			events := r.EventRecorder().(*record.FakeRecorder).Events
+			if tc.expired && (len(events) != 1 || !strings.Contains(<-events, "Expired")) {
+				subT.Error("expected an Expired event")
+			}
+			if !tc.expired && len(events) != 0 {
+				subT.Errorf("expected no event, got %s", <-events)
+			}
+		})
+	}
+
+	// e.g. by a GitOps tool, the policy re-created past its expiry is deleted again
+	t.Run("re-created past its expiry", func(subT *testing.T) {
+		rlp := newPolicy(-time.Minute)
+		r := unitTestTargetRefReconciler(rlp).BaseReconciler
+		for i := 0; i < 2; i++ {
+			if expired, err := deleteExpiredPolicy(context.TODO(), r, rlp, rlp.Spec.ExpiresAt); err != nil || !expired {
+				subT.Fatalf("expected the policy deleted, got %t, %v", expired, err)
+			}
+			recreated := newPolicy(-time.Minute)
+			if err := r.Client().Create(context.TODO(), recreated); err != nil {
+				subT.Fatal(err)
+			}
+			rlp = recreated
+		}
+		if events := r.EventRecorder().(*record.FakeRecorder).Events; len(events) != 2 {
+			subT.Errorf("expected an Expired event per deletion, got %d", len(events))
+		}
+	})
+
+	t.Run("already deleted", func(subT *testing.T) {
+		rlp := newPolicy(-time.Minute)
+		r := unitTestTargetRefReconciler().BaseReconciler
+		if expired, err := deleteExpiredPolicy(context.TODO(), r, rlp, rlp.Spec.ExpiresAt); err != nil || !expired {
+			subT.Errorf("expected the policy expired with no error, got %t, %v", expired, err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// PolicyGatewayEventMapper is an EventHandler that maps the events of the policies to the gateways they affect,
+// i.e. the gateways listing the policies in their policy refs annotations
+type PolicyGatewayEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *PolicyGatewayEventMapper) MapAuthPolicyToGateway(obj client.Object) []reconcile.Request {
+	return m.mapToGatewayRequests(obj, &common.KuadrantAuthPolicyRefsConfig{})
+}
+
+func (m *PolicyGatewayEventMapper) MapRateLimitPolicyToGateway(obj client.Object) []reconcile.Request {
+	return m.mapToGatewayRequests(obj, &common.KuadrantRateLimitPolicyRefsConfig{})
+}
+
+func (m *PolicyGatewayEventMapper) mapToGatewayRequests(obj client.Object, policyRefsConfig common.PolicyRefsConfig) []reconcile.Request {
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := m.Client.List(context.TODO(), gwList); err != nil {
+		m.Logger.V(1).Info("mapToGatewayRequests: failed to list gateways", "error", err)
+		return []reconcile.Request{}
+	}
+
+	policyKey := client.ObjectKeyFromObject(obj)
+	requests := make([]reconcile.Request, 0)
+	for idx := range gwList.Items {
+		gw := common.GatewayWrapper{Gateway: &gwList.Items[idx], PolicyRefsConfig: policyRefsConfig}
+		if !common.ContainsObjectKey(gw.PolicyRefs(), policyKey) {
+			continue
+		}
+		m.Logger.V(1).Info("mapToGatewayRequests", "policy", policyKey, "gateway", gw.Key())
+		requests = append(requests, reconcile.Request{NamespacedName: gw.Key()})
+	}
+
+	return requests
+}
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// ReconcileStalledConditionType is the condition of a policy the generation of which has not been reconciled for
+// longer than the stall threshold
+const ReconcileStalledConditionType string = "ReconcileStalled"
+
+// PolicyReconcileStallThreshold is the time the generation of a policy can stay ahead of its observed generation
+// before the reconciliation of the policy is flagged as stalled, on top of the max delay of the policy changes. 0
+// disables the detection.
+var PolicyReconcileStallThreshold = policyReconcileStallThresholdFromEnv(600)
+
+func policyReconcileStallThresholdFromEnv(def int) time.Duration {
+	seconds, err := strconv.Atoi(common.FetchEnv("POLICY_RECONCILE_STALL_THRESHOLD_SECONDS", strconv.Itoa(def)))
+	if err != nil || seconds < 0 {
+		seconds = def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+type reconcileStall struct {
+	generation int64
+	since      time.Time
+	flagged    bool
+}
+
+// reconcileStallTracker tracks, per policy, the generation not reconciled yet, with the time it was first seen at
+type reconcileStallTracker struct {
+	mu     sync.Mutex
+	stalls map[client.ObjectKey]reconcileStall
+}
+
+// Observe returns the time the policy was first seen at the given generation not reconciled
+func (t *reconcileStallTracker) Observe(key client.ObjectKey, generation int64, now time.Time) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stall, ok := t.stalls[key]
+	if !ok || stall.generation != generation {
+		stall = reconcileStall{generation: generation, since: now}
+		t.stalls[key] = stall
+	}
+	return stall.since
+}
+
+// Flag flags the generation of the policy observed last as stalled. Returns false if already flagged.
+func (t *reconcileStallTracker) Flag(key client.ObjectKey) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stall, ok := t.stalls[key]
+	if !ok || stall.flagged {
+		return false
+	}
+	stall.flagged = true
+	t.stalls[key] = stall
+	return true
+}
+
+// Forget forgets the policy, once its generation is reconciled or the policy deleted
+func (t *reconcileStallTracker) Forget(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.stalls, key)
+}
+
+// reconcileStallDetector flags the policies of a kind the generation of which stays ahead of their observed
+// generation, after every reconciliation of the wrapped reconciler, whatever its outcome. The stalls caused by errors
+// not surfacing otherwise, e.g. before the status is reconciled or failing to update it, are then reported in the
+// status of the policies and by an event, and counted per kind by the kuadrant_policy_reconcile_stalls_total metric.
+type reconcileStallDetector[T client.Object] struct {
+	reconcile.Reconciler
+	kind      string
+	client    client.Client
+	recorder  record.EventRecorder
+	stalls    *reconcileStallTracker
+	newPolicy func() T
+	status    func(T) (int64, *[]metav1.Condition)
+}
+
+func withReconcileStallDetector[T client.Object](kind string, cl client.Client, recorder record.EventRecorder, newPolicy func() T, status func(T) (int64, *[]metav1.Condition), r reconcile.Reconciler) reconcile.Reconciler {
+	if PolicyReconcileStallThreshold == 0 {
+		return r
+	}
+	return &reconcileStallDetector[T]{
+		Reconciler: r,
+		kind:       kind,
+		client:     cl,
+		recorder:   recorder,
+		stalls:     &reconcileStallTracker{stalls: make(map[client.ObjectKey]reconcileStall)},
+		newPolicy:  newPolicy,
+		status:     status,
+	}
+}
+
+func (d *reconcileStallDetector[T]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := d.Reconciler.Reconcile(ctx, req)
+
+	logger, _ := logr.FromContext(ctx)
+	remaining, detectErr := d.detect(ctx, req.NamespacedName)
+	if detectErr != nil {
+		logger.V(1).Info("failed to check whether the reconciliation of the policy is stalled", "kind", d.kind, "err", detectErr)
+	}
+
+	// the policy is checked again once the threshold is reached, even if not changed in the meantime
+	if remaining > 0 && (result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+		result.RequeueAfter = remaining
+	}
+
+	return result, err
+}
+
+// detect flags the policy as stalled if its generation has not been reconciled for longer than the threshold.
+// Returns the time left before the threshold is reached, or 0.
+func (d *reconcileStallDetector[T]) detect(ctx context.Context, key client.ObjectKey) (time.Duration, error) {
+	policy := d.newPolicy()
+	if err := d.client.Get(ctx, key, policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			d.stalls.Forget(key)
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	observedGeneration, conditions := d.status(policy)
+	if policy.GetDeletionTimestamp() != nil || policy.GetGeneration() == observedGeneration {
+		d.stalls.Forget(key)
+		return 0, nil
+	}
+
+	now := time.Now()
+	since := d.stalls.Observe(key, policy.GetGeneration(), now)
+	// the changes held by the cooldown are not stalled
+	stalledAt := since.Add(policyChangeMaxDelay() + PolicyReconcileStallThreshold)
+	if stalledAt.After(now) {
+		return stalledAt.Sub(now), nil
+	}
+
+	message := fmt.Sprintf("generation %d not reconciled since %s, observed generation is %d", policy.GetGeneration(), since.UTC().Format(time.RFC3339), observedGeneration)
+	if d.stalls.Flag(key) {
+		policyReconcileStalls.WithLabelValues(d.kind).Inc()
+		d.recorder.Event(policy, corev1.EventTypeWarning, "ReconcileStalled", message)
+	}
+
+	current, _ := common.ConditionMarshal(*conditions)
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    ReconcileStalledConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ObservedGenerationBehind",
+		Message: message,
+	})
+	desired, _ := common.ConditionMarshal(*conditions)
+	if string(current) == string(desired) {
+		return 0, nil
+	}
+	if err := d.client.Status().Update(ctx, policy); err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	return 0, nil
+}
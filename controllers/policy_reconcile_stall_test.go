@@ -0,0 +1,133 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+func TestReconcileStallDetector(t *testing.T) {
+	currentThreshold, currentCooldown := PolicyReconcileStallThreshold, PolicyChangeCooldown
+	PolicyReconcileStallThreshold, PolicyChangeCooldown = time.Minute, 0
+	defer func() {
+		PolicyReconcileStallThreshold, PolicyChangeCooldown = currentThreshold, currentCooldown
+	}()
+
+	rlp := testRateLimitPolicy("rlp", testGateway("gw"), 10)
+	rlp.Generation = 2
+	key := client.ObjectKeyFromObject(rlp)
+	req := ctrl.Request{NamespacedName: key}
+	ctx := context.TODO()
+
+	cl := fake.NewClientBuilder().WithScheme(unitTestScheme()).WithObjects(rlp).Build()
+	recorder := record.NewFakeRecorder(10)
+	// the wrapped reconciler fails before reconciling the status of the policy
+	detector := withReconcileStallDetector("RateLimitPolicy", cl, recorder,
+		func() *kuadrantv1beta2.RateLimitPolicy { return &kuadrantv1beta2.RateLimitPolicy{} },
+		func(rlp *kuadrantv1beta2.RateLimitPolicy) (int64, *[]metav1.Condition) {
+			return rlp.Status.ObservedGeneration, &rlp.Status.Conditions
+		},
+		reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		})).(*reconcileStallDetector[*kuadrantv1beta2.RateLimitPolicy])
+
+	stalledCondition := func() *metav1.Condition {
+		policy := &kuadrantv1beta2.RateLimitPolicy{}
+		if err := cl.Get(ctx, key, policy); err != nil {
+			t.Fatal(err)
+		}
+		return meta.FindStatusCondition(policy.Status.Conditions, ReconcileStalledConditionType)
+	}
+
+	result, err := detector.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Minute {
+		t.Errorf("expected the policy to be checked again once the threshold is reached, got %s", result.RequeueAfter)
+	}
+	if cond := stalledCondition(); cond != nil {
+		t.Errorf("expected the policy not to be flagged before the threshold, got %v", cond)
+	}
+
+	// the threshold is reached
+	detector.stalls.stalls[key] = reconcileStall{generation: 2, since: time.Now().Add(-2 * time.Minute)}
+	stalls := testutil.ToFloat64(policyReconcileStalls.WithLabelValues("RateLimitPolicy"))
+
+	for i := 0; i < 2; i++ {
+		if result, err = detector.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		if result.RequeueAfter != 0 {
+			t.Errorf("expected no requeue once flagged, got %s", result.RequeueAfter)
+		}
+	}
+	if cond := stalledCondition(); cond == nil || cond.Status != metav1.ConditionTrue || !strings.Contains(cond.Message, "generation 2 not reconciled") {
+		t.Errorf("expected the policy to be flagged as stalled, got %v", cond)
+	}
+	if count := testutil.ToFloat64(policyReconcileStalls.WithLabelValues("RateLimitPolicy")); count != stalls+1 {
+		t.Errorf("expected the stall to be counted once per generation, got %v", count-stalls)
+	}
+	if len(recorder.Events) != 1 || !strings.Contains(<-recorder.Events, "ReconcileStalled") {
+		t.Errorf("expected the stall to be reported by an event once per generation")
+	}
+
+	// the generation is reconciled
+	policy := &kuadrantv1beta2.RateLimitPolicy{}
+	if err := cl.Get(ctx, key, policy); err != nil {
+		t.Fatal(err)
+	}
+	policy.Status.ObservedGeneration = 2
+	if err := cl.Status().Update(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = detector.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := detector.stalls.stalls[key]; found {
+		t.Errorf("expected the policy reconciled to be forgotten")
+	}
+
+	// the policy is deleted
+	detector.stalls.Observe(key, 3, time.Now())
+	if err := cl.Delete(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = detector.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := detector.stalls.stalls[key]; found {
+		t.Errorf("expected the policy deleted to be forgotten")
+	}
+}
+
+func TestReconcileStallDetectorDisabled(t *testing.T) {
+	currentThreshold := PolicyReconcileStallThreshold
+	PolicyReconcileStallThreshold = 0
+	defer func() { PolicyReconcileStallThreshold = currentThreshold }()
+
+	r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, nil })
+	detector := withReconcileStallDetector("RateLimitPolicy", nil, nil,
+		func() *kuadrantv1beta2.RateLimitPolicy { return &kuadrantv1beta2.RateLimitPolicy{} },
+		func(rlp *kuadrantv1beta2.RateLimitPolicy) (int64, *[]metav1.Condition) {
+			return rlp.Status.ObservedGeneration, &rlp.Status.Conditions
+		},
+		r)
+	if _, ok := detector.(*reconcileStallDetector[*kuadrantv1beta2.RateLimitPolicy]); ok {
+		t.Errorf("expected the reconciler not to be wrapped when the detection is disabled")
+	}
+}
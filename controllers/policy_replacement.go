@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// PolicyReplacementConditionType is the condition of a policy replacing another, and of the policy replaced, which
+// stays enforced until its replacement is
+const PolicyReplacementConditionType string = "Replacement"
+
+// policyReplacementRequeueDelay is the delay before checking again whether the replacement of a policy deleted is enforced
+const policyReplacementRequeueDelay = 5 * time.Second
+
+// PolicyReplacementTimeout is the time a policy deleted stays enforced awaiting the enforcement of its replacement,
+// e.g. never enforced if invalid, after which the policy is deleted anyway. 0 waits for the replacement forever.
+var PolicyReplacementTimeout = policyReplacementTimeoutFromEnv(600)
+
+func policyReplacementTimeoutFromEnv(def int) time.Duration {
+	seconds, err := strconv.Atoi(common.FetchEnv("POLICY_REPLACEMENT_TIMEOUT_SECONDS", strconv.Itoa(def)))
+	if err != nil || seconds < 0 {
+		seconds = def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// policyReplacementDeadline returns the time a policy deleted stops awaiting the enforcement of its replacement at,
+// counted from its deletion, or the zero time if the policy waits forever
+func policyReplacementDeadline(policy client.Object) time.Time {
+	if PolicyReplacementTimeout == 0 || policy.GetDeletionTimestamp() == nil {
+		return time.Time{}
+	}
+	return policy.GetDeletionTimestamp().Add(PolicyReplacementTimeout)
+}
+
+// policyReplacementRequeueAfter returns the delay before checking again whether the replacement of a policy deleted
+// is enforced, or 0 if the policy has waited for its replacement long enough
+func policyReplacementRequeueAfter(policy client.Object) time.Duration {
+	deadline := policyReplacementDeadline(policy)
+	if deadline.IsZero() {
+		return policyReplacementRequeueDelay
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0
+	}
+	if remaining < policyReplacementRequeueDelay {
+		return remaining
+	}
+	return policyReplacementRequeueDelay
+}
+
+// replacedPolicyKey returns the key of the policy of the same kind and namespace a policy replaces, set by the
+// kuadrant.io/replaces annotation
+func replacedPolicyKey(policy client.Object) (client.ObjectKey, bool) {
+	name := policy.GetAnnotations()[common.PolicyReplacesAnnotation]
+	if name == "" || name == policy.GetName() {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: policy.GetNamespace(), Name: name}, true
+}
+
+// policyReplacementKeys returns, among the policies of the namespace of a policy, the key of the policy it replaces,
+// if still existing, and of the policy replacing it, if any
+func policyReplacementKeys(ctx context.Context, cl client.Client, policy client.Object, list client.ObjectList) (replaced, replacement *client.ObjectKey, err error) {
+	if err := cl.List(ctx, list, client.InNamespace(policy.GetNamespace())); err != nil {
+		return nil, nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policyKey := client.ObjectKeyFromObject(policy)
+	replacedKey, replacing := replacedPolicyKey(policy)
+	for _, item := range items {
+		other, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		otherKey := client.ObjectKeyFromObject(other)
+		if replacing && otherKey == replacedKey {
+			replaced = &otherKey
+		}
+		// a replacement deleted in turn no longer replaces the policy
+		if key, ok := replacedPolicyKey(other); ok && key == policyKey && other.GetDeletionTimestamp() == nil {
+			replacement = &otherKey
+		}
+	}
+	return replaced, replacement, nil
+}
+
+// setReplacementCondition reflects in the status conditions of a policy its replacement by another policy, or the
+// replacement of another policy by it
+func setReplacementCondition(conditions *[]metav1.Condition, policy client.Object, replaced, replacement *client.ObjectKey) {
+	cond := metav1.Condition{Type: PolicyReplacementConditionType, Status: metav1.ConditionTrue}
+	switch {
+	case replacement != nil && policy.GetDeletionTimestamp() != nil:
+		cond.Reason = "AwaitingReplacement"
+		cond.Message = fmt.Sprintf("The policy is deleted once its replacement %s is enforced", replacement)
+		if deadline := policyReplacementDeadline(policy); !deadline.IsZero() {
+			cond.Message = fmt.Sprintf("%s, or at %s at the latest", cond.Message, deadline.UTC().Format(time.RFC3339))
+		}
+	case replacement != nil:
+		cond.Reason = "ReplacedBy"
+		cond.Message = fmt.Sprintf("The policy is replaced by %s and no longer reconciled. Delete the policy to complete the replacement", replacement)
+	case replaced != nil:
+		cond.Reason = "ReplacingPolicy"
+		cond.Message = fmt.Sprintf("The policy replaces %s, enforced until this policy is", replaced)
+	default:
+		meta.RemoveStatusCondition(conditions, PolicyReplacementConditionType)
+		return
+	}
+	meta.SetStatusCondition(conditions, cond)
+}
+
+// updateAwaitingReplacementStatus reflects in the status of a policy deleted that it stays enforced until its
+// replacement is, the status not being reconciled otherwise once the policy is deleted
+func updateAwaitingReplacementStatus(ctx context.Context, r *reconcilers.BaseReconciler, policy client.Object, conditions *[]metav1.Condition, replacement client.ObjectKey) error {
+	current, _ := common.ConditionMarshal(*conditions)
+	setReplacementCondition(conditions, policy, nil, &replacement)
+	desired, _ := common.ConditionMarshal(*conditions)
+	if string(current) == string(desired) {
+		return nil
+	}
+	if err := r.UpdateResourceStatus(ctx, policy); err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// targetTakenOver tells whether the policy replacing another holds the back reference of the target network
+// object of the replaced policy, i.e. the target is enforced by the replacement
+func targetTakenOver(targetNetworkObject client.Object, annotationName string, replacement client.ObjectKey) bool {
+	if targetNetworkObject == nil {
+		return true
+	}
+	return common.ReadAnnotationsFromObject(targetNetworkObject)[annotationName] == replacement.String()
+}
+
+// authPolicyReplacementEnforced tells whether the AuthPolicy replacing another is enforced, i.e. holds the target
+// and its AuthConfig is ready for the current generation of the policy, but for the hosts still linked by Authorino
+// to the AuthConfig of the replaced policy
+func (r *AuthPolicyReconciler) authPolicyReplacementEnforced(ctx context.Context, replacementKey client.ObjectKey, targetNetworkObject client.Object) (bool, error) {
+	if !targetTakenOver(targetNetworkObject, common.AuthPolicyBackRefAnnotation, replacementKey) {
+		return false, nil
+	}
+
+	replacement := &kuadrantv1beta1.AuthPolicy{}
+	if err := r.Client().Get(ctx, replacementKey, replacement); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	authConfig, err := r.policyAuthConfig(ctx, replacement)
+	if err != nil || authConfig == nil {
+		return false, err
+	}
+	if authConfig.Annotations[common.AuthPolicyGenerationAnnotation] != strconv.FormatInt(replacement.Generation, 10) {
+		return false, nil
+	}
+	for _, cond := range authConfig.Status.Conditions {
+		if cond.Type == authorinoapi.StatusConditionReady {
+			return cond.Status == corev1.ConditionTrue || cond.Reason == authorinoapi.StatusReasonHostsNotLinked, nil
+		}
+	}
+	return false, nil
+}
+
+// policyAuthConfig returns the AuthConfig of an AuthPolicy, or nil if not found
+func (r *AuthPolicyReconciler) policyAuthConfig(ctx context.Context, ap *kuadrantv1beta1.AuthPolicy) (*authorinoapi.AuthConfig, error) {
+	namespace, err := r.authConfigNamespace(ctx, ap)
+	if err != nil {
+		return nil, err
+	}
+	authConfig := &authorinoapi.AuthConfig{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Namespace: namespace, Name: authConfigName(client.ObjectKeyFromObject(ap))}, authConfig); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return authConfig, nil
+}
+
+// relinkReplacementAuthConfig annotates the AuthConfig of the AuthPolicy replacing another once the AuthConfig of
+// the replaced policy is deleted, for Authorino to link the hosts released, not retrying on its own the hosts taken
+func (r *AuthPolicyReconciler) relinkReplacementAuthConfig(ctx context.Context, replacementKey, replacedKey client.ObjectKey) error {
+	replacement := &kuadrantv1beta1.AuthPolicy{}
+	if err := r.Client().Get(ctx, replacementKey, replacement); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	authConfig, err := r.policyAuthConfig(ctx, replacement)
+	if err != nil || authConfig == nil {
+		return err
+	}
+	annotations := common.ReadAnnotationsFromObject(authConfig)
+	if annotations[common.PolicyReplacesAnnotation] == replacedKey.Name {
+		return nil
+	}
+	annotations[common.PolicyReplacesAnnotation] = replacedKey.Name
+	authConfig.SetAnnotations(annotations)
+	return r.UpdateResource(ctx, authConfig)
+}
+
+// rateLimitPolicyReplacementEnforced tells whether the RateLimitPolicy replacing another is enforced, i.e. holds the
+// target and is available for its current generation
+func (r *RateLimitPolicyReconciler) rateLimitPolicyReplacementEnforced(ctx context.Context, replacementKey client.ObjectKey, targetNetworkObject client.Object) (bool, error) {
+	if !targetTakenOver(targetNetworkObject, common.RateLimitPolicyBackRefAnnotation, replacementKey) {
+		return false, nil
+	}
+
+	replacement := &kuadrantv1beta2.RateLimitPolicy{}
+	if err := r.Client().Get(ctx, replacementKey, replacement); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return policyEnforced(replacement, replacement.Status.ObservedGeneration, replacement.Status.Conditions), nil
+}
+
+// PolicyReplacementEventMapper maps the events of a policy to the policy it replaces and to the policies replacing it
+type PolicyReplacementEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *PolicyReplacementEventMapper) MapAuthPolicyToReplacementPolicies(obj client.Object) []reconcile.Request {
+	return m.mapToReplacementPolicies(obj, &kuadrantv1beta1.AuthPolicyList{})
+}
+
+func (m *PolicyReplacementEventMapper) MapRateLimitPolicyToReplacementPolicies(obj client.Object) []reconcile.Request {
+	return m.mapToReplacementPolicies(obj, &kuadrantv1beta2.RateLimitPolicyList{})
+}
+
+func (m *PolicyReplacementEventMapper) mapToReplacementPolicies(obj client.Object, list client.ObjectList) []reconcile.Request {
+	requests := make([]reconcile.Request, 0)
+	if replacedKey, ok := replacedPolicyKey(obj); ok {
+		requests = append(requests, reconcile.Request{NamespacedName: replacedKey})
+	}
+
+	if err := m.Client.List(context.Background(), list, client.InNamespace(obj.GetNamespace())); err != nil {
+		m.Logger.V(1).Info("mapToReplacementPolicies: failed to list policies", "error", err)
+		return requests
+	}
+	items, _ := meta.ExtractList(list)
+	for _, item := range items {
+		other, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		if key, ok := replacedPolicyKey(other); ok && key == client.ObjectKeyFromObject(obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,133 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func withPolicyReplacementTimeout(timeout time.Duration) func() {
+	current := PolicyReplacementTimeout
+	PolicyReplacementTimeout = timeout
+	return func() {
+		PolicyReplacementTimeout = current
+	}
+}
+
+func TestPolicyReplacementRequeueAfter(t *testing.T) {
+	defer withPolicyReplacementTimeout(time.Minute)()
+
+	deletedSince := func(since time.Duration) client.Object {
+		rlp := testRateLimitPolicy("rlp", testGateway("gw"), 10)
+		deletedAt := metav1.NewTime(time.Now().Add(-since))
+		rlp.DeletionTimestamp = &deletedAt
+		return rlp
+	}
+
+	if requeueAfter := policyReplacementRequeueAfter(deletedSince(0)); requeueAfter != policyReplacementRequeueDelay {
+		t.Errorf("expected the replacement checked again in %s, got %s", policyReplacementRequeueDelay, requeueAfter)
+	}
+	if requeueAfter := policyReplacementRequeueAfter(deletedSince(58 * time.Second)); requeueAfter <= 0 || requeueAfter > 2*time.Second {
+		t.Errorf("expected the replacement checked again at the timeout, got %s", requeueAfter)
+	}
+	if requeueAfter := policyReplacementRequeueAfter(deletedSince(time.Minute)); requeueAfter != 0 {
+		t.Errorf("expected the replacement no longer awaited, got %s", requeueAfter)
+	}
+
+	defer withPolicyReplacementTimeout(0)()
+	if requeueAfter := policyReplacementRequeueAfter(deletedSince(time.Hour)); requeueAfter != policyReplacementRequeueDelay {
+		t.Errorf("expected the replacement awaited forever, got %s", requeueAfter)
+	}
+}
+
+func TestRateLimitPolicyDeletionAwaitingInvalidReplacement(t *testing.T) {
+	defer withPolicyReplacementTimeout(time.Minute)()
+
+	gw := testGateway("gw")
+	gw.Annotations = map[string]string{common.KuadrantNamespaceLabel: "kuadrant-system"}
+	route := testHTTPRoute("route", gw, "api.example.com")
+	route.Annotations = map[string]string{common.RateLimitPolicyBackRefAnnotation: "ns/rlp"}
+
+	replaced := func(deletedSince time.Duration) *kuadrantv1beta2.RateLimitPolicy {
+		rlp := testRateLimitPolicy("rlp", route, 10)
+		deletedAt := metav1.NewTime(time.Now().Add(-deletedSince).Truncate(time.Second))
+		rlp.DeletionTimestamp = &deletedAt
+		rlp.Finalizers = []string{rateLimitPolicyFinalizer}
+		return rlp
+	}
+	// invalid, the replacement never takes over the target
+	replacement := testRateLimitPolicy("rlp-v2", route, 10)
+	replacement.Annotations = map[string]string{common.PolicyReplacesAnnotation: "rlp"}
+	replacement.Spec.DefaultsStrategy = kuadrantv1beta2.MergeDefaultsStrategy
+
+	t.Run("within the timeout", func(subT *testing.T) {
+		rlp := replaced(10 * time.Second)
+		r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route.DeepCopy(), rlp, replacement.DeepCopy())}
+		result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(rlp)})
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if result.RequeueAfter != policyReplacementRequeueDelay {
+			subT.Errorf("expected the replacement checked again in %s, got %s", policyReplacementRequeueDelay, result.RequeueAfter)
+		}
+
+		existing := &kuadrantv1beta2.RateLimitPolicy{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(rlp), existing); err != nil {
+			subT.Fatal(err)
+		}
+		cond := meta.FindStatusCondition(existing.Status.Conditions, PolicyReplacementConditionType)
+		if cond == nil || cond.Reason != "AwaitingReplacement" {
+			subT.Fatalf("expected the policy awaiting its replacement, got %+v", cond)
+		}
+		deadline := rlp.DeletionTimestamp.Add(time.Minute).UTC().Format(time.RFC3339)
+		if !strings.HasSuffix(cond.Message, "at "+deadline+" at the latest") {
+			subT.Errorf("expected the deadline in the message, got %s", cond.Message)
+		}
+	})
+
+	t.Run("timed out", func(subT *testing.T) {
+		rlp := replaced(2 * time.Minute)
+		r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route.DeepCopy(), rlp, replacement.DeepCopy())}
+		result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(rlp)})
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if result.RequeueAfter != 0 {
+			subT.Errorf("expected the replacement no longer awaited, got %s", result.RequeueAfter)
+		}
+
+		existing := &kuadrantv1beta2.RateLimitPolicy{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(rlp), existing); err == nil && len(existing.Finalizers) > 0 {
+			subT.Error("expected the finalizer removed")
+		} else if err != nil && !apierrors.IsNotFound(err) {
+			subT.Fatal(err)
+		}
+
+		existingRoute := &gatewayapiv1beta1.HTTPRoute{}
+		if err := r.Client().Get(context.TODO(), client.ObjectKeyFromObject(route), existingRoute); err != nil {
+			subT.Fatal(err)
+		}
+		if _, ok := existingRoute.Annotations[common.RateLimitPolicyBackRefAnnotation]; ok {
+			subT.Error("expected the target released by the policy deleted")
+		}
+
+		events := r.EventRecorder().(*record.FakeRecorder).Events
+		if len(events) != 1 || !strings.Contains(<-events, "ReplacementTimedOut") {
+			subT.Error("expected a ReplacementTimedOut event")
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// PolicyReportPath is the path of the endpoint rendering the report of the attachment of the policies
+const PolicyReportPath = "/policy-report"
+
+// PolicyReport lists every policy of the cluster, with its target, its status and the resources generated from it
+type PolicyReport struct {
+	GeneratedAt metav1.Time         `json:"generatedAt"`
+	Policies    []PolicyReportEntry `json:"policies"`
+}
+
+// PolicyReportEntry is the report of a policy
+type PolicyReportEntry struct {
+	Kind       string                                   `json:"kind"`
+	Policy     string                                   `json:"policy"`
+	TargetRef  gatewayapiv1alpha2.PolicyTargetReference `json:"targetRef"`
+	Conditions []metav1.Condition                       `json:"conditions,omitempty"`
+	// AuthConfigs are the AuthConfigs generated from an AuthPolicy
+	AuthConfigs []string `json:"authConfigs,omitempty"`
+	// LimitsNamespaces are the namespaces of the limits of a RateLimitPolicy in Limitador
+	LimitsNamespaces []string    `json:"limitsNamespaces,omitempty"`
+	CreatedAt        metav1.Time `json:"createdAt"`
+	// LastTransitionAt is the time of the last transition of the conditions of the policy
+	LastTransitionAt *metav1.Time `json:"lastTransitionAt,omitempty"`
+}
+
+// policyReport builds the report of the AuthPolicies and the RateLimitPolicies of the cluster
+func policyReport(ctx context.Context, cli client.Client) (*PolicyReport, error) {
+	report := &PolicyReport{
+		GeneratedAt: metav1.NewTime(time.Now()),
+		Policies:    make([]PolicyReportEntry, 0),
+	}
+
+	authConfigList := &authorinov1beta1.AuthConfigList{}
+	if err := cli.List(ctx, authConfigList); err != nil {
+		return nil, err
+	}
+	authConfigs := make(map[client.ObjectKey][]string)
+	for idx := range authConfigList.Items {
+		if policyKey, ok := authConfigPolicyKey(&authConfigList.Items[idx]); ok {
+			authConfigs[policyKey] = append(authConfigs[policyKey], client.ObjectKeyFromObject(&authConfigList.Items[idx]).String())
+		}
+	}
+
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := cli.List(ctx, apList); err != nil {
+		return nil, err
+	}
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		entry := newPolicyReportEntry("AuthPolicy", ap, ap.Spec.TargetRef, ap.Status.Conditions)
+		entry.AuthConfigs = authConfigs[client.ObjectKeyFromObject(ap)]
+		report.Policies = append(report.Policies, entry)
+	}
+
+	rlpList := &kuadrantv1beta2.RateLimitPolicyList{}
+	if err := cli.List(ctx, rlpList); err != nil {
+		return nil, err
+	}
+	for idx := range rlpList.Items {
+		rlp := &rlpList.Items[idx]
+		entry := newPolicyReportEntry("RateLimitPolicy", rlp, rlp.Spec.TargetRef, rlp.Status.Conditions)
+		entry.LimitsNamespaces = rlp.Status.LimitsNamespaces
+		report.Policies = append(report.Policies, entry)
+	}
+
+	return report, nil
+}
+
+func newPolicyReportEntry(kind string, policy client.Object, targetRef gatewayapiv1alpha2.PolicyTargetReference, conditions []metav1.Condition) PolicyReportEntry {
+	entry := PolicyReportEntry{
+		Kind:       kind,
+		Policy:     client.ObjectKeyFromObject(policy).String(),
+		TargetRef:  targetRef,
+		Conditions: common.CopyConditions(conditions),
+		CreatedAt:  policy.GetCreationTimestamp(),
+	}
+	for idx := range conditions {
+		transition := conditions[idx].LastTransitionTime
+		if entry.LastTransitionAt == nil || entry.LastTransitionAt.Before(&transition) {
+			entry.LastTransitionAt = &transition
+		}
+	}
+	return entry
+}
+
+// PolicyReports renders the report of the attachment of the policies
+type PolicyReports struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewPolicyReports(c client.Client, logger logr.Logger) *PolicyReports {
+	return &PolicyReports{client: c, logger: logger}
+}
+
+// ServeHTTP renders the report from the current state of the policies in response to a GET request.
+// The requests must be authenticated with a bearer token of a subject allowed to get the path of the endpoint.
+func (p *PolicyReports) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if statusCode, err := authorizeNonResourceRequest(p.client, req, PolicyReportPath, "get"); err != nil {
+		p.logger.Info("unauthorized policy report request", "reason", err.Error())
+		http.Error(rw, http.StatusText(statusCode), statusCode)
+		return
+	}
+
+	report, err := policyReport(req.Context(), p.client)
+	if err != nil {
+		p.logger.Error(err, "failed to build the policy report")
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Content-Disposition", `inline; filename="policy-report.json"`)
+	if err := json.NewEncoder(rw).Encode(report); err != nil {
+		p.logger.Error(err, "failed to write the policy report")
+	}
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const TargetRefInvalidConditionType string = "TargetRefInvalid"
+
+// NormalizePolicyTargetRefs enables the correction of the group and the kind of the targetRefs of the policies
+// mistyped unambiguously, e.g. `httproute` instead of `HTTPRoute`, read from the NORMALIZE_POLICY_TARGETREFS env var.
+// The policies are rejected with the correct form in the status otherwise.
+var NormalizePolicyTargetRefs = normalizePolicyTargetRefsFromEnv()
+
+func normalizePolicyTargetRefsFromEnv() bool {
+	normalize, err := strconv.ParseBool(common.FetchEnv("NORMALIZE_POLICY_TARGETREFS", "false"))
+	return err == nil && normalize
+}
+
+// normalizePolicyTargetRef corrects the targetRef of a policy and updates the policy, when enabled.
+// Returns true if the policy was updated, reconciled again on the update event.
+func normalizePolicyTargetRef(ctx context.Context, cli client.Client, policy client.Object, targetRef *gatewayapiv1alpha2.PolicyTargetReference) (bool, error) {
+	if !NormalizePolicyTargetRefs {
+		return false, nil
+	}
+
+	normalized, corrected := common.NormalizeTargetRef(*targetRef)
+	if !corrected {
+		return false, nil
+	}
+
+	logger, _ := logr.FromContext(ctx)
+	logger.Info("normalizing targetRef", "group", normalized.Group, "kind", normalized.Kind)
+
+	*targetRef = normalized
+	if err := cli.Update(ctx, policy); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
+// setTargetRefInvalidCondition reflects the policies not attached because of an invalid targetRef
+func setTargetRefInvalidCondition(conditions *[]metav1.Condition, specErr error) {
+	if !common.IsInvalidTargetRef(specErr) {
+		meta.RemoveStatusCondition(conditions, TargetRefInvalidConditionType)
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    TargetRefInvalidConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "InvalidTargetRef",
+		Message: specErr.Error(),
+	})
+}
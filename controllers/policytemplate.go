@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+const (
+	TemplateResolvedConditionType string = "TemplateResolved"
+)
+
+//+kubebuilder:rbac:groups=kuadrant.io,resources=policytemplates,verbs=get;list;watch
+
+// policyTemplateError is the error of a PolicyTemplate referenced by a policy that cannot be resolved
+type policyTemplateError struct {
+	templateKey client.ObjectKey
+	err         error
+}
+
+func (e *policyTemplateError) Error() string {
+	return fmt.Sprintf("failed to resolve policy template %s: %v", e.templateKey, e.err)
+}
+
+func (e *policyTemplateError) Unwrap() error {
+	return e.err
+}
+
+func isPolicyTemplateError(err error) bool {
+	templateErr := &policyTemplateError{}
+	return errors.As(err, &templateErr)
+}
+
+// fetchPolicyTemplate returns the PolicyTemplate referenced by a policy of the given namespace,
+// or nil if the policy does not reference any template
+func fetchPolicyTemplate(ctx context.Context, cli client.Client, namespace string, templateRef *corev1.LocalObjectReference) (*kuadrantv1beta2.PolicyTemplate, error) {
+	if templateRef == nil {
+		return nil, nil
+	}
+
+	template := &kuadrantv1beta2.PolicyTemplate{}
+	templateKey := client.ObjectKey{Name: templateRef.Name, Namespace: namespace}
+	if err := cli.Get(ctx, templateKey, template); err != nil {
+		return nil, &policyTemplateError{templateKey: templateKey, err: err}
+	}
+
+	return template, nil
+}
+
+// resolveRateLimitPolicyTemplate sets the limits of the RateLimitPolicy to the limits inherited from its template
+// overridden by the limits of the policy. The RateLimitPolicy must not be written back to the cluster.
+func resolveRateLimitPolicyTemplate(ctx context.Context, cli client.Client, rlp *kuadrantv1beta2.RateLimitPolicy) error {
+	template, err := fetchPolicyTemplate(ctx, cli, rlp.Namespace, rlp.Spec.TemplateRef)
+	if err != nil || template == nil {
+		return err
+	}
+
+	rlp.Spec.Limits = template.ResolveLimits(rlp.Spec.Limits)
+	return nil
+}
+
+// setTemplateResolvedCondition reflects the resolution of the template referenced by a policy in its status conditions
+func setTemplateResolvedCondition(ctx context.Context, cli client.Client, conditions *[]metav1.Condition, namespace string, templateRef *corev1.LocalObjectReference) {
+	if templateRef == nil {
+		meta.RemoveStatusCondition(conditions, TemplateResolvedConditionType)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:   TemplateResolvedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: "TemplateResolved",
+	}
+
+	template, err := fetchPolicyTemplate(ctx, cli, namespace, templateRef)
+	switch {
+	case apierrors.IsNotFound(err):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "TemplateNotFound"
+		cond.Message = fmt.Sprintf("PolicyTemplate %s not found", templateRef.Name)
+	case err != nil:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "ReconcilliationError"
+		cond.Message = err.Error()
+	default:
+		cond.Message = fmt.Sprintf("PolicyTemplate %s resolved at generation %d", template.Name, template.Generation)
+	}
+
+	meta.SetStatusCondition(conditions, cond)
+}
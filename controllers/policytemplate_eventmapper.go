@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+// PolicyTemplateEventMapper is an EventHandler that maps PolicyTemplate events to the policies referencing the template
+type PolicyTemplateEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *PolicyTemplateEventMapper) MapToRateLimitPolicy(obj client.Object) []reconcile.Request {
+	rlpList := &kuadrantv1beta2.RateLimitPolicyList{}
+	if err := m.Client.List(context.TODO(), rlpList, client.InNamespace(obj.GetNamespace())); err != nil {
+		m.Logger.V(1).Info("MapToRateLimitPolicy: failed to list ratelimitpolicies", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range rlpList.Items {
+		rlp := &rlpList.Items[idx]
+		if rlp.Spec.TemplateRef != nil && rlp.Spec.TemplateRef.Name == obj.GetName() {
+			m.Logger.V(1).Info("MapToRateLimitPolicy", "policytemplate", client.ObjectKeyFromObject(obj), "ratelimitpolicy", client.ObjectKeyFromObject(rlp))
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rlp)})
+		}
+	}
+
+	return requests
+}
+
+func (m *PolicyTemplateEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := m.Client.List(context.TODO(), apList, client.InNamespace(obj.GetNamespace())); err != nil {
+		m.Logger.V(1).Info("MapToAuthPolicy: failed to list authpolicies", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		if ap.Spec.TemplateRef != nil && ap.Spec.TemplateRef.Name == obj.GetName() {
+			m.Logger.V(1).Info("MapToAuthPolicy", "policytemplate", client.ObjectKeyFromObject(obj), "authpolicy", client.ObjectKeyFromObject(ap))
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ap)})
+		}
+	}
+
+	return requests
+}
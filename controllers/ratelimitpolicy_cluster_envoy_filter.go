@@ -92,7 +92,13 @@ func (r *RateLimitPolicyReconciler) gatewayRateLimitingClusterEnvoyFilter(ctx co
 		},
 	}
 
-	if len(rlpRefs) < 1 {
+	// the default rate limit of the kuadrant instance is enforced by the gateways without any policy as well
+	defaultRateLimit, err := gatewayDefaultRateLimit(ctx, r.Client(), gw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rlpRefs) < 1 && defaultRateLimit == nil {
 		common.TagObjectToDelete(ef)
 		return ef, nil
 	}
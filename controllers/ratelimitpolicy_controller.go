@@ -21,14 +21,22 @@ import (
 	"encoding/json"
 
 	"github.com/go-logr/logr"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
 	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
@@ -39,6 +47,8 @@ const rateLimitPolicyFinalizer = "ratelimitpolicy.kuadrant.io/finalizer"
 // RateLimitPolicyReconciler reconciles a RateLimitPolicy object
 type RateLimitPolicyReconciler struct {
 	reconcilers.TargetRefReconciler
+	// ReconcileTrigger enqueues the reconciliation of the policies on demand (optional)
+	ReconcileTrigger *ReconcileTrigger
 }
 
 //+kubebuilder:rbac:groups=kuadrant.io,resources=ratelimitpolicies,verbs=get;list;watch;create;update;patch;delete
@@ -60,7 +70,7 @@ type RateLimitPolicyReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *RateLimitPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := r.Logger().WithValues("RateLimitPolicy", req.NamespacedName)
+	logger := r.Logger().WithValues("RateLimitPolicy", req.NamespacedName, "reconcileID", controller.ReconcileIDFromContext(eventCtx))
 	logger.Info("Reconciling RateLimitPolicy")
 	ctx := logr.NewContext(eventCtx, logger)
 
@@ -85,6 +95,28 @@ func (r *RateLimitPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl
 
 	markedForDeletion := rlp.GetDeletionTimestamp() != nil
 
+	if !markedForDeletion {
+		// the deletion of the policy triggers a new reconciliation, cleaning up its resources
+		if expired, err := deleteExpiredPolicy(ctx, r.BaseReconciler, rlp, rlp.Spec.ExpiresAt); err != nil || expired {
+			return ctrl.Result{}, err
+		}
+
+		// the update of the policy triggers a new reconciliation
+		if updated, err := normalizePolicyTargetRef(ctx, r.Client(), rlp, &rlp.Spec.TargetRef); err != nil || updated {
+			return ctrl.Result{}, err
+		}
+
+		// a policy with an invalid targetRef is not attached to any network object
+		if err := common.ValidateTargetRef(rlp.GetTargetRef()); err != nil {
+			logger.V(1).Info("Invalid targetRef. Cleaning up", "error", err.Error())
+			delResErr := r.deleteResources(ctx, rlp, nil)
+			if delResErr == nil {
+				delResErr = err
+			}
+			return r.reconcileStatus(ctx, rlp, delResErr)
+		}
+	}
+
 	// fetch the target network object
 	targetNetworkObject, err := r.FetchValidTargetRef(ctx, rlp.GetTargetRef(), rlp.Namespace)
 	if err != nil {
@@ -107,6 +139,27 @@ func (r *RateLimitPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl
 		if controllerutil.ContainsFinalizer(rlp, rateLimitPolicyFinalizer) {
 			logger.V(1).Info("Handling removal of ratelimitpolicy object")
 
+			// the policy replaced stays enforced until its replacement is
+			_, replacement, err := policyReplacementKeys(ctx, r.Client(), rlp, &kuadrantv1beta2.RateLimitPolicyList{})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if replacement != nil {
+				enforced, err := r.rateLimitPolicyReplacementEnforced(ctx, *replacement, targetNetworkObject)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if !enforced {
+					if requeueAfter := policyReplacementRequeueAfter(rlp); requeueAfter > 0 {
+						logger.V(1).Info("deletion pending the enforcement of the replacement", "replacement", replacement)
+						return ctrl.Result{RequeueAfter: requeueAfter}, updateAwaitingReplacementStatus(ctx, r.BaseReconciler, rlp, &rlp.Status.Conditions, *replacement)
+					}
+					// the replacement, e.g. invalid, is not awaited any longer
+					logger.Info("replacement not enforced in time, deleting the policy", "replacement", replacement)
+					r.EventRecorder().Eventf(rlp, corev1.EventTypeWarning, "ReplacementTimedOut", "Replacement %s not enforced within %s, the policy is deleted", replacement, PolicyReplacementTimeout)
+				}
+			}
+
 			if err := r.deleteResources(ctx, rlp, targetNetworkObject); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -117,6 +170,8 @@ func (r *RateLimitPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl
 			}
 		}
 
+		rateLimitPolicyChanges.Forget(client.ObjectKeyFromObject(rlp))
+
 		return ctrl.Result{}, nil
 	}
 
@@ -128,6 +183,20 @@ func (r *RateLimitPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl
 		}
 	}
 
+	// the policy replaced is left enforced as is until deleted, not to compete with its replacement for the target
+	if _, replacement, err := policyReplacementKeys(ctx, r.Client(), rlp, &kuadrantv1beta2.RateLimitPolicyList{}); err != nil {
+		return ctrl.Result{}, err
+	} else if replacement != nil {
+		logger.V(1).Info("policy replaced, skipping the reconciliation of the spec", "replacement", replacement)
+		return r.reconcileStatus(ctx, rlp, nil)
+	}
+
+	// the rapid changes of the policy are coalesced, applied once the policy is stable for the cooldown
+	if applyAt, remaining := policyChangeCooldownRemaining(rateLimitPolicyChanges, rlp, rlp.Status.ObservedGeneration, rlp.Status.Conditions); remaining > 0 {
+		logger.V(1).Info("policy changed, waiting for the cooldown before applying the changes", "generation", rlp.Generation, "remaining", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, updateChangePendingStatus(ctx, r.BaseReconciler, rlp, &rlp.Status.Conditions, applyAt)
+	}
+
 	// reconcile the ratelimitpolicy spec
 	specErr := r.reconcileResources(ctx, rlp, targetNetworkObject)
 
@@ -148,7 +217,8 @@ func (r *RateLimitPolicyReconciler) Reconcile(eventCtx context.Context, req ctrl
 	}
 
 	logger.Info("RateLimitPolicy reconciled successfully")
-	return ctrl.Result{}, nil
+	// reconciled again on expiry
+	return ctrl.Result{RequeueAfter: policyExpiresIn(rlp.Spec.ExpiresAt)}, nil
 }
 
 func (r *RateLimitPolicyReconciler) reconcileResources(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, targetNetworkObject client.Object) error {
@@ -163,6 +233,15 @@ func (r *RateLimitPolicyReconciler) reconcileResources(ctx context.Context, rlp
 		return err
 	}
 
+	// the policy whose template cannot be resolved is not applied, the limits configured before are kept
+	if err := resolveRateLimitPolicyTemplate(ctx, r.Client(), rlp.DeepCopy()); err != nil {
+		return err
+	}
+
+	if err := r.validateSharedLimits(ctx, rlp); err != nil {
+		return err
+	}
+
 	// reconcile based on gateway diffs
 	gatewayDiffObj, err := r.ComputeGatewayDiffs(ctx, rlp, targetNetworkObject, &common.KuadrantRateLimitPolicyRefsConfig{})
 	if err != nil {
@@ -181,11 +260,23 @@ func (r *RateLimitPolicyReconciler) reconcileResources(ctx context.Context, rlp
 		return err
 	}
 
+	// a policy replacing another takes over its target
+	if replacedKey, ok := replacedPolicyKey(rlp); ok {
+		if err := r.ReplaceTargetBackReference(ctx, replacedKey, client.ObjectKeyFromObject(rlp), targetNetworkObject, common.RateLimitPolicyBackRefAnnotation); err != nil {
+			return err
+		}
+	}
+
 	// set direct back ref - i.e. claim the target network object as taken asap
 	if err := r.reconcileNetworkResourceDirectBackReference(ctx, rlp, targetNetworkObject); err != nil {
 		return err
 	}
 
+	// list the policy in the routes it affects
+	if err := r.ReconcileEffectivePoliciesAnnotation(ctx, targetNetworkObject); err != nil {
+		return err
+	}
+
 	// set annotation of policies afftecting the gateway - should be the last step, only when all the reconciliation steps succeed
 	return r.ReconcileGatewayPolicyReferences(ctx, rlp, gatewayDiffObj)
 }
@@ -214,6 +305,10 @@ func (r *RateLimitPolicyReconciler) deleteResources(ctx context.Context, rlp *ku
 		if err := r.deleteNetworkResourceDirectBackReference(ctx, rlp, targetNetworkObject); err != nil {
 			return err
 		}
+
+		if err := r.ReconcileEffectivePoliciesAnnotation(ctx, targetNetworkObject); err != nil {
+			return err
+		}
 	}
 
 	// update annotation of policies afftecting the gateway
@@ -226,6 +321,10 @@ func (r *RateLimitPolicyReconciler) reconcileNetworkResourceDirectBackReference(
 }
 
 func (r *RateLimitPolicyReconciler) deleteNetworkResourceDirectBackReference(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, targetNetworkObject client.Object) error {
+	// the target taken over by the replacement of the policy is left to it
+	if common.ReadAnnotationsFromObject(targetNetworkObject)[common.RateLimitPolicyBackRefAnnotation] != client.ObjectKeyFromObject(rlp).String() {
+		return nil
+	}
 	return r.DeleteTargetBackReference(ctx, client.ObjectKeyFromObject(rlp), targetNetworkObject, common.RateLimitPolicyBackRefAnnotation)
 }
 
@@ -241,8 +340,21 @@ func (r *RateLimitPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Logger: r.Logger().WithName("gatewayRateLimitPolicyEventMapper"),
 		Client: r.Client(),
 	}
-	return ctrl.NewControllerManagedBy(mgr).
+	policyTemplateEventMapper := &PolicyTemplateEventMapper{
+		Logger: r.Logger().WithName("policyTemplateEventMapper"),
+		Client: r.Client(),
+	}
+	kuadrantEventMapper := &KuadrantEventMapper{
+		Logger: r.Logger().WithName("kuadrantEventMapper"),
+		Client: r.Client(),
+	}
+	policyReplacementEventMapper := &PolicyReplacementEventMapper{
+		Logger: r.Logger().WithName("policyReplacementEventMapper"),
+		Client: r.Client(),
+	}
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&kuadrantv1beta2.RateLimitPolicy{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: RateLimitPolicyReconcileWorkers, RateLimiter: RateLimitPolicyReconcileRateLimiter}).
 		Watches(
 			&source.Kind{Type: &gatewayapiv1beta1.HTTPRoute{}},
 			handler.EnqueueRequestsFromMapFunc(httpRouteEventMapper.MapToRateLimitPolicy),
@@ -258,5 +370,44 @@ func (r *RateLimitPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&source.Kind{Type: &kuadrantv1beta2.RateLimitPolicy{}},
 			handler.EnqueueRequestsFromMapFunc(gatewayRateLimtPolicyEventMapper.MapRouteRateLimitPolicy),
 		).
-		Complete(r)
+		Watches(
+			&source.Kind{Type: &kuadrantv1beta2.PolicyTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(policyTemplateEventMapper.MapToRateLimitPolicy),
+		).
+		// When limitador becomes ready or loads the limits, update the status of the rlps
+		Watches(
+			&source.Kind{Type: &limitadorv1alpha1.Limitador{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToRateLimitPolicy),
+			builder.WithPredicates(limitadorReadinessChanged),
+		).
+		Watches(
+			&source.Kind{Type: &appsv1.Deployment{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToRateLimitPolicy),
+			builder.WithPredicates(limitadorReadinessChanged),
+		).
+		// the default rate limit of the kuadrant instance is part of the wasm config of the gateways without a gateway rlp,
+		// and the counter domain of the kuadrant instance sets the namespaces of the limits
+		Watches(
+			&source.Kind{Type: &kuadrantv1beta1.Kuadrant{}},
+			handler.EnqueueRequestsFromMapFunc(kuadrantEventMapper.MapToRateLimitPolicy),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// the policy replaced and its replacement reflect each other in their status
+		Watches(
+			&source.Kind{Type: &kuadrantv1beta2.RateLimitPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(policyReplacementEventMapper.MapRateLimitPolicyToReplacementPolicies),
+		)
+
+	if r.ReconcileTrigger != nil {
+		controllerBuilder = controllerBuilder.Watches(r.ReconcileTrigger.Source(&kuadrantv1beta2.RateLimitPolicyList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	stallDetector := withReconcileStallDetector("RateLimitPolicy", r.Client(), r.EventRecorder(),
+		func() *kuadrantv1beta2.RateLimitPolicy { return &kuadrantv1beta2.RateLimitPolicy{} },
+		func(rlp *kuadrantv1beta2.RateLimitPolicy) (int64, *[]metav1.Condition) {
+			return rlp.Status.ObservedGeneration, &rlp.Status.Conditions
+		},
+		r)
+
+	return controllerBuilder.Complete(withLastSuccessMetric("ratelimitpolicy", stallDetector))
 }
@@ -200,7 +200,7 @@ var _ = Describe("RateLimitPolicy controller", func() {
 			Expect(existingLimitador.Spec.Limits).To(ContainElements(limitadorv1alpha1.RateLimit{
 				MaxValue:   1,
 				Seconds:    3 * 60,
-				Namespace:  rlptools.LimitsNamespaceFromRLP(rlp),
+				Namespace:  rlptools.LimitsNamespace(client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(rlp)),
 				Conditions: []string{`limit.l1__2804bad6 == "1"`},
 				Variables:  []string{},
 			}))
@@ -227,7 +227,7 @@ var _ = Describe("RateLimitPolicy controller", func() {
 				RateLimitPolicies: []wasm.RateLimitPolicy{
 					{
 						Name:   rlpKey.String(),
-						Domain: rlptools.LimitsNamespaceFromRLP(rlp),
+						Domain: rlptools.LimitsNamespace(client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(rlp)),
 						Rules: []wasm.Rule{
 							{
 								Conditions: []wasm.Condition{
@@ -395,7 +395,7 @@ var _ = Describe("RateLimitPolicy controller", func() {
 			Expect(existingWASMConfig.RateLimitPolicies).To(HaveLen(1))
 			wasmRLP := existingWASMConfig.RateLimitPolicies[0]
 			Expect(wasmRLP.Name).To(Equal(rlpKey.String()))
-			Expect(wasmRLP.Domain).To(Equal(rlptools.LimitsNamespaceFromRLP(rlp)))
+			Expect(wasmRLP.Domain).To(Equal(rlptools.LimitsNamespace(client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(rlp))))
 			Expect(wasmRLP.Rules).To(ContainElement(wasm.Rule{ // rule to activate the 'toys' limit definition
 				Conditions: []wasm.Condition{
 					{
@@ -546,7 +546,7 @@ var _ = Describe("RateLimitPolicy controller", func() {
 			Expect(existingLimitador.Spec.Limits).To(ContainElements(limitadorv1alpha1.RateLimit{
 				MaxValue:   1,
 				Seconds:    3 * 60,
-				Namespace:  rlptools.LimitsNamespaceFromRLP(rlp),
+				Namespace:  rlptools.LimitsNamespace(client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(rlp)),
 				Conditions: []string{`limit.l1__2804bad6 == "1"`},
 				Variables:  []string{},
 			}))
@@ -573,7 +573,7 @@ var _ = Describe("RateLimitPolicy controller", func() {
 				RateLimitPolicies: []wasm.RateLimitPolicy{
 					{
 						Name:   rlpKey.String(),
-						Domain: rlptools.LimitsNamespaceFromRLP(rlp),
+						Domain: rlptools.LimitsNamespace(client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(rlp)),
 						Rules: []wasm.Rule{
 							{
 								Conditions: []wasm.Condition{
@@ -671,7 +671,7 @@ var _ = Describe("RateLimitPolicy controller", func() {
 			Expect(existingLimitador.Spec.Limits).To(ContainElements(limitadorv1alpha1.RateLimit{
 				MaxValue:   1,
 				Seconds:    3 * 60,
-				Namespace:  rlptools.LimitsNamespaceFromRLP(rlp),
+				Namespace:  rlptools.LimitsNamespace(client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(rlp)),
 				Conditions: []string{`limit.l1__2804bad6 == "1"`},
 				Variables:  []string{},
 			}))
@@ -701,6 +701,75 @@ var _ = Describe("RateLimitPolicy controller", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(existingGateway.GetAnnotations()).To(HaveKeyWithValue(common.RateLimitPoliciesBackRefAnnotation, string(serialized)))
 		})
+
+		It("Lists the RateLimitPolicy in the effective policies of the HTTPRoutes attached to the Gateway later", func() {
+			// create ratelimitpolicy
+			rlp := &kuadrantv1beta2.RateLimitPolicy{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "RateLimitPolicy",
+					APIVersion: kuadrantv1beta2.GroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      rlpName,
+					Namespace: testNamespace,
+				},
+				Spec: kuadrantv1beta2.RateLimitPolicySpec{
+					TargetRef: gatewayapiv1alpha2.PolicyTargetReference{
+						Group: gatewayapiv1beta1.Group("gateway.networking.k8s.io"),
+						Kind:  "Gateway",
+						Name:  gatewayapiv1beta1.ObjectName(gwName),
+					},
+					Limits: map[string]kuadrantv1beta2.Limit{
+						"l1": {
+							Rates: []kuadrantv1beta2.Rate{
+								{
+									Limit: 1, Duration: 3, Unit: kuadrantv1beta2.TimeUnit("minute"),
+								},
+							},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(context.Background(), rlp)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Check RLP status is available
+			rlpKey := client.ObjectKey{Name: rlpName, Namespace: testNamespace}
+			Eventually(testRLPIsAvailable(rlpKey), time.Minute, 5*time.Second).Should(BeTrue())
+
+			// create httproute, attached once the policy is reconciled
+			httpRoute := testBuildBasicHttpRoute(routeName, gwName, testNamespace, []string{"*.example.com"})
+			err = k8sClient.Create(context.Background(), httpRoute)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Check the effective policies of the HTTPRoute
+			routeKey := client.ObjectKeyFromObject(httpRoute)
+			Eventually(func() map[string]string {
+				existingRoute := &gatewayapiv1beta1.HTTPRoute{}
+				if err := k8sClient.Get(context.Background(), routeKey, existingRoute); err != nil {
+					return nil
+				}
+				return existingRoute.GetAnnotations()
+			}, time.Minute, 5*time.Second).Should(HaveKeyWithValue(common.EffectivePoliciesAnnotation, fmt.Sprintf("ratelimitpolicy/%s", rlpKey)))
+
+			// detach the httproute from the gateway
+			Eventually(func() error {
+				existingRoute := &gatewayapiv1beta1.HTTPRoute{}
+				if err := k8sClient.Get(context.Background(), routeKey, existingRoute); err != nil {
+					return err
+				}
+				existingRoute.Spec.ParentRefs = nil
+				return k8sClient.Update(context.Background(), existingRoute)
+			}, time.Minute, 5*time.Second).Should(Succeed())
+
+			Eventually(func() map[string]string {
+				existingRoute := &gatewayapiv1beta1.HTTPRoute{}
+				if err := k8sClient.Get(context.Background(), routeKey, existingRoute); err != nil {
+					return map[string]string{common.EffectivePoliciesAnnotation: err.Error()}
+				}
+				return existingRoute.GetAnnotations()
+			}, time.Minute, 5*time.Second).ShouldNot(HaveKey(common.EffectivePoliciesAnnotation))
+		})
 	})
 })
 
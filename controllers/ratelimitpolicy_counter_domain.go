@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
+)
+
+// sharedLimitsConflictError is the error of a policy whose limits differ between the gateways sharing its counters
+type sharedLimitsConflictError struct {
+	counterDomain string
+	reason        string
+}
+
+func (e *sharedLimitsConflictError) Error() string {
+	return e.reason
+}
+
+func isSharedLimitsConflict(err error) bool {
+	conflictErr := &sharedLimitsConflictError{}
+	return errors.As(err, &conflictErr)
+}
+
+// conditionReason returns the reason of the condition of the policy rejected by the conflict
+func (e *sharedLimitsConflictError) conditionReason() string {
+	if e.counterDomain != "" {
+		return "CounterDomainConflict"
+	}
+	return "SharedLimitsConflict"
+}
+
+// kuadrantCounterDomain returns the counter domain of the kuadrant instance of a namespace, or empty if none
+func kuadrantCounterDomain(ctx context.Context, cl client.Client, kuadrantNamespace string) (string, error) {
+	if kuadrantNamespace == "" {
+		return "", nil
+	}
+
+	kuadrantList := &kuadrantv1beta1.KuadrantList{}
+	if err := cl.List(ctx, kuadrantList, client.InNamespace(kuadrantNamespace)); err != nil {
+		return "", err
+	}
+	for idx := range kuadrantList.Items {
+		if kObj := &kuadrantList.Items[idx]; kObj.GetDeletionTimestamp() == nil {
+			return kObj.LimitadorCounterDomain(), nil
+		}
+	}
+	return "", nil
+}
+
+// policyCounterDomain returns the counter domain of the kuadrant instance managing the target of a policy
+func policyCounterDomain(ctx context.Context, cl client.Client, rlp *kuadrantv1beta2.RateLimitPolicy) (string, error) {
+	kuadrantNamespace, _ := common.GetKuadrantNamespaceFromPolicy(rlp)
+	return kuadrantCounterDomain(ctx, cl, kuadrantNamespace)
+}
+
+// gatewayCounterDomain returns the counter domain of the kuadrant instance managing a gateway
+func gatewayCounterDomain(ctx context.Context, cl client.Client, gw client.Object) (string, error) {
+	kuadrantNamespace, err := common.GetKuadrantNamespace(gw)
+	if err != nil {
+		return "", nil
+	}
+	return kuadrantCounterDomain(ctx, cl, kuadrantNamespace)
+}
+
+// gatewaysRateLimits returns the gateways enforcing a policy and the Limitador limits of the policy for each of them,
+// the limits of the gateway policies merged, in the namespaces of the counter domain
+func (r *RateLimitPolicyReconciler) gatewaysRateLimits(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, counterDomain string) ([]client.ObjectKey, map[client.ObjectKey]rlptools.RateLimitList, error) {
+	rlpKey := client.ObjectKeyFromObject(rlp)
+	gwKeys, err := r.rlpGatewayKeys(ctx, rlp)
+	if err != nil {
+		return nil, nil, err
+	}
+	rateLimits := make(map[client.ObjectKey]rlptools.RateLimitList, len(gwKeys))
+	for _, gwKey := range gwKeys {
+		effectiveRLP := rlp.DeepCopy()
+		if _, err := r.resolveGatewayDefaults(ctx, effectiveRLP, gwKey); err != nil {
+			return nil, nil, err
+		}
+		gwRateLimits := rlptools.LimitadorRateLimitsFromRLP(effectiveRLP, []client.ObjectKey{gwKey})
+		for idx := range gwRateLimits {
+			gwRateLimits[idx].Namespace = rlptools.PolicyLimitsNamespace(counterDomain, gwKey, rlpKey)
+		}
+		rateLimits[gwKey] = gwRateLimits
+	}
+	return gwKeys, rateLimits, nil
+}
+
+// validateSharedLimits rejects the policies whose limits differ between the gateways sharing their counters, e.g. by
+// the limits of the gateway policies merged into the limits of a policy of a route
+func (r *RateLimitPolicyReconciler) validateSharedLimits(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy) error {
+	counterDomain, err := policyCounterDomain(ctx, r.Client(), rlp)
+	if err != nil || (counterDomain == "" && rlptools.LimitsPerGateway) {
+		return err
+	}
+
+	effectiveRLP := rlp.DeepCopy()
+	if err := resolveRateLimitPolicyTemplate(ctx, r.Client(), effectiveRLP); err != nil {
+		return err
+	}
+	gwKeys, rateLimits, err := r.gatewaysRateLimits(ctx, effectiveRLP, counterDomain)
+	if err != nil {
+		return err
+	}
+	return sharedLimitsConflict(counterDomain, gwKeys, rateLimits)
+}
+
+// sharedLimitsConflict returns a conflict error if the limits of a policy differ between the gateways sharing its
+// counters, i.e. all the gateways of the policy under the counter domain, or unless the counters are isolated per
+// gateway. Limitador would otherwise enforce the limits of every gateway on the requests of all of them.
+func sharedLimitsConflict(counterDomain string, gwKeys []client.ObjectKey, rateLimits map[client.ObjectKey]rlptools.RateLimitList) error {
+	if counterDomain == "" && rlptools.LimitsPerGateway {
+		return nil
+	}
+	for _, gwKey := range gwKeys {
+		if rlptools.Equal(rateLimits[gwKeys[0]], rateLimits[gwKey]) {
+			continue
+		}
+		if counterDomain != "" {
+			return &sharedLimitsConflictError{counterDomain: counterDomain, reason: fmt.Sprintf("the limits of the policy differ between gateways %s and %s, sharing the counters of counter domain %s", gwKeys[0], gwKey, counterDomain)}
+		}
+		return &sharedLimitsConflictError{reason: fmt.Sprintf("the limits of the policy differ between gateways %s and %s, sharing the counters of the policy", gwKeys[0], gwKey)}
+	}
+	return nil
+}
+
+// uniqueRateLimits returns the limits without the duplicates, i.e. the limits of a policy generated once per gateway
+// sharing its counters
+func uniqueRateLimits(rateLimits []limitadorv1alpha1.RateLimit) rlptools.RateLimitList {
+	unique := make(rlptools.RateLimitList, 0, len(rateLimits))
+	for _, rateLimit := range rateLimits {
+		if _, found := common.Find(unique, func(other limitadorv1alpha1.RateLimit) bool { return reflect.DeepEqual(rateLimit, other) }); !found {
+			unique = append(unique, rateLimit)
+		}
+	}
+	return unique
+}
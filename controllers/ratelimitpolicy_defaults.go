@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const (
+	GatewayDefaultsAppliedConditionType string = "GatewayDefaultsApplied"
+)
+
+// gatewayDefaultsRLP returns the policy targeting the gateway whose limits are merged into the policies
+// targeting the routes of the gateway, or nil if there is none
+func (r *RateLimitPolicyReconciler) gatewayDefaultsRLP(ctx context.Context, gwKey client.ObjectKey) (*kuadrantv1beta2.RateLimitPolicy, error) {
+	gateway := &gatewayapiv1beta1.Gateway{}
+	if err := r.Client().Get(ctx, gwKey, gateway); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	rlpRef, found := common.ReadAnnotationsFromObject(gateway)[common.RateLimitPolicyBackRefAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	gwRLP := &kuadrantv1beta2.RateLimitPolicy{}
+	if err := r.Client().Get(ctx, common.NamespacedNameToObjectKey(rlpRef, gateway.Namespace), gwRLP); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if gwRLP.Spec.DefaultsStrategy != kuadrantv1beta2.MergeDefaultsStrategy {
+		return nil, nil
+	}
+
+	if err := resolveRateLimitPolicyTemplate(ctx, r.Client(), gwRLP); err != nil {
+		return nil, err
+	}
+
+	return gwRLP, nil
+}
+
+// resolveGatewayDefaults merges the limits of the policy targeting the gateway into the limits of a policy
+// targeting one of the routes of the gateway. The RateLimitPolicy must not be written back to the cluster.
+// Returns the policy whose limits were merged, or nil if none.
+func (r *RateLimitPolicyReconciler) resolveGatewayDefaults(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, gwKey client.ObjectKey) (*kuadrantv1beta2.RateLimitPolicy, error) {
+	if !common.IsTargetRefHTTPRoute(rlp.Spec.TargetRef) || rlp.Spec.IgnoreGatewayDefaults {
+		return nil, nil
+	}
+
+	gwRLP, err := r.gatewayDefaultsRLP(ctx, gwKey)
+	if err != nil || gwRLP == nil {
+		return nil, err
+	}
+
+	rlp.Spec.Limits = kuadrantv1beta2.MergeLimits(gwRLP.Spec.Limits, rlp.Spec.Limits)
+	return gwRLP, nil
+}
+
+// gatewayDefaultsCondition returns a condition listing the gateway policies whose limits are merged into the
+// limits of the policy, or nil if none
+func (r *RateLimitPolicyReconciler) gatewayDefaultsCondition(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy) (*metav1.Condition, error) {
+	gwKeys, err := r.rlpGatewayKeys(ctx, rlp)
+	if err != nil {
+		return nil, err
+	}
+	gwRLPKeys := make([]string, 0)
+	for _, gwKey := range gwKeys {
+		gwRLP, err := r.resolveGatewayDefaults(ctx, rlp.DeepCopy(), gwKey)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if gwRLP != nil {
+			gwRLPKeys = append(gwRLPKeys, client.ObjectKeyFromObject(gwRLP).String())
+		}
+	}
+
+	if len(gwRLPKeys) == 0 {
+		return nil, nil
+	}
+
+	return &metav1.Condition{
+		Type:    GatewayDefaultsAppliedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "GatewayDefaultsApplied",
+		Message: fmt.Sprintf("Limits merged from the gateway policies: %s", strings.Join(gwRLPKeys, ", ")),
+	}, nil
+}
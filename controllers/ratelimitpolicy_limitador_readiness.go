@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+const (
+	RLPLimitadorNotReadyReason string = "LimitadorNotReady"
+	RLPLimitsNotAppliedReason  string = "LimitsNotApplied"
+)
+
+// limitadorReadiness returns the reason and the message of the limits of a policy not being enforced by the Limitador
+// instance of its kuadrant instance, or empty if enforced or unknown
+func (r *RateLimitPolicyReconciler) limitadorReadiness(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, limitsNamespaces []string) (string, string, error) {
+	kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(rlp)
+	if !isSet || len(rlp.Spec.Limits) == 0 {
+		return "", "", nil
+	}
+
+	limitadorKey := client.ObjectKey{Name: common.LimitadorName, Namespace: kuadrantNamespace}
+	limitador := &limitadorv1alpha1.Limitador{}
+	if err := r.Client().Get(ctx, limitadorKey, limitador); err != nil {
+		if apierrors.IsNotFound(err) {
+			return RLPLimitadorNotReadyReason, fmt.Sprintf("Limitador %s not found", limitadorKey), nil
+		}
+		return "", "", err
+	}
+
+	notReadyReason, err := limitadorNotReadyReason(ctx, r.Client(), limitador)
+	if err != nil {
+		return "", "", err
+	}
+	if notReadyReason != nil {
+		return RLPLimitadorNotReadyReason, fmt.Sprintf("Limitador %s is not ready: %s", limitadorKey, *notReadyReason), nil
+	}
+
+	// the limitador operator has not loaded the last limits yet
+	if limitador.Status.ObservedGeneration != 0 && limitador.Status.ObservedGeneration < limitador.Generation {
+		return RLPLimitsNotAppliedReason, fmt.Sprintf("The limits of Limitador %s are not applied yet", limitadorKey), nil
+	}
+
+	missing := make([]string, 0)
+	for _, limitsNamespace := range limitsNamespaces {
+		if _, found := common.Find(limitador.Spec.Limits, func(limit limitadorv1alpha1.RateLimit) bool {
+			return limit.Namespace == limitsNamespace
+		}); !found {
+			missing = append(missing, limitsNamespace)
+		}
+	}
+	if len(missing) > 0 {
+		return RLPLimitsNotAppliedReason, fmt.Sprintf("Limitador %s has no limits of the namespaces %s", limitadorKey, strings.Join(missing, ", ")), nil
+	}
+
+	return "", "", nil
+}
+
+// limitadorNotReadyReason returns the reason a Limitador instance is not ready, or nil if ready.
+// The Ready condition of the Limitador instance prevails if set, the Available condition of its deployment otherwise.
+func limitadorNotReadyReason(ctx context.Context, cli client.Client, limitador *limitadorv1alpha1.Limitador) (*string, error) {
+	if readyCond := meta.FindStatusCondition(limitador.Status.Conditions, "Ready"); readyCond != nil {
+		if readyCond.Status != "True" {
+			return &readyCond.Message, nil
+		}
+		return nil, nil
+	}
+
+	return limitadorDeploymentNotAvailableReason(ctx, cli, limitador.Namespace)
+}
+
+// limitadorDeploymentNotAvailableReason returns the reason the deployment of Limitador is not available, or nil if available
+func limitadorDeploymentNotAvailableReason(ctx context.Context, cli client.Client, namespace string) (*string, error) {
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: namespace}, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			reason := err.Error()
+			return &reason, nil
+		}
+		return nil, err
+	}
+
+	availableCondition := common.FindDeploymentStatusCondition(deployment.Status.Conditions, "Available")
+	if availableCondition == nil {
+		reason := "Available condition not found"
+		return &reason, nil
+	}
+	if availableCondition.Status != corev1.ConditionTrue {
+		return &availableCondition.Message, nil
+	}
+
+	return nil, nil
+}
+
+// limitadorReadinessChanged filters the events of the Limitador instances and of their deployments to the ones
+// possibly changing the readiness of Limitador or the limits loaded
+var limitadorReadinessChanged = predicate.Funcs{
+	CreateFunc:  func(e event.CreateEvent) bool { return isLimitadorObject(e.Object) },
+	DeleteFunc:  func(e event.DeleteEvent) bool { return isLimitadorObject(e.Object) },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if !isLimitadorObject(e.ObjectNew) {
+			return false
+		}
+		switch newObj := e.ObjectNew.(type) {
+		case *limitadorv1alpha1.Limitador:
+			oldObj, ok := e.ObjectOld.(*limitadorv1alpha1.Limitador)
+			return !ok || oldObj.Status.ObservedGeneration != newObj.Status.ObservedGeneration ||
+				!reflect.DeepEqual(oldObj.Status.Conditions, newObj.Status.Conditions)
+		case *appsv1.Deployment:
+			oldObj, ok := e.ObjectOld.(*appsv1.Deployment)
+			return !ok || !reflect.DeepEqual(
+				common.FindDeploymentStatusCondition(oldObj.Status.Conditions, "Available"),
+				common.FindDeploymentStatusCondition(newObj.Status.Conditions, "Available"))
+		}
+		return false
+	},
+}
+
+func isLimitadorObject(obj client.Object) bool {
+	return obj.GetName() == common.LimitadorName
+}
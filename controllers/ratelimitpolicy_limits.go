@@ -5,10 +5,12 @@ import (
 
 	"github.com/go-logr/logr"
 	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
 	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
 )
 
@@ -37,11 +39,6 @@ func (r *RateLimitPolicyReconciler) reconcileLimitador(ctx context.Context, rlp
 	logger, _ := logr.FromContext(ctx)
 	logger = logger.WithName("reconcileLimitador").WithValues("rlp refs", common.Map(rlpRefs, func(ref client.ObjectKey) string { return ref.String() }))
 
-	rateLimitIndex, err := r.buildRateLimitIndex(ctx, rlpRefs)
-	if err != nil {
-		return err
-	}
-
 	// get the current limitador cr for the kuadrant instance so we can compare if it needs to be updated
 	logger.V(1).Info("get kuadrant namespace")
 	var kuadrantNamespace string
@@ -62,20 +59,32 @@ func (r *RateLimitPolicyReconciler) reconcileLimitador(ctx context.Context, rlp
 	}
 	limitadorKey := client.ObjectKey{Name: common.LimitadorName, Namespace: kuadrantNamespace}
 	limitador := &limitadorv1alpha1.Limitador{}
-	err = r.Client().Get(ctx, limitadorKey, limitador)
+	err := r.Client().Get(ctx, limitadorKey, limitador)
 	logger.V(1).Info("get limitador", "limitador", limitadorKey, "err", err)
 	if err != nil {
 		return err
 	}
 
+	rateLimitIndex, err := r.buildRateLimitIndex(ctx, rlpRefs, limitador.Spec.Limits)
+	if err != nil {
+		return err
+	}
+
+	// the limits of other sources are left untouched
+	rateLimits := rateLimitIndex.MergeInto(limitador.Spec.Limits, rlptools.IsRateLimitPolicyLimit)
+
+	if rlptools.ExceedsLimitsSoftCap(rateLimits) {
+		logger.Info("number of limits exceeds the soft cap of limitador", "limitador", limitadorKey, "limits", len(rateLimits), "softCap", rlptools.LimitsSoftCap)
+	}
+
 	// return if limitador is up to date
-	if rlptools.Equal(rateLimitIndex.ToRateLimits(), limitador.Spec.Limits) {
+	if rlptools.Equal(rateLimits, limitador.Spec.Limits) {
 		logger.V(1).Info("limitador is up to date, skipping update")
 		return nil
 	}
 
 	// update limitador
-	limitador.Spec.Limits = rateLimitIndex.ToRateLimits()
+	limitador.Spec.Limits = rateLimits
 	err = r.UpdateResource(ctx, limitador)
 	logger.V(1).Info("update limitador", "limitador", limitadorKey, "err", err)
 	if err != nil {
@@ -85,7 +94,12 @@ func (r *RateLimitPolicyReconciler) reconcileLimitador(ctx context.Context, rlp
 	return nil
 }
 
-func (r *RateLimitPolicyReconciler) buildRateLimitIndex(ctx context.Context, rlpRefs []client.ObjectKey) (*rlptools.RateLimitIndex, error) {
+// buildRateLimitIndex returns the limits of the policies to configure in Limitador. The policies whose limits cannot be
+// resolved, i.e. referencing a PolicyTemplate not found, the policies whose limits differ between the gateways sharing
+// their counters, and the policies whose changes are held by the cooldown, keep
+// the limits currently configured, given in currentLimits; the condition is reported in the status of the policy by
+// its own reconciliation.
+func (r *RateLimitPolicyReconciler) buildRateLimitIndex(ctx context.Context, rlpRefs []client.ObjectKey, currentLimits []limitadorv1alpha1.RateLimit) (*rlptools.RateLimitIndex, error) {
 	logger, _ := logr.FromContext(ctx)
 	logger = logger.WithName("buildRateLimitIndex").WithValues("ratelimitpolicies", rlpRefs)
 
@@ -103,8 +117,75 @@ func (r *RateLimitPolicyReconciler) buildRateLimitIndex(ctx context.Context, rlp
 			return nil, err
 		}
 
-		rateLimitIndex.Set(rlpKey, rlptools.LimitadorRateLimitsFromRLP(rlp))
+		if rateLimitPolicyChangePending(rlp) {
+			logger.V(1).Info("changes of the policy pending, keeping the limits configured", "ratelimitpolicy", rlpKey)
+			rateLimitIndex.Set(rlpKey, rlptools.RateLimitPolicyLimits(currentLimits, rlpKey))
+			continue
+		}
+
+		rateLimits, err := r.policyRateLimits(ctx, rlp)
+		if isPolicyTemplateError(err) || isSharedLimitsConflict(err) {
+			logger.Info("failed to resolve the limits of the policy, keeping the limits configured", "ratelimitpolicy", rlpKey, "err", err)
+			rateLimitIndex.Set(rlpKey, rlptools.RateLimitPolicyLimits(currentLimits, rlpKey))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rateLimitIndex.Set(rlpKey, rateLimits)
 	}
 
 	return rateLimitIndex, nil
 }
+
+// rateLimitPolicyChangePending tells whether the changes of a policy are held by the cooldown, not to be applied by
+// the reconciliation of the other policies of the same gateways
+func rateLimitPolicyChangePending(rlp *kuadrantv1beta2.RateLimitPolicy) bool {
+	_, remaining := policyChangeCooldownRemaining(rateLimitPolicyChanges, rlp, rlp.Status.ObservedGeneration, rlp.Status.Conditions)
+	return remaining > 0
+}
+
+// policyRateLimits returns the Limitador limits of a policy for all the gateways enforcing it
+func (r *RateLimitPolicyReconciler) policyRateLimits(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy) (rlptools.RateLimitList, error) {
+	if err := resolveRateLimitPolicyTemplate(ctx, r.Client(), rlp); err != nil {
+		return nil, err
+	}
+
+	counterDomain, err := policyCounterDomain(ctx, r.Client(), rlp)
+	if err != nil {
+		return nil, err
+	}
+
+	// the defaults merged into the limits of the policy may differ for each gateway
+	gwKeys, gwRateLimits, err := r.gatewaysRateLimits(ctx, rlp, counterDomain)
+	if err != nil {
+		return nil, err
+	}
+	if err := sharedLimitsConflict(counterDomain, gwKeys, gwRateLimits); err != nil {
+		return nil, err
+	}
+	rateLimits := make(rlptools.RateLimitList, 0)
+	for _, gwKey := range gwKeys {
+		rateLimits = append(rateLimits, gwRateLimits[gwKey]...)
+	}
+	// the gateways sharing the counters share the limits
+	return uniqueRateLimits(rateLimits), nil
+}
+
+// rlpGatewayKeys returns the keys of the gateways enforcing a policy, i.e. the targeted gateway
+// or the parent gateways of the targeted route. A policy whose target is not found, or not ready or accepted yet, is
+// enforced by no gateway.
+func (r *RateLimitPolicyReconciler) rlpGatewayKeys(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy) ([]client.ObjectKey, error) {
+	logger, _ := logr.FromContext(ctx)
+
+	targetNetworkObject, err := r.FetchValidTargetRef(ctx, rlp.GetTargetRef(), rlp.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) || reconcilers.IsInvalidTarget(err) {
+			logger.V(1).Info("target of the policy not valid", "ratelimitpolicy", client.ObjectKeyFromObject(rlp), "err", err)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.TargetedGatewayKeys(ctx, targetNetworkObject), nil
+}
@@ -0,0 +1,186 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
+)
+
+func testGateway(name string) *gatewayapiv1beta1.Gateway {
+	return &gatewayapiv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "gw-ns"},
+		Spec: gatewayapiv1beta1.GatewaySpec{
+			GatewayClassName: "istio",
+			Listeners:        []gatewayapiv1beta1.Listener{{Name: "http", Port: 80, Protocol: "HTTP"}},
+		},
+	}
+}
+
+func testHTTPRoute(name string, gw *gatewayapiv1beta1.Gateway, hostname string) *gatewayapiv1beta1.HTTPRoute {
+	gwNamespace := gatewayapiv1beta1.Namespace(gw.Namespace)
+	kind := gatewayapiv1beta1.Kind("Gateway")
+	parentRef := gatewayapiv1beta1.ParentReference{Kind: &kind, Name: gatewayapiv1beta1.ObjectName(gw.Name), Namespace: &gwNamespace}
+	pathPrefix := gatewayapiv1beta1.PathMatchPathPrefix
+	return &gatewayapiv1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec: gatewayapiv1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapiv1beta1.CommonRouteSpec{ParentRefs: []gatewayapiv1beta1.ParentReference{parentRef}},
+			Hostnames:       []gatewayapiv1beta1.Hostname{gatewayapiv1beta1.Hostname(hostname)},
+			Rules: []gatewayapiv1beta1.HTTPRouteRule{{
+				Matches: []gatewayapiv1beta1.HTTPRouteMatch{{Path: &gatewayapiv1beta1.HTTPPathMatch{Type: &pathPrefix, Value: &[]string{"/"}[0]}}},
+			}},
+		},
+		Status: gatewayapiv1beta1.HTTPRouteStatus{
+			RouteStatus: gatewayapiv1beta1.RouteStatus{
+				Parents: []gatewayapiv1beta1.RouteParentStatus{{
+					ParentRef:  parentRef,
+					Conditions: []metav1.Condition{{Type: "Accepted", Status: metav1.ConditionTrue}},
+				}},
+			},
+		},
+	}
+}
+
+func testRateLimitPolicy(name string, target client.Object, limit int) *kuadrantv1beta2.RateLimitPolicy {
+	targetNamespace := gatewayapiv1alpha2.Namespace(target.GetNamespace())
+	kind := "HTTPRoute"
+	if _, ok := target.(*gatewayapiv1beta1.Gateway); ok {
+		kind = "Gateway"
+	}
+	return &kuadrantv1beta2.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Generation: 1},
+		Spec: kuadrantv1beta2.RateLimitPolicySpec{
+			TargetRef: gatewayapiv1alpha2.PolicyTargetReference{
+				Group:     gatewayapiv1beta1.GroupName,
+				Kind:      gatewayapiv1alpha2.Kind(kind),
+				Name:      gatewayapiv1alpha2.ObjectName(target.GetName()),
+				Namespace: &targetNamespace,
+			},
+			Limits: map[string]kuadrantv1beta2.Limit{
+				"global": {Rates: []kuadrantv1beta2.Rate{{Limit: limit, Duration: 1, Unit: "minute"}}},
+			},
+		},
+		Status: kuadrantv1beta2.RateLimitPolicyStatus{ObservedGeneration: 1},
+	}
+}
+
+// testRateLimitPolicyLimits returns the limits generated from a policy enforced by a gateway
+func testRateLimitPolicyLimits(rlp *kuadrantv1beta2.RateLimitPolicy, gw *gatewayapiv1beta1.Gateway) rlptools.RateLimitList {
+	return rlptools.LimitadorRateLimitsFromRLP(rlp, []client.ObjectKey{client.ObjectKeyFromObject(gw)})
+}
+
+func TestBuildRateLimitIndexPolicyTemplateNotFound(t *testing.T) {
+	gw := testGateway("gw")
+	route := testHTTPRoute("route", gw, "api.example.com")
+	gwRLP := testRateLimitPolicy("gw-rlp", gw, 100)
+	routeRLP := testRateLimitPolicy("route-rlp", route, 10)
+	routeRLP.Spec.TemplateRef = &corev1.LocalObjectReference{Name: "missing"}
+
+	configuredRouteRLP := routeRLP.DeepCopy()
+	configuredRouteRLP.Spec.Limits["global"] = kuadrantv1beta2.Limit{Rates: []kuadrantv1beta2.Rate{{Limit: 5, Duration: 1, Unit: "minute"}}}
+	manualLimit := limitadorv1alpha1.RateLimit{Namespace: "manual", MaxValue: 1, Seconds: 1}
+	currentLimits := append(testRateLimitPolicyLimits(configuredRouteRLP, gw), manualLimit)
+
+	r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route, gwRLP, routeRLP)}
+	ctx := context.TODO()
+
+	index, err := r.buildRateLimitIndex(ctx, []client.ObjectKey{client.ObjectKeyFromObject(gwRLP), client.ObjectKeyFromObject(routeRLP)}, currentLimits)
+	if err != nil {
+		t.Fatalf("the policy with a template not found should not fail the others: %v", err)
+	}
+
+	if limits, _ := index.Get(client.ObjectKeyFromObject(gwRLP)); !rlptools.Equal(limits, testRateLimitPolicyLimits(gwRLP, gw)) {
+		t.Errorf("expected the limits of the gateway policy to be applied, got %v", limits)
+	}
+	if limits, _ := index.Get(client.ObjectKeyFromObject(routeRLP)); !rlptools.Equal(limits, testRateLimitPolicyLimits(configuredRouteRLP, gw)) {
+		t.Errorf("expected the limits configured of the policy with a template not found to be kept, got %v", limits)
+	}
+
+	merged := index.MergeInto(currentLimits, rlptools.IsRateLimitPolicyLimit)
+	if _, found := common.Find(merged, func(limit limitadorv1alpha1.RateLimit) bool { return limit.Namespace == manualLimit.Namespace }); !found {
+		t.Errorf("expected the limits of other sources to be kept, got %v", merged)
+	}
+	if len(merged) != 3 {
+		t.Errorf("expected 3 limits, got %d: %v", len(merged), merged)
+	}
+
+	// the policy is rejected by its own reconciliation
+	err = r.reconcileResources(ctx, routeRLP, route)
+	if !isPolicyTemplateError(err) {
+		t.Errorf("expected the policy template error, got %v", err)
+	}
+}
+
+func TestBuildRateLimitIndexSharedLimitsConflict(t *testing.T) {
+	defer func(perGateway bool) { rlptools.LimitsPerGateway = perGateway }(rlptools.LimitsPerGateway)
+	rlptools.LimitsPerGateway = false
+
+	gwA := testGateway("gw-a")
+	gwB := testGateway("gw-b")
+	route := testHTTPRoute("route", gwA, "api.example.com")
+	routeB := testHTTPRoute("route", gwB, "api.example.com")
+	route.Spec.ParentRefs = append(route.Spec.ParentRefs, routeB.Spec.ParentRefs...)
+	route.Status.Parents = append(route.Status.Parents, routeB.Status.Parents...)
+
+	// the defaults of the gateways define the same limit with different rates
+	gwARLP := testRateLimitPolicy("gw-a-rlp", gwA, 100)
+	gwBRLP := testRateLimitPolicy("gw-b-rlp", gwB, 50)
+	for _, gwRLP := range []*kuadrantv1beta2.RateLimitPolicy{gwARLP, gwBRLP} {
+		gwRLP.Spec.DefaultsStrategy = kuadrantv1beta2.MergeDefaultsStrategy
+	}
+	gwA.Annotations = map[string]string{common.RateLimitPolicyBackRefAnnotation: client.ObjectKeyFromObject(gwARLP).String()}
+	gwB.Annotations = map[string]string{common.RateLimitPolicyBackRefAnnotation: client.ObjectKeyFromObject(gwBRLP).String()}
+	routeRLP := testRateLimitPolicy("route-rlp", route, 10)
+	routeRLP.Spec.Limits = map[string]kuadrantv1beta2.Limit{
+		"route": {Rates: []kuadrantv1beta2.Rate{{Limit: 10, Duration: 1, Unit: "minute"}}},
+	}
+
+	configuredRouteRLP := routeRLP.DeepCopy()
+	configuredRouteRLP.Spec.Limits["route"] = kuadrantv1beta2.Limit{Rates: []kuadrantv1beta2.Rate{{Limit: 5, Duration: 1, Unit: "minute"}}}
+	currentLimits := testRateLimitPolicyLimits(configuredRouteRLP, gwA)
+
+	r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gwA, gwB, route, gwARLP, gwBRLP, routeRLP)}
+	ctx := context.TODO()
+	rlpRefs := []client.ObjectKey{client.ObjectKeyFromObject(gwARLP), client.ObjectKeyFromObject(gwBRLP), client.ObjectKeyFromObject(routeRLP)}
+
+	err := r.validateSharedLimits(ctx, routeRLP)
+	if !isSharedLimitsConflict(err) {
+		t.Fatalf("expected the shared limits conflict, got %v", err)
+	}
+	if cond := r.availableCondition(err); cond.Reason != "SharedLimitsConflict" {
+		t.Errorf("expected the SharedLimitsConflict reason, got %s", cond.Reason)
+	}
+
+	index, err := r.buildRateLimitIndex(ctx, rlpRefs, currentLimits)
+	if err != nil {
+		t.Fatalf("the conflicting policy should not fail the others: %v", err)
+	}
+	if limits, _ := index.Get(client.ObjectKeyFromObject(routeRLP)); !rlptools.Equal(limits, currentLimits) {
+		t.Errorf("expected the limits configured of the conflicting policy to be kept, got %v", limits)
+	}
+
+	// the counters isolated per gateway enforce the defaults of each gateway on its own requests only
+	rlptools.LimitsPerGateway = true
+	if err := r.validateSharedLimits(ctx, routeRLP); err != nil {
+		t.Errorf("expected no conflict with the counters isolated per gateway, got %v", err)
+	}
+	index, err = r.buildRateLimitIndex(ctx, rlpRefs, currentLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits, _ := index.Get(client.ObjectKeyFromObject(routeRLP)); len(limits) != 4 {
+		t.Errorf("expected 2 limits for each gateway, got %v", limits)
+	}
+}
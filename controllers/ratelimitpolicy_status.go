@@ -2,21 +2,27 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/go-logr/logr"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools"
 )
 
 const (
-	RLPAvailableConditionType string = "Available"
+	RLPAvailableConditionType          string = "Available"
+	LimitsSoftCapExceededConditionType string = "LimitsSoftCapExceeded"
 )
 
 func (r *RateLimitPolicyReconciler) reconcileStatus(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, specErr error) (ctrl.Result, error) {
@@ -55,20 +61,143 @@ func (r *RateLimitPolicyReconciler) reconcileStatus(ctx context.Context, rlp *ku
 	return ctrl.Result{}, nil
 }
 
-func (r *RateLimitPolicyReconciler) calculateStatus(_ context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, specErr error) *kuadrantv1beta2.RateLimitPolicyStatus {
+func (r *RateLimitPolicyReconciler) calculateStatus(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, specErr error) *kuadrantv1beta2.RateLimitPolicyStatus {
 	newStatus := &kuadrantv1beta2.RateLimitPolicyStatus{
 		// Copy initial conditions. Otherwise, status will always be updated
 		Conditions:         common.CopyConditions(rlp.Status.Conditions),
 		ObservedGeneration: rlp.Status.ObservedGeneration,
 	}
 
+	if specErr == nil {
+		counterDomain, err := policyCounterDomain(ctx, r.Client(), rlp)
+		if err != nil {
+			logger, _ := logr.FromContext(ctx)
+			logger.V(1).Info("failed to check the counter domain of the policy", "err", err)
+		}
+		newStatus.CounterDomain = counterDomain
+
+		rlpKey := client.ObjectKeyFromObject(rlp)
+		gwKeys, err := r.rlpGatewayKeys(ctx, rlp)
+		if err != nil {
+			logger, _ := logr.FromContext(ctx)
+			logger.V(1).Info("failed to fetch the gateways of the policy", "err", err)
+			// the namespaces reported are kept until the gateways are known again
+			newStatus.LimitsNamespaces = rlp.Status.LimitsNamespaces
+		}
+		for _, gwKey := range gwKeys {
+			if limitsNamespace := rlptools.PolicyLimitsNamespace(counterDomain, gwKey, rlpKey); !common.Contains(newStatus.LimitsNamespaces, limitsNamespace) {
+				newStatus.LimitsNamespaces = append(newStatus.LimitsNamespaces, limitsNamespace)
+			}
+		}
+	}
+
 	availableCond := r.availableCondition(specErr)
 
+	if specErr == nil {
+		reason, message, err := r.limitadorReadiness(ctx, rlp, newStatus.LimitsNamespaces)
+		if err != nil {
+			logger, _ := logr.FromContext(ctx)
+			logger.V(1).Info("failed to check the readiness of limitador", "err", err)
+		} else if reason != "" {
+			availableCond.Status = metav1.ConditionFalse
+			availableCond.Reason = reason
+			availableCond.Message = message
+		}
+	}
+
 	meta.SetStatusCondition(&newStatus.Conditions, *availableCond)
 
+	// the changes pending, if any, are applied, and the generation reconciled
+	meta.RemoveStatusCondition(&newStatus.Conditions, PolicyChangePendingConditionType)
+	meta.RemoveStatusCondition(&newStatus.Conditions, ReconcileStalledConditionType)
+
+	setTargetRefInvalidCondition(&newStatus.Conditions, specErr)
+
+	// the limits of the policy are bound to the limitador instance of the kuadrant instance managing the target
+	if kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(rlp); isSet {
+		newStatus.Limitador = &kuadrantv1beta2.LimitadorReference{
+			Name:      common.LimitadorName,
+			Namespace: kuadrantNamespace,
+		}
+	}
+
+	setTemplateResolvedCondition(ctx, r.Client(), &newStatus.Conditions, rlp.Namespace, rlp.Spec.TemplateRef)
+
+	if err := clearBackendRemovedCondition(ctx, r.Client(), &newStatus.Conditions, rlp); err != nil {
+		logger, _ := logr.FromContext(ctx)
+		logger.V(1).Info("failed to check the kuadrant instance of the policy", "err", err)
+	}
+
+	if cond, err := r.gatewayDefaultsCondition(ctx, rlp); err != nil {
+		logger, _ := logr.FromContext(ctx)
+		logger.V(1).Info("failed to check the gateway defaults of the policy", "err", err)
+	} else if cond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *cond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, GatewayDefaultsAppliedConditionType)
+	}
+
+	// informational only, the limits are enforced regardless
+	if cond := r.limitsSoftCapCondition(ctx, rlp); cond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *cond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, LimitsSoftCapExceededConditionType)
+	}
+
+	setExpiringCondition(&newStatus.Conditions, rlp.Spec.ExpiresAt)
+
+	if replaced, replacement, err := policyReplacementKeys(ctx, r.Client(), rlp, &kuadrantv1beta2.RateLimitPolicyList{}); err != nil {
+		logger, _ := logr.FromContext(ctx)
+		logger.V(1).Info("failed to check the replacement of the policy", "err", err)
+	} else {
+		setReplacementCondition(&newStatus.Conditions, rlp, replaced, replacement)
+	}
+
+	if specErr == nil {
+		failureMode, err := r.effectiveFailureMode(ctx, rlp)
+		if err != nil {
+			logger, _ := logr.FromContext(ctx)
+			logger.V(1).Info("failed to check the failure mode of the gateways of the policy", "err", err)
+		}
+		newStatus.FailureMode = failureMode
+	}
+
 	return newStatus
 }
 
+// effectiveFailureMode returns the failure mode enforced by the gateways of a policy, closed if any of their policies is closed
+func (r *RateLimitPolicyReconciler) effectiveFailureMode(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy) (kuadrantv1beta2.FailureMode, error) {
+	if rlp.GetFailureMode() == kuadrantv1beta2.FailClosed {
+		return kuadrantv1beta2.FailClosed, nil
+	}
+
+	gwKeys, err := r.rlpGatewayKeys(ctx, rlp)
+	if err != nil {
+		return rlp.GetFailureMode(), err
+	}
+	for _, gwKey := range gwKeys {
+		gw := &gatewayapiv1beta1.Gateway{}
+		if err := r.Client().Get(ctx, gwKey, gw); err != nil {
+			return rlp.GetFailureMode(), client.IgnoreNotFound(err)
+		}
+		gwWrapper := common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}
+		for _, rlpKey := range gwWrapper.PolicyRefs() {
+			other := &kuadrantv1beta2.RateLimitPolicy{}
+			if err := r.Client().Get(ctx, rlpKey, other); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return rlp.GetFailureMode(), err
+			}
+			if other.GetFailureMode() == kuadrantv1beta2.FailClosed {
+				return kuadrantv1beta2.FailClosed, nil
+			}
+		}
+	}
+
+	return rlp.GetFailureMode(), nil
+}
+
 func (r *RateLimitPolicyReconciler) availableCondition(specErr error) *metav1.Condition {
 	cond := &metav1.Condition{
 		Type:    RLPAvailableConditionType,
@@ -81,7 +210,40 @@ func (r *RateLimitPolicyReconciler) availableCondition(specErr error) *metav1.Co
 		cond.Status = metav1.ConditionFalse
 		cond.Reason = "ReconcilliationError"
 		cond.Message = specErr.Error()
+		conflictErr := &sharedLimitsConflictError{}
+		if errors.As(specErr, &conflictErr) {
+			cond.Reason = conflictErr.conditionReason()
+		}
 	}
 
 	return cond
 }
+
+// limitsSoftCapCondition returns a warning condition if the policy contributes limits to a Limitador instance
+// whose number of limits exceeds the soft cap
+func (r *RateLimitPolicyReconciler) limitsSoftCapCondition(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy) *metav1.Condition {
+	kuadrantNamespace, isSet := common.GetKuadrantNamespaceFromPolicy(rlp)
+	if !isSet || len(rlp.Spec.Limits) == 0 {
+		return nil
+	}
+
+	limitador := &limitadorv1alpha1.Limitador{}
+	if err := r.Client().Get(ctx, client.ObjectKey{Name: common.LimitadorName, Namespace: kuadrantNamespace}, limitador); err != nil {
+		return nil
+	}
+
+	return limitsSoftCapExceededCondition(limitador)
+}
+
+// limitsSoftCapExceededCondition returns a warning condition if the number of limits of a Limitador instance exceeds the soft cap
+func limitsSoftCapExceededCondition(limitador *limitadorv1alpha1.Limitador) *metav1.Condition {
+	if !rlptools.ExceedsLimitsSoftCap(limitador.Spec.Limits) {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    LimitsSoftCapExceededConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "LimitsSoftCapExceeded",
+		Message: fmt.Sprintf("Limitador %s has %d limits, above the soft cap of %d", client.ObjectKeyFromObject(limitador), len(limitador.Spec.Limits), rlptools.LimitsSoftCap),
+	}
+}
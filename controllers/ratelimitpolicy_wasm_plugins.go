@@ -94,12 +94,12 @@ func (r *RateLimitPolicyReconciler) gatewayWASMPlugin(ctx context.Context, gw co
 		},
 	}
 
-	if len(rlpRefs) < 1 {
-		common.TagObjectToDelete(wasmPlugin)
-		return wasmPlugin, nil
+	currentConfig, err := r.currentWASMPluginConfig(ctx, client.ObjectKeyFromObject(wasmPlugin))
+	if err != nil {
+		return nil, err
 	}
 
-	pluginConfig, err := r.wasmPluginConfig(ctx, gw, rlpRefs)
+	pluginConfig, err := r.wasmPluginConfig(ctx, gw, rlpRefs, currentConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -119,8 +119,22 @@ func (r *RateLimitPolicyReconciler) gatewayWASMPlugin(ctx context.Context, gw co
 	return wasmPlugin, nil
 }
 
-// returns nil when there is no rate limit policy to apply
-func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw common.GatewayWrapper, rlpRefs []client.ObjectKey) (*wasm.Plugin, error) {
+// currentWASMPluginConfig returns the config of the WasmPlugin of a gateway in place, or nil if none
+func (r *RateLimitPolicyReconciler) currentWASMPluginConfig(ctx context.Context, key client.ObjectKey) (*wasm.Plugin, error) {
+	wasmPlugin := &istioclientgoextensionv1alpha1.WasmPlugin{}
+	if err := r.Client().Get(ctx, key, wasmPlugin); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if wasmPlugin.Spec.PluginConfig == nil {
+		return nil, nil
+	}
+	return rlptools.WASMPluginFromStruct(wasmPlugin.Spec.PluginConfig)
+}
+
+// returns nil when there is no rate limit policy nor default rate limit to apply.
+// The policies whose limits cannot be resolved, i.e. referencing a PolicyTemplate not found, and the policies whose
+// changes are held by the cooldown, keep their config in currentConfig, if any.
+func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw common.GatewayWrapper, rlpRefs []client.ObjectKey, currentConfig *wasm.Plugin) (*wasm.Plugin, error) {
 	logger, _ := logr.FromContext(ctx)
 	logger = logger.WithName("wasmPluginConfig").WithValues("gateway", gw.Key())
 
@@ -128,6 +142,7 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 		rlp   kuadrantv1beta2.RateLimitPolicy
 		route gatewayapiv1beta1.HTTPRoute
 		skip  bool
+		keep  bool
 	}
 	rlps := make(map[string]*store, len(rlpRefs))
 	routeKeys := make(map[string]struct{}, 0)
@@ -142,13 +157,24 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 			return nil, err
 		}
 
+		keep := rateLimitPolicyChangePending(rlp)
+		if keep {
+			logger.V(1).Info("changes of the policy pending, keeping the config in place", "ratelimitpolicy", rlpKey)
+		} else if err := r.resolveWASMPluginLimits(ctx, rlp, gw.Key()); err != nil {
+			if !isPolicyTemplateError(err) {
+				return nil, err
+			}
+			logger.Info("failed to resolve the limits of the policy, keeping the config in place", "ratelimitpolicy", rlpKey, "err", err)
+			keep = true
+		}
+
 		// target ref is a HTTPRoute
 		if common.IsTargetRefHTTPRoute(rlp.Spec.TargetRef) {
 			route, err := r.FetchValidHTTPRoute(ctx, rlp.TargetKey())
 			if err != nil {
 				return nil, err
 			}
-			rlps[rlpKey.String()] = &store{rlp: *rlp, route: *route}
+			rlps[rlpKey.String()] = &store{rlp: *rlp, route: *route, keep: keep}
 			routeKeys[client.ObjectKeyFromObject(route).String()] = struct{}{}
 			continue
 		}
@@ -158,7 +184,7 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 			return nil, fmt.Errorf("wasmPluginConfig: multiple gateway RLP found and only one expected. rlp keys: %v", rlpRefs)
 		}
 		gwRLPKey = rlpKey.String()
-		rlps[gwRLPKey] = &store{rlp: *rlp}
+		rlps[gwRLPKey] = &store{rlp: *rlp, keep: keep}
 	}
 
 	gwHostnames := gw.Hostnames()
@@ -169,16 +195,7 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 	// if there is a gateway rlp, fake a single httproute with all rules from all httproutes accepted by the gateway,
 	// that do not have a rlp of its own, so we can generate wasm rules for those cases
 	if gwRLPKey != "" {
-		rules := make([]gatewayapiv1beta1.HTTPRouteRule, 0)
-		routes := r.FetchAcceptedGatewayHTTPRoutes(ctx, rlps[gwRLPKey].rlp.TargetKey())
-		for idx := range routes {
-			route := routes[idx]
-			// skip routes that have a rlp of its own
-			if _, found := routeKeys[client.ObjectKeyFromObject(&route).String()]; found {
-				continue
-			}
-			rules = append(rules, route.Spec.Rules...)
-		}
+		rules := r.gatewayRulesWithoutPolicy(ctx, rlps[gwRLPKey].rlp.TargetKey(), routeKeys)
 		if len(rules) == 0 {
 			logger.V(1).Info("no httproutes attached to the targeted gateway, skipping wasm config for the gateway rlp", "ratelimitpolicy", gwRLPKey)
 			rlps[gwRLPKey].skip = true
@@ -192,13 +209,43 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 		}
 	}
 
+	// the gateway fails closed if any of its policies does
+	failureMode := wasm.FailureModeAllow
+	for _, rlpKey := range rlpRefs {
+		s := rlps[rlpKey.String()]
+		if s.keep {
+			// the policies kept as they are keep the gateway failing closed if it does
+			if currentConfig != nil && currentConfig.FailureMode == wasm.FailureModeDeny {
+				failureMode = wasm.FailureModeDeny
+			}
+			continue
+		}
+		if s.rlp.GetFailureMode() == kuadrantv1beta2.FailClosed {
+			failureMode = wasm.FailureModeDeny
+		}
+	}
+
+	// the gateways of a kuadrant instance with a counter domain share the counters of the policies
+	counterDomain, err := gatewayCounterDomain(ctx, r.Client(), gw.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
 	wasmPlugin := &wasm.Plugin{
-		FailureMode:       wasm.FailureModeDeny,
+		FailureMode:       failureMode,
 		RateLimitPolicies: make([]wasm.RateLimitPolicy, 0),
 	}
 
 	for _, rlpKey := range rlpRefs {
 		s := rlps[rlpKey.String()]
+		if s.keep {
+			if currentConfig != nil {
+				if current, found := common.Find(currentConfig.RateLimitPolicies, func(p wasm.RateLimitPolicy) bool { return p.Name == rlpKey.String() }); found {
+					wasmPlugin.RateLimitPolicies = append(wasmPlugin.RateLimitPolicies, *current)
+				}
+			}
+			continue
+		}
 		if s.skip {
 			continue
 		}
@@ -220,13 +267,38 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 
 		wasmPlugin.RateLimitPolicies = append(wasmPlugin.RateLimitPolicies, wasm.RateLimitPolicy{
 			Name:      rlpKey.String(),
-			Domain:    rlptools.LimitsNamespaceFromRLP(&rlp),
+			Domain:    rlptools.PolicyLimitsNamespace(counterDomain, gw.Key(), rlpKey),
 			Rules:     rules,
 			Hostnames: common.HostnamesToStrings(hostnames), // we might be listing more hostnames than needed due to route selectors hostnames possibly being more restrictive
 			Service:   common.KuadrantRateLimitClusterName,
 		})
 	}
 
+	// without a gateway rlp, the default rate limit of the kuadrant instance applies to the same rules instead
+	if gwRLPKey == "" {
+		defaultRateLimit, err := gatewayDefaultRateLimit(ctx, r.Client(), gw.Gateway)
+		if err != nil {
+			return nil, err
+		}
+		if rules := r.gatewayRulesWithoutPolicy(ctx, gw.Key(), routeKeys); defaultRateLimit != nil && len(rules) > 0 {
+			route := &gatewayapiv1beta1.HTTPRoute{
+				Spec: gatewayapiv1beta1.HTTPRouteSpec{
+					Hostnames: gwHostnames,
+					Rules:     rules,
+				},
+			}
+			if wasmRules := rlptools.WasmRules(rlptools.DefaultRateLimitPolicy(defaultRateLimit), route); len(wasmRules) > 0 {
+				wasmPlugin.RateLimitPolicies = append(wasmPlugin.RateLimitPolicies, wasm.RateLimitPolicy{
+					Name:      rlptools.DefaultRateLimitName,
+					Domain:    rlptools.DefaultLimitsNamespace(gw.Key()),
+					Rules:     wasmRules,
+					Hostnames: common.HostnamesToStrings(gwHostnames),
+					Service:   common.KuadrantRateLimitClusterName,
+				})
+			}
+		}
+	}
+
 	// avoid building a wasm plugin config if there are no rules to apply
 	if len(wasmPlugin.RateLimitPolicies) == 0 {
 		return nil, nil
@@ -234,3 +306,29 @@ func (r *RateLimitPolicyReconciler) wasmPluginConfig(ctx context.Context, gw com
 
 	return wasmPlugin, nil
 }
+
+// resolveWASMPluginLimits resolves the limits of a policy enforced by a gateway, i.e. inherited from its template and
+// merged with the defaults of the gateway policy
+func (r *RateLimitPolicyReconciler) resolveWASMPluginLimits(ctx context.Context, rlp *kuadrantv1beta2.RateLimitPolicy, gwKey client.ObjectKey) error {
+	if err := resolveRateLimitPolicyTemplate(ctx, r.Client(), rlp); err != nil {
+		return err
+	}
+	_, err := r.resolveGatewayDefaults(ctx, rlp, gwKey)
+	return err
+}
+
+// gatewayRulesWithoutPolicy returns the rules of the httproutes accepted by a gateway that do not have a rlp of
+// their own, i.e. not in routeKeys
+func (r *RateLimitPolicyReconciler) gatewayRulesWithoutPolicy(ctx context.Context, gwKey client.ObjectKey, routeKeys map[string]struct{}) []gatewayapiv1beta1.HTTPRouteRule {
+	rules := make([]gatewayapiv1beta1.HTTPRouteRule, 0)
+	routes := r.FetchAcceptedGatewayHTTPRoutes(ctx, gwKey)
+	for idx := range routes {
+		route := routes[idx]
+		// skip routes that have a rlp of its own
+		if _, found := routeKeys[client.ObjectKeyFromObject(&route).String()]; found {
+			continue
+		}
+		rules = append(rules, route.Spec.Rules...)
+	}
+	return rules
+}
@@ -0,0 +1,107 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+	"github.com/kuadrant/kuadrant-operator/pkg/rlptools/wasm"
+)
+
+func TestWasmPluginConfigPolicyTemplateNotFound(t *testing.T) {
+	gw := testGateway("gw")
+	route := testHTTPRoute("route", gw, "api.example.com")
+	otherRoute := testHTTPRoute("other-route", gw, "www.example.com")
+	gwRLP := testRateLimitPolicy("gw-rlp", gw, 100)
+	routeRLP := testRateLimitPolicy("route-rlp", route, 10)
+	routeRLP.Spec.TemplateRef = &corev1.LocalObjectReference{Name: "missing"}
+
+	r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route, otherRoute, gwRLP, routeRLP)}
+	gwWrapper := common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}
+	rlpRefs := []client.ObjectKey{client.ObjectKeyFromObject(gwRLP), client.ObjectKeyFromObject(routeRLP)}
+
+	configuredRouteRLP := wasm.RateLimitPolicy{
+		Name:      client.ObjectKeyFromObject(routeRLP).String(),
+		Domain:    "ns/route-rlp",
+		Service:   common.KuadrantRateLimitClusterName,
+		Hostnames: []string{"api.example.com"},
+		Rules:     []wasm.Rule{{Data: []wasm.DataItem{{Static: &wasm.StaticSpec{Key: "limit.global__f63bec56", Value: "1"}}}}},
+	}
+
+	t.Run("config of the policy in place kept", func(subT *testing.T) {
+		config, err := r.wasmPluginConfig(context.TODO(), gwWrapper, rlpRefs, &wasm.Plugin{
+			FailureMode:       wasm.FailureModeAllow,
+			RateLimitPolicies: []wasm.RateLimitPolicy{configuredRouteRLP},
+		})
+		if err != nil {
+			subT.Fatalf("the policy with a template not found should not fail the others: %v", err)
+		}
+		if len(config.RateLimitPolicies) != 2 {
+			subT.Fatalf("expected the config of both policies, got %v", config.RateLimitPolicies)
+		}
+		if name := config.RateLimitPolicies[0].Name; name != client.ObjectKeyFromObject(gwRLP).String() {
+			subT.Errorf("expected the config of the gateway policy, got %s", name)
+		}
+		if !reflect.DeepEqual(config.RateLimitPolicies[1], configuredRouteRLP) {
+			subT.Errorf("expected the config in place of the policy with a template not found, got %v", config.RateLimitPolicies[1])
+		}
+	})
+
+	t.Run("no config in place", func(subT *testing.T) {
+		config, err := r.wasmPluginConfig(context.TODO(), gwWrapper, rlpRefs, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if len(config.RateLimitPolicies) != 1 || config.RateLimitPolicies[0].Name != client.ObjectKeyFromObject(gwRLP).String() {
+			subT.Errorf("expected the config of the gateway policy only, got %v", config.RateLimitPolicies)
+		}
+		// the route of the policy not applied is not covered by the gateway policy either
+		if hostnames := config.RateLimitPolicies[0].Hostnames; reflect.DeepEqual(hostnames, []string{"api.example.com"}) {
+			subT.Errorf("expected the gateway policy not to apply to the route of the other policy, got %v", hostnames)
+		}
+	})
+}
+
+func TestWasmPluginConfigFailureMode(t *testing.T) {
+	gw := testGateway("gw")
+	route := testHTTPRoute("route", gw, "api.example.com")
+	otherRoute := testHTTPRoute("other-route", gw, "www.example.com")
+	gwWrapper := common.GatewayWrapper{Gateway: gw, PolicyRefsConfig: &common.KuadrantRateLimitPolicyRefsConfig{}}
+
+	testCases := []struct {
+		name                string
+		gwFailureMode       kuadrantv1beta2.FailureMode
+		routeFailureMode    kuadrantv1beta2.FailureMode
+		expectedFailureMode wasm.FailureModeType
+	}{
+		{name: "not set", expectedFailureMode: wasm.FailureModeAllow},
+		{name: "all policies failing open", gwFailureMode: kuadrantv1beta2.FailOpen, routeFailureMode: kuadrantv1beta2.FailOpen, expectedFailureMode: wasm.FailureModeAllow},
+		{name: "gateway policy failing closed", gwFailureMode: kuadrantv1beta2.FailClosed, expectedFailureMode: wasm.FailureModeDeny},
+		{name: "route policy failing closed", gwFailureMode: kuadrantv1beta2.FailOpen, routeFailureMode: kuadrantv1beta2.FailClosed, expectedFailureMode: wasm.FailureModeDeny},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			gwRLP := testRateLimitPolicy("gw-rlp", gw, 100)
+			gwRLP.Spec.FailureMode = tc.gwFailureMode
+			routeRLP := testRateLimitPolicy("route-rlp", route, 10)
+			routeRLP.Spec.FailureMode = tc.routeFailureMode
+
+			r := &RateLimitPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(gw, route, otherRoute, gwRLP, routeRLP)}
+			config, err := r.wasmPluginConfig(context.TODO(), gwWrapper, []client.ObjectKey{client.ObjectKeyFromObject(gwRLP), client.ObjectKeyFromObject(routeRLP)}, nil)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if config.FailureMode != tc.expectedFailureMode {
+				subT.Errorf("expected the failure mode %s, got %s", tc.expectedFailureMode, config.FailureMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ReconcileTriggerPath is the path of the endpoint to trigger the reconciliation of all the resources on demand
+const ReconcileTriggerPath = "/reconcile"
+
+// ErrReconcileTriggerNotStarted is returned when triggering the reconciliation of objects the controller of which is
+// not started, e.g. in a replica not elected leader
+var ErrReconcileTriggerNotStarted = errors.New("controller not started, e.g. not the leader replica")
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// ReconcileTrigger enqueues the reconciliation of all the resources watched by the controllers on demand,
+// without requiring any change to the resources
+type ReconcileTrigger struct {
+	client  client.Client
+	logger  logr.Logger
+	targets []reconcileTriggerTarget
+}
+
+type reconcileTriggerTarget struct {
+	list    client.ObjectList
+	events  chan event.GenericEvent
+	started *atomic.Bool
+}
+
+// reconcileTriggerSource is a channel source telling whether the controller watching it is started
+type reconcileTriggerSource struct {
+	*source.Channel
+	started *atomic.Bool
+}
+
+func (s *reconcileTriggerSource) Start(ctx context.Context, h handler.EventHandler, queue workqueue.RateLimitingInterface, prct ...predicate.Predicate) error {
+	if err := s.Channel.Start(ctx, h, queue, prct...); err != nil {
+		return err
+	}
+	s.started.Store(true)
+	return nil
+}
+
+func NewReconcileTrigger(c client.Client, logger logr.Logger) *ReconcileTrigger {
+	return &ReconcileTrigger{client: c, logger: logger}
+}
+
+// Source returns a source of events for the objects of the given list type,
+// to be watched by the controller of the objects
+func (t *ReconcileTrigger) Source(list client.ObjectList) source.Source {
+	events := make(chan event.GenericEvent)
+	started := &atomic.Bool{}
+	t.targets = append(t.targets, reconcileTriggerTarget{list: list, events: events, started: started})
+	return &reconcileTriggerSource{Channel: &source.Channel{Source: events}, started: started}
+}
+
+// Trigger enqueues the reconciliation of all the objects watched through the sources of the trigger.
+// Returns the number of objects enqueued.
+func (t *ReconcileTrigger) Trigger(ctx context.Context) (int, error) {
+	return t.TriggerFor(ctx, nil)
+}
+
+// TriggerFor enqueues the reconciliation of the objects of the given list types watched through the sources of the
+// trigger, all if none.
+// Returns the number of objects enqueued. Fails with ErrReconcileTriggerNotStarted, without waiting, if the controller
+// of any of the list types is not started.
+func (t *ReconcileTrigger) TriggerFor(ctx context.Context, lists []client.ObjectList) (int, error) {
+	count := 0
+	for _, target := range t.targets {
+		if len(lists) > 0 && !containsListType(lists, target.list) {
+			continue
+		}
+		// the controllers only run in the leader replica
+		if !target.started.Load() {
+			return count, ErrReconcileTriggerNotStarted
+		}
+		list, _ := target.list.DeepCopyObject().(client.ObjectList)
+		if err := t.client.List(ctx, list); err != nil {
+			return count, err
+		}
+		err := meta.EachListItem(list, func(obj runtime.Object) error {
+			object, ok := obj.(client.Object)
+			if !ok {
+				return fmt.Errorf("%T is not a client.Object", obj)
+			}
+			select {
+			case target.events <- event.GenericEvent{Object: object}:
+				count++
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("failed to enqueue %s: %w", client.ObjectKeyFromObject(object), ctx.Err())
+			}
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func containsListType(lists []client.ObjectList, list client.ObjectList) bool {
+	for _, l := range lists {
+		if reflect.TypeOf(l) == reflect.TypeOf(list) {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP triggers the reconciliation of all the resources.
+// The requests must be authenticated with a bearer token of a subject allowed to post to the path of the endpoint.
+func (t *ReconcileTrigger) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if statusCode, err := t.authorize(req); err != nil {
+		t.logger.Info("unauthorized reconcile request", "reason", err.Error())
+		http.Error(rw, http.StatusText(statusCode), statusCode)
+		return
+	}
+
+	count, err := t.Trigger(req.Context())
+	if err != nil {
+		t.logger.Error(err, "failed to trigger reconciliation", "enqueued", count)
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	t.logger.Info("reconciliation triggered", "enqueued", count)
+	fmt.Fprintf(rw, "enqueued %d resources\n", count)
+}
+
+// authorize reviews the bearer token of the request and checks whether the subject is allowed to post to the endpoint
+func (t *ReconcileTrigger) authorize(req *http.Request) (int, error) {
+	return authorizeNonResourceRequest(t.client, req, ReconcileTriggerPath, "post")
+}
+
+// authorizeNonResourceRequest reviews the bearer token of a request to an endpoint of the operator and checks whether
+// the subject is allowed the verb on the path of the endpoint
+func authorizeNonResourceRequest(cli client.Client, req *http.Request, path, verb string) (int, error) {
+	authorization := req.Header.Get("Authorization")
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if token == authorization || token == "" {
+		return http.StatusUnauthorized, fmt.Errorf("missing bearer token")
+	}
+
+	tokenReview := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := cli.Create(req.Context(), tokenReview); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("token not authenticated: %s", tokenReview.Status.Error)
+	}
+
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: verb,
+			},
+		},
+	}
+	if err := cli.Create(req.Context(), accessReview); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !accessReview.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("subject %s not allowed: %s", user.Username, accessReview.Status.Reason)
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+func TestReconcileTrigger(t *testing.T) {
+	gw := testGateway("gw")
+	cl := fake.NewClientBuilder().WithScheme(unitTestScheme()).WithObjects(
+		testRateLimitPolicy("rlp-1", gw, 10),
+		testRateLimitPolicy("rlp-2", gw, 20),
+	).Build()
+
+	trigger := NewReconcileTrigger(cl, logr.Discard())
+	rlpSource, ok := trigger.Source(&kuadrantv1beta2.RateLimitPolicyList{}).(*reconcileTriggerSource)
+	if !ok {
+		t.Fatal("expected a reconcile trigger source")
+	}
+	trigger.Source(&kuadrantv1beta1.AuthPolicyList{})
+
+	// the controllers are not started, e.g. in a replica not elected leader
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	if count, err := trigger.Trigger(ctx); !errors.Is(err, ErrReconcileTriggerNotStarted) || count != 0 {
+		t.Fatalf("expected the trigger to fail without waiting, got %d, %v", count, err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected the trigger not to wait for the controllers to be started")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	if err := rlpSource.InjectStopChannel(stop); err != nil {
+		t.Fatal(err)
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	if err := rlpSource.Start(ctx, &handler.EnqueueRequestForObject{}, queue); err != nil {
+		t.Fatal(err)
+	}
+
+	// the controller of the authpolicies is still not started
+	if _, err := trigger.Trigger(ctx); !errors.Is(err, ErrReconcileTriggerNotStarted) {
+		t.Errorf("expected the trigger to fail while a controller is not started, got %v", err)
+	}
+
+	count, err := trigger.TriggerFor(ctx, []client.ObjectList{&kuadrantv1beta2.RateLimitPolicyList{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 objects enqueued, got %d", count)
+	}
+	for i := 0; i < 2; i++ {
+		if item, shutdown := queue.Get(); shutdown || item == nil {
+			t.Fatal("expected the objects to be enqueued")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// Number of concurrent reconciliations of each controller.
+// The policies securing the traffic get more workers by default, so their changes are applied first under load.
+var (
+	AuthPolicyReconcileWorkers      = reconcileWorkersFromEnv("AUTHPOLICY_RECONCILE_WORKERS", 2)
+	RateLimitPolicyReconcileWorkers = reconcileWorkersFromEnv("RATELIMITPOLICY_RECONCILE_WORKERS", 1)
+	KuadrantReconcileWorkers        = reconcileWorkersFromEnv("KUADRANT_RECONCILE_WORKERS", 1)
+)
+
+// Rate of the requeues of each controller, so a kind of resource requeued in a loop cannot monopolize the
+// reconciliation capacity. The policies securing the traffic get a higher rate by default.
+var (
+	AuthPolicyReconcileRateLimiter      = reconcileRateLimiterFromEnv("AUTHPOLICY", 20, 200)
+	RateLimitPolicyReconcileRateLimiter = reconcileRateLimiterFromEnv("RATELIMITPOLICY", 10, 100)
+	KuadrantReconcileRateLimiter        = reconcileRateLimiterFromEnv("KUADRANT", 10, 100)
+)
+
+// reconcileRates holds the resolved rates of the requeues, indexed by env var name
+var reconcileRates = map[string]string{}
+
+func reconcileWorkersFromEnv(key string, def int) int {
+	workers, err := strconv.Atoi(common.FetchEnv(key, strconv.Itoa(def)))
+	if err != nil || workers < 1 {
+		return def
+	}
+	return workers
+}
+
+// reconcileRateLimiterFromEnv returns the rate limiter of the queue of a controller, with the overall rate of requeues
+// read from the <kind>_RECONCILE_QPS and <kind>_RECONCILE_BURST env vars.
+// The per-item exponential backoff on failures is the one of controller-runtime.
+func reconcileRateLimiterFromEnv(kind string, defQPS float64, defBurst int) workqueue.RateLimiter {
+	qps, err := strconv.ParseFloat(common.FetchEnv(kind+"_RECONCILE_QPS", ""), 64)
+	if err != nil || qps <= 0 {
+		qps = defQPS
+	}
+	burst, err := strconv.Atoi(common.FetchEnv(kind+"_RECONCILE_BURST", ""))
+	if err != nil || burst < 1 {
+		burst = defBurst
+	}
+	reconcileRates[kind+"_RECONCILE_QPS"] = strconv.FormatFloat(qps, 'f', -1, 64)
+	reconcileRates[kind+"_RECONCILE_BURST"] = strconv.Itoa(burst)
+
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+// ReferenceGrantEventMapper is an EventHandler that maps ReferenceGrant events to the policies targeting objects
+// in the namespace of the ReferenceGrant from other namespaces
+type ReferenceGrantEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *ReferenceGrantEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	apList := &kuadrantv1beta1.AuthPolicyList{}
+	if err := m.Client.List(context.TODO(), apList); err != nil {
+		m.Logger.V(1).Info("MapToAuthPolicy: failed to list authpolicies", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range apList.Items {
+		ap := &apList.Items[idx]
+		if !ap.IsCrossNamespaceTargetRef() || string(*ap.Spec.TargetRef.Namespace) != obj.GetNamespace() {
+			continue
+		}
+		m.Logger.V(1).Info("MapToAuthPolicy", "referencegrant", client.ObjectKeyFromObject(obj), "authpolicy", client.ObjectKeyFromObject(ap))
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ap)})
+	}
+
+	return requests
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// limitadorDeploymentLabels are the labels set by the Limitador operator on the deployments of Limitador
+var limitadorDeploymentLabels = map[string]string{"app": "limitador"}
+
+// NewCache returns the cache of the manager, holding the ConfigMaps of the namespace of the operator only, i.e. the
+// ConfigMaps watched by the operator: the component overrides and the operator config, and the deployments of
+// Limitador only, watched for their readiness and their rollouts.
+// The other ConfigMaps and deployments of the cluster are not cached.
+func NewCache(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+	if opts.SelectorsByObject == nil {
+		opts.SelectorsByObject = cache.SelectorsByObject{}
+	}
+	opts.SelectorsByObject[&corev1.ConfigMap{}] = cache.ObjectSelector{
+		Field: fields.OneTermEqualSelector("metadata.namespace", operatorNamespace()),
+	}
+	opts.SelectorsByObject[&appsv1.Deployment{}] = cache.ObjectSelector{
+		Label: labels.SelectorFromSet(limitadorDeploymentLabels),
+	}
+	return cache.New(config, opts)
+}
+
+// NewClient returns the client of the manager, reading the ConfigMaps and the deployments out of the scope of the
+// cache from the API server
+func NewClient(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewDelegatingClient(client.NewDelegatingClientInput{
+		CacheReader:     &scopedCacheReader{Reader: cache, apiReader: c, namespace: operatorNamespace()},
+		Client:          c,
+		UncachedObjects: uncachedObjects,
+	})
+}
+
+// scopedCacheReader reads the ConfigMaps of the namespace of the operator and the deployments of Limitador from the
+// cache, and the other ConfigMaps and deployments from the API server
+type scopedCacheReader struct {
+	client.Reader
+	apiReader client.Reader
+	namespace string
+}
+
+func (r *scopedCacheReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		if key.Namespace != r.namespace {
+			return r.apiReader.Get(ctx, key, obj, opts...)
+		}
+	case *appsv1.Deployment:
+		if key.Name != common.LimitadorName {
+			return r.apiReader.Get(ctx, key, obj, opts...)
+		}
+	}
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func (r *scopedCacheReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	switch list.(type) {
+	case *corev1.ConfigMapList:
+		if (&client.ListOptions{}).ApplyOptions(opts).Namespace != r.namespace {
+			return r.apiReader.List(ctx, list, opts...)
+		}
+	case *appsv1.DeploymentList:
+		return r.apiReader.List(ctx, list, opts...)
+	}
+	return r.Reader.List(ctx, list, opts...)
+}
@@ -0,0 +1,58 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+func TestScopedCacheReader(t *testing.T) {
+	operatorConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "overrides", Namespace: operatorNamespace()}}
+	istioConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "istio-system"}}
+	limitadorDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: common.LimitadorName, Namespace: "kuadrant-system", Labels: limitadorDeploymentLabels}}
+	authorinoDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "authorino", Namespace: "kuadrant-system"}}
+
+	// the cache only holds the configmaps of the namespace of the operator and the deployments of limitador
+	cacheReader := fake.NewClientBuilder().WithScheme(unitTestScheme()).WithObjects(operatorConfigMap, secret, limitadorDeployment).Build()
+	apiReader := fake.NewClientBuilder().WithScheme(unitTestScheme()).WithObjects(operatorConfigMap, istioConfigMap, limitadorDeployment, authorinoDeployment).Build()
+	reader := &scopedCacheReader{Reader: cacheReader, apiReader: apiReader, namespace: operatorNamespace()}
+	ctx := context.TODO()
+
+	for _, cm := range []*corev1.ConfigMap{operatorConfigMap, istioConfigMap} {
+		if err := reader.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+			t.Errorf("expected the configmap %s to be read, got %v", client.ObjectKeyFromObject(cm), err)
+		}
+	}
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(secret), &corev1.Secret{}); err != nil {
+		t.Errorf("expected the other kinds to be read from the cache, got %v", err)
+	}
+
+	for _, deployment := range []*appsv1.Deployment{limitadorDeployment, authorinoDeployment} {
+		if err := reader.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err != nil {
+			t.Errorf("expected the deployment %s to be read, got %v", client.ObjectKeyFromObject(deployment), err)
+		}
+	}
+	deploymentList := &appsv1.DeploymentList{}
+	if err := reader.List(ctx, deploymentList, client.InNamespace("kuadrant-system")); err != nil || len(deploymentList.Items) != 2 {
+		t.Errorf("expected the deployments to be listed from the api server, got %v, %v", deploymentList.Items, err)
+	}
+
+	cmList := &corev1.ConfigMapList{}
+	if err := reader.List(ctx, cmList, client.InNamespace("istio-system")); err != nil || len(cmList.Items) != 1 {
+		t.Errorf("expected the configmaps of the other namespaces to be listed from the api server, got %v, %v", cmList.Items, err)
+	}
+	cmList = &corev1.ConfigMapList{}
+	if err := reader.List(ctx, cmList); err != nil || len(cmList.Items) != 2 {
+		t.Errorf("expected the configmaps of all the namespaces to be listed from the api server, got %v, %v", cmList.Items, err)
+	}
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+// SecretEventMapper is an EventHandler that maps Secret events to the policies whose AuthConfigs reference the Secret
+// by name, or select it by label with an API key or mTLS identity
+type SecretEventMapper struct {
+	Logger logr.Logger
+	Client client.Client
+}
+
+func (m *SecretEventMapper) MapToAuthPolicy(obj client.Object) []reconcile.Request {
+	authConfigList := &authorinov1beta1.AuthConfigList{}
+	if err := m.Client.List(context.TODO(), authConfigList); err != nil {
+		m.Logger.V(1).Info("MapToAuthPolicy: failed to list authconfigs", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for idx := range authConfigList.Items {
+		authConfig := &authConfigList.Items[idx]
+		policyKey, ok := authConfigPolicyKey(authConfig)
+		if !ok || !authConfigUsesSecret(authConfig, obj) {
+			continue
+		}
+		m.Logger.V(1).Info("MapToAuthPolicy", "secret", client.ObjectKeyFromObject(obj), "authpolicy", policyKey)
+		requests = append(requests, reconcile.Request{NamespacedName: policyKey})
+	}
+
+	return requests
+}
+
+// authConfigUsesSecret tells whether an AuthConfig references a Secret by name, or selects it by label
+func authConfigUsesSecret(authConfig *authorinov1beta1.AuthConfig, secret client.Object) bool {
+	if secret.GetNamespace() == authConfig.Namespace {
+		authScheme := &kuadrantv1beta1.AuthSchemeSpec{
+			Identity:      authConfig.Spec.Identity,
+			Metadata:      authConfig.Spec.Metadata,
+			Authorization: authConfig.Spec.Authorization,
+			Response:      authConfig.Spec.Response,
+		}
+		for _, name := range authScheme.SecretRefs() {
+			if name == secret.GetName() {
+				return true
+			}
+		}
+	}
+	for _, selector := range authConfigSecretSelectors(&authConfig.Spec) {
+		if selector.selects(authConfig.Namespace, secret) {
+			return true
+		}
+	}
+	return false
+}
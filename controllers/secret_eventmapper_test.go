@@ -0,0 +1,134 @@
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// testSecretsAuthConfig returns the AuthConfig of the policy ns/ap, referencing the Secret oauth2-credentials and
+// selecting the API keys labeled app=toystore
+func testSecretsAuthConfig(allNamespaces bool) *authorinov1beta1.AuthConfig {
+	return &authorinov1beta1.AuthConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ap-config",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				common.AuthPolicyNameAnnotation:      "ap",
+				common.AuthPolicyNamespaceAnnotation: "ns",
+			},
+		},
+		Spec: authorinov1beta1.AuthConfigSpec{
+			Hosts: []string{"api.example.com"},
+			Identity: []*authorinov1beta1.Identity{
+				{
+					Name: "introspection",
+					OAuth2: &authorinov1beta1.Identity_OAuth2Config{
+						TokenIntrospectionUrl: "https://idp.example.com/introspect",
+						Credentials:           &corev1.LocalObjectReference{Name: "oauth2-credentials"},
+					},
+				},
+				{
+					Name: "api-key-users",
+					APIKey: &authorinov1beta1.Identity_APIKey{
+						Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "toystore"}},
+						AllNamespaces: allNamespaces,
+					},
+				},
+			},
+		},
+	}
+}
+
+func testSecret(namespace, name string, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+}
+
+func TestSecretEventMapper(t *testing.T) {
+	r := unitTestTargetRefReconciler(testSecretsAuthConfig(false))
+	mapper := &SecretEventMapper{Logger: logr.Discard(), Client: r.Client()}
+	policyRequest := reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "ap"}}
+
+	testCases := []struct {
+		name   string
+		secret *corev1.Secret
+		mapped bool
+	}{
+		{name: "referenced by name", secret: testSecret("ns", "oauth2-credentials", nil), mapped: true},
+		{name: "same name in another namespace", secret: testSecret("other", "oauth2-credentials", nil), mapped: false},
+		{name: "selected by label", secret: testSecret("ns", "api-key-1", map[string]string{"app": "toystore"}), mapped: true},
+		{name: "selected by label in another namespace", secret: testSecret("other", "api-key-1", map[string]string{"app": "toystore"}), mapped: false},
+		{name: "not selected", secret: testSecret("ns", "api-key-2", map[string]string{"app": "other"}), mapped: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			requests := mapper.MapToAuthPolicy(tc.secret)
+			if tc.mapped && (len(requests) != 1 || requests[0] != policyRequest) {
+				subT.Errorf("expected the policy enqueued, got %v", requests)
+			}
+			if !tc.mapped && len(requests) != 0 {
+				subT.Errorf("expected no policy enqueued, got %v", requests)
+			}
+		})
+	}
+
+	t.Run("selected by label in all namespaces", func(subT *testing.T) {
+		r := unitTestTargetRefReconciler(testSecretsAuthConfig(true))
+		mapper := &SecretEventMapper{Logger: logr.Discard(), Client: r.Client()}
+		if requests := mapper.MapToAuthPolicy(testSecret("other", "api-key-1", map[string]string{"app": "toystore"})); len(requests) != 1 {
+			subT.Errorf("expected the policy enqueued, got %v", requests)
+		}
+	})
+}
+
+func TestAuthPolicyMissingSecrets(t *testing.T) {
+	testCases := []struct {
+		name               string
+		secrets            []client.Object
+		missing            []client.ObjectKey
+		unmatchedSelectors []string
+	}{
+		{
+			name:    "all found",
+			secrets: []client.Object{testSecret("ns", "oauth2-credentials", nil), testSecret("ns", "api-key-1", map[string]string{"app": "toystore"})},
+		},
+		{
+			name:    "referenced missing",
+			secrets: []client.Object{testSecret("ns", "api-key-1", map[string]string{"app": "toystore"})},
+			missing: []client.ObjectKey{{Namespace: "ns", Name: "oauth2-credentials"}},
+		},
+		{
+			name:               "none selected",
+			secrets:            []client.Object{testSecret("ns", "oauth2-credentials", nil), testSecret("other", "api-key-1", map[string]string{"app": "toystore"})},
+			unmatchedSelectors: []string{"api-key-users (app=toystore)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			r := &AuthPolicyReconciler{TargetRefReconciler: unitTestTargetRefReconciler(tc.secrets...)}
+			missing, unmatchedSelectors, err := r.missingSecrets(context.TODO(), testSecretsAuthConfig(false))
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if len(missing) != len(tc.missing) || (len(missing) > 0 && missing[0] != tc.missing[0]) {
+				subT.Errorf("expected the secrets missing %v, got %v", tc.missing, missing)
+			}
+			selectors := common.Map(unmatchedSelectors, authConfigSecretSelector.String)
+			if len(selectors) != len(tc.unmatchedSelectors) || (len(selectors) > 0 && selectors[0] != tc.unmatchedSelectors[0]) {
+				subT.Errorf("expected the selectors selecting no secret %v, got %v", tc.unmatchedSelectors, selectors)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// SimulatorPath is the path of the endpoint to simulate which policies apply to a request
+const SimulatorPath = "/simulate"
+
+// SimulationResult lists the HTTPRoutes matching a simulated request, along with the policies in play for each route
+type SimulationResult struct {
+	Request common.HTTPRequestAttributes `json:"request"`
+	Routes  []SimulatedRoute             `json:"routes"`
+}
+
+// SimulatedRoute is a HTTPRoute matching a simulated request
+type SimulatedRoute struct {
+	HTTPRoute string `json:"httpRoute"`
+	// RuleIndex is the index of the first rule of the HTTPRoute matching the request
+	RuleIndex         int                        `json:"ruleIndex"`
+	Gateways          []string                   `json:"gateways"`
+	AuthPolicies      []string                   `json:"authPolicies"`
+	RateLimitPolicies []SimulatedRateLimitPolicy `json:"rateLimitPolicies"`
+}
+
+// SimulatedRateLimitPolicy is a RateLimitPolicy in play for a simulated request, with the limits selecting the route rule.
+// The when conditions of the limits are not evaluated.
+type SimulatedRateLimitPolicy struct {
+	Name   string                           `json:"name"`
+	Limits map[string]kuadrantv1beta2.Limit `json:"limits"`
+}
+
+// Simulator resolves which policies apply to a request, without sending the request.
+// It does not tell whether the request would be allowed, only which policies and limits are in play.
+type Simulator struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewSimulator(c client.Client, logger logr.Logger) *Simulator {
+	return &Simulator{client: c, logger: logger}
+}
+
+// Simulate walks the HTTPRoutes matching the request, and the policies targeting the routes and their gateways
+func (s *Simulator) Simulate(ctx context.Context, req common.HTTPRequestAttributes) (*SimulationResult, error) {
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	if req.Path == "" {
+		req.Path = "/"
+	}
+
+	routeList := &gatewayapiv1beta1.HTTPRouteList{}
+	if err := s.client.List(ctx, routeList); err != nil {
+		return nil, err
+	}
+
+	result := &SimulationResult{Request: req, Routes: make([]SimulatedRoute, 0)}
+	for idx := range routeList.Items {
+		route := &routeList.Items[idx]
+		if !req.MatchesHostnames(common.RouteHostnames(route)) {
+			continue
+		}
+		ruleIndex := -1
+		for ruleIdx, rule := range route.Spec.Rules {
+			if req.MatchesHTTPRouteRule(rule) {
+				ruleIndex = ruleIdx
+				break
+			}
+		}
+		if ruleIndex < 0 {
+			continue
+		}
+
+		simulatedRoute, err := s.simulateRoute(ctx, req, route, ruleIndex)
+		if err != nil {
+			return nil, err
+		}
+		result.Routes = append(result.Routes, *simulatedRoute)
+	}
+
+	return result, nil
+}
+
+func (s *Simulator) simulateRoute(ctx context.Context, req common.HTTPRequestAttributes, route *gatewayapiv1beta1.HTTPRoute, ruleIndex int) (*SimulatedRoute, error) {
+	simulated := &SimulatedRoute{
+		HTTPRoute:         client.ObjectKeyFromObject(route).String(),
+		RuleIndex:         ruleIndex,
+		Gateways:          make([]string, 0),
+		AuthPolicies:      make([]string, 0),
+		RateLimitPolicies: make([]SimulatedRateLimitPolicy, 0),
+	}
+
+	// the policies targeting the route take precedence over the policies targeting the gateways
+	targets := []client.Object{route}
+	for _, parentRef := range route.Spec.ParentRefs {
+		gwKey := client.ObjectKey{Name: string(parentRef.Name), Namespace: string(common.GetDefaultIfNil(parentRef.Namespace, gatewayapiv1beta1.Namespace(route.Namespace)))}
+		gateway := &gatewayapiv1beta1.Gateway{}
+		if err := s.client.Get(ctx, gwKey, gateway); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+		simulated.Gateways = append(simulated.Gateways, gwKey.String())
+		targets = append(targets, gateway)
+	}
+
+	for _, target := range targets {
+		annotations := common.ReadAnnotationsFromObject(target)
+
+		if apRef, found := annotations[common.AuthPolicyBackRefAnnotation]; found {
+			ap := &kuadrantv1beta1.AuthPolicy{}
+			if err := s.client.Get(ctx, common.NamespacedNameToObjectKey(apRef, target.GetNamespace()), ap); client.IgnoreNotFound(err) != nil {
+				return nil, err
+			} else if err == nil && authPolicyMatchesRequest(ap, req) {
+				simulated.AuthPolicies = append(simulated.AuthPolicies, client.ObjectKeyFromObject(ap).String())
+			}
+		}
+
+		if rlpRef, found := annotations[common.RateLimitPolicyBackRefAnnotation]; found {
+			rlp := &kuadrantv1beta2.RateLimitPolicy{}
+			if err := s.client.Get(ctx, common.NamespacedNameToObjectKey(rlpRef, target.GetNamespace()), rlp); client.IgnoreNotFound(err) != nil {
+				return nil, err
+			} else if err == nil {
+				if err := resolveRateLimitPolicyTemplate(ctx, s.client, rlp); err != nil {
+					return nil, err
+				}
+				simulated.RateLimitPolicies = append(simulated.RateLimitPolicies, SimulatedRateLimitPolicy{
+					Name:   client.ObjectKeyFromObject(rlp).String(),
+					Limits: limitsSelectingRule(rlp, route, route.Spec.Rules[ruleIndex]),
+				})
+			}
+		}
+	}
+
+	return simulated, nil
+}
+
+// authPolicyMatchesRequest tells whether the request matches one of the rules of the policy, if any
+func authPolicyMatchesRequest(ap *kuadrantv1beta1.AuthPolicy, req common.HTTPRequestAttributes) bool {
+	if len(ap.Spec.AuthRules) == 0 {
+		return true
+	}
+	_, found := common.Find(ap.Spec.AuthRules, func(rule kuadrantv1beta1.AuthRule) bool {
+		return req.MatchesRule(common.HTTPRouteRule{Hosts: rule.Hosts, Methods: rule.Methods, Paths: rule.Paths})
+	})
+	return found
+}
+
+// limitsSelectingRule returns the limits of the policy whose route selectors select the rule of the route.
+// The limits of the policies targeting a gateway apply to all the routes.
+func limitsSelectingRule(rlp *kuadrantv1beta2.RateLimitPolicy, route *gatewayapiv1beta1.HTTPRoute, rule gatewayapiv1beta1.HTTPRouteRule) map[string]kuadrantv1beta2.Limit {
+	limits := make(map[string]kuadrantv1beta2.Limit)
+	for name, limit := range rlp.Spec.Limits {
+		if len(limit.RouteSelectors) == 0 || common.IsTargetRefGateway(rlp.Spec.TargetRef) {
+			limits[name] = limit
+			continue
+		}
+		_, found := common.Find(limit.RouteSelectors, func(selector kuadrantv1beta2.RouteSelector) bool {
+			_, selected := common.Find(selector.SelectRules(route), func(selectedRule gatewayapiv1beta1.HTTPRouteRule) bool {
+				return reflect.DeepEqual(selectedRule, rule)
+			})
+			return selected
+		})
+		if found {
+			limits[name] = limit
+		}
+	}
+	return limits
+}
+
+// ServeHTTP simulates the request described in the JSON body of a POST request.
+// The requests must be authenticated with a bearer token of a subject allowed to post to the path of the endpoint.
+func (s *Simulator) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if statusCode, err := authorizeNonResourceRequest(s.client, req, SimulatorPath, "post"); err != nil {
+		s.logger.Info("unauthorized simulate request", "reason", err.Error())
+		http.Error(rw, http.StatusText(statusCode), statusCode)
+		return
+	}
+
+	attributes := common.HTTPRequestAttributes{}
+	if err := json.NewDecoder(req.Body).Decode(&attributes); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid request attributes: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.Simulate(req.Context(), attributes)
+	if err != nil {
+		s.logger.Error(err, "failed to simulate request")
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(result); err != nil {
+		s.logger.Error(err, "failed to write simulation result")
+	}
+}
@@ -156,6 +156,32 @@ var _ = BeforeSuite(func() {
 
 	Expect(err).NotTo(HaveOccurred())
 
+	operatorConfigBaseReconciler := reconcilers.NewBaseReconciler(
+		mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+		log.Log.WithName("operatorconfig"),
+		mgr.GetEventRecorderFor("OperatorConfig"),
+	)
+
+	err = (&OperatorConfigReconciler{
+		BaseReconciler: operatorConfigBaseReconciler,
+	}).SetupWithManager(mgr)
+
+	Expect(err).NotTo(HaveOccurred())
+
+	effectivePoliciesBaseReconciler := reconcilers.NewBaseReconciler(
+		mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+		log.Log.WithName("effectivepolicies"),
+		mgr.GetEventRecorderFor("EffectivePolicies"),
+	)
+
+	err = (&EffectivePoliciesReconciler{
+		TargetRefReconciler: reconcilers.TargetRefReconciler{
+			BaseReconciler: effectivePoliciesBaseReconciler,
+		},
+	}).SetupWithManager(mgr)
+
+	Expect(err).NotTo(HaveOccurred())
+
 	go func() {
 		defer GinkgoRecover()
 		err = mgr.Start(ctrl.SetupSignalHandler())
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/common"
+)
+
+// TopologyPath is the path of the endpoint rendering the topology of the gateways, the routes, their backends
+// and the policies targeting them
+const TopologyPath = "/topology"
+
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+
+// Topology is the graph policy → route → gateway and route → backend of the cluster
+type Topology struct {
+	GeneratedAt metav1.Time       `json:"generatedAt"`
+	Gateways    []TopologyGateway `json:"gateways"`
+	Routes      []TopologyRoute   `json:"routes"`
+	Backends    []TopologyBackend `json:"backends"`
+}
+
+// TopologyGateway is a gateway of the topology, with the policies targeting it
+type TopologyGateway struct {
+	Name     string   `json:"name"`
+	Policies []string `json:"policies"`
+}
+
+// TopologyRoute is an HTTPRoute of the topology, with its parent gateways, its backend Services and the policies
+// targeting it
+type TopologyRoute struct {
+	Name     string   `json:"name"`
+	Gateways []string `json:"gateways"`
+	Backends []string `json:"backends"`
+	Policies []string `json:"policies"`
+}
+
+// TopologyBackend is a Service referenced as backend by the routes
+type TopologyBackend struct {
+	Name string `json:"name"`
+	// Found tells whether the Service exists
+	Found bool `json:"found"`
+	// ReadyEndpoints is the number of endpoints of the Service ready to receive traffic
+	ReadyEndpoints int `json:"readyEndpoints"`
+	// Healthy tells whether the Service has ready endpoints. The requests to an unhealthy backend fail with 503
+	Healthy bool `json:"healthy"`
+}
+
+// topology builds the topology from the Gateways, the HTTPRoutes, the Services and the EndpointSlices of the cluster
+func topology(ctx context.Context, cli client.Client) (*Topology, error) {
+	graph := &Topology{
+		GeneratedAt: metav1.NewTime(time.Now()),
+		Gateways:    make([]TopologyGateway, 0),
+		Routes:      make([]TopologyRoute, 0),
+		Backends:    make([]TopologyBackend, 0),
+	}
+
+	gwList := &gatewayapiv1beta1.GatewayList{}
+	if err := cli.List(ctx, gwList); err != nil {
+		return nil, err
+	}
+	for idx := range gwList.Items {
+		graph.Gateways = append(graph.Gateways, TopologyGateway{
+			Name:     client.ObjectKeyFromObject(&gwList.Items[idx]).String(),
+			Policies: common.EffectivePolicies(&gwList.Items[idx]),
+		})
+	}
+
+	routeList := &gatewayapiv1beta1.HTTPRouteList{}
+	if err := cli.List(ctx, routeList); err != nil {
+		return nil, err
+	}
+	backendKeys := make([]client.ObjectKey, 0)
+	for idx := range routeList.Items {
+		route := &routeList.Items[idx]
+		entry := TopologyRoute{
+			Name:     client.ObjectKeyFromObject(route).String(),
+			Gateways: make([]string, 0),
+			Backends: make([]string, 0),
+			Policies: common.EffectivePolicies(route),
+		}
+		for _, parentRef := range route.Spec.ParentRefs {
+			if kind := common.GetDefaultIfNil(parentRef.Kind, "Gateway"); kind != "Gateway" {
+				continue
+			}
+			gwKey := client.ObjectKey{Name: string(parentRef.Name), Namespace: string(common.GetDefaultIfNil(parentRef.Namespace, gatewayapiv1beta1.Namespace(route.Namespace)))}
+			entry.Gateways = append(entry.Gateways, gwKey.String())
+		}
+		for _, serviceKey := range common.HTTPRouteBackendServiceKeys(route) {
+			entry.Backends = append(entry.Backends, serviceKey.String())
+			if !common.ContainsObjectKey(backendKeys, serviceKey) {
+				backendKeys = append(backendKeys, serviceKey)
+			}
+		}
+		graph.Routes = append(graph.Routes, entry)
+	}
+
+	// the endpoint slices are read from the cache of the manager, watched from the first request on
+	sliceList := &discoveryv1.EndpointSliceList{}
+	if err := cli.List(ctx, sliceList); err != nil {
+		return nil, err
+	}
+	slices := make(map[client.ObjectKey][]discoveryv1.EndpointSlice)
+	for idx := range sliceList.Items {
+		slice := sliceList.Items[idx]
+		if serviceName, ok := slice.Labels[discoveryv1.LabelServiceName]; ok {
+			serviceKey := client.ObjectKey{Name: serviceName, Namespace: slice.Namespace}
+			slices[serviceKey] = append(slices[serviceKey], slice)
+		}
+	}
+
+	sort.Slice(backendKeys, func(i, j int) bool { return backendKeys[i].String() < backendKeys[j].String() })
+	for _, serviceKey := range backendKeys {
+		backend := TopologyBackend{Name: serviceKey.String()}
+		if err := cli.Get(ctx, serviceKey, &corev1.Service{}); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		} else if err == nil {
+			backend.Found = true
+			backend.ReadyEndpoints = common.ReadyEndpoints(slices[serviceKey]...)
+			backend.Healthy = backend.ReadyEndpoints > 0
+		}
+		graph.Backends = append(graph.Backends, backend)
+	}
+
+	return graph, nil
+}
+
+// TopologyReports renders the topology of the cluster
+type TopologyReports struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewTopologyReports(c client.Client, logger logr.Logger) *TopologyReports {
+	return &TopologyReports{client: c, logger: logger}
+}
+
+// ServeHTTP renders the topology from the current state of the cluster in response to a GET request.
+// The requests must be authenticated with a bearer token of a subject allowed to get the path of the endpoint.
+func (t *TopologyReports) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if statusCode, err := authorizeNonResourceRequest(t.client, req, TopologyPath, "get"); err != nil {
+		t.logger.Info("unauthorized topology request", "reason", err.Error())
+		http.Error(rw, http.StatusText(statusCode), statusCode)
+		return
+	}
+
+	graph, err := topology(req.Context(), t.client)
+	if err != nil {
+		t.logger.Error(err, "failed to build the topology")
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(graph); err != nil {
+		t.logger.Error(err, "failed to write the topology")
+	}
+}
@@ -0,0 +1,50 @@
+//go:build unit
+
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+	authorinoopv1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	authorinov1beta1 "github.com/kuadrant/authorino/api/v1beta1"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	istioextensionv1alpha1 "istio.io/client-go/pkg/apis/extensions/v1alpha1"
+	istionetworkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istiosecurityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	maistrav1 "github.com/kuadrant/kuadrant-operator/api/external/maistra/v1"
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+	"github.com/kuadrant/kuadrant-operator/pkg/reconcilers"
+)
+
+// unitTestScheme returns a scheme with the types watched by the controllers, for the fake clients of the unit tests
+func unitTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(limitadorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(authorinoopv1beta1.AddToScheme(scheme))
+	utilruntime.Must(authorinov1beta1.AddToScheme(scheme))
+	utilruntime.Must(istionetworkingv1alpha3.AddToScheme(scheme))
+	utilruntime.Must(istiosecurityv1beta1.AddToScheme(scheme))
+	utilruntime.Must(istioextensionv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gatewayapiv1beta1.AddToScheme(scheme))
+	utilruntime.Must(kuadrantv1beta1.AddToScheme(scheme))
+	utilruntime.Must(kuadrantv1beta2.AddToScheme(scheme))
+	utilruntime.Must(maistrav1.SchemeBuilder.AddToScheme(scheme))
+	return scheme
+}
+
+// unitTestTargetRefReconciler returns a reconciler reading from and writing to a fake client with the given objects
+func unitTestTargetRefReconciler(objs ...client.Object) reconcilers.TargetRefReconciler {
+	cl := fake.NewClientBuilder().WithScheme(unitTestScheme()).WithObjects(objs...).Build()
+	return reconcilers.TargetRefReconciler{
+		BaseReconciler: reconcilers.NewBaseReconciler(cl, cl.Scheme(), cl, logr.Discard(), record.NewFakeRecorder(100)),
+	}
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+// WatchersHealthPath is the path where the sync status of the watchers is served
+const WatchersHealthPath = "/healthz/watchers"
+
+// WatcherStatus is the sync status of the informer of a resource kind
+type WatcherStatus struct {
+	Synced    bool       `json:"synced"`
+	LastEvent *time.Time `json:"lastEvent,omitempty"`
+	// Objects are the last changes of the objects of the kind, indexed by namespace/name, if tracked
+	Objects map[string]ObjectChange `json:"objects,omitempty"`
+}
+
+// ObjectChange is the last change of an object received by the informer of its kind
+type ObjectChange struct {
+	ResourceVersion string    `json:"resourceVersion"`
+	LastChange      time.Time `json:"lastChange"`
+	// Changes is the number of changes received since the operator started, a high count denoting a churning object
+	Changes int `json:"changes"`
+}
+
+// WatchersHealth tracks whether the informers watched by the controllers are synced
+// and the last time each of them received an event
+type WatchersHealth struct {
+	cache  cache.Cache
+	logger logr.Logger
+	kinds  map[string]client.Object
+
+	mutex      sync.RWMutex
+	informers  map[string]cache.Informer
+	lastEvents map[string]time.Time
+	// objectChanges are the last changes per kind and object, only tracked if not nil
+	objectChanges map[string]map[string]ObjectChange
+}
+
+// watchedKinds returns the resource kinds watched by the controllers whose informers are checked, indexed by kind
+func watchedKinds() map[string]client.Object {
+	return map[string]client.Object{
+		"Kuadrant":         &kuadrantv1beta1.Kuadrant{},
+		"Gateway":          &gatewayapiv1beta1.Gateway{},
+		"HTTPRoute":        &gatewayapiv1beta1.HTTPRoute{},
+		"AuthPolicy":       &kuadrantv1beta1.AuthPolicy{},
+		"RateLimitPolicy":  &kuadrantv1beta2.RateLimitPolicy{},
+		"PolicyTemplate":   &kuadrantv1beta2.PolicyTemplate{},
+		"IdentityProvider": &kuadrantv1beta2.IdentityProvider{},
+		"Authorino":        &authorinov1beta1.Authorino{},
+	}
+}
+
+func NewWatchersHealth(c cache.Cache, logger logr.Logger) *WatchersHealth {
+	return &WatchersHealth{
+		cache:      c,
+		logger:     logger,
+		kinds:      watchedKinds(),
+		informers:  make(map[string]cache.Informer),
+		lastEvents: make(map[string]time.Time),
+	}
+}
+
+// TrackObjectChanges enables the tracking of the last change of each watched object, to spot the objects churning
+// and driving repeated reconciliations. The objects are forgotten once deleted. Must be called before Start.
+func (w *WatchersHealth) TrackObjectChanges() {
+	w.objectChanges = make(map[string]map[string]ObjectChange, len(w.kinds))
+	for kind := range w.kinds {
+		w.objectChanges[kind] = make(map[string]ObjectChange)
+	}
+}
+
+// Start registers an event handler on the informer of each watched kind.
+// Implements manager.Runnable
+func (w *WatchersHealth) Start(ctx context.Context) error {
+	for kind, obj := range w.kinds {
+		// blocks until the informer is synced
+		informer, err := w.cache.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("failed to get informer for %s: %w", kind, err)
+		}
+
+		kind := kind
+		recordEvent := func() { w.recordEvent(kind) }
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				recordEvent()
+				w.recordObjectChange(kind, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				recordEvent()
+				// the periodic resyncs do not change the objects
+				if resourceVersion(oldObj) != resourceVersion(newObj) {
+					w.recordObjectChange(kind, newObj)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				recordEvent()
+				w.forgetObject(kind, obj)
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to add event handler for %s: %w", kind, err)
+		}
+
+		w.mutex.Lock()
+		w.informers[kind] = informer
+		w.mutex.Unlock()
+		w.logger.V(1).Info("watching informer", "kind", kind)
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+// The informers are running in every replica, regardless of leader election.
+func (w *WatchersHealth) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *WatchersHealth) recordEvent(kind string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lastEvents[kind] = time.Now()
+}
+
+func (w *WatchersHealth) recordObjectChange(kind string, obj interface{}) {
+	if w.objectChanges == nil {
+		return
+	}
+	key, err := toolscache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	change := w.objectChanges[kind][key]
+	w.objectChanges[kind][key] = ObjectChange{
+		ResourceVersion: resourceVersion(obj),
+		LastChange:      time.Now(),
+		Changes:         change.Changes + 1,
+	}
+}
+
+func (w *WatchersHealth) forgetObject(kind string, obj interface{}) {
+	if w.objectChanges == nil {
+		return
+	}
+	key, err := toolscache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.objectChanges[kind], key)
+}
+
+func resourceVersion(obj interface{}) string {
+	if o, ok := obj.(client.Object); ok {
+		return o.GetResourceVersion()
+	}
+	return ""
+}
+
+// Status returns the sync status of the informer of each watched kind
+func (w *WatchersHealth) Status() map[string]WatcherStatus {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	status := make(map[string]WatcherStatus, len(w.kinds))
+	for kind := range w.kinds {
+		watcherStatus := WatcherStatus{}
+		if informer, ok := w.informers[kind]; ok {
+			watcherStatus.Synced = informer.HasSynced()
+		}
+		if lastEvent, ok := w.lastEvents[kind]; ok {
+			watcherStatus.LastEvent = &lastEvent
+		}
+		if changes := w.objectChanges[kind]; len(changes) > 0 {
+			watcherStatus.Objects = make(map[string]ObjectChange, len(changes))
+			for key, change := range changes {
+				watcherStatus.Objects[key] = change
+			}
+		}
+		status[kind] = watcherStatus
+	}
+	return status
+}
+
+// Checker fails if the informer of any of the watched kinds is not synced.
+// Implements healthz.Checker
+func (w *WatchersHealth) Checker(_ *http.Request) error {
+	notSynced := make([]string, 0)
+	for kind, watcherStatus := range w.Status() {
+		if !watcherStatus.Synced {
+			notSynced = append(notSynced, kind)
+		}
+	}
+	if len(notSynced) > 0 {
+		sort.Strings(notSynced)
+		return fmt.Errorf("informers not synced: %s", strings.Join(notSynced, ", "))
+	}
+	return nil
+}
+
+// ServeHTTP writes the sync status of the watchers as JSON,
+// with status code 503 if the informer of any of the watched kinds is not synced
+func (w *WatchersHealth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	statusCode := http.StatusOK
+	if err := w.Checker(req); err != nil {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	if err := json.NewEncoder(rw).Encode(w.Status()); err != nil {
+		w.logger.Error(err, "failed to write watchers status")
+	}
+}
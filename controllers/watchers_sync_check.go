@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+const (
+	WatcherSyncFailedConditionType string = "WatcherSyncFailed"
+
+	// watchersSyncCheckInterval is the interval between two checks of the access to the watched kinds
+	watchersSyncCheckInterval = time.Minute
+)
+
+// watcherSyncFailures holds the watched kinds the operator failed to list at the last check, with the error
+var watcherSyncFailures = &watcherSyncFailuresTracker{failures: make(map[string]string)}
+
+type watcherSyncFailuresTracker struct {
+	mu       sync.Mutex
+	failures map[string]string
+}
+
+// Set records the failures of the last check, returning the kinds recovered since the previous one
+func (t *watcherSyncFailuresTracker) Set(failures map[string]string) (recovered []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for kind := range t.failures {
+		if _, ok := failures[kind]; !ok {
+			recovered = append(recovered, kind)
+		}
+	}
+	t.failures = failures
+	return recovered
+}
+
+func (t *watcherSyncFailuresTracker) Get() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failures := make(map[string]string, len(t.failures))
+	for kind, err := range t.failures {
+		failures[kind] = err
+	}
+	return failures
+}
+
+// WatchersSyncCheck periodically lists the watched kinds from the API server, detecting the informers that fail to
+// sync while the operator runs, e.g. on the permissions to the kind revoked. The informers retry on their own but,
+// once synced, keep serving their last objects, the policies of the kind silently not reconciled meanwhile.
+type WatchersSyncCheck struct {
+	reader  client.Reader
+	scheme  *runtime.Scheme
+	trigger *ReconcileTrigger
+	logger  logr.Logger
+}
+
+func NewWatchersSyncCheck(reader client.Reader, scheme *runtime.Scheme, trigger *ReconcileTrigger, logger logr.Logger) *WatchersSyncCheck {
+	return &WatchersSyncCheck{reader: reader, scheme: scheme, trigger: trigger, logger: logger}
+}
+
+// NeedLeaderElection runs the check in the leader replica only, where the controllers run
+func (c *WatchersSyncCheck) NeedLeaderElection() bool {
+	return true
+}
+
+// Start checks the access to the watched kinds every watchersSyncCheckInterval until the context is done
+func (c *WatchersSyncCheck) Start(ctx context.Context) error {
+	ticker := time.NewTicker(watchersSyncCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+// check lists the watched kinds, recording the failures for the WatcherSyncFailed condition of the kuadrant
+// instances and the kuadrant_watcher_sync_failed gauge. On any kind recovered, all the resources are reconciled,
+// the events of the kind missed meanwhile.
+func (c *WatchersSyncCheck) check(ctx context.Context) {
+	failures := make(map[string]string)
+	for kind, obj := range watchedKinds() {
+		if err := c.list(ctx, obj); err != nil {
+			c.logger.Info("failed to list watched kind", "kind", kind, "error", err.Error())
+			failures[kind] = err.Error()
+			watcherSyncFailed.WithLabelValues(kind).Set(1)
+			continue
+		}
+		watcherSyncFailed.WithLabelValues(kind).Set(0)
+	}
+
+	previous := watcherSyncFailures.Get()
+	recovered := watcherSyncFailures.Set(failures)
+
+	var lists []client.ObjectList
+	if len(recovered) > 0 {
+		c.logger.Info("watched kinds recovered, reconciling all the resources", "kinds", recovered)
+	} else {
+		failed := false
+		for kind := range failures {
+			if _, ok := previous[kind]; !ok {
+				failed = true
+			}
+		}
+		if !failed {
+			return
+		}
+		// reflected in the status of the kuadrant instances
+		lists = []client.ObjectList{&kuadrantv1beta1.KuadrantList{}}
+	}
+	if count, err := c.trigger.TriggerFor(ctx, lists); err != nil {
+		c.logger.Error(err, "failed to trigger reconciliation", "enqueued", count)
+	}
+}
+
+// list reads a single object of a kind, metadata only, from the API server
+func (c *WatchersSyncCheck) list(ctx context.Context, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return c.reader.List(ctx, list, client.Limit(1))
+}
+
+// watcherSyncFailedCondition returns a warning condition listing the watched kinds the operator failed to list at the
+// last check, or nil
+func watcherSyncFailedCondition() *metav1.Condition {
+	failures := watcherSyncFailures.Get()
+	if len(failures) == 0 {
+		return nil
+	}
+
+	kinds := make([]string, 0, len(failures))
+	for kind := range failures {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	failed := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		failed = append(failed, fmt.Sprintf("%s (%s)", kind, failures[kind]))
+	}
+
+	return &metav1.Condition{
+		Type:    WatcherSyncFailedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ListFailed",
+		Message: fmt.Sprintf("The resources of the kinds are not watched, their policies not reconciled: %s", strings.Join(failed, "; ")),
+	}
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 
@@ -100,15 +102,70 @@ func main() {
 	setupLog := log.Log
 
 	var (
-		configFile string
-		err        error
+		configFile       string
+		childCleanupMode string
+		deletionPolicy   string
+		fieldManager     string
+		limitadorRollout bool
+		auditLog         string
+		failOnGatewayAPI bool
+		trackChanges     bool
+		gatewayReady     bool
+		observer         bool
+		err              error
 	)
 	flag.StringVar(&configFile, "config", "",
 		"The operator will load its initial configuration from this file. "+
 			"Omit this flag to use the default configuration values. "+
 			"Command-line flags override configuration from this file.")
+	flag.StringVar(&childCleanupMode, "child-cleanup-mode", string(controllers.OwnerRefCleanupMode),
+		"How the resources managed for a Kuadrant instance (Authorino, Limitador) are removed. "+
+			"'owner-ref' relies on the garbage collector; 'explicit' deletes them when the Kuadrant instance is removed.")
+	flag.StringVar(&deletionPolicy, "kuadrant-deletion-policy", string(controllers.MarkPoliciesDeletionPolicy),
+		"How the removal of a Kuadrant instance still backing policies is handled. "+
+			"'mark-policies' flags the policies with the BackendRemoved condition; 'block' keeps the instance until the policies are deleted.")
+	flag.StringVar(&fieldManager, "field-manager", common.KuadrantOperatorName,
+		"The name of the field manager of the writes of the operator. "+
+			"Run a shadow instance with a distinct field manager to tell apart the fields each instance owns.")
+	flag.BoolVar(&limitadorRollout, "limitador-rollout-on-config-change", false,
+		"Have the Limitador operator recreate the deployment of Limitador, rolling out its pods, when the storage "+
+			"config of the Limitador instance changes, which the Limitador operator does not apply to an existing deployment.")
+	flag.StringVar(&auditLog, "audit-log", "",
+		"Record the writes of the operator (timestamp, actor, object, action, result) as JSON lines in this file, "+
+			"or in the local syslog with 'syslog'. Omit this flag to disable the audit log.")
+	flag.BoolVar(&failOnGatewayAPI, "fail-on-incompatible-gateway-api", false,
+		"Exit at startup if the installed Gateway API is older than the minimum supported. "+
+			"Otherwise, the incompatibility is reported in the status of the Kuadrant instances.")
+	flag.BoolVar(&trackChanges, "track-object-changes", false,
+		"Track the last change of each watched object, served along with the status of the watchers, "+
+			"to spot the objects churning and driving repeated reconciliations.")
+	flag.BoolVar(&gatewayReady, "gateway-ready-condition", false,
+		"Report in the status of the gateways managed by Kuadrant the kuadrant.io/Ready condition, "+
+			"true once all the policies affecting the gateway are enforced, for rollouts to wait on.")
+	flag.BoolVar(&observer, "observer", false,
+		"Run the operator in observer mode: the status of the Kuadrant instances and the policies is computed and "+
+			"the endpoints are served, but the resources managed for them are never written, e.g. for a migration or an audit.")
 	flag.Parse()
 
+	switch controllers.ChildCleanupMode(childCleanupMode) {
+	case controllers.OwnerRefCleanupMode, controllers.ExplicitCleanupMode:
+	default:
+		setupLog.Error(fmt.Errorf("invalid value %q", childCleanupMode), "unsupported child cleanup mode")
+		os.Exit(1)
+	}
+
+	switch controllers.KuadrantDeletionPolicy(deletionPolicy) {
+	case controllers.MarkPoliciesDeletionPolicy, controllers.BlockDeletionPolicy:
+	default:
+		setupLog.Error(fmt.Errorf("invalid value %q", deletionPolicy), "unsupported kuadrant deletion policy")
+		os.Exit(1)
+	}
+
+	if fieldManager == "" {
+		setupLog.Error(fmt.Errorf("empty value"), "invalid field manager")
+		os.Exit(1)
+	}
+
 	options := ctrl.Options{Scheme: scheme}
 
 	if configFile != "" {
@@ -119,28 +176,103 @@ func main() {
 		}
 	}
 
+	// the ConfigMaps of the cluster are not cached, but the ones of the namespace of the operator
+	options.NewCache = controllers.NewCache
+	options.NewClient = controllers.NewClient
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// reported in the status of the kuadrant instances, along with the config read from the env vars
+	startupConfig := map[string]string{
+		"LOG_LEVEL":      logLevel,
+		"LOG_MODE":       logMode,
+		"cacheNamespace": options.Namespace,
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		startupConfig["--"+f.Name] = f.Value.String()
+	})
+
+	// read before the cache starts, the watches of the gateway api resources fail on an older gateway api
+	// the version unknown is reported in the status of the kuadrant instances, only an older version is incompatible
+	gatewayAPIVersion, err := common.GatewayAPIVersion(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		setupLog.Error(err, "unable to detect the gateway api version")
+	}
+	if err := common.CheckGatewayAPIVersion(gatewayAPIVersion); common.IsGatewayAPIVersionUnknown(err) {
+		setupLog.Info("unknown gateway api version", "detected", gatewayAPIVersion, "required", common.GatewayAPIMinVersion, "reason", err.Error())
+	} else if err != nil {
+		setupLog.Error(err, "incompatible gateway api", "detected", gatewayAPIVersion, "required", common.GatewayAPIMinVersion)
+		if failOnGatewayAPI {
+			os.Exit(1)
+		}
+	}
+
+	reconcileTrigger := controllers.NewReconcileTrigger(mgr.GetClient(), log.Log.WithName("reconcile-trigger"))
+
+	// all the writes of the reconcilers are owned by the same field manager
+	reconcilersClient := common.NewFieldOwnerClient(mgr.GetClient(), fieldManager)
+
+	// toggled at runtime by the operator config
+	reconcilersClient = common.NewDryRunClient(reconcilersClient, controllers.DryRunEnabled)
+
+	// closed once the manager stops, nothing being written to it before the manager starts
+	var auditSink io.WriteCloser
+	if auditLog != "" {
+		auditSink, err = common.OpenAuditSink(auditLog)
+		if err != nil {
+			setupLog.Error(err, "unable to open the audit log")
+			os.Exit(1)
+		}
+		reconcilersClient = common.NewAuditClient(reconcilersClient, common.NewAuditLogger(auditSink, fieldManager))
+	}
+
+	// the writes dropped are not audited, nor submitted in dry-run mode
+	if observer {
+		setupLog.Info("observer mode enabled: the managed resources are not written, only the status")
+		reconcilersClient = common.NewObserverClient(reconcilersClient, log.Log.WithName("observer"))
+	}
+
 	kuadrantBaseReconciler := reconcilers.NewBaseReconciler(
-		mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+		reconcilersClient, mgr.GetScheme(), mgr.GetAPIReader(),
 		log.Log.WithName("kuadrant"),
 		mgr.GetEventRecorderFor("Kuadrant"),
 	)
 
 	if err = (&controllers.KuadrantReconciler{
-		BaseReconciler: kuadrantBaseReconciler,
-		Scheme:         mgr.GetScheme(),
+		BaseReconciler:                 kuadrantBaseReconciler,
+		Scheme:                         mgr.GetScheme(),
+		ChildCleanupMode:               controllers.ChildCleanupMode(childCleanupMode),
+		DeletionPolicy:                 controllers.KuadrantDeletionPolicy(deletionPolicy),
+		ReconcileTrigger:               reconcileTrigger,
+		StartupConfig:                  startupConfig,
+		LimitadorRolloutOnConfigChange: limitadorRollout,
+		GatewayAPIVersion:              gatewayAPIVersion,
+		ObserverMode:                   observer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Kuadrant")
 		os.Exit(1)
 	}
 
+	operatorConfigBaseReconciler := reconcilers.NewBaseReconciler(
+		reconcilersClient, mgr.GetScheme(), mgr.GetAPIReader(),
+		log.Log.WithName("operatorconfig"),
+		mgr.GetEventRecorderFor("OperatorConfig"),
+	)
+
+	if err = (&controllers.OperatorConfigReconciler{
+		BaseReconciler:   operatorConfigBaseReconciler,
+		ReconcileTrigger: reconcileTrigger,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+
 	rateLimitPolicyBaseReconciler := reconcilers.NewBaseReconciler(
-		mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+		reconcilersClient, mgr.GetScheme(), mgr.GetAPIReader(),
 		log.Log.WithName("ratelimitpolicy"),
 		mgr.GetEventRecorderFor("RateLimitPolicy"),
 	)
@@ -149,13 +281,14 @@ func main() {
 		TargetRefReconciler: reconcilers.TargetRefReconciler{
 			BaseReconciler: rateLimitPolicyBaseReconciler,
 		},
+		ReconcileTrigger: reconcileTrigger,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RateLimitPolicy")
 		os.Exit(1)
 	}
 
 	authPolicyBaseReconciler := reconcilers.NewBaseReconciler(
-		mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+		reconcilersClient, mgr.GetScheme(), mgr.GetAPIReader(),
 		log.Log.WithName("authpolicy"),
 		mgr.GetEventRecorderFor("AuthPolicy"),
 	)
@@ -164,13 +297,93 @@ func main() {
 		TargetRefReconciler: reconcilers.TargetRefReconciler{
 			BaseReconciler: authPolicyBaseReconciler,
 		},
+		ReconcileTrigger: reconcileTrigger,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AuthPolicy")
 		os.Exit(1)
 	}
 
+	effectivePoliciesBaseReconciler := reconcilers.NewBaseReconciler(
+		reconcilersClient, mgr.GetScheme(), mgr.GetAPIReader(),
+		log.Log.WithName("effectivepolicies"),
+		mgr.GetEventRecorderFor("EffectivePolicies"),
+	)
+
+	if err = (&controllers.EffectivePoliciesReconciler{
+		TargetRefReconciler: reconcilers.TargetRefReconciler{
+			BaseReconciler: effectivePoliciesBaseReconciler,
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EffectivePolicies")
+		os.Exit(1)
+	}
+
+	if gatewayReady {
+		gatewayReadinessBaseReconciler := reconcilers.NewBaseReconciler(
+			reconcilersClient, mgr.GetScheme(), mgr.GetAPIReader(),
+			log.Log.WithName("gatewayreadiness"),
+			mgr.GetEventRecorderFor("GatewayReadiness"),
+		)
+
+		if err = (&controllers.GatewayReadinessReconciler{
+			BaseReconciler: gatewayReadinessBaseReconciler,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "GatewayReadiness")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
+	watchersHealth := controllers.NewWatchersHealth(mgr.GetCache(), log.Log.WithName("watchers"))
+	if trackChanges {
+		watchersHealth.TrackObjectChanges()
+	}
+	if err := mgr.Add(watchersHealth); err != nil {
+		setupLog.Error(err, "unable to set up watchers health")
+		os.Exit(1)
+	}
+	if err := mgr.AddMetricsExtraHandler(controllers.WatchersHealthPath, watchersHealth); err != nil {
+		setupLog.Error(err, "unable to set up watchers health endpoint")
+		os.Exit(1)
+	}
+
+	// the informers of the kinds failing to sync while the operator runs serve stale objects
+	watchersSyncCheck := controllers.NewWatchersSyncCheck(mgr.GetAPIReader(), mgr.GetScheme(), reconcileTrigger, log.Log.WithName("watchers-sync-check"))
+	if err := mgr.Add(watchersSyncCheck); err != nil {
+		setupLog.Error(err, "unable to set up watchers sync check")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddMetricsExtraHandler(controllers.ReconcileTriggerPath, reconcileTrigger); err != nil {
+		setupLog.Error(err, "unable to set up reconcile endpoint")
+		os.Exit(1)
+	}
+
+	simulator := controllers.NewSimulator(mgr.GetClient(), log.Log.WithName("simulator"))
+	if err := mgr.AddMetricsExtraHandler(controllers.SimulatorPath, simulator); err != nil {
+		setupLog.Error(err, "unable to set up simulate endpoint")
+		os.Exit(1)
+	}
+
+	envoyClusters := controllers.NewEnvoyClusters(mgr.GetClient(), log.Log.WithName("envoyClusters"))
+	if err := mgr.AddMetricsExtraHandler(controllers.EnvoyClustersPath, envoyClusters); err != nil {
+		setupLog.Error(err, "unable to set up envoy clusters endpoint")
+		os.Exit(1)
+	}
+
+	policyReports := controllers.NewPolicyReports(mgr.GetClient(), log.Log.WithName("policyReports"))
+	if err := mgr.AddMetricsExtraHandler(controllers.PolicyReportPath, policyReports); err != nil {
+		setupLog.Error(err, "unable to set up policy report endpoint")
+		os.Exit(1)
+	}
+
+	topologyReports := controllers.NewTopologyReports(mgr.GetClient(), log.Log.WithName("topologyReports"))
+	if err := mgr.AddMetricsExtraHandler(controllers.TopologyPath, topologyReports); err != nil {
+		setupLog.Error(err, "unable to set up topology endpoint")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -181,7 +394,14 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	// os.Exit does not run the deferred functions
+	if auditSink != nil {
+		if closeErr := auditSink.Close(); closeErr != nil {
+			setupLog.Error(closeErr, "unable to close the audit log")
+		}
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
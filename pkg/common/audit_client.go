@@ -0,0 +1,170 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// AuditSyslogSink is the value of the audit log sink selecting the local syslog daemon instead of a file
+const AuditSyslogSink = "syslog"
+
+// AuditEntry is a record of a write performed by the operator
+type AuditEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Actor       string    `json:"actor"`
+	Action      string    `json:"action"`
+	Kind        string    `json:"kind"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Subresource string    `json:"subresource,omitempty"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// AuditLogger writes the audit entries as JSON lines, separate from the operational logs
+type AuditLogger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	actor string
+	now   func() time.Time
+}
+
+// NewAuditLogger returns an audit logger writing the entries of the given actor to out
+func NewAuditLogger(out io.Writer, actor string) *AuditLogger {
+	return &AuditLogger{out: out, actor: actor, now: time.Now}
+}
+
+// OpenAuditSink opens the destination of the audit log: the local syslog daemon, or a file the entries are appended to
+func OpenAuditSink(sink string) (io.WriteCloser, error) {
+	if sink == AuditSyslogSink {
+		return syslog.New(syslog.LOG_NOTICE|syslog.LOG_AUTH, KuadrantOperatorName)
+	}
+	return os.OpenFile(sink, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+}
+
+// Log records the outcome of a write
+func (l *AuditLogger) Log(action, kind, namespace, name, subresource string, err error) {
+	entry := AuditEntry{
+		Timestamp:   l.now().UTC(),
+		Actor:       l.actor,
+		Action:      action,
+		Kind:        kind,
+		Namespace:   namespace,
+		Name:        name,
+		Subresource: subresource,
+		Result:      "success",
+	}
+	if err != nil {
+		entry.Result = "failure"
+		entry.Error = err.Error()
+	}
+
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(append(line, '\n'))
+}
+
+// auditClient records the writes of the wrapped client in the audit log, whatever their outcome
+type auditClient struct {
+	client.Client
+	audit *AuditLogger
+}
+
+// NewAuditClient returns a client recording its writes in the given audit log
+func NewAuditClient(c client.Client, audit *AuditLogger) client.Client {
+	return &auditClient{Client: c, audit: audit}
+}
+
+func (c *auditClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	c.log("create", obj, "", err)
+	return err
+}
+
+func (c *auditClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	c.log("update", obj, "", err)
+	return err
+}
+
+func (c *auditClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.log("patch", obj, "", err)
+	return err
+}
+
+func (c *auditClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	c.log("delete", obj, "", err)
+	return err
+}
+
+func (c *auditClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	c.log("deletecollection", obj, "", err)
+	return err
+}
+
+func (c *auditClient) Status() client.SubResourceWriter {
+	return &auditSubResourceWriter{SubResourceWriter: c.Client.Status(), client: c, subResource: "status"}
+}
+
+func (c *auditClient) SubResource(subResource string) client.SubResourceClient {
+	subResourceClient := c.Client.SubResource(subResource)
+	return &auditSubResourceClient{
+		SubResourceReader: subResourceClient,
+		SubResourceWriter: &auditSubResourceWriter{SubResourceWriter: subResourceClient, client: c, subResource: subResource},
+	}
+}
+
+func (c *auditClient) log(action string, obj client.Object, subResource string, err error) {
+	kind := strings.TrimPrefix(fmt.Sprintf("%T", obj), "*")
+	if gvk, gvkErr := apiutil.GVKForObject(obj, c.Scheme()); gvkErr == nil {
+		kind = gvk.GroupKind().String()
+	}
+	c.audit.Log(action, kind, obj.GetNamespace(), obj.GetName(), subResource, err)
+}
+
+type auditSubResourceClient struct {
+	client.SubResourceReader
+	client.SubResourceWriter
+}
+
+type auditSubResourceWriter struct {
+	client.SubResourceWriter
+	client      *auditClient
+	subResource string
+}
+
+func (w *auditSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	err := w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+	w.client.log("create", obj, w.subResource, err)
+	return err
+}
+
+func (w *auditSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	err := w.SubResourceWriter.Update(ctx, obj, opts...)
+	w.client.log("update", obj, w.subResource, err)
+	return err
+}
+
+func (w *auditSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	err := w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	w.client.log("patch", obj, w.subResource, err)
+	return err
+}
@@ -0,0 +1,72 @@
+//go:build unit
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAuditClient(t *testing.T) {
+	ctx := context.TODO()
+	out := &bytes.Buffer{}
+	audit := NewAuditLogger(out, "kuadrant-operator")
+	audit.now = func() time.Time { return time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC) }
+	cl := NewAuditClient(fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), audit)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "kuadrant"}}
+	if err := cl.Create(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	cm.Data = map[string]string{"key": "value"}
+	if err := cl.Update(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.Delete(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	// the failed writes are recorded too
+	if err := cl.Delete(ctx, cm); err == nil {
+		t.Fatal("expected the deletion of a missing object to fail")
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("unexpected number of audit entries: %d\n%s", len(lines), out.String())
+	}
+
+	entries := make([]AuditEntry, 0, len(lines))
+	for _, line := range lines {
+		entry := AuditEntry{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid audit entry %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	expectedActions := []string{"create", "update", "delete", "delete"}
+	expectedResults := []string{"success", "success", "success", "failure"}
+	for idx, entry := range entries {
+		if entry.Action != expectedActions[idx] || entry.Result != expectedResults[idx] {
+			t.Errorf("unexpected entry %d: %+v", idx, entry)
+		}
+		if entry.Actor != "kuadrant-operator" || entry.Kind != "ConfigMap" || entry.Namespace != "kuadrant" || entry.Name != "limits" {
+			t.Errorf("unexpected object of entry %d: %+v", idx, entry)
+		}
+		if !entry.Timestamp.Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected timestamp of entry %d: %v", idx, entry.Timestamp)
+		}
+	}
+	if entries[3].Error == "" {
+		t.Errorf("missing error of the failed write: %+v", entries[3])
+	}
+}
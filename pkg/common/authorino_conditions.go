@@ -2,6 +2,7 @@ package common
 
 import (
 	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func FindAuthorinoStatusCondition(conditions []authorinov1beta1.Condition, conditionType string) *authorinov1beta1.Condition {
@@ -13,3 +14,9 @@ func FindAuthorinoStatusCondition(conditions []authorinov1beta1.Condition, condi
 
 	return nil
 }
+
+// IsAuthorinoReady tells whether the Ready condition of an Authorino instance is true
+func IsAuthorinoReady(authorino *authorinov1beta1.Authorino) bool {
+	readyCondition := FindAuthorinoStatusCondition(authorino.Status.Conditions, "Ready")
+	return readyCondition != nil && readyCondition.Status == corev1.ConditionTrue
+}
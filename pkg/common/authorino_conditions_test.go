@@ -7,6 +7,7 @@ import (
 
 	goCmp "github.com/google/go-cmp/cmp"
 	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestFindAuthorinoStatusCondition(t *testing.T) {
@@ -53,3 +54,36 @@ func TestFindAuthorinoStatusCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAuthorinoReady(t *testing.T) {
+	testCases := []struct {
+		name       string
+		conditions []authorinov1beta1.Condition
+		expected   bool
+	}{
+		{
+			name:       "when the ready condition is true then return true",
+			conditions: []authorinov1beta1.Condition{{Type: "Ready", Status: corev1.ConditionTrue}},
+			expected:   true,
+		},
+		{
+			name:       "when the ready condition is false then return false",
+			conditions: []authorinov1beta1.Condition{{Type: "Ready", Status: corev1.ConditionFalse}},
+			expected:   false,
+		},
+		{
+			name:       "when no ready condition then return false",
+			conditions: []authorinov1beta1.Condition{},
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authorino := &authorinov1beta1.Authorino{Status: authorinov1beta1.AuthorinoStatus{Conditions: tc.conditions}}
+			if result := IsAuthorinoReady(authorino); result != tc.expected {
+				t.Errorf("unexpected readiness: got %t, want %t", result, tc.expected)
+			}
+		})
+	}
+}
@@ -35,11 +35,34 @@ const (
 	RateLimitPolicyBackRefAnnotation   = "kuadrant.io/ratelimitpolicy"
 	AuthPoliciesBackRefAnnotation      = "kuadrant.io/authpolicies"
 	AuthPolicyBackRefAnnotation        = "kuadrant.io/authpolicy"
+	AuthPolicyNamespaceAnnotation      = "kuadrant.io/authpolicy-namespace"
+	AuthPolicyNameAnnotation           = "kuadrant.io/authpolicy-name"
+	AuthPolicyGenerationAnnotation     = "kuadrant.io/authpolicy-generation"
+	AuthPolicyTargetKindAnnotation     = "kuadrant.io/authpolicy-target-kind"
+	AuthPolicyGatewaysAnnotation       = "kuadrant.io/authpolicy-gateways"
+	EffectivePoliciesAnnotation        = "kuadrant.io/effective-policies"
+	AuthorinoInstanceAnnotation        = "kuadrant.io/authorino-instance"
 	KuadrantNamespaceLabel             = "kuadrant.io/namespace"
+	MetricsServiceLabel                = "kuadrant.io/metrics-service"
+	ComponentOverridesLabel            = "kuadrant.io/component-overrides"
+	OperatorConfigLabel                = "kuadrant.io/operator-config"
+	DefaultAuthPolicyLabel             = "kuadrant.io/default-authpolicy"
+	PolicyReplacesAnnotation           = "kuadrant.io/replaces"
 	NamespaceSeparator                 = '/'
 	LimitadorName                      = "limitador"
 )
 
+// Recommended labels of the resources managed by the kuadrant instances
+const (
+	AppNameLabel      = "app.kubernetes.io/name"
+	AppInstanceLabel  = "app.kubernetes.io/instance"
+	AppComponentLabel = "app.kubernetes.io/component"
+	AppPartOfLabel    = "app.kubernetes.io/part-of"
+	AppManagedByLabel = "app.kubernetes.io/managed-by"
+
+	KuadrantOperatorName = "kuadrant-operator"
+)
+
 type KuadrantPolicy interface {
 	client.Object
 	GetTargetRef() gatewayapiv1alpha2.PolicyTargetReference
@@ -188,6 +211,17 @@ func ReverseSlice[T any](input []T) []T {
 	return output
 }
 
+// ManagedResourceLabels returns the recommended labels of a component managed by the kuadrant instance
+func ManagedResourceLabels(kuadrantName, component string) map[string]string {
+	return map[string]string{
+		AppNameLabel:      component,
+		AppInstanceLabel:  kuadrantName,
+		AppComponentLabel: component,
+		AppPartOfLabel:    "kuadrant",
+		AppManagedByLabel: KuadrantOperatorName,
+	}
+}
+
 // MergeMapStringString Merge desired into existing.
 // Not Thread-Safe. Does it matter?
 func MergeMapStringString(existing *map[string]string, desired map[string]string) bool {
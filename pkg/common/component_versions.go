@@ -0,0 +1,72 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	AuthorinoComponent  = "authorino"
+	LimitadorComponent  = "limitador"
+	GatewayAPIComponent = "gateway-api"
+)
+
+// VersionRange is a range of releases of a component, from Min included to Max excluded. Unbounded if empty.
+type VersionRange struct {
+	Min string
+	Max string
+}
+
+func (v VersionRange) String() string {
+	switch {
+	case v.Max == "":
+		return fmt.Sprintf(">= %s", v.Min)
+	case v.Min == "":
+		return fmt.Sprintf("< %s", v.Max)
+	default:
+		return fmt.Sprintf(">= %s, < %s", v.Min, v.Max)
+	}
+}
+
+// SupportedComponentVersions is the matrix of the releases of the components supported by the operator
+var SupportedComponentVersions = map[string]VersionRange{
+	AuthorinoComponent:  {Min: "v0.13.0", Max: "v1.0.0"},
+	LimitadorComponent:  {Min: "v1.1.0", Max: "v2.0.0"},
+	GatewayAPIComponent: {Min: GatewayAPIMinVersion, Max: "v2.0.0"},
+}
+
+// UnsupportedComponentVersions returns the components whose detected release is out of the supported matrix,
+// formatted as "<component> <version> (supported <range>)". The releases unknown or not semantic, e.g. "latest",
+// cannot be checked and are skipped.
+func UnsupportedComponentVersions(detected map[string]string) []string {
+	unsupported := make([]string, 0)
+	for component, detectedVersion := range detected {
+		supported, ok := SupportedComponentVersions[component]
+		if !ok {
+			continue
+		}
+		parsed, err := version.ParseGeneric(detectedVersion)
+		if err != nil {
+			continue
+		}
+		if (supported.Min != "" && parsed.LessThan(version.MustParseGeneric(supported.Min))) ||
+			(supported.Max != "" && !parsed.LessThan(version.MustParseGeneric(supported.Max))) {
+			unsupported = append(unsupported, fmt.Sprintf("%s %s (supported %s)", component, detectedVersion, supported))
+		}
+	}
+	sort.Strings(unsupported)
+	return unsupported
+}
+
+// ImageTag returns the tag of a container image, or empty if untagged
+func ImageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 || strings.Contains(image[idx:], "/") {
+		return ""
+	}
+	return image[idx+1:]
+}
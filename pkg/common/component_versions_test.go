@@ -0,0 +1,61 @@
+//go:build unit
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnsupportedComponentVersions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		detected map[string]string
+		expected []string
+	}{
+		{
+			name:     "supported",
+			detected: map[string]string{AuthorinoComponent: "v0.14.0", LimitadorComponent: "v1.2.0", GatewayAPIComponent: "v0.6.2"},
+			expected: []string{},
+		},
+		{
+			name:     "older",
+			detected: map[string]string{AuthorinoComponent: "v0.12.0", LimitadorComponent: "v1.2.0"},
+			expected: []string{"authorino v0.12.0 (supported >= v0.13.0, < v1.0.0)"},
+		},
+		{
+			name:     "newer",
+			detected: map[string]string{LimitadorComponent: "2.0.0", GatewayAPIComponent: "v0.5.1"},
+			expected: []string{"gateway-api v0.5.1 (supported >= v0.6.0, < v2.0.0)", "limitador 2.0.0 (supported >= v1.1.0, < v2.0.0)"},
+		},
+		{
+			name:     "unknown",
+			detected: map[string]string{AuthorinoComponent: "latest", LimitadorComponent: "", "other": "v0.0.1"},
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if unsupported := UnsupportedComponentVersions(tc.detected); !reflect.DeepEqual(unsupported, tc.expected) {
+				subT.Errorf("expected %v, got %v", tc.expected, unsupported)
+			}
+		})
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	testCases := map[string]string{
+		"quay.io/kuadrant/authorino:v0.14.0":                    "v0.14.0",
+		"quay.io/kuadrant/limitador":                            "",
+		"localhost:5000/kuadrant/limitador":                     "",
+		"localhost:5000/kuadrant/limitador:v1.2.0":              "v1.2.0",
+		"quay.io/kuadrant/limitador:v1.2.0@sha256:0123456789ab": "v1.2.0",
+	}
+
+	for image, expected := range testCases {
+		if tag := ImageTag(image); tag != expected {
+			t.Errorf("%s: expected %q, got %q", image, expected, tag)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunClient submits the writes of the wrapped client in dry-run mode while enabled, so the resources are
+// validated by the API server but not persisted. The writes of the status are always persisted, the status
+// reporting what the operator would do.
+type dryRunClient struct {
+	client.Client
+	enabled func() bool
+}
+
+// NewDryRunClient returns a client submitting its writes in dry-run mode while the given function returns true
+func NewDryRunClient(c client.Client, enabled func() bool) client.Client {
+	return &dryRunClient{Client: c, enabled: enabled}
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.enabled() {
+		opts = append(opts, client.DryRunAll)
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.enabled() {
+		opts = append(opts, client.DryRunAll)
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.enabled() {
+		opts = append(opts, client.DryRunAll)
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.enabled() {
+		opts = append(opts, client.DryRunAll)
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if c.enabled() {
+		opts = append(opts, client.DryRunAll)
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
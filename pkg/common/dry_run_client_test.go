@@ -0,0 +1,58 @@
+//go:build unit
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunRecorder records whether the writes are in dry-run mode
+type dryRunRecorder struct {
+	client.Client
+	dryRuns []bool
+}
+
+func (r *dryRunRecorder) Create(_ context.Context, _ client.Object, opts ...client.CreateOption) error {
+	r.dryRuns = append(r.dryRuns, len((&client.CreateOptions{}).ApplyOptions(opts).DryRun) > 0)
+	return nil
+}
+
+func (r *dryRunRecorder) Update(_ context.Context, _ client.Object, opts ...client.UpdateOption) error {
+	r.dryRuns = append(r.dryRuns, len((&client.UpdateOptions{}).ApplyOptions(opts).DryRun) > 0)
+	return nil
+}
+
+func (r *dryRunRecorder) Delete(_ context.Context, _ client.Object, opts ...client.DeleteOption) error {
+	r.dryRuns = append(r.dryRuns, len((&client.DeleteOptions{}).ApplyOptions(opts).DryRun) > 0)
+	return nil
+}
+
+func TestDryRunClient(t *testing.T) {
+	ctx := context.TODO()
+	recorder := &dryRunRecorder{}
+	enabled := false
+	cl := NewDryRunClient(recorder, func() bool { return enabled })
+	obj := &corev1.ConfigMap{}
+
+	_ = cl.Create(ctx, obj)
+	enabled = true
+	_ = cl.Create(ctx, obj)
+	_ = cl.Update(ctx, obj)
+	_ = cl.Delete(ctx, obj)
+	enabled = false
+	_ = cl.Update(ctx, obj)
+
+	expected := []bool{false, true, true, true, false}
+	if len(recorder.dryRuns) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, recorder.dryRuns)
+	}
+	for idx := range expected {
+		if recorder.dryRuns[idx] != expected[idx] {
+			t.Errorf("expected %v, got %v", expected, recorder.dryRuns)
+		}
+	}
+}
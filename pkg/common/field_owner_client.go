@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldOwnerClient sets the field manager of all the writes of the wrapped client,
+// so the fields set by the operator are owned by the same manager whatever the write method
+type fieldOwnerClient struct {
+	client.Client
+	owner client.FieldOwner
+}
+
+// NewFieldOwnerClient returns a client writing the resources with the given field manager
+func NewFieldOwnerClient(c client.Client, fieldManager string) client.Client {
+	return &fieldOwnerClient{Client: c, owner: client.FieldOwner(fieldManager)}
+}
+
+func (c *fieldOwnerClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, obj, append([]client.CreateOption{c.owner}, opts...)...)
+}
+
+func (c *fieldOwnerClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, obj, append([]client.UpdateOption{c.owner}, opts...)...)
+}
+
+func (c *fieldOwnerClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.Client.Patch(ctx, obj, patch, append([]client.PatchOption{c.owner}, opts...)...)
+}
+
+func (c *fieldOwnerClient) Status() client.SubResourceWriter {
+	return &fieldOwnerSubResourceWriter{SubResourceWriter: c.Client.Status(), owner: c.owner}
+}
+
+func (c *fieldOwnerClient) SubResource(subResource string) client.SubResourceClient {
+	subResourceClient := c.Client.SubResource(subResource)
+	return &fieldOwnerSubResourceClient{
+		SubResourceReader: subResourceClient,
+		SubResourceWriter: &fieldOwnerSubResourceWriter{SubResourceWriter: subResourceClient, owner: c.owner},
+	}
+}
+
+type fieldOwnerSubResourceClient struct {
+	client.SubResourceReader
+	client.SubResourceWriter
+}
+
+type fieldOwnerSubResourceWriter struct {
+	client.SubResourceWriter
+	owner client.FieldOwner
+}
+
+func (w *fieldOwnerSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return w.SubResourceWriter.Create(ctx, obj, subResource, append([]client.SubResourceCreateOption{w.owner}, opts...)...)
+}
+
+func (w *fieldOwnerSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.SubResourceWriter.Update(ctx, obj, append([]client.SubResourceUpdateOption{w.owner}, opts...)...)
+}
+
+func (w *fieldOwnerSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.SubResourceWriter.Patch(ctx, obj, patch, append([]client.SubResourcePatchOption{w.owner}, opts...)...)
+}
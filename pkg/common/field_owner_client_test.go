@@ -0,0 +1,70 @@
+//go:build unit
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManagerRecorder records the field manager of the writes
+type fieldManagerRecorder struct {
+	client.Client
+	fieldManagers []string
+}
+
+func (r *fieldManagerRecorder) Create(_ context.Context, _ client.Object, opts ...client.CreateOption) error {
+	r.fieldManagers = append(r.fieldManagers, (&client.CreateOptions{}).ApplyOptions(opts).FieldManager)
+	return nil
+}
+
+func (r *fieldManagerRecorder) Update(_ context.Context, _ client.Object, opts ...client.UpdateOption) error {
+	r.fieldManagers = append(r.fieldManagers, (&client.UpdateOptions{}).ApplyOptions(opts).FieldManager)
+	return nil
+}
+
+func (r *fieldManagerRecorder) Patch(_ context.Context, _ client.Object, _ client.Patch, opts ...client.PatchOption) error {
+	r.fieldManagers = append(r.fieldManagers, (&client.PatchOptions{}).ApplyOptions(opts).FieldManager)
+	return nil
+}
+
+func (r *fieldManagerRecorder) Status() client.SubResourceWriter {
+	return &statusFieldManagerRecorder{recorder: r}
+}
+
+type statusFieldManagerRecorder struct {
+	client.SubResourceWriter
+	recorder *fieldManagerRecorder
+}
+
+func (r *statusFieldManagerRecorder) Update(_ context.Context, _ client.Object, opts ...client.SubResourceUpdateOption) error {
+	r.recorder.fieldManagers = append(r.recorder.fieldManagers, (&client.SubResourceUpdateOptions{}).ApplyOptions(opts).FieldManager)
+	return nil
+}
+
+func TestFieldOwnerClient(t *testing.T) {
+	ctx := context.TODO()
+	recorder := &fieldManagerRecorder{}
+	cl := NewFieldOwnerClient(recorder, "kuadrant-operator")
+	obj := &corev1.ConfigMap{}
+
+	_ = cl.Create(ctx, obj)
+	_ = cl.Update(ctx, obj)
+	_ = cl.Patch(ctx, obj, client.Merge)
+	_ = cl.Status().Update(ctx, obj)
+	// explicit options prevail
+	_ = cl.Update(ctx, obj, client.FieldOwner("shadow"))
+
+	expected := []string{"kuadrant-operator", "kuadrant-operator", "kuadrant-operator", "kuadrant-operator", "shadow"}
+	if len(recorder.fieldManagers) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, recorder.fieldManagers)
+	}
+	for idx := range expected {
+		if recorder.fieldManagers[idx] != expected[idx] {
+			t.Errorf("expected %v, got %v", expected, recorder.fieldManagers)
+		}
+	}
+}
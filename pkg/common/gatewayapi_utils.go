@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -203,6 +204,15 @@ func HTTPMethodToString(method *gatewayapiv1beta1.HTTPMethod) string {
 }
 
 func GetKuadrantNamespaceFromPolicyTargetRef(ctx context.Context, cli client.Client, policy KuadrantPolicy) (string, error) {
+	gw, err := GetGatewayFromPolicyTargetRef(ctx, cli, policy)
+	if err != nil {
+		return "", err
+	}
+	return GetKuadrantNamespace(gw)
+}
+
+// GetGatewayFromPolicyTargetRef returns the gateway targeted by a policy, or the first parent gateway of the targeted HTTPRoute
+func GetGatewayFromPolicyTargetRef(ctx context.Context, cli client.Client, policy KuadrantPolicy) (*gatewayapiv1beta1.Gateway, error) {
 	targetRef := policy.GetTargetRef()
 	gwNamespacedName := types.NamespacedName{Namespace: string(GetDefaultIfNil(targetRef.Namespace, policy.GetWrappedNamespace())), Name: string(targetRef.Name)}
 	if IsTargetRefHTTPRoute(targetRef) {
@@ -212,17 +222,20 @@ func GetKuadrantNamespaceFromPolicyTargetRef(ctx context.Context, cli client.Cli
 			types.NamespacedName{Namespace: string(GetDefaultIfNil(targetRef.Namespace, policy.GetWrappedNamespace())), Name: string(targetRef.Name)},
 			route,
 		); err != nil {
-			return "", err
+			return nil, err
 		}
 		// First should be OK considering there's 1 Kuadrant instance per cluster and all are tagged
+		if len(route.Spec.ParentRefs) == 0 {
+			return nil, fmt.Errorf("httproute %s has no parent gateway", client.ObjectKeyFromObject(route))
+		}
 		parentRef := route.Spec.ParentRefs[0]
-		gwNamespacedName = types.NamespacedName{Namespace: string(*parentRef.Namespace), Name: string(parentRef.Name)}
+		gwNamespacedName = types.NamespacedName{Namespace: string(GetDefaultIfNil(parentRef.Namespace, gatewayapiv1beta1.Namespace(route.Namespace))), Name: string(parentRef.Name)}
 	}
 	gw := &gatewayapiv1beta1.Gateway{}
 	if err := cli.Get(ctx, gwNamespacedName, gw); err != nil {
-		return "", err
+		return nil, err
 	}
-	return GetKuadrantNamespace(gw)
+	return gw, nil
 }
 
 func GetKuadrantNamespaceFromPolicy(policy KuadrantPolicy) (string, bool) {
@@ -267,6 +280,21 @@ func DeleteKuadrantAnnotationFromGateway(gw *gatewayapiv1beta1.Gateway, namespac
 	}
 }
 
+// ReferenceGrantPermits tells whether a ReferenceGrant allows the objects of a kind in a namespace to refer to an
+// object of another kind in the namespace of the ReferenceGrant
+func ReferenceGrantPermits(grant *gatewayapiv1beta1.ReferenceGrant, fromGroup, fromKind, fromNamespace, toGroup, toKind, toName string) bool {
+	_, fromFound := Find(grant.Spec.From, func(from gatewayapiv1beta1.ReferenceGrantFrom) bool {
+		return string(from.Group) == fromGroup && string(from.Kind) == fromKind && string(from.Namespace) == fromNamespace
+	})
+	if !fromFound {
+		return false
+	}
+	_, toFound := Find(grant.Spec.To, func(to gatewayapiv1beta1.ReferenceGrantTo) bool {
+		return string(to.Group) == toGroup && string(to.Kind) == toKind && (to.Name == nil || string(*to.Name) == toName)
+	})
+	return toFound
+}
+
 // routePathMatchToRulePath converts HTTPRoute pathmatch rule to kuadrant's rule path
 func routePathMatchToRulePath(pathMatch *gatewayapiv1beta1.HTTPPathMatch) []string {
 	if pathMatch == nil {
@@ -500,6 +528,35 @@ func (g GatewayWrapper) Hostnames() []gatewayapiv1beta1.Hostname {
 	return hostnames
 }
 
+// IstioTLSTerminateModeOption is the TLS option of the listeners of the Istio gateways setting the TLS mode, e.g.
+// MUTUAL to verify the client certificates
+const IstioTLSTerminateModeOption = "gateway.istio.io/tls-terminate-mode"
+
+// ClientCertificateListeners returns the names of the HTTPS listeners terminating TLS with the verification of the
+// client certificates, forwarded to the external authorization service
+func (g GatewayWrapper) ClientCertificateListeners() []string {
+	listeners := make([]string, 0)
+	if g.Gateway == nil {
+		return listeners
+	}
+
+	for _, listener := range g.Spec.Listeners {
+		tls := listener.TLS
+		if listener.Protocol != gatewayapiv1beta1.HTTPSProtocolType || tls == nil {
+			continue
+		}
+		if tls.Mode != nil && *tls.Mode != gatewayapiv1beta1.TLSModeTerminate {
+			continue
+		}
+		switch tls.Options[IstioTLSTerminateModeOption] {
+		case "MUTUAL", "OPTIONAL_MUTUAL":
+			listeners = append(listeners, string(listener.Name))
+		}
+	}
+
+	return listeners
+}
+
 // GatewayWrapperList is a list of GatewayWrappers that implements sort.Interface
 type GatewayWrapperList []GatewayWrapper
 
@@ -609,3 +666,62 @@ func IsHTTPRouteAccepted(httpRoute *gatewayapiv1beta1.HTTPRoute) bool {
 
 	return true
 }
+
+// HTTPRouteBackendServiceKeys returns the keys of the Services referenced as backends by the rules of the HTTPRoute.
+// Backend references to kinds other than Service are ignored.
+func HTTPRouteBackendServiceKeys(httpRoute *gatewayapiv1beta1.HTTPRoute) []client.ObjectKey {
+	keys := make([]client.ObjectKey, 0)
+	if httpRoute == nil {
+		return keys
+	}
+
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			ref := backendRef.BackendObjectReference
+			if ref.Group != nil && *ref.Group != "" {
+				continue
+			}
+			if ref.Kind != nil && *ref.Kind != "Service" {
+				continue
+			}
+
+			key := client.ObjectKey{Name: string(ref.Name), Namespace: httpRoute.Namespace}
+			if ref.Namespace != nil {
+				key.Namespace = string(*ref.Namespace)
+			}
+
+			if !ContainsObjectKey(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+// policyBackRefKinds maps the back reference annotations of the targeted network objects to the kinds of policy
+var policyBackRefKinds = map[string]string{
+	AuthPolicyBackRefAnnotation:      "authpolicy",
+	RateLimitPolicyBackRefAnnotation: "ratelimitpolicy",
+}
+
+// EffectivePolicies returns the sorted list of policies directly targeting any of the given network objects,
+// read from their back reference annotations, in the form <kind>/<namespace>/<name>
+func EffectivePolicies(objs ...client.Object) []string {
+	uniquePolicies := make(map[string]struct{})
+	for _, obj := range objs {
+		annotations := ReadAnnotationsFromObject(obj)
+		for annotation, kind := range policyBackRefKinds {
+			if policyRef, ok := annotations[annotation]; ok {
+				uniquePolicies[fmt.Sprintf("%s/%s", kind, policyRef)] = struct{}{}
+			}
+		}
+	}
+
+	policies := make([]string, 0, len(uniquePolicies))
+	for policy := range uniquePolicies {
+		policies = append(policies, policy)
+	}
+	sort.Strings(policies)
+	return policies
+}
@@ -1370,3 +1370,180 @@ func TestIsHTTPRouteAccepted(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPRouteBackendServiceKeys(t *testing.T) {
+	backendRef := func(group, kind, namespace *string, name string) gatewayapiv1beta1.HTTPBackendRef {
+		return gatewayapiv1beta1.HTTPBackendRef{
+			BackendRef: gatewayapiv1beta1.BackendRef{
+				BackendObjectReference: gatewayapiv1beta1.BackendObjectReference{
+					Group:     (*gatewayapiv1beta1.Group)(group),
+					Kind:      (*gatewayapiv1beta1.Kind)(kind),
+					Namespace: (*gatewayapiv1beta1.Namespace)(namespace),
+					Name:      gatewayapiv1beta1.ObjectName(name),
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		route    *gatewayapiv1beta1.HTTPRoute
+		expected []client.ObjectKey
+	}{
+		{
+			"nil route",
+			nil,
+			[]client.ObjectKey{},
+		},
+		{
+			"services from multiple rules without duplicates",
+			&gatewayapiv1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+				Spec: gatewayapiv1beta1.HTTPRouteSpec{
+					Rules: []gatewayapiv1beta1.HTTPRouteRule{
+						{
+							BackendRefs: []gatewayapiv1beta1.HTTPBackendRef{
+								backendRef(nil, nil, nil, "svc-a"),
+								backendRef(Ptr(""), Ptr("Service"), Ptr("other"), "svc-b"),
+							},
+						},
+						{
+							BackendRefs: []gatewayapiv1beta1.HTTPBackendRef{
+								backendRef(nil, nil, nil, "svc-a"),
+							},
+						},
+					},
+				},
+			},
+			[]client.ObjectKey{{Namespace: "ns", Name: "svc-a"}, {Namespace: "other", Name: "svc-b"}},
+		},
+		{
+			"non service backends are ignored",
+			&gatewayapiv1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+				Spec: gatewayapiv1beta1.HTTPRouteSpec{
+					Rules: []gatewayapiv1beta1.HTTPRouteRule{
+						{
+							BackendRefs: []gatewayapiv1beta1.HTTPBackendRef{
+								backendRef(Ptr("example.com"), Ptr("Bucket"), nil, "bucket"),
+							},
+						},
+					},
+				},
+			},
+			[]client.ObjectKey{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			res := HTTPRouteBackendServiceKeys(tc.route)
+			if !reflect.DeepEqual(res, tc.expected) {
+				subT.Errorf("result (%v) does not match expected (%v)", res, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEffectivePolicies(t *testing.T) {
+	route := &gatewayapiv1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "toystore",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				RateLimitPolicyBackRefAnnotation: "ns/toystore-rlp",
+				"other":                          "ns/other",
+			},
+		},
+	}
+	gw := &gatewayapiv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw",
+			Namespace: "gw-ns",
+			Annotations: map[string]string{
+				AuthPolicyBackRefAnnotation:      "gw-ns/gw-auth",
+				RateLimitPolicyBackRefAnnotation: "gw-ns/gw-rlp",
+			},
+		},
+	}
+
+	expected := []string{"authpolicy/gw-ns/gw-auth", "ratelimitpolicy/gw-ns/gw-rlp", "ratelimitpolicy/ns/toystore-rlp"}
+	if policies := EffectivePolicies(route, gw, gw); !reflect.DeepEqual(policies, expected) {
+		t.Errorf("expected %v, got %v", expected, policies)
+	}
+
+	if policies := EffectivePolicies(&gatewayapiv1beta1.HTTPRoute{}); len(policies) != 0 {
+		t.Errorf("expected no policies, got %v", policies)
+	}
+}
+
+func TestReferenceGrantPermits(t *testing.T) {
+	otherGateway := gatewayapiv1beta1.ObjectName("other")
+	grant := &gatewayapiv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "gateways"},
+		Spec: gatewayapiv1beta1.ReferenceGrantSpec{
+			From: []gatewayapiv1beta1.ReferenceGrantFrom{{Group: "kuadrant.io", Kind: "AuthPolicy", Namespace: "policies"}},
+			To:   []gatewayapiv1beta1.ReferenceGrantTo{{Group: "gateway.networking.k8s.io", Kind: "Gateway", Name: &otherGateway}},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		toName        *gatewayapiv1beta1.ObjectName
+		fromNamespace string
+		fromKind      string
+		expected      bool
+	}{
+		{name: "when any gateway then permitted", fromNamespace: "policies", fromKind: "AuthPolicy", expected: true},
+		{name: "when named gateway then permitted", toName: &otherGateway, fromNamespace: "policies", fromKind: "AuthPolicy", expected: true},
+		{name: "when other namespace then forbidden", fromNamespace: "tenant", fromKind: "AuthPolicy", expected: false},
+		{name: "when other kind then forbidden", fromNamespace: "policies", fromKind: "RateLimitPolicy", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			grant.Spec.To[0].Name = tc.toName
+			if permitted := ReferenceGrantPermits(grant, "kuadrant.io", tc.fromKind, tc.fromNamespace, "gateway.networking.k8s.io", "Gateway", "other"); permitted != tc.expected {
+				subT.Fatalf("expected %t, got %t", tc.expected, permitted)
+			}
+		})
+	}
+
+	grant.Spec.To[0].Name = &otherGateway
+	if ReferenceGrantPermits(grant, "kuadrant.io", "AuthPolicy", "policies", "gateway.networking.k8s.io", "Gateway", "gw") {
+		t.Fatal("expected a gateway of another name to be forbidden")
+	}
+}
+
+func TestGatewayWrapperClientCertificateListeners(t *testing.T) {
+	passthrough := gatewayapiv1beta1.TLSModePassthrough
+	gw := &gatewayapiv1beta1.Gateway{
+		Spec: gatewayapiv1beta1.GatewaySpec{
+			Listeners: []gatewayapiv1beta1.Listener{
+				{Name: "http", Protocol: gatewayapiv1beta1.HTTPProtocolType},
+				{Name: "https", Protocol: gatewayapiv1beta1.HTTPSProtocolType, TLS: &gatewayapiv1beta1.GatewayTLSConfig{}},
+				{
+					Name:     "mtls",
+					Protocol: gatewayapiv1beta1.HTTPSProtocolType,
+					TLS: &gatewayapiv1beta1.GatewayTLSConfig{
+						Options: map[gatewayapiv1beta1.AnnotationKey]gatewayapiv1beta1.AnnotationValue{IstioTLSTerminateModeOption: "MUTUAL"},
+					},
+				},
+				{
+					Name:     "passthrough",
+					Protocol: gatewayapiv1beta1.HTTPSProtocolType,
+					TLS: &gatewayapiv1beta1.GatewayTLSConfig{
+						Mode:    &passthrough,
+						Options: map[gatewayapiv1beta1.AnnotationKey]gatewayapiv1beta1.AnnotationValue{IstioTLSTerminateModeOption: "MUTUAL"},
+					},
+				},
+			},
+		},
+	}
+
+	if listeners := (GatewayWrapper{Gateway: gw}).ClientCertificateListeners(); !reflect.DeepEqual(listeners, []string{"mtls"}) {
+		t.Fatalf("ClientCertificateListeners() = %v, wanted [mtls]", listeners)
+	}
+	if listeners := (GatewayWrapper{}).ClientCertificateListeners(); len(listeners) != 0 {
+		t.Fatalf("ClientCertificateListeners() = %v, wanted none", listeners)
+	}
+}
@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// GatewayAPIMinVersion is the oldest release of the Gateway API whose CRDs serve the versions the operator watches
+	GatewayAPIMinVersion = "v0.6.0"
+
+	// GatewayAPIBundleVersionAnnotation is set on the CRDs of the Gateway API to the release they belong to
+	GatewayAPIBundleVersionAnnotation = "gateway.networking.k8s.io/bundle-version"
+
+	gatewayCRDName = "gateways.gateway.networking.k8s.io"
+)
+
+// GatewayAPIVersion returns the release of the Gateway API installed in the cluster, read from the Gateway CRD.
+// Returns an empty version if the CRD is not installed.
+func GatewayAPIVersion(ctx context.Context, reader client.Reader) (string, error) {
+	crd := &apiextv1.CustomResourceDefinition{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: gatewayCRDName}, crd); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return crd.GetAnnotations()[GatewayAPIBundleVersionAnnotation], nil
+}
+
+// GatewayAPIVersionUnknownError is returned when the release of the Gateway API installed cannot be told, e.g.
+// the CRDs are not annotated with it, which does not mean the release is not supported
+type GatewayAPIVersionUnknownError struct {
+	Detected string
+	Reason   string
+}
+
+func (e *GatewayAPIVersionUnknownError) Error() string {
+	return fmt.Sprintf("gateway api version %q unknown, %s, required %s or newer", e.Detected, e.Reason, GatewayAPIMinVersion)
+}
+
+func IsGatewayAPIVersionUnknown(err error) bool {
+	unknownErr := &GatewayAPIVersionUnknownError{}
+	return errors.As(err, &unknownErr)
+}
+
+// CheckGatewayAPIVersion returns an error if the given release of the Gateway API is older than GatewayAPIMinVersion,
+// or a GatewayAPIVersionUnknownError if the release cannot be told
+func CheckGatewayAPIVersion(detected string) error {
+	if detected == "" {
+		return &GatewayAPIVersionUnknownError{Reason: "gateway api not installed or not annotated with its version"}
+	}
+
+	detectedVersion, err := version.ParseSemantic(detected)
+	if err != nil {
+		return &GatewayAPIVersionUnknownError{Detected: detected, Reason: err.Error()}
+	}
+
+	if detectedVersion.LessThan(version.MustParseSemantic(GatewayAPIMinVersion)) {
+		return fmt.Errorf("gateway api %s installed, required %s or newer", detected, GatewayAPIMinVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build unit
+
+package common
+
+import (
+	"testing"
+)
+
+func TestCheckGatewayAPIVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		detected string
+		valid    bool
+		unknown  bool
+	}{
+		{name: "minimum", detected: "v0.6.0", valid: true},
+		{name: "newer patch", detected: "v0.6.2", valid: true},
+		{name: "newer", detected: "v1.0.0", valid: true},
+		{name: "older", detected: "v0.5.1", valid: false},
+		{name: "experimental suffix", detected: "v0.5.0-rc1", valid: false},
+		{name: "unknown", detected: "", valid: false, unknown: true},
+		{name: "invalid", detected: "latest", valid: false, unknown: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			err := CheckGatewayAPIVersion(tc.detected)
+			if tc.valid && err != nil {
+				subT.Errorf("unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				subT.Errorf("expected an error for version %q", tc.detected)
+			}
+			if IsGatewayAPIVersionUnknown(err) != tc.unknown {
+				subT.Errorf("expected version %q unknown to be %t, got %v", tc.detected, tc.unknown, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// HTTPRequestAttributes are the attributes of an HTTP request matched against the HTTPRoutes and the policies
+type HTTPRequestAttributes struct {
+	Method  string            `json:"method,omitempty"`
+	Path    string            `json:"path,omitempty"`
+	Host    string            `json:"host,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// header returns the value of a header of the request, case insensitive on the name of the header
+func (r *HTTPRequestAttributes) header(name string) (string, bool) {
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// MatchesHostnames tells whether the host of the request matches one of the hostnames.
+// No hostnames match all the hosts.
+func (r *HTTPRequestAttributes) MatchesHostnames(hostnames []string) bool {
+	if len(hostnames) == 0 {
+		return true
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	_, found := Find(hostnames, func(hostname string) bool {
+		return hostname == "*" || Name(host).SubsetOf(Name(hostname))
+	})
+	return found
+}
+
+// MatchesHTTPRouteRule tells whether the request matches one of the matches of a HTTPRouteRule.
+// A rule without matches matches all the requests.
+func (r *HTTPRequestAttributes) MatchesHTTPRouteRule(rule gatewayapiv1beta1.HTTPRouteRule) bool {
+	if len(rule.Matches) == 0 {
+		return true
+	}
+	_, found := Find(rule.Matches, r.MatchesHTTPRouteMatch)
+	return found
+}
+
+// MatchesHTTPRouteMatch tells whether the request matches the path, method and headers of a HTTPRouteMatch.
+// The query params are not matched.
+func (r *HTTPRequestAttributes) MatchesHTTPRouteMatch(match gatewayapiv1beta1.HTTPRouteMatch) bool {
+	if match.Method != nil && !strings.EqualFold(string(*match.Method), r.Method) {
+		return false
+	}
+
+	if match.Path != nil && !r.matchesPath(match.Path) {
+		return false
+	}
+
+	for _, headerMatch := range match.Headers {
+		value, ok := r.header(string(headerMatch.Name))
+		if !ok {
+			return false
+		}
+		if headerMatch.Type != nil && *headerMatch.Type == gatewayapiv1beta1.HeaderMatchRegularExpression {
+			if matched, err := regexp.MatchString(headerMatch.Value, value); err != nil || !matched {
+				return false
+			}
+			continue
+		}
+		if value != headerMatch.Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *HTTPRequestAttributes) matchesPath(pathMatch *gatewayapiv1beta1.HTTPPathMatch) bool {
+	value := GetDefaultIfNil(pathMatch.Value, "/")
+	switch GetDefaultIfNil(pathMatch.Type, gatewayapiv1beta1.PathMatchPathPrefix) {
+	case gatewayapiv1beta1.PathMatchExact:
+		return r.Path == value
+	case gatewayapiv1beta1.PathMatchRegularExpression:
+		matched, err := regexp.MatchString(value, r.Path)
+		return err == nil && matched
+	default:
+		// the prefix matches whole path elements
+		prefix := strings.TrimSuffix(value, "/")
+		return prefix == "" || r.Path == prefix || strings.HasPrefix(r.Path, prefix+"/")
+	}
+}
+
+// MatchesRule tells whether the request matches the hosts, methods and paths of a kuadrant rule.
+// Paths ending with * match by prefix.
+func (r *HTTPRequestAttributes) MatchesRule(rule HTTPRouteRule) bool {
+	if !r.MatchesHostnames(rule.Hosts) {
+		return false
+	}
+
+	if len(rule.Methods) > 0 {
+		if _, found := Find(rule.Methods, func(method string) bool { return strings.EqualFold(method, r.Method) }); !found {
+			return false
+		}
+	}
+
+	if len(rule.Paths) > 0 {
+		if _, found := Find(rule.Paths, func(path string) bool {
+			if strings.HasSuffix(path, "*") {
+				return strings.HasPrefix(r.Path, strings.TrimSuffix(path, "*"))
+			}
+			return r.Path == path
+		}); !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+//go:build unit
+
+package common
+
+import (
+	"testing"
+
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestHTTPRequestAttributesMatchesHostnames(t *testing.T) {
+	req := &HTTPRequestAttributes{Host: "api.toystore.com:8080"}
+
+	testCases := []struct {
+		name      string
+		hostnames []string
+		expected  bool
+	}{
+		{name: "when no hostnames then match", hostnames: nil, expected: true},
+		{name: "when same hostname then match", hostnames: []string{"api.toystore.com"}, expected: true},
+		{name: "when wildcard hostname then match", hostnames: []string{"*.toystore.com"}, expected: true},
+		{name: "when catch-all hostname then match", hostnames: []string{"*"}, expected: true},
+		{name: "when other hostnames then no match", hostnames: []string{"toystore.com", "*.other.com"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := req.MatchesHostnames(tc.hostnames); result != tc.expected {
+				t.Errorf("unexpected match: got %t, want %t", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPRequestAttributesMatchesHTTPRouteMatch(t *testing.T) {
+	pathPrefix := gatewayapiv1beta1.PathMatchPathPrefix
+	pathExact := gatewayapiv1beta1.PathMatchExact
+	pathRegex := gatewayapiv1beta1.PathMatchRegularExpression
+	headerRegex := gatewayapiv1beta1.HeaderMatchRegularExpression
+	get := gatewayapiv1beta1.HTTPMethodGet
+	post := gatewayapiv1beta1.HTTPMethodPost
+
+	req := &HTTPRequestAttributes{Method: "GET", Path: "/toys/1", Headers: map[string]string{"X-Tier": "gold"}}
+
+	testCases := []struct {
+		name     string
+		match    gatewayapiv1beta1.HTTPRouteMatch
+		expected bool
+	}{
+		{name: "when empty match then match", expected: true},
+		{name: "when path prefix matches then match", match: gatewayapiv1beta1.HTTPRouteMatch{Path: &gatewayapiv1beta1.HTTPPathMatch{Type: &pathPrefix, Value: Ptr("/toys")}}, expected: true},
+		{name: "when path prefix matches part of an element then no match", match: gatewayapiv1beta1.HTTPRouteMatch{Path: &gatewayapiv1beta1.HTTPPathMatch{Type: &pathPrefix, Value: Ptr("/to")}}, expected: false},
+		{name: "when exact path differs then no match", match: gatewayapiv1beta1.HTTPRouteMatch{Path: &gatewayapiv1beta1.HTTPPathMatch{Type: &pathExact, Value: Ptr("/toys")}}, expected: false},
+		{name: "when path regex matches then match", match: gatewayapiv1beta1.HTTPRouteMatch{Path: &gatewayapiv1beta1.HTTPPathMatch{Type: &pathRegex, Value: Ptr("^/toys/[0-9]+$")}}, expected: true},
+		{name: "when method matches then match", match: gatewayapiv1beta1.HTTPRouteMatch{Method: &get}, expected: true},
+		{name: "when method differs then no match", match: gatewayapiv1beta1.HTTPRouteMatch{Method: &post}, expected: false},
+		{name: "when header matches case insensitive then match", match: gatewayapiv1beta1.HTTPRouteMatch{Headers: []gatewayapiv1beta1.HTTPHeaderMatch{{Name: "x-tier", Value: "gold"}}}, expected: true},
+		{name: "when header regex matches then match", match: gatewayapiv1beta1.HTTPRouteMatch{Headers: []gatewayapiv1beta1.HTTPHeaderMatch{{Type: &headerRegex, Name: "X-Tier", Value: "^g"}}}, expected: true},
+		{name: "when header missing then no match", match: gatewayapiv1beta1.HTTPRouteMatch{Headers: []gatewayapiv1beta1.HTTPHeaderMatch{{Name: "X-Other", Value: "gold"}}}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := req.MatchesHTTPRouteMatch(tc.match); result != tc.expected {
+				t.Errorf("unexpected match: got %t, want %t", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPRequestAttributesMatchesRule(t *testing.T) {
+	req := &HTTPRequestAttributes{Method: "post", Path: "/toys/1", Host: "api.toystore.com"}
+
+	testCases := []struct {
+		name     string
+		rule     HTTPRouteRule
+		expected bool
+	}{
+		{name: "when empty rule then match", expected: true},
+		{name: "when all match then match", rule: HTTPRouteRule{Hosts: []string{"*.toystore.com"}, Methods: []string{"POST"}, Paths: []string{"/toys*"}}, expected: true},
+		{name: "when exact path differs then no match", rule: HTTPRouteRule{Paths: []string{"/toys"}}, expected: false},
+		{name: "when method differs then no match", rule: HTTPRouteRule{Methods: []string{"GET"}}, expected: false},
+		{name: "when host differs then no match", rule: HTTPRouteRule{Hosts: []string{"other.com"}}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := req.MatchesRule(tc.rule); result != tc.expected {
+				t.Errorf("unexpected match: got %t, want %t", result, tc.expected)
+			}
+		})
+	}
+}
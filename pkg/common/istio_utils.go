@@ -2,8 +2,11 @@ package common
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
+	"google.golang.org/protobuf/proto"
+	istiosecurity "istio.io/api/security/v1beta1"
 	istiocommon "istio.io/api/type/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -20,3 +23,66 @@ func IstioWorkloadSelectorFromGateway(ctx context.Context, k8sClient client.Clie
 		MatchLabels: gatewayWorkloadSelector,
 	}
 }
+
+// MaxIstioExcludingRules is the maximum number of Istio rules generated by IstioRulesExcluding
+const MaxIstioExcludingRules = 64
+
+// IstioRulesExcluding returns a set of Istio rules matching the requests matched by toRules
+// that are not matched by any of the excluded rules.
+// Because Istio rules cannot express the negation of a conjunction, each excluded rule
+// multiplies the given rules by the number of non-empty attributes (hosts, methods and paths) it defines,
+// i.e. not (hosts and methods and paths) == (not hosts) or (not methods) or (not paths).
+func IstioRulesExcluding(toRules []*istiosecurity.Rule_To, excluded []HTTPRouteRule) ([]*istiosecurity.Rule_To, error) {
+	result := toRules
+	for _, exclusion := range excluded {
+		negations := make([]*istiosecurity.Operation, 0, 3)
+		if len(exclusion.Hosts) > 0 {
+			negations = append(negations, &istiosecurity.Operation{NotHosts: exclusion.Hosts})
+		}
+		if len(exclusion.Methods) > 0 {
+			negations = append(negations, &istiosecurity.Operation{NotMethods: exclusion.Methods})
+		}
+		if len(exclusion.Paths) > 0 {
+			negations = append(negations, &istiosecurity.Operation{NotPaths: exclusion.Paths})
+		}
+		if len(negations) == 0 {
+			return nil, fmt.Errorf("exclusion %+v matches all requests", exclusion)
+		}
+
+		next := make([]*istiosecurity.Rule_To, 0, len(result)*len(negations))
+		for _, rule := range result {
+			for _, negation := range negations {
+				next = append(next, istioRuleWithNegation(rule, negation))
+			}
+		}
+		if len(next) > MaxIstioExcludingRules {
+			return nil, fmt.Errorf("too many exclusions: more than %d rules required", MaxIstioExcludingRules)
+		}
+		result = next
+	}
+	return result, nil
+}
+
+func istioRuleWithNegation(rule *istiosecurity.Rule_To, negation *istiosecurity.Operation) *istiosecurity.Rule_To {
+	newRule, _ := proto.Clone(rule).(*istiosecurity.Rule_To)
+	if newRule.Operation == nil {
+		newRule.Operation = &istiosecurity.Operation{}
+	}
+	newRule.Operation.NotHosts = append(newRule.Operation.NotHosts, negation.NotHosts...)
+	newRule.Operation.NotMethods = append(newRule.Operation.NotMethods, negation.NotMethods...)
+	newRule.Operation.NotPaths = append(newRule.Operation.NotPaths, negation.NotPaths...)
+	return newRule
+}
+
+// EqualProtoSlices tells whether two slices of protobuf messages are equal, comparing the messages pairwise with proto.Equal
+func EqualProtoSlices[T proto.Message](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
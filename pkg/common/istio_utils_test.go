@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr"
+	"google.golang.org/protobuf/proto"
+	istiosecurity "istio.io/api/security/v1beta1"
 	istiocommon "istio.io/api/type/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -106,3 +108,87 @@ func TestIstioWorkloadSelectorFromGatewayMissingHostnameAddress(t *testing.T) {
 		t.Error("should have built the istio workload selector from the gateway labels")
 	}
 }
+
+func TestIstioRulesExcluding(t *testing.T) {
+	toRules := []*istiosecurity.Rule_To{
+		{
+			Operation: &istiosecurity.Operation{
+				Hosts: []string{"*.example.com"},
+			},
+		},
+	}
+
+	t.Run("no exclusions", func(t *testing.T) {
+		rules, err := IstioRulesExcluding(toRules, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 1 || !proto.Equal(rules[0], toRules[0]) {
+			t.Errorf("expected the rules to be unchanged, got %v", rules)
+		}
+	})
+
+	t.Run("excluded paths", func(t *testing.T) {
+		rules, err := IstioRulesExcluding(toRules, []HTTPRouteRule{{Paths: []string{"/healthz", "/metrics"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := &istiosecurity.Rule_To{
+			Operation: &istiosecurity.Operation{
+				Hosts:    []string{"*.example.com"},
+				NotPaths: []string{"/healthz", "/metrics"},
+			},
+		}
+		if len(rules) != 1 || !proto.Equal(rules[0], expected) {
+			t.Errorf("expected %v, got %v", expected, rules)
+		}
+		if len(toRules[0].Operation.NotPaths) != 0 {
+			t.Error("should not have modified the original rules")
+		}
+	})
+
+	t.Run("excluded route rule", func(t *testing.T) {
+		rules, err := IstioRulesExcluding(toRules, []HTTPRouteRule{{Hosts: []string{"api.example.com"}, Paths: []string{"/healthz"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []*istiosecurity.Rule_To{
+			{
+				Operation: &istiosecurity.Operation{
+					Hosts:    []string{"*.example.com"},
+					NotHosts: []string{"api.example.com"},
+				},
+			},
+			{
+				Operation: &istiosecurity.Operation{
+					Hosts:    []string{"*.example.com"},
+					NotPaths: []string{"/healthz"},
+				},
+			},
+		}
+		if len(rules) != len(expected) {
+			t.Fatalf("expected %d rules, got %d", len(expected), len(rules))
+		}
+		for i := range expected {
+			if !proto.Equal(rules[i], expected[i]) {
+				t.Errorf("expected %v, got %v", expected[i], rules[i])
+			}
+		}
+	})
+
+	t.Run("exclusion matching all requests", func(t *testing.T) {
+		if _, err := IstioRulesExcluding(toRules, []HTTPRouteRule{{}}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("too many exclusions", func(t *testing.T) {
+		excluded := make([]HTTPRouteRule, 0)
+		for i := 0; i < 4; i++ {
+			excluded = append(excluded, HTTPRouteRule{Hosts: []string{"a"}, Methods: []string{"GET"}, Paths: []string{"/a"}})
+		}
+		if _, err := IstioRulesExcluding(toRules, excluded); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -25,6 +25,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -76,6 +77,21 @@ func IsObjectTaggedToDelete(obj client.Object) bool {
 	return ok && annotation == "true"
 }
 
+// TagObjectManagedBy sets the AppManagedByLabel of the object to the operator, so all the resources created by
+// the operator can be listed with a label selector. Returns whether the label was changed.
+func TagObjectManagedBy(obj client.Object) bool {
+	labels := obj.GetLabels()
+	if labels[AppManagedByLabel] == KuadrantOperatorName {
+		return false
+	}
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[AppManagedByLabel] = KuadrantOperatorName
+	obj.SetLabels(labels)
+	return true
+}
+
 // StatusConditionsMarshalJSON marshals the list of conditions as a JSON array, sorted by
 // condition type.
 func StatusConditionsMarshalJSON(input []metav1.Condition) ([]byte, error) {
@@ -113,6 +129,38 @@ func IsOwnedBy(owned, owner client.Object) bool {
 	return false
 }
 
+// UpdateStaleOwnerReferences replaces the owner references of the existing object pointing to a previous
+// instance of any of the owners of the desired object, i.e. same group, kind and name but different UID.
+// Returns true if the owner references of the existing object were updated.
+func UpdateStaleOwnerReferences(existing, desired client.Object) bool {
+	ownerRefs := existing.GetOwnerReferences()
+	update := false
+
+	for _, desiredRef := range desired.GetOwnerReferences() {
+		desiredGV, err := schema.ParseGroupVersion(desiredRef.APIVersion)
+		if err != nil {
+			continue
+		}
+		for idx := range ownerRefs {
+			gv, err := schema.ParseGroupVersion(ownerRefs[idx].APIVersion)
+			if err != nil {
+				continue
+			}
+			if gv.Group == desiredGV.Group && ownerRefs[idx].Kind == desiredRef.Kind &&
+				ownerRefs[idx].Name == desiredRef.Name && ownerRefs[idx].UID != desiredRef.UID {
+				ownerRefs[idx] = desiredRef
+				update = true
+			}
+		}
+	}
+
+	if update {
+		existing.SetOwnerReferences(ownerRefs)
+	}
+
+	return update
+}
+
 // GetServicePortNumber returns the port number from the referenced key and port info
 // the port info can be named port or already a number.
 func GetServicePortNumber(ctx context.Context, k8sClient client.Client, serviceKey client.ObjectKey, servicePort string) (int32, error) {
@@ -200,3 +248,17 @@ func FindDeploymentStatusCondition(conditions []appsv1.DeploymentCondition, cond
 
 	return nil
 }
+
+// ReadyEndpoints returns the number of endpoints of the EndpointSlices ready to receive traffic.
+// An endpoint with an unknown readiness is ready, as interpreted by the consumers of the EndpointSlices.
+func ReadyEndpoints(slices ...discoveryv1.EndpointSlice) int {
+	ready := 0
+	for idx := range slices {
+		for _, endpoint := range slices[idx].Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	return ready
+}
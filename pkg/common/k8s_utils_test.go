@@ -16,8 +16,10 @@ import (
 	"github.com/kuadrant/limitador-operator/api/v1alpha1"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -585,6 +587,62 @@ func TestIsOwnedBy(t *testing.T) {
 	}
 }
 
+func TestUpdateStaleOwnerReferences(t *testing.T) {
+	ownerRef := func(kind, name, uid string) metav1.OwnerReference {
+		return metav1.OwnerReference{APIVersion: "kuadrant.io/v1beta1", Kind: kind, Name: name, UID: types.UID(uid)}
+	}
+
+	testCases := []struct {
+		name              string
+		existingRefs      []metav1.OwnerReference
+		desiredRefs       []metav1.OwnerReference
+		expected          bool
+		expectedOwnerRefs []metav1.OwnerReference
+	}{
+		{
+			name:              "when the owner was recreated then replace the stale owner reference",
+			existingRefs:      []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "old")},
+			desiredRefs:       []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "new")},
+			expected:          true,
+			expectedOwnerRefs: []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "new")},
+		},
+		{
+			name:              "when the owner reference is up to date then return false",
+			existingRefs:      []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "new")},
+			desiredRefs:       []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "new")},
+			expected:          false,
+			expectedOwnerRefs: []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "new")},
+		},
+		{
+			name:              "when the owner reference points to another owner then keep it",
+			existingRefs:      []metav1.OwnerReference{ownerRef("Kuadrant", "other", "old"), ownerRef("Other", "kuadrant", "old")},
+			desiredRefs:       []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "new")},
+			expected:          false,
+			expectedOwnerRefs: []metav1.OwnerReference{ownerRef("Kuadrant", "other", "old"), ownerRef("Other", "kuadrant", "old")},
+		},
+		{
+			name:              "when the desired object has no owner references then return false",
+			existingRefs:      []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "old")},
+			expected:          false,
+			expectedOwnerRefs: []metav1.OwnerReference{ownerRef("Kuadrant", "kuadrant", "old")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{OwnerReferences: tc.existingRefs}}
+			desired := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{OwnerReferences: tc.desiredRefs}}
+			actual := UpdateStaleOwnerReferences(existing, desired)
+			if actual != tc.expected {
+				t.Errorf("unexpected result: got %v, want %v", actual, tc.expected)
+			}
+			if !reflect.DeepEqual(existing.GetOwnerReferences(), tc.expectedOwnerRefs) {
+				t.Errorf("unexpected owner references: got %v, want %v", existing.GetOwnerReferences(), tc.expectedOwnerRefs)
+			}
+		})
+	}
+}
+
 func TestGetServicePortNumber(t *testing.T) {
 	ctx := context.TODO()
 	k8sClient := fake.NewClientBuilder().Build()
@@ -895,3 +953,44 @@ func TestFindDeploymentStatusCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestTagObjectManagedBy(t *testing.T) {
+	obj := &corev1.ConfigMap{}
+	if !TagObjectManagedBy(obj) {
+		t.Error("Expected the label to be added to an object without labels")
+	}
+	if !reflect.DeepEqual(obj.GetLabels(), map[string]string{AppManagedByLabel: KuadrantOperatorName}) {
+		t.Errorf("Unexpected labels: %v", obj.GetLabels())
+	}
+	if TagObjectManagedBy(obj) {
+		t.Error("Expected the label of a labeled object to be left unchanged")
+	}
+
+	obj = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "limitador", AppManagedByLabel: "helm"}}}
+	if !TagObjectManagedBy(obj) {
+		t.Error("Expected the label set to another manager to be changed")
+	}
+	if !reflect.DeepEqual(obj.GetLabels(), map[string]string{"app": "limitador", AppManagedByLabel: KuadrantOperatorName}) {
+		t.Errorf("Unexpected labels: %v", obj.GetLabels())
+	}
+}
+
+func TestReadyEndpoints(t *testing.T) {
+	ready, notReady := true, false
+	slices := []discoveryv1.EndpointSlice{
+		{Endpoints: []discoveryv1.Endpoint{
+			{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			{Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+		}},
+		{Endpoints: []discoveryv1.Endpoint{
+			{Conditions: discoveryv1.EndpointConditions{}},
+		}},
+	}
+
+	if got := ReadyEndpoints(); got != 0 {
+		t.Errorf("ReadyEndpoints() = %d, want 0", got)
+	}
+	if got := ReadyEndpoints(slices...); got != 2 {
+		t.Errorf("ReadyEndpoints() = %d, want 2", got)
+	}
+}
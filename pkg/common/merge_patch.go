@@ -0,0 +1,26 @@
+package common
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+// ApplyMergePatches returns a copy of the value with the JSON merge patches (RFC 7386) applied in order.
+// The value is not modified.
+func ApplyMergePatches[T any](value T, patches [][]byte) (T, error) {
+	var patched T
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return patched, err
+	}
+	for _, patch := range patches {
+		if data, err = jsonpatch.MergePatch(data, patch); err != nil {
+			return patched, err
+		}
+	}
+
+	err = json.Unmarshal(data, &patched)
+	return patched, err
+}
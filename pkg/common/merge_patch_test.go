@@ -0,0 +1,46 @@
+//go:build unit
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyMergePatches(t *testing.T) {
+	requirements := corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+	}
+
+	patched, err := ApplyMergePatches(requirements, [][]byte{
+		[]byte(`{"limits":{"cpu":"1","memory":null}}`),
+		[]byte(`{"limits":{"cpu":"2"}}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+	}
+	if !reflect.DeepEqual(patched, expected) {
+		t.Errorf("unexpected patched value: got %v, want %v", patched, expected)
+	}
+
+	if _, found := requirements.Limits[corev1.ResourceMemory]; !found {
+		t.Errorf("the original value was modified: %v", requirements)
+	}
+
+	if _, err := ApplyMergePatches(requirements, [][]byte{[]byte(`{"limits":`)}); err == nil {
+		t.Error("expected an error for an invalid patch")
+	}
+
+	if unpatched, err := ApplyMergePatches(requirements, nil); err != nil || !reflect.DeepEqual(unpatched, requirements) {
+		t.Errorf("unexpected value without patches: %v, %v", unpatched, err)
+	}
+}
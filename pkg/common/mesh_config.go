@@ -2,15 +2,29 @@ package common
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	istiomeshv1alpha1 "istio.io/api/mesh/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	ExtAuthorizerName = "kuadrant-authorization"
+	// ExtAuthorizerFailOpenName is the name of the ExtensionProvider letting the requests through when Authorino is unavailable
+	ExtAuthorizerFailOpenName = "kuadrant-authorization-fail-open"
+	// ExtAuthorizerInstancePrefix prefixes the names of the ExtensionProviders of the Authorino instances pinned by the gateways
+	ExtAuthorizerInstancePrefix = "kuadrant-authorization-instance-"
+
+	authorinoDefaultGRPCPort = 50051
 )
 
+// DefaultExtAuthzTimeout is the timeout of the ext_authz requests applied by Istio when the provider sets none
+const DefaultExtAuthzTimeout = 600 * time.Second
+
 type Authorizer interface {
 	GetExtensionProvider() *istiomeshv1alpha1.MeshConfig_ExtensionProvider
 }
@@ -37,11 +51,60 @@ func (k *KuadrantAuthorizer) GetExtensionProvider() *istiomeshv1alpha1.MeshConfi
 	return k.extensionProvider
 }
 
+// NewKuadrantFailOpenAuthorizer Creates a new KuadrantAuthorizer letting the requests through when Authorino is unavailable
+func NewKuadrantFailOpenAuthorizer(namespace string) *KuadrantAuthorizer {
+	extensionProvider := createKuadrantAuthorizer(namespace)
+	extensionProvider.Name = ExtAuthorizerFailOpenName
+	extensionProvider.GetEnvoyExtAuthzGrpc().FailOpen = true
+	return &KuadrantAuthorizer{
+		extensionProvider: extensionProvider,
+	}
+}
+
+// NewKuadrantInstanceAuthorizer Creates a new KuadrantAuthorizer sending the requests to a named Authorino instance
+func NewKuadrantInstanceAuthorizer(namespace, authorinoName string, failOpen bool) *KuadrantAuthorizer {
+	extensionProvider := createKuadrantAuthorizer(namespace)
+	extensionProvider.Name = KuadrantInstanceAuthorizerName(namespace, authorinoName, failOpen)
+	extensionProvider.GetEnvoyExtAuthzGrpc().Service = fmt.Sprintf("%s-authorino-authorization.%s.svc.cluster.local", authorinoName, namespace)
+	extensionProvider.GetEnvoyExtAuthzGrpc().FailOpen = failOpen
+	return &KuadrantAuthorizer{
+		extensionProvider: extensionProvider,
+	}
+}
+
+// KuadrantInstanceAuthorizerName returns the name of the ExtensionProvider of a named Authorino instance of the
+// namespace of a kuadrant instance. The names of the namespaces have no dots, separating them from the names of the
+// Authorino instances.
+func KuadrantInstanceAuthorizerName(namespace, authorinoName string, failOpen bool) string {
+	if failOpen {
+		return fmt.Sprintf("%s%s.%s-fail-open", ExtAuthorizerInstancePrefix, namespace, authorinoName)
+	}
+	return fmt.Sprintf("%s%s.%s", ExtAuthorizerInstancePrefix, namespace, authorinoName)
+}
+
+// kuadrantInstanceAuthorizerNamespace returns the namespace of the kuadrant instance of the ExtensionProvider of a
+// named Authorino instance, or false if not the ExtensionProvider of a named Authorino instance
+func kuadrantInstanceAuthorizerNamespace(providerName string) (string, bool) {
+	if !strings.HasPrefix(providerName, ExtAuthorizerInstancePrefix) {
+		return "", false
+	}
+	namespace, _, found := strings.Cut(strings.TrimPrefix(providerName, ExtAuthorizerInstancePrefix), ".")
+	return namespace, found && namespace != ""
+}
+
+// WithTimeout sets the timeout of the ext_authz requests to the Kuadrant ExtensionProvider
+func (k *KuadrantAuthorizer) WithTimeout(timeout time.Duration) *KuadrantAuthorizer {
+	if provider := k.extensionProvider.GetEnvoyExtAuthzGrpc(); provider != nil {
+		provider.Timeout = durationpb.New(timeout)
+	}
+	return k
+}
+
 // createKuadrantAuthorizer Creates the Istio MeshConfig ExtensionProvider for Kuadrant
 func createKuadrantAuthorizer(namespace string) *istiomeshv1alpha1.MeshConfig_ExtensionProvider {
 	envoyExtAuthGRPC := &istiomeshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExtAuthzGrpc{
 		EnvoyExtAuthzGrpc: &istiomeshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationGrpcProvider{
-			Port:    50051,
+			Port:    authorinoDefaultGRPCPort,
 			Service: fmt.Sprintf("authorino-authorino-authorization.%s.svc.cluster.local", namespace),
 		},
 	}
@@ -51,6 +114,41 @@ func createKuadrantAuthorizer(namespace string) *istiomeshv1alpha1.MeshConfig_Ex
 	}
 }
 
+// AuthorizerListenerMismatches returns the settings of the listener of an Authorino instance that break the
+// wiring with the ext_authz provider, in which case the AuthPolicies are silently not enforced
+func AuthorizerListenerMismatches(authorizer Authorizer, authorino *authorinov1beta1.Authorino) []string {
+	mismatches := make([]string, 0)
+
+	provider := authorizer.GetExtensionProvider().GetEnvoyExtAuthzGrpc()
+	if provider == nil {
+		return mismatches
+	}
+
+	grpcPort := AuthorinoGRPCPort(authorino)
+	if uint32(grpcPort) != provider.GetPort() {
+		mismatches = append(mismatches, fmt.Sprintf("gRPC port %d differs from the port %d of the %s extension provider", grpcPort, provider.GetPort(), ExtAuthorizerName))
+	}
+
+	// the extension provider connects in plain text
+	if tlsEnabled := authorino.Spec.Listener.Tls.Enabled; tlsEnabled == nil || *tlsEnabled {
+		mismatches = append(mismatches, fmt.Sprintf("TLS is enabled in the listener, not supported by the %s extension provider", ExtAuthorizerName))
+	}
+
+	return mismatches
+}
+
+// AuthorinoGRPCPort returns the port of the gRPC auth service of an Authorino instance
+func AuthorinoGRPCPort(authorino *authorinov1beta1.Authorino) int32 {
+	grpcPort := int32(authorinoDefaultGRPCPort)
+	if authorino.Spec.Listener.Port != nil {
+		grpcPort = *authorino.Spec.Listener.Port
+	}
+	if authorino.Spec.Listener.Ports.GRPC != nil {
+		grpcPort = *authorino.Spec.Listener.Ports.GRPC
+	}
+	return grpcPort
+}
+
 // HasKuadrantAuthorizer returns true if the IstioOperator has the Kuadrant ExtensionProvider
 func HasKuadrantAuthorizer(configWrapper ConfigWrapper, authorizer KuadrantAuthorizer) (bool, error) {
 	config, err := configWrapper.GetMeshConfig()
@@ -60,19 +158,38 @@ func HasKuadrantAuthorizer(configWrapper ConfigWrapper, authorizer KuadrantAutho
 	return hasExtensionProvider(authorizer.GetExtensionProvider(), extensionProvidersFromMeshConfig(config)), nil
 }
 
-// RegisterKuadrantAuthorizer adds the Kuadrant ExtensionProvider to the IstioOperator
+// IsKuadrantAuthorizerUpToDate returns true if the IstioOperator has the Kuadrant ExtensionProvider with the same settings
+func IsKuadrantAuthorizerUpToDate(configWrapper ConfigWrapper, authorizer Authorizer) (bool, error) {
+	config, err := configWrapper.GetMeshConfig()
+	if err != nil {
+		return false, err
+	}
+	for _, extensionProvider := range extensionProvidersFromMeshConfig(config) {
+		if extensionProvider.Name == authorizer.GetExtensionProvider().Name {
+			return proto.Equal(extensionProvider, authorizer.GetExtensionProvider()), nil
+		}
+	}
+	return false, nil
+}
+
+// RegisterKuadrantAuthorizer adds the Kuadrant ExtensionProvider to the IstioOperator, or updates its settings
 func RegisterKuadrantAuthorizer(configWrapper ConfigWrapper, authorizer Authorizer) error {
 	config, err := configWrapper.GetMeshConfig()
 	if err != nil {
 		return err
 	}
-	if !hasExtensionProvider(authorizer.GetExtensionProvider(), extensionProvidersFromMeshConfig(config)) {
-		config.ExtensionProviders = append(config.ExtensionProviders, authorizer.GetExtensionProvider())
-		if err = configWrapper.SetMeshConfig(config); err != nil {
-			return err
+	for idx, extensionProvider := range config.ExtensionProviders {
+		if extensionProvider.Name != authorizer.GetExtensionProvider().Name {
+			continue
+		}
+		if proto.Equal(extensionProvider, authorizer.GetExtensionProvider()) {
+			return nil
 		}
+		config.ExtensionProviders[idx] = authorizer.GetExtensionProvider()
+		return configWrapper.SetMeshConfig(config)
 	}
-	return nil
+	config.ExtensionProviders = append(config.ExtensionProviders, authorizer.GetExtensionProvider())
+	return configWrapper.SetMeshConfig(config)
 }
 
 // UnregisterKuadrantAuthorizer removes the Kuadrant ExtensionProvider from the IstioOperator
@@ -90,6 +207,35 @@ func UnregisterKuadrantAuthorizer(configWrapper ConfigWrapper, authorizer Author
 	return nil
 }
 
+// UnregisterStaleKuadrantInstanceAuthorizers removes the ExtensionProviders of the Authorino instances of the
+// namespace of a kuadrant instance no longer pinned by any gateway, i.e. not in the list of authorizers. The
+// ExtensionProviders of the other kuadrant instances are left untouched. Returns true if any was removed.
+func UnregisterStaleKuadrantInstanceAuthorizers(configWrapper ConfigWrapper, namespace string, authorizers []Authorizer) (bool, error) {
+	config, err := configWrapper.GetMeshConfig()
+	if err != nil {
+		return false, err
+	}
+
+	desired := make([]*istiomeshv1alpha1.MeshConfig_ExtensionProvider, 0, len(authorizers))
+	for _, authorizer := range authorizers {
+		desired = append(desired, authorizer.GetExtensionProvider())
+	}
+
+	providers := make([]*istiomeshv1alpha1.MeshConfig_ExtensionProvider, 0, len(config.ExtensionProviders))
+	for _, extensionProvider := range config.ExtensionProviders {
+		if providerNamespace, ok := kuadrantInstanceAuthorizerNamespace(extensionProvider.Name); ok && providerNamespace == namespace && !hasExtensionProvider(extensionProvider, desired) {
+			continue
+		}
+		providers = append(providers, extensionProvider)
+	}
+	if len(providers) == len(config.ExtensionProviders) {
+		return false, nil
+	}
+
+	config.ExtensionProviders = providers
+	return true, configWrapper.SetMeshConfig(config)
+}
+
 func extensionProvidersFromMeshConfig(config *istiomeshv1alpha1.MeshConfig) (extensionProviders []*istiomeshv1alpha1.MeshConfig_ExtensionProvider) {
 	extensionProviders = config.ExtensionProviders
 	if len(extensionProviders) == 0 {
@@ -5,7 +5,9 @@ package common
 
 import (
 	"testing"
+	"time"
 
+	authorinov1beta1 "github.com/kuadrant/authorino-operator/api/v1beta1"
 	"gotest.tools/assert"
 	istiomeshv1alpha1 "istio.io/api/mesh/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -53,6 +55,70 @@ func TestKuadrantAuthorizer_GetExtensionProvider(t *testing.T) {
 	assert.Equal(t, provider.GetEnvoyExtAuthzGrpc().Service, "authorino-authorino-authorization.default.svc.cluster.local")
 }
 
+func TestKuadrantFailOpenAuthorizer_GetExtensionProvider(t *testing.T) {
+	provider := NewKuadrantFailOpenAuthorizer("default").GetExtensionProvider()
+
+	assert.Equal(t, provider.Name, ExtAuthorizerFailOpenName)
+	assert.Equal(t, provider.GetEnvoyExtAuthzGrpc().Service, "authorino-authorino-authorization.default.svc.cluster.local")
+	assert.Equal(t, provider.GetEnvoyExtAuthzGrpc().FailOpen, true)
+	assert.Equal(t, NewKuadrantAuthorizer("default").GetExtensionProvider().GetEnvoyExtAuthzGrpc().FailOpen, false)
+}
+
+func TestKuadrantInstanceAuthorizer_GetExtensionProvider(t *testing.T) {
+	provider := NewKuadrantInstanceAuthorizer("kuadrant", "tenant-a", false).GetExtensionProvider()
+	assert.Equal(t, provider.Name, "kuadrant-authorization-instance-kuadrant.tenant-a")
+	assert.Equal(t, provider.GetEnvoyExtAuthzGrpc().Service, "tenant-a-authorino-authorization.kuadrant.svc.cluster.local")
+	assert.Equal(t, provider.GetEnvoyExtAuthzGrpc().FailOpen, false)
+
+	provider = NewKuadrantInstanceAuthorizer("kuadrant", "tenant-a", true).GetExtensionProvider()
+	assert.Equal(t, provider.Name, "kuadrant-authorization-instance-kuadrant.tenant-a-fail-open")
+	assert.Equal(t, provider.GetEnvoyExtAuthzGrpc().FailOpen, true)
+}
+
+func TestKuadrantInstanceAuthorizerName(t *testing.T) {
+	// the same authorino instance name in the namespaces of two kuadrant instances
+	assert.Assert(t, KuadrantInstanceAuthorizerName("team-a", "authorino", false) != KuadrantInstanceAuthorizerName("team", "a-authorino", false))
+	assert.Assert(t, KuadrantInstanceAuthorizerName("team-a", "tenant", false) != KuadrantInstanceAuthorizerName("team-b", "tenant", false))
+
+	namespace, ok := kuadrantInstanceAuthorizerNamespace(KuadrantInstanceAuthorizerName("team-a", "tenant.example", true))
+	assert.Equal(t, ok, true)
+	assert.Equal(t, namespace, "team-a")
+
+	_, ok = kuadrantInstanceAuthorizerNamespace(ExtAuthorizerFailOpenName)
+	assert.Equal(t, ok, false)
+}
+
+func TestUnregisterStaleKuadrantInstanceAuthorizers(t *testing.T) {
+	configWrapper := &stubbedConfigWrapper{getStubbedMeshConfig()}
+	pinned := NewKuadrantInstanceAuthorizer("kuadrant", "tenant-a", false)
+	stale := NewKuadrantInstanceAuthorizer("kuadrant", "tenant-b", false)
+	// the same instances pinned by the gateways of another kuadrant instance
+	otherPinned := NewKuadrantInstanceAuthorizer("kuadrant-b", "tenant-a", false)
+	otherStale := NewKuadrantInstanceAuthorizer("kuadrant-b", "tenant-b", false)
+	for _, authorizer := range []Authorizer{NewKuadrantAuthorizer("kuadrant"), pinned, stale, otherPinned, otherStale} {
+		assert.NilError(t, RegisterKuadrantAuthorizer(configWrapper, authorizer))
+	}
+
+	removed, err := UnregisterStaleKuadrantInstanceAuthorizers(configWrapper, "kuadrant", []Authorizer{pinned})
+	assert.NilError(t, err)
+	assert.Equal(t, removed, true)
+	assert.Equal(t, len(configWrapper.istioMeshConfig.ExtensionProviders), 5)
+	assert.Equal(t, configWrapper.istioMeshConfig.ExtensionProviders[2].Name, pinned.GetExtensionProvider().Name)
+	assert.Equal(t, configWrapper.istioMeshConfig.ExtensionProviders[3].Name, otherPinned.GetExtensionProvider().Name)
+	assert.Equal(t, configWrapper.istioMeshConfig.ExtensionProviders[4].Name, otherStale.GetExtensionProvider().Name)
+
+	removed, err = UnregisterStaleKuadrantInstanceAuthorizers(configWrapper, "kuadrant", []Authorizer{pinned})
+	assert.NilError(t, err)
+	assert.Equal(t, removed, false)
+
+	// all the providers of the pinned instances of a kuadrant instance deleted
+	removed, err = UnregisterStaleKuadrantInstanceAuthorizers(configWrapper, "kuadrant-b", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, removed, true)
+	assert.Equal(t, len(configWrapper.istioMeshConfig.ExtensionProviders), 3)
+	assert.Equal(t, configWrapper.istioMeshConfig.ExtensionProviders[2].Name, pinned.GetExtensionProvider().Name)
+}
+
 func TestHasKuadrantAuthorizer(t *testing.T) {
 	authorizer := NewKuadrantAuthorizer("default")
 	configWrapper := &stubbedConfigWrapper{getStubbedMeshConfig()}
@@ -79,6 +145,24 @@ func TestRegisterKuadrantAuthorizer(t *testing.T) {
 	assert.Equal(t, meshConfig.ExtensionProviders[1].Name, "kuadrant-authorization")
 }
 
+func TestRegisterKuadrantAuthorizerUpdatesTimeout(t *testing.T) {
+	configWrapper := &stubbedConfigWrapper{getStubbedMeshConfig()}
+	assert.NilError(t, RegisterKuadrantAuthorizer(configWrapper, NewKuadrantAuthorizer("default")))
+
+	authorizer := NewKuadrantAuthorizer("default").WithTimeout(250 * time.Millisecond)
+	upToDate, err := IsKuadrantAuthorizerUpToDate(configWrapper, authorizer)
+	assert.NilError(t, err)
+	assert.Equal(t, upToDate, false)
+
+	assert.NilError(t, RegisterKuadrantAuthorizer(configWrapper, authorizer))
+	assert.Equal(t, len(configWrapper.istioMeshConfig.ExtensionProviders), 2)
+	assert.Equal(t, configWrapper.istioMeshConfig.ExtensionProviders[1].GetEnvoyExtAuthzGrpc().GetTimeout().AsDuration(), 250*time.Millisecond)
+
+	upToDate, err = IsKuadrantAuthorizerUpToDate(configWrapper, authorizer)
+	assert.NilError(t, err)
+	assert.Equal(t, upToDate, true)
+}
+
 func TestUnregisterKuadrantAuthorizer(t *testing.T) {
 	authorizer := NewKuadrantAuthorizer("default")
 	configWrapper := &stubbedConfigWrapper{getStubbedMeshConfig()}
@@ -94,3 +178,23 @@ func TestUnregisterKuadrantAuthorizer(t *testing.T) {
 	meshConfig, _ := configWrapper.GetMeshConfig()
 	assert.Equal(t, meshConfig.GetExtensionProviders()[0].Name, "custom-authorizer")
 }
+
+func TestAuthorizerListenerMismatches(t *testing.T) {
+	authorizer := NewKuadrantAuthorizer("default")
+	tlsDisabled := false
+	customPort := int32(50052)
+
+	authorino := &authorinov1beta1.Authorino{}
+	authorino.Spec.Listener.Tls.Enabled = &tlsDisabled
+	assert.Equal(t, len(AuthorizerListenerMismatches(authorizer, authorino)), 0)
+
+	authorino.Spec.Listener.Ports.GRPC = &customPort
+	mismatches := AuthorizerListenerMismatches(authorizer, authorino)
+	assert.Equal(t, len(mismatches), 1)
+	assert.Equal(t, mismatches[0], "gRPC port 50052 differs from the port 50051 of the kuadrant-authorization extension provider")
+
+	// TLS is enabled by default
+	mismatches = AuthorizerListenerMismatches(authorizer, &authorinov1beta1.Authorino{})
+	assert.Equal(t, len(mismatches), 1)
+	assert.Equal(t, mismatches[0], "TLS is enabled in the listener, not supported by the kuadrant-authorization extension provider")
+}
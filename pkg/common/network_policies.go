@@ -0,0 +1,122 @@
+package common
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NetworkPolicyEndpoint is one end of a connection between pods, as selected by the NetworkPolicies
+type NetworkPolicyEndpoint struct {
+	Namespace       string
+	NamespaceLabels map[string]string
+	PodLabels       map[string]string
+}
+
+// NetworkPoliciesAllowEgress tells whether the NetworkPolicies of the namespace of src let it connect to a port of dst.
+// The peers and the ports that cannot be resolved from the policies alone, i.e. ip blocks and named ports against
+// numbered ones, are assumed to match, so a connection is only reported blocked when it certainly is.
+func NetworkPoliciesAllowEgress(policies []networkingv1.NetworkPolicy, src, dst NetworkPolicyEndpoint, port intstr.IntOrString) bool {
+	isolated := false
+	for idx := range policies {
+		policy := &policies[idx]
+		if policy.Namespace != src.Namespace || !networkPolicyHasType(policy, networkingv1.PolicyTypeEgress) || !labelSelectorMatches(&policy.Spec.PodSelector, src.PodLabels) {
+			continue
+		}
+		isolated = true
+		for _, rule := range policy.Spec.Egress {
+			if networkPolicyPortsMatch(rule.Ports, port) && networkPolicyPeersMatch(rule.To, policy.Namespace, dst) {
+				return true
+			}
+		}
+	}
+	return !isolated
+}
+
+// NetworkPoliciesAllowIngress tells whether the NetworkPolicies of the namespace of dst let src connect to a port of it
+func NetworkPoliciesAllowIngress(policies []networkingv1.NetworkPolicy, src, dst NetworkPolicyEndpoint, port intstr.IntOrString) bool {
+	isolated := false
+	for idx := range policies {
+		policy := &policies[idx]
+		if policy.Namespace != dst.Namespace || !networkPolicyHasType(policy, networkingv1.PolicyTypeIngress) || !labelSelectorMatches(&policy.Spec.PodSelector, dst.PodLabels) {
+			continue
+		}
+		isolated = true
+		for _, rule := range policy.Spec.Ingress {
+			if networkPolicyPortsMatch(rule.Ports, port) && networkPolicyPeersMatch(rule.From, policy.Namespace, src) {
+				return true
+			}
+		}
+	}
+	return !isolated
+}
+
+// networkPolicyHasType tells whether a NetworkPolicy isolates the pods it selects for the given direction.
+// Without policy types, the policy always applies to the ingress, and to the egress if it has egress rules.
+func networkPolicyHasType(policy *networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == networkingv1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+func networkPolicyPeersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, endpoint NetworkPolicyEndpoint) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			return true
+		}
+		if peer.NamespaceSelector == nil {
+			if endpoint.Namespace != policyNamespace {
+				continue
+			}
+		} else if !labelSelectorMatches(peer.NamespaceSelector, endpoint.NamespaceLabels) {
+			continue
+		}
+		if peer.PodSelector == nil || labelSelectorMatches(peer.PodSelector, endpoint.PodLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+func networkPolicyPortsMatch(ports []networkingv1.NetworkPolicyPort, port intstr.IntOrString) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		if p.Protocol != nil && *p.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+		if p.Port == nil || p.Port.Type != port.Type {
+			return true
+		}
+		if port.Type == intstr.String {
+			if p.Port.StrVal == port.StrVal {
+				return true
+			}
+			continue
+		}
+		if p.Port.IntVal == port.IntVal || (p.EndPort != nil && p.Port.IntVal <= port.IntVal && port.IntVal <= *p.EndPort) {
+			return true
+		}
+	}
+	return false
+}
+
+func labelSelectorMatches(selector *metav1.LabelSelector, objLabels map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(objLabels))
+}
@@ -0,0 +1,84 @@
+//go:build unit
+
+package common
+
+import (
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNetworkPoliciesAllow(t *testing.T) {
+	gateway := NetworkPolicyEndpoint{
+		Namespace:       "gateway-system",
+		NamespaceLabels: map[string]string{"kubernetes.io/metadata.name": "gateway-system"},
+		PodLabels:       map[string]string{"istio": "ingressgateway"},
+	}
+	limitador := NetworkPolicyEndpoint{
+		Namespace:       "kuadrant-system",
+		NamespaceLabels: map[string]string{"kubernetes.io/metadata.name": "kuadrant-system"},
+		PodLabels:       map[string]string{"app": "limitador"},
+	}
+	port := intstr.FromInt(8081)
+	otherPort := intstr.FromInt(8080)
+
+	denyAll := func(namespace string, policyTypes ...networkingv1.PolicyType) networkingv1.NetworkPolicy {
+		return networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "deny-all", Namespace: namespace},
+			Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: policyTypes},
+		}
+	}
+	allowIngressFromGateway := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-gateway", Namespace: "kuadrant-system"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "limitador"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "gateway-system"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"istio": "ingressgateway"}},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: &port}},
+			}},
+		},
+	}
+	allowEgressToKuadrant := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-kuadrant", Namespace: "gateway-system"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				To: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kuadrant-system"}},
+				}},
+			}},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		policies        []networkingv1.NetworkPolicy
+		port            intstr.IntOrString
+		expectedEgress  bool
+		expectedIngress bool
+	}{
+		{name: "when no policies then allowed", port: port, expectedEgress: true, expectedIngress: true},
+		{name: "when ingress denied then blocked", policies: []networkingv1.NetworkPolicy{denyAll("kuadrant-system")}, port: port, expectedEgress: true, expectedIngress: false},
+		{name: "when egress denied then blocked", policies: []networkingv1.NetworkPolicy{denyAll("gateway-system", networkingv1.PolicyTypeEgress)}, port: port, expectedEgress: false, expectedIngress: true},
+		{name: "when policies of other namespaces then allowed", policies: []networkingv1.NetworkPolicy{denyAll("default", networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress)}, port: port, expectedEgress: true, expectedIngress: true},
+		{name: "when ingress allowed from the gateway then allowed", policies: []networkingv1.NetworkPolicy{denyAll("kuadrant-system"), allowIngressFromGateway}, port: port, expectedEgress: true, expectedIngress: true},
+		{name: "when ingress allowed on another port then blocked", policies: []networkingv1.NetworkPolicy{denyAll("kuadrant-system"), allowIngressFromGateway}, port: otherPort, expectedEgress: true, expectedIngress: false},
+		{name: "when egress allowed to the namespace then allowed", policies: []networkingv1.NetworkPolicy{denyAll("gateway-system", networkingv1.PolicyTypeEgress), allowEgressToKuadrant}, port: port, expectedEgress: true, expectedIngress: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if got := NetworkPoliciesAllowEgress(tc.policies, gateway, limitador, tc.port); got != tc.expectedEgress {
+				subT.Errorf("NetworkPoliciesAllowEgress() = %t, want %t", got, tc.expectedEgress)
+			}
+			if got := NetworkPoliciesAllowIngress(tc.policies, gateway, limitador, tc.port); got != tc.expectedIngress {
+				subT.Errorf("NetworkPoliciesAllowIngress() = %t, want %t", got, tc.expectedIngress)
+			}
+		})
+	}
+}
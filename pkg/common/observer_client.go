@@ -0,0 +1,50 @@
+package common
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// observerClient drops the writes of the wrapped client, so the resources are never mutated, unlike in dry-run mode
+// not even submitted to the API server. The writes of the status go through, the status reporting what the operator
+// observes.
+type observerClient struct {
+	client.Client
+	logger logr.Logger
+}
+
+// NewObserverClient returns a client dropping all its writes but the ones of the status
+func NewObserverClient(c client.Client, logger logr.Logger) client.Client {
+	return &observerClient{Client: c, logger: logger}
+}
+
+func (c *observerClient) skip(action string, obj client.Object) {
+	c.logger.V(1).Info("observer mode: write skipped", "action", action, "kind", obj.GetObjectKind().GroupVersionKind().Kind, "object", client.ObjectKeyFromObject(obj))
+}
+
+func (c *observerClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.skip("create", obj)
+	return nil
+}
+
+func (c *observerClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.skip("update", obj)
+	return nil
+}
+
+func (c *observerClient) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	c.skip("patch", obj)
+	return nil
+}
+
+func (c *observerClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	c.skip("delete", obj)
+	return nil
+}
+
+func (c *observerClient) DeleteAllOf(_ context.Context, obj client.Object, _ ...client.DeleteAllOfOption) error {
+	c.skip("deleteAllOf", obj)
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build unit
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// writeRecorder records the writes reaching the client, and of the status
+type writeRecorder struct {
+	client.Client
+	writes []string
+}
+
+func (r *writeRecorder) Create(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+	r.writes = append(r.writes, "create")
+	return nil
+}
+
+func (r *writeRecorder) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	r.writes = append(r.writes, "update")
+	return nil
+}
+
+func (r *writeRecorder) Delete(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+	r.writes = append(r.writes, "delete")
+	return nil
+}
+
+func (r *writeRecorder) Status() client.SubResourceWriter {
+	return &statusWriteRecorder{recorder: r}
+}
+
+type statusWriteRecorder struct {
+	client.SubResourceWriter
+	recorder *writeRecorder
+}
+
+func (s *statusWriteRecorder) Update(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+	s.recorder.writes = append(s.recorder.writes, "status")
+	return nil
+}
+
+func TestObserverClient(t *testing.T) {
+	ctx := context.TODO()
+	recorder := &writeRecorder{}
+	cl := NewObserverClient(recorder, logr.Discard())
+	obj := &corev1.ConfigMap{}
+
+	for _, err := range []error{cl.Create(ctx, obj), cl.Update(ctx, obj), cl.Delete(ctx, obj), cl.Status().Update(ctx, obj)} {
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if len(recorder.writes) != 1 || recorder.writes[0] != "status" {
+		t.Fatalf("expected only the status written, got %v", recorder.writes)
+	}
+}
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// supportedTargetRefKinds are the kinds of network resources the policies can target
+var supportedTargetRefKinds = []string{"HTTPRoute", "Gateway"}
+
+// InvalidTargetRefError is the error of a policy targetRef not referring to a supported kind of network resource
+type InvalidTargetRefError struct {
+	// Field is the invalid field of the targetRef, i.e. Group or Kind
+	Field string
+	Value string
+	// Suggestion is the correct form of the value, when the mistake is unambiguous
+	Suggestion string
+}
+
+func (e *InvalidTargetRefError) Error() string {
+	msg := fmt.Sprintf("invalid targetRef.%s %s.", e.Field, e.Value)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(" Did you mean %s?", e.Suggestion)
+	}
+	if e.Field == "Group" {
+		return msg + fmt.Sprintf(" The only supported group is %s", gatewayapiv1beta1.GroupName)
+	}
+	return msg + fmt.Sprintf(" The only supported kinds are %s", strings.Join(supportedTargetRefKinds, " and "))
+}
+
+func IsInvalidTargetRef(err error) bool {
+	targetRefErr := &InvalidTargetRefError{}
+	return errors.As(err, &targetRefErr)
+}
+
+// ValidateTargetRef checks the group and the kind of a targetRef refer to a supported kind of network resource.
+// The error suggests the correct form of a mistyped value.
+func ValidateTargetRef(targetRef gatewayapiv1alpha2.PolicyTargetReference) error {
+	if group := string(targetRef.Group); group != gatewayapiv1beta1.GroupName {
+		return &InvalidTargetRefError{Field: "Group", Value: group, Suggestion: suggestTargetRefGroup(group)}
+	}
+	if kind := string(targetRef.Kind); !Contains(supportedTargetRefKinds, kind) {
+		return &InvalidTargetRefError{Field: "Kind", Value: kind, Suggestion: suggestTargetRefKind(kind)}
+	}
+	return nil
+}
+
+// NormalizeTargetRef returns the targetRef with the group and the kind corrected when the mistakes are unambiguous,
+// i.e. in the casing, a version appended to the group or the plural of the kind, and whether it was corrected
+func NormalizeTargetRef(targetRef gatewayapiv1alpha2.PolicyTargetReference) (gatewayapiv1alpha2.PolicyTargetReference, bool) {
+	normalized := *targetRef.DeepCopy()
+	if group := string(targetRef.Group); group != gatewayapiv1beta1.GroupName {
+		if suggestion := suggestTargetRefGroup(group); suggestion != "" {
+			normalized.Group = gatewayapiv1alpha2.Group(suggestion)
+		}
+	}
+	if kind := string(targetRef.Kind); !Contains(supportedTargetRefKinds, kind) {
+		if suggestion := suggestTargetRefKind(kind); suggestion != "" {
+			normalized.Kind = gatewayapiv1alpha2.Kind(suggestion)
+		}
+	}
+	return normalized, normalized.Group != targetRef.Group || normalized.Kind != targetRef.Kind
+}
+
+func suggestTargetRefGroup(group string) string {
+	// e.g. Gateway.Networking.K8s.io, gateway.networking.k8s.io/v1beta1
+	name, _, _ := strings.Cut(strings.TrimSpace(group), "/")
+	if strings.EqualFold(name, gatewayapiv1beta1.GroupName) {
+		return gatewayapiv1beta1.GroupName
+	}
+	return ""
+}
+
+func suggestTargetRefKind(kind string) string {
+	// e.g. httproute, Gateways
+	name := strings.TrimSpace(kind)
+	for _, supported := range supportedTargetRefKinds {
+		if strings.EqualFold(name, supported) || strings.EqualFold(name, supported+"s") {
+			return supported
+		}
+	}
+	return ""
+}
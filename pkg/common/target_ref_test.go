@@ -0,0 +1,69 @@
+//go:build unit
+
+package common
+
+import (
+	"strings"
+	"testing"
+
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestValidateTargetRef(t *testing.T) {
+	testCases := []struct {
+		name       string
+		group      string
+		kind       string
+		expected   string
+		suggestion string
+	}{
+		{name: "when valid httproute then no error", group: "gateway.networking.k8s.io", kind: "HTTPRoute"},
+		{name: "when valid gateway then no error", group: "gateway.networking.k8s.io", kind: "Gateway"},
+		{name: "when kind casing mistyped then suggested", group: "gateway.networking.k8s.io", kind: "httproute", expected: "invalid targetRef.Kind httproute", suggestion: "HTTPRoute"},
+		{name: "when kind plural then suggested", group: "gateway.networking.k8s.io", kind: "Gateways", expected: "invalid targetRef.Kind Gateways", suggestion: "Gateway"},
+		{name: "when kind unknown then no suggestion", group: "gateway.networking.k8s.io", kind: "GRPCRoute", expected: "invalid targetRef.Kind GRPCRoute"},
+		{name: "when group casing mistyped then suggested", group: "Gateway.Networking.K8s.io", kind: "HTTPRoute", expected: "invalid targetRef.Group Gateway.Networking.K8s.io", suggestion: "gateway.networking.k8s.io"},
+		{name: "when group with version then suggested", group: "gateway.networking.k8s.io/v1beta1", kind: "HTTPRoute", expected: "invalid targetRef.Group gateway.networking.k8s.io/v1beta1", suggestion: "gateway.networking.k8s.io"},
+		{name: "when group unknown then no suggestion", group: "networking.istio.io", kind: "HTTPRoute", expected: "invalid targetRef.Group networking.istio.io"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			err := ValidateTargetRef(gatewayapiv1alpha2.PolicyTargetReference{Group: gatewayapiv1alpha2.Group(tc.group), Kind: gatewayapiv1alpha2.Kind(tc.kind), Name: "target"})
+			if tc.expected == "" {
+				if err != nil {
+					subT.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !IsInvalidTargetRef(err) || !strings.HasPrefix(err.Error(), tc.expected) {
+				subT.Fatalf("expected error %q, got %v", tc.expected, err)
+			}
+			if hasSuggestion := strings.Contains(err.Error(), "Did you mean"); hasSuggestion != (tc.suggestion != "") || !strings.Contains(err.Error(), tc.suggestion) {
+				subT.Fatalf("expected suggestion %q, got %v", tc.suggestion, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeTargetRef(t *testing.T) {
+	targetRef := gatewayapiv1alpha2.PolicyTargetReference{Group: "Gateway.networking.k8s.io/v1beta1", Kind: "httproutes", Name: "target"}
+	normalized, corrected := NormalizeTargetRef(targetRef)
+	if !corrected {
+		t.Fatal("expected the targetRef to be corrected")
+	}
+	if normalized.Group != "gateway.networking.k8s.io" || normalized.Kind != "HTTPRoute" || normalized.Name != "target" {
+		t.Fatalf("unexpected normalized targetRef: %+v", normalized)
+	}
+	if targetRef.Kind != "httproutes" {
+		t.Fatal("expected the original targetRef to be unchanged")
+	}
+
+	if _, corrected := NormalizeTargetRef(normalized); corrected {
+		t.Fatal("expected a valid targetRef not to be corrected")
+	}
+
+	if normalized, corrected := NormalizeTargetRef(gatewayapiv1alpha2.PolicyTargetReference{Group: "gateway.networking.k8s.io", Kind: "Service", Name: "target"}); corrected || normalized.Kind != "Service" {
+		t.Fatalf("expected an unknown kind not to be corrected, got %+v", normalized)
+	}
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskOrder resolves the order of a set of named tasks from a comma-separated list of their names.
+// The tasks omitted from the list are disabled, and an empty list enables all the tasks in the default order.
+// Each task must be listed after the tasks it depends on.
+func TaskOrder(defaults []string, dependencies map[string][]string, list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return defaults, nil
+	}
+
+	known := make(map[string]struct{}, len(defaults))
+	for _, name := range defaults {
+		known[name] = struct{}{}
+	}
+
+	order := make([]string, 0, len(defaults))
+	positions := make(map[string]int, len(defaults))
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown task %s", name)
+		}
+		if _, ok := positions[name]; ok {
+			return nil, fmt.Errorf("duplicate task %s", name)
+		}
+		positions[name] = len(order)
+		order = append(order, name)
+	}
+
+	for _, name := range order {
+		for _, dependency := range dependencies[name] {
+			position, ok := positions[dependency]
+			if !ok {
+				return nil, fmt.Errorf("task %s requires task %s", name, dependency)
+			}
+			if position > positions[name] {
+				return nil, fmt.Errorf("task %s must run after task %s", name, dependency)
+			}
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,47 @@
+//go:build unit
+
+package common
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTaskOrder(t *testing.T) {
+	defaults := []string{"a", "b", "c"}
+	dependencies := map[string][]string{"c": {"a"}}
+
+	testCases := []struct {
+		name        string
+		list        string
+		expected    []string
+		expectedErr string
+	}{
+		{name: "when empty list then defaults", list: "", expected: defaults},
+		{name: "when reordered then custom order", list: "b, a,c", expected: []string{"b", "a", "c"}},
+		{name: "when omitted then disabled", list: "a,c", expected: []string{"a", "c"}},
+		{name: "when unknown task then error", list: "a,d", expectedErr: "unknown task d"},
+		{name: "when duplicate task then error", list: "a,b,a", expectedErr: "duplicate task a"},
+		{name: "when dependency disabled then error", list: "b,c", expectedErr: "task c requires task a"},
+		{name: "when dependency after then error", list: "c,a", expectedErr: "task c must run after task a"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			order, err := TaskOrder(defaults, dependencies, tc.list)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					subT.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(order, tc.expected) {
+				subT.Fatalf("expected %v, got %v", tc.expected, order)
+			}
+		})
+	}
+}
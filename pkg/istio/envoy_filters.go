@@ -84,3 +84,34 @@ func AlwaysUpdateEnvoyFilter(existingObj, desiredObj client.Object) (bool, error
 	}
 	return true, nil
 }
+
+// LimitadorClusterAddress returns the address and the port of the rate limit service set by the patch of an EnvoyFilter
+// built by LimitadorClusterPatch, if any
+func LimitadorClusterAddress(ef *istionetworkingv1alpha3.EnvoyFilter) (string, int, bool) {
+	for _, configPatch := range ef.Spec.ConfigPatches {
+		if configPatch.GetPatch().GetValue() == nil {
+			continue
+		}
+		value := configPatch.GetPatch().GetValue().AsMap()
+		if value["name"] != common.KuadrantRateLimitClusterName {
+			continue
+		}
+		loadAssignment, _ := value["load_assignment"].(map[string]interface{})
+		endpoints, _ := loadAssignment["endpoints"].([]interface{})
+		for _, endpoint := range endpoints {
+			lbEndpoints, _ := endpoint.(map[string]interface{})["lb_endpoints"].([]interface{})
+			for _, lbEndpoint := range lbEndpoints {
+				lbEndpointMap, _ := lbEndpoint.(map[string]interface{})
+				endpointMap, _ := lbEndpointMap["endpoint"].(map[string]interface{})
+				addressMap, _ := endpointMap["address"].(map[string]interface{})
+				socketAddress, _ := addressMap["socket_address"].(map[string]interface{})
+				address, _ := socketAddress["address"].(string)
+				port, _ := socketAddress["port_value"].(float64)
+				if address != "" {
+					return address, int(port), true
+				}
+			}
+		}
+	}
+	return "", 0, false
+}
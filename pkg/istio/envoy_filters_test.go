@@ -0,0 +1,27 @@
+//go:build unit
+// +build unit
+
+package istio
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	istionetworkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+)
+
+func TestLimitadorClusterAddress(t *testing.T) {
+	patches, err := LimitadorClusterPatch("limitador-limitador.kuadrant-system.svc.cluster.local", 8081)
+	assert.NilError(t, err)
+
+	ef := &istionetworkingv1alpha3.EnvoyFilter{}
+	ef.Spec.ConfigPatches = patches
+
+	address, port, found := LimitadorClusterAddress(ef)
+	assert.Assert(t, found)
+	assert.Equal(t, address, "limitador-limitador.kuadrant-system.svc.cluster.local")
+	assert.Equal(t, port, 8081)
+
+	_, _, found = LimitadorClusterAddress(&istionetworkingv1alpha3.EnvoyFilter{})
+	assert.Assert(t, !found)
+}
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/go-logr/logr"
+	uberzap "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,6 +34,9 @@ import (
 var (
 	// Log is the base logger
 	Log logr.Logger = logr.New(ctrllog.NullLogSink{})
+
+	// level is the minimum enabled logging level of the loggers created by NewLogger, changeable at runtime
+	level = uberzap.NewAtomicLevel()
 )
 
 // Level configures the verbosity of the logging.
@@ -59,12 +63,30 @@ const (
 
 // ToLevel converts a string to a log level.
 func ToLevel(level string) Level {
-	var l zapcore.Level
-	err := l.UnmarshalText([]byte(level))
+	l, err := ParseLevel(level)
 	if err != nil {
 		panic(err)
 	}
-	return Level(l)
+	return l
+}
+
+// ParseLevel converts a string to a log level, returning an error if unknown.
+func ParseLevel(level string) (Level, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return InfoLevel, err
+	}
+	return Level(l), nil
+}
+
+// UpdateLevel changes the minimum enabled logging level of the loggers created by NewLogger, without recreating them.
+func UpdateLevel(l Level) {
+	level.SetLevel(zapcore.Level(l))
+}
+
+// CurrentLevel returns the minimum enabled logging level of the loggers created by NewLogger.
+func CurrentLevel() Level {
+	return Level(level.Level())
 }
 
 // Mode defines the log output mode.
@@ -150,8 +172,10 @@ func NewLogger(opts ...Opts) logr.Logger {
 		o.DestWriter = os.Stderr
 	}
 
+	level.SetLevel(zapcore.Level(o.LogLevel))
+
 	return zap.New(
-		zap.Level(zapcore.Level(o.LogLevel)),
+		zap.Level(level),
 		zap.UseDevMode(o.LogMode == ModeDev),
 		zap.WriteTo(o.DestWriter),
 	)
@@ -20,6 +20,8 @@ limitations under the License.
 package log
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	// In this package there is no ginkgo tests
@@ -62,3 +64,20 @@ func TestToMode(t *testing.T) {
 		ToMode("invalid")
 	}()
 }
+
+func TestUpdateLevel(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(SetLevel(InfoLevel), WriteTo(&out))
+	defer UpdateLevel(InfoLevel)
+
+	logger.V(1).Info("before")
+	assert.Equal(t, out.Len(), 0)
+
+	UpdateLevel(DebugLevel)
+	assert.Equal(t, int(CurrentLevel()), int(DebugLevel))
+	logger.V(1).Info("after")
+	assert.Assert(t, strings.Contains(out.String(), "after"))
+
+	_, err := ParseLevel("invalid")
+	assert.Assert(t, err != nil)
+}
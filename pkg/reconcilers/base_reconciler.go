@@ -114,6 +114,7 @@ func (b *BaseReconciler) ReconcileResource(ctx context.Context, obj, desired cli
 
 		// Not found
 		if !common.IsObjectTaggedToDelete(desired) {
+			common.TagObjectManagedBy(desired)
 			return b.CreateResource(ctx, desired)
 		}
 
@@ -131,6 +132,11 @@ func (b *BaseReconciler) ReconcileResource(ctx context.Context, obj, desired cli
 		return err
 	}
 
+	// the mutators may replace the labels, or the object be created before labeled
+	if common.TagObjectManagedBy(obj) {
+		update = true
+	}
+
 	if update {
 		return b.UpdateResource(ctx, obj)
 	}
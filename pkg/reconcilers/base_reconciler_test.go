@@ -26,9 +26,16 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr"
+	authorinoopapi "github.com/kuadrant/authorino-operator/api/v1beta1"
+	authorinoapi "github.com/kuadrant/authorino/api/v1beta1"
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
+	istioclientgoextensionv1alpha1 "istio.io/client-go/pkg/apis/extensions/v1alpha1"
+	istioclientnetworkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istiosecurityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -263,3 +270,79 @@ func TestBaseReconcilerDelete(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestBaseReconcilerManagedByLabel(t *testing.T) {
+	ctx := logr.NewContext(context.Background(), log.Log)
+
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		v1.AddToScheme,
+		policyv1.AddToScheme,
+		limitadorv1alpha1.AddToScheme,
+		authorinoopapi.AddToScheme,
+		authorinoapi.AddToScheme,
+		istioclientgoextensionv1alpha1.AddToScheme,
+		istioclientnetworkingv1alpha3.AddToScheme,
+		istiosecurityv1beta1.AddToScheme,
+	} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the kinds of the resources managed by the operator
+	managedKinds := []client.Object{
+		&limitadorv1alpha1.Limitador{},
+		&authorinoopapi.Authorino{},
+		&authorinoapi.AuthConfig{},
+		&istioclientgoextensionv1alpha1.WasmPlugin{},
+		&istioclientnetworkingv1alpha3.EnvoyFilter{},
+		&istiosecurityv1beta1.AuthorizationPolicy{},
+		&v1.Service{},
+		&policyv1.PodDisruptionBudget{},
+	}
+
+	// replaces the labels, as the mutators of the resources with labels of their own do
+	labelsMutator := func(existing, desired client.Object) (bool, error) {
+		existing.SetLabels(desired.GetLabels())
+		return true, nil
+	}
+
+	for _, managedKind := range managedKinds {
+		kind := fmt.Sprintf("%T", managedKind)
+		newObject := func() client.Object {
+			obj := managedKind.DeepCopyObject().(client.Object)
+			obj.SetName("managed")
+			obj.SetNamespace("operator-unittest")
+			return obj
+		}
+		t.Run(kind, func(subT *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(s).Build()
+			baseReconciler := NewBaseReconciler(cl, s, cl, log.Log, record.NewFakeRecorder(10))
+
+			desired := newObject()
+			if err := baseReconciler.ReconcileResource(ctx, newObject(), desired, CreateOnlyMutator); err != nil {
+				subT.Fatal(err)
+			}
+			created := newObject()
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(desired), created); err != nil {
+				subT.Fatal(err)
+			}
+			if value := created.GetLabels()[common.AppManagedByLabel]; value != common.KuadrantOperatorName {
+				subT.Errorf("created %s without the managed-by label: %v", kind, created.GetLabels())
+			}
+
+			// the label is preserved by the reconciliation
+			if err := baseReconciler.ReconcileResource(ctx, newObject(), newObject(), labelsMutator); err != nil {
+				subT.Fatal(err)
+			}
+			updated := newObject()
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(desired), updated); err != nil {
+				subT.Fatal(err)
+			}
+			if value := updated.GetLabels()[common.AppManagedByLabel]; value != common.KuadrantOperatorName {
+				subT.Errorf("updated %s without the managed-by label: %v", kind, updated.GetLabels())
+			}
+		})
+	}
+}
@@ -18,8 +18,10 @@ package reconcilers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -32,6 +34,21 @@ import (
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
 )
 
+// InvalidTargetError is the error of a target network object found, but not valid to enforce policies yet, i.e. a
+// gateway not ready or an HTTPRoute not accepted
+type InvalidTargetError struct {
+	Reason string
+}
+
+func (e *InvalidTargetError) Error() string {
+	return e.Reason
+}
+
+func IsInvalidTarget(err error) bool {
+	targetErr := &InvalidTargetError{}
+	return errors.As(err, &targetErr)
+}
+
 type TargetRefReconciler struct {
 	*BaseReconciler
 }
@@ -54,7 +71,7 @@ func (r *TargetRefReconciler) FetchValidGateway(ctx context.Context, key client.
 	}
 
 	if meta.IsStatusConditionFalse(gw.Status.Conditions, common.GatewayProgrammedConditionType) {
-		return nil, fmt.Errorf("FetchValidGateway: gateway (%v) not ready", key)
+		return nil, &InvalidTargetError{Reason: fmt.Sprintf("FetchValidGateway: gateway (%v) not ready", key)}
 	}
 
 	return gw, nil
@@ -71,7 +88,7 @@ func (r *TargetRefReconciler) FetchValidHTTPRoute(ctx context.Context, key clien
 	}
 
 	if !common.IsHTTPRouteAccepted(httpRoute) {
-		return nil, fmt.Errorf("FetchValidHTTPRoute: httproute (%v) not accepted", key)
+		return nil, &InvalidTargetError{Reason: fmt.Sprintf("FetchValidHTTPRoute: httproute (%v) not accepted", key)}
 	}
 
 	return httpRoute, nil
@@ -96,17 +113,30 @@ func (r *TargetRefReconciler) FetchValidTargetRef(ctx context.Context, targetRef
 }
 
 // FetchAcceptedGatewayHTTPRoutes returns the list of HTTPRoutes that have been accepted as children of a gateway.
-func (r *TargetRefReconciler) FetchAcceptedGatewayHTTPRoutes(ctx context.Context, gwKey client.ObjectKey) (routes []gatewayapiv1beta1.HTTPRoute) {
+// Failing to list the HTTPRoutes is logged and an empty list returned. See ListAcceptedGatewayHTTPRoutes to handle the error.
+func (r *TargetRefReconciler) FetchAcceptedGatewayHTTPRoutes(ctx context.Context, gwKey client.ObjectKey) []gatewayapiv1beta1.HTTPRoute {
 	logger, _ := logr.FromContext(ctx)
-	logger = logger.WithName("FetchAcceptedGatewayHTTPRoutes").WithValues("gateway", gwKey)
 
-	routeList := &gatewayapiv1beta1.HTTPRouteList{}
-	err := r.Client().List(ctx, routeList)
+	routes, err := r.ListAcceptedGatewayHTTPRoutes(ctx, gwKey)
 	if err != nil {
-		logger.V(1).Info("failed to list httproutes", "err", err)
-		return
+		logger.WithName("FetchAcceptedGatewayHTTPRoutes").V(1).Info("failed to list httproutes", "gateway", gwKey, "err", err)
+		return nil
 	}
+	return routes
+}
+
+// ListAcceptedGatewayHTTPRoutes returns the list of HTTPRoutes that have been accepted as children of a gateway,
+// or the error listing the HTTPRoutes
+func (r *TargetRefReconciler) ListAcceptedGatewayHTTPRoutes(ctx context.Context, gwKey client.ObjectKey) ([]gatewayapiv1beta1.HTTPRoute, error) {
+	logger, _ := logr.FromContext(ctx)
+	logger = logger.WithName("ListAcceptedGatewayHTTPRoutes").WithValues("gateway", gwKey)
 
+	routeList := &gatewayapiv1beta1.HTTPRouteList{}
+	if err := r.Client().List(ctx, routeList); err != nil {
+		return nil, err
+	}
+
+	var routes []gatewayapiv1beta1.HTTPRoute
 	for idx := range routeList.Items {
 		route := routeList.Items[idx]
 		routeParentStatus, found := common.Find(route.Status.RouteStatus.Parents, func(p gatewayapiv1beta1.RouteParentStatus) bool {
@@ -122,7 +152,7 @@ func (r *TargetRefReconciler) FetchAcceptedGatewayHTTPRoutes(ctx context.Context
 		logger.V(1).Info("skipping route, not attached to gateway", "httproute", client.ObjectKeyFromObject(&route))
 	}
 
-	return
+	return routes, nil
 }
 
 // TargetedGatewayKeys returns the list of gateways that are being referenced from the target.
@@ -176,6 +206,23 @@ func (r *TargetRefReconciler) ReconcileTargetBackReference(ctx context.Context,
 	return nil
 }
 
+// ReplaceTargetBackReference hands the back reference of a target network object over from a policy to the policy
+// replacing it. The back reference to any other policy is left untouched.
+func (r *TargetRefReconciler) ReplaceTargetBackReference(ctx context.Context, replacedKey, policyKey client.ObjectKey, targetNetworkObject client.Object, annotationName string) error {
+	logger, _ := logr.FromContext(ctx)
+
+	objAnnotations := common.ReadAnnotationsFromObject(targetNetworkObject)
+	if objAnnotations[annotationName] != replacedKey.String() {
+		return nil
+	}
+
+	objAnnotations[annotationName] = policyKey.String()
+	targetNetworkObject.SetAnnotations(objAnnotations)
+	err := r.UpdateResource(ctx, targetNetworkObject)
+	logger.V(1).Info("ReplaceTargetBackReference: update target object", "kind", targetNetworkObject.GetObjectKind().GroupVersionKind(), "name", client.ObjectKeyFromObject(targetNetworkObject), "err", err)
+	return err
+}
+
 func (r *TargetRefReconciler) DeleteTargetBackReference(ctx context.Context, policyKey client.ObjectKey, targetNetworkObject client.Object, annotationName string) error {
 	logger, _ := logr.FromContext(ctx)
 
@@ -198,6 +245,60 @@ func (r *TargetRefReconciler) DeleteTargetBackReference(ctx context.Context, pol
 	return nil
 }
 
+// ReconcileEffectivePoliciesAnnotation annotates the HTTPRoutes affected by changes to the policies targeting a network
+// object with the list of policies affecting each route, i.e. the policies targeting the route or its parent gateways
+func (r *TargetRefReconciler) ReconcileEffectivePoliciesAnnotation(ctx context.Context, targetNetworkObject client.Object) error {
+	logger, _ := logr.FromContext(ctx)
+
+	var routes []gatewayapiv1beta1.HTTPRoute
+	switch obj := targetNetworkObject.(type) {
+	case *gatewayapiv1beta1.HTTPRoute:
+		route := gatewayapiv1beta1.HTTPRoute{}
+		if err := r.Client().Get(ctx, client.ObjectKeyFromObject(obj), &route); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		routes = append(routes, route)
+	case *gatewayapiv1beta1.Gateway:
+		routes = r.FetchAcceptedGatewayHTTPRoutes(ctx, client.ObjectKeyFromObject(obj))
+	}
+
+	for idx := range routes {
+		route := &routes[idx]
+
+		affectingObjects := []client.Object{route}
+		for _, gwKey := range r.TargetedGatewayKeys(ctx, route) {
+			gw := &gatewayapiv1beta1.Gateway{}
+			if err := r.Client().Get(ctx, gwKey, gw); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return err
+				}
+				continue
+			}
+			affectingObjects = append(affectingObjects, gw)
+		}
+
+		annotations := common.ReadAnnotationsFromObject(route)
+		effectivePolicies := strings.Join(common.EffectivePolicies(affectingObjects...), ",")
+		if annotations[common.EffectivePoliciesAnnotation] == effectivePolicies {
+			continue
+		}
+		if effectivePolicies == "" {
+			delete(annotations, common.EffectivePoliciesAnnotation)
+		} else {
+			annotations[common.EffectivePoliciesAnnotation] = effectivePolicies
+		}
+		route.SetAnnotations(annotations)
+
+		err := r.UpdateResource(ctx, route)
+		logger.V(1).Info("ReconcileEffectivePoliciesAnnotation: update httproute", "httproute", client.ObjectKeyFromObject(route), "effective policies", effectivePolicies, "err", err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetAllGatewayPolicyRefs returns the policy refs of a given policy kind from all gateways managed by kuadrant.
 // The gateway objects are handled in order of creation to mitigate the risk of non-idenpotent reconciliations based on
 // this list of policy refs; nevertheless, the actual order of returned policy refs depends on the order the policy refs
@@ -464,6 +464,65 @@ func TestTargetedGatewayKeys(t *testing.T) {
 	}
 }
 
+func TestListAcceptedGatewayHTTPRoutes(t *testing.T) {
+	var (
+		namespace = "operator-unittest"
+		gwKey     = client.ObjectKey{Name: "my-gateway", Namespace: namespace}
+	)
+	ctx := logr.NewContext(context.Background(), log.Log)
+
+	s := runtime.NewScheme()
+	if err := gatewayapiv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	route := func(name, gwName string, accepted metav1.ConditionStatus) *gatewayapiv1beta1.HTTPRoute {
+		kind := gatewayapiv1beta1.Kind("Gateway")
+		return &gatewayapiv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status: gatewayapiv1beta1.HTTPRouteStatus{
+				RouteStatus: gatewayapiv1beta1.RouteStatus{
+					Parents: []gatewayapiv1beta1.RouteParentStatus{
+						{
+							ParentRef:  gatewayapiv1beta1.ParentReference{Kind: &kind, Name: gatewayapiv1beta1.ObjectName(gwName)},
+							Conditions: []metav1.Condition{{Type: "Accepted", Status: accepted}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		route("accepted", gwKey.Name, metav1.ConditionTrue),
+		route("not-accepted", gwKey.Name, metav1.ConditionFalse),
+		route("other-gateway", "other-gateway", metav1.ConditionTrue),
+	).Build()
+	targetRefReconciler := TargetRefReconciler{
+		BaseReconciler: NewBaseReconciler(cl, s, cl, log.Log, record.NewFakeRecorder(1000)),
+	}
+
+	routes, err := targetRefReconciler.ListAcceptedGatewayHTTPRoutes(ctx, gwKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].Name != "accepted" {
+		t.Fatalf("expected the accepted route only, got %v", routes)
+	}
+
+	// the httproutes cannot be listed
+	failingCl := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	failingReconciler := TargetRefReconciler{
+		BaseReconciler: NewBaseReconciler(failingCl, s, failingCl, log.Log, record.NewFakeRecorder(1000)),
+	}
+	if _, err := failingReconciler.ListAcceptedGatewayHTTPRoutes(ctx, gwKey); err == nil {
+		t.Fatal("expected error listing the httproutes")
+	}
+	if routes := failingReconciler.FetchAcceptedGatewayHTTPRoutes(ctx, gwKey); routes != nil {
+		t.Fatalf("expected no routes, got %v", routes)
+	}
+}
+
 func TestDeleteTargetBackReference(t *testing.T) {
 	var (
 		namespace             = "operator-unittest"
@@ -560,3 +619,71 @@ func TestDeleteTargetBackReference(t *testing.T) {
 		}
 	}
 }
+
+func TestReplaceTargetBackReference(t *testing.T) {
+	var (
+		namespace             = "operator-unittest"
+		annotationName string = "some-annotation"
+	)
+	baseCtx := context.Background()
+	ctx := logr.NewContext(baseCtx, log.Log)
+
+	s := scheme.Scheme
+	err := gatewayapiv1beta1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replacedKey := client.ObjectKey{Name: "old", Namespace: namespace}
+	policyKey := client.ObjectKey{Name: "new", Namespace: namespace}
+	otherKey := client.ObjectKey{Name: "other", Namespace: namespace}
+
+	route := func(name string, backRef client.ObjectKey) *gatewayapiv1beta1.HTTPRoute {
+		return &gatewayapiv1beta1.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "gateway.networking.k8s.io/v1beta1",
+				Kind:       "HTTPRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{annotationName: backRef.String()},
+			},
+		}
+	}
+	replacedRoute := route("replaced-route", replacedKey)
+	otherRoute := route("other-route", otherKey)
+
+	cl := fake.NewFakeClient(replacedRoute, otherRoute)
+	clientAPIReader := fake.NewFakeClient(replacedRoute, otherRoute)
+	recorder := record.NewFakeRecorder(1000)
+
+	baseReconciler := NewBaseReconciler(cl, s, clientAPIReader, log.Log, recorder)
+	targetRefReconciler := TargetRefReconciler{
+		BaseReconciler: baseReconciler,
+	}
+
+	testCases := []struct {
+		name     string
+		route    *gatewayapiv1beta1.HTTPRoute
+		expected client.ObjectKey
+	}{
+		{name: "when referenced by the replaced policy then handed over", route: replacedRoute, expected: policyKey},
+		{name: "when referenced by another policy then untouched", route: otherRoute, expected: otherKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if err := targetRefReconciler.ReplaceTargetBackReference(ctx, replacedKey, policyKey, tc.route, annotationName); err != nil {
+				subT.Fatal(err)
+			}
+			res := &gatewayapiv1beta1.HTTPRoute{}
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(tc.route), res); err != nil {
+				subT.Fatal(err)
+			}
+			if val := res.GetAnnotations()[annotationName]; val != tc.expected.String() {
+				subT.Fatalf("annotation value (%s) does not match expected (%s)", val, tc.expected.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package rlptools
+
+import (
+	"fmt"
+	"strings"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
+)
+
+// DefaultRateLimitName is the name of the default rate limit of a kuadrant instance, in the wasm config and in the
+// Limitador namespace of its limits
+const DefaultRateLimitName = "kuadrant-default"
+
+// DefaultRateLimitPolicy returns the default rate limit of a kuadrant instance as a RateLimitPolicy with a single
+// limit, so it renders as any other policy
+func DefaultRateLimitPolicy(spec *kuadrantv1beta1.DefaultRateLimitSpec) *kuadrantv1beta2.RateLimitPolicy {
+	limit := kuadrantv1beta2.Limit{
+		Rates: make([]kuadrantv1beta2.Rate, 0, len(spec.Rates)),
+	}
+	for _, rate := range spec.Rates {
+		limit.Rates = append(limit.Rates, kuadrantv1beta2.Rate{
+			Limit:    rate.Limit,
+			Duration: rate.Duration,
+			Unit:     kuadrantv1beta2.TimeUnit(rate.Unit),
+		})
+	}
+	for _, counter := range spec.Counters {
+		limit.Counters = append(limit.Counters, kuadrantv1beta2.ContextSelector(counter))
+	}
+
+	rlp := &kuadrantv1beta2.RateLimitPolicy{}
+	rlp.Name = DefaultRateLimitName
+	rlp.Spec.Limits = map[string]kuadrantv1beta2.Limit{DefaultRateLimitName: limit}
+	return rlp
+}
+
+// DefaultLimitsNamespace returns the Limitador namespace of the default limits enforced by a gateway
+func DefaultLimitsNamespace(gwKey client.ObjectKey) string {
+	return fmt.Sprintf("%s#%s", gwKey, DefaultRateLimitName)
+}
+
+// IsDefaultRateLimit tells whether a Limitador limit was generated from the default rate limit of a kuadrant instance
+func IsDefaultRateLimit(rateLimit limitadorv1alpha1.RateLimit) bool {
+	return strings.HasSuffix(rateLimit.Namespace, "#"+DefaultRateLimitName)
+}
+
+// LimitadorDefaultRateLimits converts the default rate limit of a kuadrant instance into a list of Limitador rate
+// limit objects, one set for each of the given gateways
+func LimitadorDefaultRateLimits(spec *kuadrantv1beta1.DefaultRateLimitSpec, gwKeys []client.ObjectKey) []limitadorv1alpha1.RateLimit {
+	rateLimits := make([]limitadorv1alpha1.RateLimit, 0)
+	if spec == nil {
+		return rateLimits
+	}
+	rlp := DefaultRateLimitPolicy(spec)
+	for _, gwKey := range gwKeys {
+		gwRateLimits := LimitadorRateLimitsFromRLP(rlp, []client.ObjectKey{gwKey})
+		for idx := range gwRateLimits {
+			gwRateLimits[idx].Namespace = DefaultLimitsNamespace(gwKey)
+		}
+		rateLimits = append(rateLimits, gwRateLimits...)
+	}
+	return rateLimits
+}
@@ -0,0 +1,69 @@
+//go:build unit
+
+package rlptools
+
+import (
+	"testing"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
+)
+
+func TestLimitadorDefaultRateLimits(t *testing.T) {
+	spec := &kuadrantv1beta1.DefaultRateLimitSpec{
+		Rates:    []kuadrantv1beta1.DefaultRateLimitRate{{Limit: 1000, Duration: 1, Unit: "minute"}},
+		Counters: []string{"source.address"},
+	}
+	gwKeys := []client.ObjectKey{{Name: "gw-a", Namespace: "ns"}, {Name: "gw-b", Namespace: "ns"}}
+
+	rateLimits := LimitadorDefaultRateLimits(spec, gwKeys)
+	if len(rateLimits) != 2 {
+		t.Fatalf("expected 2 limits, got %d", len(rateLimits))
+	}
+
+	for idx, gwKey := range gwKeys {
+		rateLimit := rateLimits[idx]
+		if rateLimit.Namespace != "ns/"+gwKey.Name+"#kuadrant-default" {
+			t.Errorf("unexpected namespace %s", rateLimit.Namespace)
+		}
+		if rateLimit.MaxValue != 1000 || rateLimit.Seconds != 60 {
+			t.Errorf("unexpected rate %d per %d seconds", rateLimit.MaxValue, rateLimit.Seconds)
+		}
+		if len(rateLimit.Variables) != 1 || rateLimit.Variables[0] != "source.address" {
+			t.Errorf("unexpected variables %v", rateLimit.Variables)
+		}
+		if !IsDefaultRateLimit(rateLimit) {
+			t.Errorf("limit %v not reported as default", rateLimit)
+		}
+		if IsRateLimitPolicyLimit(rateLimit) {
+			t.Errorf("default limit %v reported as limit of a policy", rateLimit)
+		}
+	}
+
+	if rateLimits := LimitadorDefaultRateLimits(nil, gwKeys); len(rateLimits) != 0 {
+		t.Errorf("expected no limits without default rate limit, got %v", rateLimits)
+	}
+}
+
+func TestIsDefaultRateLimit(t *testing.T) {
+	gwKey := client.ObjectKey{Name: "gw", Namespace: "gw-ns"}
+	testCases := []struct {
+		name      string
+		namespace string
+		expected  bool
+	}{
+		{"default limit", DefaultLimitsNamespace(gwKey), true},
+		{"limit of a policy", LimitsNamespace(gwKey, client.ObjectKey{Name: "rlp", Namespace: "ns"}), false},
+		{"limit set manually", "toystore", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if got := IsDefaultRateLimit(limitadorv1alpha1.RateLimit{Namespace: tc.namespace}); got != tc.expected {
+				subT.Errorf("IsDefaultRateLimit(%s) = %t, want %t", tc.namespace, got, tc.expected)
+			}
+		})
+	}
+}
@@ -39,6 +39,18 @@ func (l *RateLimitIndex) ToRateLimits() RateLimitList {
 	return limitadorRateLimits
 }
 
+// MergeInto returns the given rate limits with the ones managed by the index replaced by the rate limits of the index.
+// The rate limits of other sources, i.e. not matching the managed func, are kept in place.
+func (l *RateLimitIndex) MergeInto(rateLimits RateLimitList, managed func(limitadorv1alpha1.RateLimit) bool) RateLimitList {
+	merged := make(RateLimitList, 0, len(rateLimits))
+	for _, rateLimit := range rateLimits {
+		if !managed(rateLimit) {
+			merged = append(merged, rateLimit)
+		}
+	}
+	return append(merged, l.ToRateLimits()...)
+}
+
 type RateLimitList []limitadorv1alpha1.RateLimit
 
 func (l RateLimitList) Len() int {
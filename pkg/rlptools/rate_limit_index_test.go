@@ -3,6 +3,7 @@
 package rlptools
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -138,3 +139,90 @@ func TestRateLimitIndexToRateLimits(t *testing.T) {
 		}
 	})
 }
+
+func TestRateLimitIndexMergeInto(t *testing.T) {
+	defer func(perGateway bool) { LimitsPerGateway = perGateway }(LimitsPerGateway)
+	LimitsPerGateway = true
+
+	rlp1Namespace := LimitsNamespace(client.ObjectKey{Name: "gw", Namespace: "gw-ns"}, client.ObjectKey{Name: "rlp-1", Namespace: "ns"})
+	rlp2Namespace := LimitsNamespace(client.ObjectKey{Name: "gw", Namespace: "gw-ns"}, client.ObjectKey{Name: "rlp-2", Namespace: "ns"})
+	foreignLimit := limitadorv1alpha1.RateLimit{Namespace: "manual", MaxValue: 5, Seconds: 1}
+
+	existing := RateLimitList{
+		{Namespace: rlp1Namespace, MaxValue: 10, Seconds: 1},
+		foreignLimit,
+		{Namespace: rlp2Namespace, MaxValue: 50, Seconds: 1},
+	}
+
+	t.Run("update of a single policy", func(subT *testing.T) {
+		index := NewRateLimitIndex()
+		index.Set(client.ObjectKey{Name: "rlp-1", Namespace: "ns"}, RateLimitList{
+			{Namespace: rlp1Namespace, MaxValue: 20, Seconds: 1},
+		})
+		index.Set(client.ObjectKey{Name: "rlp-2", Namespace: "ns"}, RateLimitList{
+			{Namespace: rlp2Namespace, MaxValue: 50, Seconds: 1},
+		})
+
+		merged := index.MergeInto(existing, IsRateLimitPolicyLimit)
+		expected := RateLimitList{
+			foreignLimit,
+			{Namespace: rlp1Namespace, MaxValue: 20, Seconds: 1},
+			{Namespace: rlp2Namespace, MaxValue: 50, Seconds: 1},
+		}
+		if !reflect.DeepEqual(merged, expected) {
+			subT.Fatal("expected:", expected, "merged:", merged)
+		}
+	})
+
+	t.Run("removal of a policy", func(subT *testing.T) {
+		index := NewRateLimitIndex()
+		index.Set(client.ObjectKey{Name: "rlp-2", Namespace: "ns"}, RateLimitList{
+			{Namespace: rlp2Namespace, MaxValue: 50, Seconds: 1},
+		})
+
+		merged := index.MergeInto(existing, IsRateLimitPolicyLimit)
+		expected := RateLimitList{
+			foreignLimit,
+			{Namespace: rlp2Namespace, MaxValue: 50, Seconds: 1},
+		}
+		if !reflect.DeepEqual(merged, expected) {
+			subT.Fatal("expected:", expected, "merged:", merged)
+		}
+	})
+
+	t.Run("upgrade with the limits of the previous versions", func(subT *testing.T) {
+		legacyLimit := limitadorv1alpha1.RateLimit{
+			Namespace:  "ns/rlp-1",
+			MaxValue:   10,
+			Seconds:    1,
+			Conditions: []string{fmt.Sprintf("%s == \"1\"", LimitNameToLimitadorIdentifier("toys"))},
+		}
+		manualLimit := limitadorv1alpha1.RateLimit{Namespace: "ns/manual", MaxValue: 5, Seconds: 1, Conditions: []string{`req.method == "GET"`}}
+
+		index := NewRateLimitIndex()
+		index.Set(client.ObjectKey{Name: "rlp-1", Namespace: "ns"}, RateLimitList{
+			{Namespace: rlp1Namespace, MaxValue: 10, Seconds: 1},
+		})
+
+		merged := index.MergeInto(RateLimitList{legacyLimit, manualLimit}, IsRateLimitPolicyLimit)
+		expected := RateLimitList{
+			manualLimit,
+			{Namespace: rlp1Namespace, MaxValue: 10, Seconds: 1},
+		}
+		if !reflect.DeepEqual(merged, expected) {
+			subT.Fatal("expected:", expected, "merged:", merged)
+		}
+	})
+
+	t.Run("no existing limits", func(subT *testing.T) {
+		index := NewRateLimitIndex()
+		index.Set(client.ObjectKey{Name: "rlp-1", Namespace: "ns"}, RateLimitList{
+			{Namespace: rlp1Namespace, MaxValue: 20, Seconds: 1},
+		})
+
+		merged := index.MergeInto(nil, IsRateLimitPolicyLimit)
+		if !reflect.DeepEqual(merged, index.ToRateLimits()) {
+			subT.Fatal("expected:", index.ToRateLimits(), "merged:", merged)
+		}
+	})
+}
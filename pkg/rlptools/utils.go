@@ -4,9 +4,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 	"unicode"
 
 	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
@@ -14,8 +17,38 @@ import (
 
 const (
 	LimitadorRateLimitIdentitiferPrefix = "limit."
+
+	defaultLimitsSoftCap = 1000
 )
 
+// LimitsSoftCap is the number of limits configured in a Limitador instance above which a warning is reported.
+// A value of zero or less disables the warning.
+var LimitsSoftCap = limitsSoftCapFromEnv()
+
+func limitsSoftCapFromEnv() int {
+	softCap, err := strconv.Atoi(common.FetchEnv("LIMITADOR_LIMITS_SOFT_CAP", strconv.Itoa(defaultLimitsSoftCap)))
+	if err != nil {
+		return defaultLimitsSoftCap
+	}
+	return softCap
+}
+
+// ExceedsLimitsSoftCap tells whether a list of Limitador limits is above the soft cap
+func ExceedsLimitsSoftCap(limits []limitadorv1alpha1.RateLimit) bool {
+	return LimitsSoftCap > 0 && len(limits) > LimitsSoftCap
+}
+
+// LimitsPerGateway enables the isolation of the counters of the policies per gateway, read from the
+// LIMITADOR_COUNTERS_PER_GATEWAY env var. Disabled by default, the limits of a policy are in the namespace
+// `<policy-namespace>/<policy-name>` of the previous versions, shared by all the gateways enforcing the policy, so the
+// counters are kept on upgrade.
+var LimitsPerGateway = limitsPerGatewayFromEnv()
+
+func limitsPerGatewayFromEnv() bool {
+	perGateway, err := strconv.ParseBool(common.FetchEnv("LIMITADOR_COUNTERS_PER_GATEWAY", "false"))
+	return err == nil && perGateway
+}
+
 func LimitNameToLimitadorIdentifier(uniqueLimitName string) string {
 	identifier := LimitadorRateLimitIdentitiferPrefix
 
@@ -36,29 +69,101 @@ func LimitNameToLimitadorIdentifier(uniqueLimitName string) string {
 }
 
 // LimitadorRateLimitsFromRLP converts rate limits from a Kuadrant RateLimitPolicy into a list of Limitador rate limit
-// objects
-func LimitadorRateLimitsFromRLP(rlp *kuadrantv1beta2.RateLimitPolicy) []limitadorv1alpha1.RateLimit {
-	limitsNamespace := LimitsNamespaceFromRLP(rlp)
-
+// objects, one set for each of the gateways enforcing the policy
+func LimitadorRateLimitsFromRLP(rlp *kuadrantv1beta2.RateLimitPolicy, gwKeys []client.ObjectKey) []limitadorv1alpha1.RateLimit {
 	rateLimits := make([]limitadorv1alpha1.RateLimit, 0)
-	for limitKey, limit := range rlp.Spec.Limits {
-		limitIdentifier := LimitNameToLimitadorIdentifier(limitKey)
-		for _, rate := range limit.Rates {
-			maxValue, seconds := rateToSeconds(rate)
-			rateLimits = append(rateLimits, limitadorv1alpha1.RateLimit{
-				Namespace:  limitsNamespace,
-				MaxValue:   maxValue,
-				Seconds:    seconds,
-				Conditions: []string{fmt.Sprintf("%s == \"1\"", limitIdentifier)},
-				Variables:  common.GetEmptySliceIfNil(limit.CountersAsStringList()),
-			})
+	for _, gwKey := range gwKeys {
+		limitsNamespace := LimitsNamespace(gwKey, client.ObjectKeyFromObject(rlp))
+		for limitKey, limit := range rlp.Spec.Limits {
+			limitIdentifier := LimitNameToLimitadorIdentifier(limitKey)
+			for _, rate := range limit.Rates {
+				maxValue, seconds := rateToSeconds(rate)
+				rateLimits = append(rateLimits, limitadorv1alpha1.RateLimit{
+					Namespace:  limitsNamespace,
+					MaxValue:   maxValue,
+					Seconds:    seconds,
+					Conditions: []string{fmt.Sprintf("%s == \"1\"", limitIdentifier)},
+					Variables:  common.GetEmptySliceIfNil(limit.CountersAsStringList()),
+				})
+			}
 		}
 	}
 	return rateLimits
 }
 
-func LimitsNamespaceFromRLP(rlp *kuadrantv1beta2.RateLimitPolicy) string {
-	return fmt.Sprintf("%s/%s", rlp.GetNamespace(), rlp.GetName())
+// LimitsNamespace returns the Limitador namespace of the limits of a policy enforced by a gateway.
+// With LimitsPerGateway, the counters are isolated per gateway, so the tenants of different gateways cannot interfere
+// with each other. Otherwise the counters are shared by all the gateways enforcing the policy.
+func LimitsNamespace(gwKey, rlpKey client.ObjectKey) string {
+	if !LimitsPerGateway {
+		return rlpKey.String()
+	}
+	return fmt.Sprintf("%s#%s", gwKey, rlpKey)
+}
+
+// SharedLimitsNamespace returns the Limitador namespace of the limits of a policy under a counter domain, shared by
+// all the gateways enforcing the policy, and by the clusters setting the same domain
+func SharedLimitsNamespace(counterDomain string, rlpKey client.ObjectKey) string {
+	return fmt.Sprintf("%s#%s", counterDomain, rlpKey)
+}
+
+// PolicyLimitsNamespace returns the Limitador namespace of the limits of a policy enforced by a gateway, shared
+// under the counter domain if any. Otherwise, the namespace is isolated per gateway only with LimitsPerGateway, and
+// shared by all the gateways enforcing the policy by default.
+func PolicyLimitsNamespace(counterDomain string, gwKey, rlpKey client.ObjectKey) string {
+	if counterDomain == "" {
+		return LimitsNamespace(gwKey, rlpKey)
+	}
+	return SharedLimitsNamespace(counterDomain, rlpKey)
+}
+
+// IsRateLimitPolicyLimit tells whether a Limitador limit was generated from a RateLimitPolicy,
+// i.e. its namespace is the one of the limits of a policy enforced by a gateway or shared under a counter domain,
+// or it is a limit generated by the versions before the limits were isolated per gateway
+func IsRateLimitPolicyLimit(rateLimit limitadorv1alpha1.RateLimit) bool {
+	scope, rlpKey, found := strings.Cut(rateLimit.Namespace, "#")
+	if !found {
+		return isLegacyRateLimitPolicyLimit(rateLimit)
+	}
+	return strings.Count(scope, string(common.NamespaceSeparator)) <= 1 && strings.Count(rlpKey, string(common.NamespaceSeparator)) == 1
+}
+
+// isLegacyRateLimitPolicyLimit tells whether a Limitador limit was generated from a RateLimitPolicy by the versions
+// before the limits were isolated per gateway, i.e. its namespace is `<policy-namespace>/<policy-name>` and its
+// conditions are the ones generated from the limits of a policy. The limits set manually in the namespace of a single
+// object are told apart by their conditions.
+func isLegacyRateLimitPolicyLimit(rateLimit limitadorv1alpha1.RateLimit) bool {
+	namespace, name, found := strings.Cut(rateLimit.Namespace, string(common.NamespaceSeparator))
+	if !found || namespace == "" || name == "" || strings.ContainsRune(name, common.NamespaceSeparator) || len(rateLimit.Conditions) == 0 {
+		return false
+	}
+	for _, condition := range rateLimit.Conditions {
+		if !strings.HasPrefix(condition, LimitadorRateLimitIdentitiferPrefix) || !strings.HasSuffix(condition, ` == "1"`) {
+			return false
+		}
+	}
+	return true
+}
+
+// RateLimitPolicyLimits returns the limits generated from a policy among the given Limitador limits
+func RateLimitPolicyLimits(rateLimits []limitadorv1alpha1.RateLimit, rlpKey client.ObjectKey) RateLimitList {
+	return common.Filter(rateLimits, func(rateLimit limitadorv1alpha1.RateLimit) bool {
+		key, ok := rateLimitPolicyKey(rateLimit)
+		return ok && key == rlpKey
+	})
+}
+
+// rateLimitPolicyKey returns the key of the policy a Limitador limit was generated from
+func rateLimitPolicyKey(rateLimit limitadorv1alpha1.RateLimit) (client.ObjectKey, bool) {
+	if !IsRateLimitPolicyLimit(rateLimit) {
+		return client.ObjectKey{}, false
+	}
+	rlpKey := rateLimit.Namespace
+	if _, key, found := strings.Cut(rlpKey, "#"); found {
+		rlpKey = key
+	}
+	namespace, name, _ := strings.Cut(rlpKey, string(common.NamespaceSeparator))
+	return client.ObjectKey{Namespace: namespace, Name: name}, true
 }
 
 var timeUnitMap = map[kuadrantv1beta2.TimeUnit]int{
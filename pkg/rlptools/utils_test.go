@@ -3,12 +3,14 @@
 package rlptools
 
 import (
+	"fmt"
 	"reflect"
 	"regexp"
 	"testing"
 
 	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kuadrantv1beta2 "github.com/kuadrant/kuadrant-operator/api/v1beta2"
 	"github.com/kuadrant/kuadrant-operator/pkg/common"
@@ -178,6 +180,9 @@ func TestLimitNameToLimitadorIdentifier(t *testing.T) {
 }
 
 func TestLimitadorRateLimitsFromRLP(t *testing.T) {
+	defer func(perGateway bool) { LimitsPerGateway = perGateway }(LimitsPerGateway)
+	LimitsPerGateway = true
+
 	testCases := []struct {
 		name     string
 		rlp      *kuadrantv1beta2.RateLimitPolicy
@@ -188,7 +193,7 @@ func TestLimitadorRateLimitsFromRLP(t *testing.T) {
 			rlp:  testRLP_1Limit_1Rate("testNS", "rlpA"),
 			expected: []limitadorv1alpha1.RateLimit{
 				{
-					Namespace:  "testNS/rlpA",
+					Namespace:  "testNS/gwA#testNS/rlpA",
 					MaxValue:   5,
 					Seconds:    10,
 					Conditions: []string{`limit.l1__2804bad6 == "1"`},
@@ -201,14 +206,14 @@ func TestLimitadorRateLimitsFromRLP(t *testing.T) {
 			rlp:  testRLP_2Limits_1Rate("testNS", "rlpA"),
 			expected: []limitadorv1alpha1.RateLimit{
 				{
-					Namespace:  "testNS/rlpA",
+					Namespace:  "testNS/gwA#testNS/rlpA",
 					MaxValue:   5,
 					Seconds:    10,
 					Conditions: []string{`limit.l1__2804bad6 == "1"`},
 					Variables:  []string{},
 				},
 				{
-					Namespace:  "testNS/rlpA",
+					Namespace:  "testNS/gwA#testNS/rlpA",
 					MaxValue:   3,
 					Seconds:    3600,
 					Conditions: []string{`limit.l2__8a1cee43 == "1"`},
@@ -221,14 +226,14 @@ func TestLimitadorRateLimitsFromRLP(t *testing.T) {
 			rlp:  testRLP_1Limit_2Rates("testNS", "rlpA"),
 			expected: []limitadorv1alpha1.RateLimit{
 				{
-					Namespace:  "testNS/rlpA",
+					Namespace:  "testNS/gwA#testNS/rlpA",
 					MaxValue:   5,
 					Seconds:    10,
 					Conditions: []string{`limit.l1__2804bad6 == "1"`},
 					Variables:  []string{},
 				},
 				{
-					Namespace:  "testNS/rlpA",
+					Namespace:  "testNS/gwA#testNS/rlpA",
 					MaxValue:   3,
 					Seconds:    60,
 					Conditions: []string{`limit.l1__2804bad6 == "1"`},
@@ -241,7 +246,7 @@ func TestLimitadorRateLimitsFromRLP(t *testing.T) {
 			rlp:  testRLP_1Limit_1Rate_1Counter("testNS", "rlpA"),
 			expected: []limitadorv1alpha1.RateLimit{
 				{
-					Namespace:  "testNS/rlpA",
+					Namespace:  "testNS/gwA#testNS/rlpA",
 					MaxValue:   5,
 					Seconds:    10,
 					Conditions: []string{`limit.l1__2804bad6 == "1"`},
@@ -253,7 +258,7 @@ func TestLimitadorRateLimitsFromRLP(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(subT *testing.T) {
-			rateLimits := LimitadorRateLimitsFromRLP(tc.rlp)
+			rateLimits := LimitadorRateLimitsFromRLP(tc.rlp, []client.ObjectKey{{Namespace: "testNS", Name: "gwA"}})
 			// Instead of sorting to compare, check len and then iterate
 			if len(rateLimits) != len(tc.expected) {
 				subT.Errorf("expected limits len (%d), got (%d)", len(tc.expected), len(rateLimits))
@@ -363,3 +368,106 @@ func TestConvertRateIntoSeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestExceedsLimitsSoftCap(t *testing.T) {
+	defer func(softCap int) { LimitsSoftCap = softCap }(LimitsSoftCap)
+
+	limits := make([]limitadorv1alpha1.RateLimit, 3)
+
+	LimitsSoftCap = 3
+	if ExceedsLimitsSoftCap(limits) {
+		t.Error("limits at the soft cap should not exceed it")
+	}
+
+	LimitsSoftCap = 2
+	if !ExceedsLimitsSoftCap(limits) {
+		t.Error("limits above the soft cap should exceed it")
+	}
+
+	LimitsSoftCap = 0
+	if ExceedsLimitsSoftCap(limits) {
+		t.Error("a soft cap of zero should disable the check")
+	}
+}
+
+func TestIsRateLimitPolicyLimit(t *testing.T) {
+	defer func(perGateway bool) { LimitsPerGateway = perGateway }(LimitsPerGateway)
+	LimitsPerGateway = true
+
+	generatedConditions := []string{fmt.Sprintf("%s == \"1\"", LimitNameToLimitadorIdentifier("toys"))}
+
+	testCases := []struct {
+		name       string
+		namespace  string
+		conditions []string
+		expected   bool
+	}{
+		{"limit of a policy", LimitsNamespace(client.ObjectKey{Name: "gw", Namespace: "gw-ns"}, client.ObjectKey{Name: "rlp", Namespace: "ns"}), generatedConditions, true},
+		{"limit of a policy under a counter domain", SharedLimitsNamespace("global", client.ObjectKey{Name: "rlp", Namespace: "ns"}), generatedConditions, true},
+		{"default limit", DefaultLimitsNamespace(client.ObjectKey{Name: "gw", Namespace: "gw-ns"}), generatedConditions, false},
+		{"limit set manually", "toystore", nil, false},
+		{"limit of a policy generated by a previous version", "ns/rlp", generatedConditions, true},
+		{"limit set manually in the namespace of a single object", "ns/rlp", nil, false},
+		{"limit set manually in the namespace of a single object with conditions", "ns/rlp", []string{`req.method == "GET"`}, false},
+		{"malformed legacy keys", "ns/rlp/other", generatedConditions, false},
+		{"malformed keys", "gw#rlp", generatedConditions, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if got := IsRateLimitPolicyLimit(limitadorv1alpha1.RateLimit{Namespace: tc.namespace, Conditions: tc.conditions}); got != tc.expected {
+				subT.Errorf("IsRateLimitPolicyLimit(%s) = %t, want %t", tc.namespace, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPolicyLimitsNamespace(t *testing.T) {
+	gwKey := client.ObjectKey{Name: "gw", Namespace: "gw-ns"}
+	rlpKey := client.ObjectKey{Name: "rlp", Namespace: "ns"}
+
+	defer func(perGateway bool) { LimitsPerGateway = perGateway }(LimitsPerGateway)
+
+	LimitsPerGateway = false
+	if got := PolicyLimitsNamespace("", gwKey, rlpKey); got != "ns/rlp" {
+		t.Errorf("PolicyLimitsNamespace() = %s, want the namespace of the policy", got)
+	}
+	if !IsRateLimitPolicyLimit(LimitadorRateLimitsFromRLP(&kuadrantv1beta2.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: rlpKey.Name, Namespace: rlpKey.Namespace},
+		Spec: kuadrantv1beta2.RateLimitPolicySpec{
+			Limits: map[string]kuadrantv1beta2.Limit{"toys": {Rates: []kuadrantv1beta2.Rate{{Limit: 5, Duration: 10, Unit: "second"}}}},
+		},
+	}, []client.ObjectKey{gwKey})[0]) {
+		t.Error("the limits of a policy in the namespace of the policy should be managed")
+	}
+
+	LimitsPerGateway = true
+	if got := PolicyLimitsNamespace("", gwKey, rlpKey); got != "gw-ns/gw#ns/rlp" {
+		t.Errorf("PolicyLimitsNamespace() = %s, want the namespace of the gateway", got)
+	}
+	if got := PolicyLimitsNamespace("global", gwKey, rlpKey); got != "global#ns/rlp" {
+		t.Errorf("PolicyLimitsNamespace() = %s, want the namespace of the counter domain", got)
+	}
+}
+
+func TestRateLimitPolicyLimits(t *testing.T) {
+	gwKey := client.ObjectKey{Name: "gw", Namespace: "gw-ns"}
+	rlp := testRLP_1Limit_1Rate("ns", "rlp")
+	other := testRLP_1Limit_1Rate("ns", "other")
+
+	for _, perGateway := range []bool{false, true} {
+		t.Run(fmt.Sprintf("counters per gateway %t", perGateway), func(subT *testing.T) {
+			defer func(perGateway bool) { LimitsPerGateway = perGateway }(LimitsPerGateway)
+			LimitsPerGateway = perGateway
+
+			rlpLimits := LimitadorRateLimitsFromRLP(rlp, []client.ObjectKey{gwKey})
+			limits := append(RateLimitList{}, rlpLimits...)
+			limits = append(limits, LimitadorRateLimitsFromRLP(other, []client.ObjectKey{gwKey})...)
+			limits = append(limits, limitadorv1alpha1.RateLimit{Namespace: "manual", MaxValue: 1, Seconds: 1})
+
+			if got := RateLimitPolicyLimits(limits, client.ObjectKeyFromObject(rlp)); !Equal(got, rlpLimits) {
+				subT.Errorf("expected the limits of the policy only, got %v", got)
+			}
+		})
+	}
+}